@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/client/cli"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/config"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// The profile flag is pulled out before loading the configuration,
+	// because the profile decides which configuration to load.
+	profile, args, err := cli.ExtractProfileFlag(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	if profile == "" {
+		profile = os.Getenv("GOPHKEEPER_PROFILE")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	if err := cfg.UseProfile(profile); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	app := cli.NewApp(cfg)
+	if err := app.Run(ctx, args); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/config"
+)
+
+// runConfigCmd handles the "config" subcommand. "validate" loads the
+// configuration exactly as the server would, so an invalid combination
+// fails here with the same error, and prints the effective settings with
+// secrets redacted.
+func runConfigCmd(args []string) error {
+	if len(args) != 1 || args[0] != "validate" {
+		return errors.New("usage: gophkeeper-server config validate")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	fmt.Println(cfg.Display())
+	return nil
+}
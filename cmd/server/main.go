@@ -0,0 +1,457 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/blob"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/cache"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/config"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/handler"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/kms"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/logging"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/notify"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/cached"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/postgres"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/sqlite"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/service"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+func main() {
+	slog.SetDefault(logging.New(os.Stderr))
+	if len(os.Args) > 1 {
+		var cmd func([]string) error
+		switch os.Args[1] {
+		case "migrate":
+			cmd = runMigrate
+		case "backup":
+			cmd = runBackup
+		case "restore":
+			cmd = runRestore
+		case "config":
+			cmd = runConfigCmd
+		}
+		if cmd != nil {
+			if err := cmd(os.Args[2:]); err != nil {
+				slog.Error(os.Args[1]+" failed", "err", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	if err := run(); err != nil {
+		slog.Error("server exited", "err", err)
+		os.Exit(1)
+	}
+}
+
+// runDebugServer serves the pprof endpoints on their own listener until
+// ctx is cancelled. They stay off the API port on purpose: the profiles
+// expose server internals, so the listener belongs on localhost or
+// behind a firewall.
+func runDebugServer(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	slog.Info("debug server listening", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Warn("debug server", "err", err)
+	}
+}
+
+// newBlobStore builds the configured blob store, or nil to keep all
+// payloads in Postgres.
+func newBlobStore(ctx context.Context, cfg *config.Config) (blob.Store, error) {
+	switch cfg.BlobStore {
+	case "disk":
+		return blob.NewDiskStore(cfg.BlobDir)
+	case "s3":
+		return blob.NewS3Store(ctx, blob.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			UseSSL:    cfg.S3UseSSL,
+		})
+	default:
+		return nil, nil
+	}
+}
+
+// storage bundles the repositories of the configured database backend.
+type storage struct {
+	users        repository.UserRepository
+	secrets      repository.SecretRepository
+	attachments  repository.AttachmentRepository
+	versions     repository.SecretVersionRepository
+	audit        repository.AuditRepository
+	outbox       repository.OutboxRepository
+	shares       repository.ShareRepository
+	secretShares repository.SecretShareRepository
+	devices      repository.DeviceRepository
+	orgs         repository.OrgRepository
+	emergency    repository.EmergencyRepository
+	webhooks     repository.WebhookRepository
+	tm           repository.TransactionManager
+	close        func()
+	// health holds the backend's readiness probes: database connectivity
+	// and, for Postgres, whether all migrations have run.
+	health []handler.HealthCheck
+}
+
+// newStorage connects the configured database backend and builds its
+// repositories.
+func newStorage(ctx context.Context, cfg *config.Config) (*storage, error) {
+	switch cfg.DBDriver {
+	case "memory":
+		slog.Warn("memory storage holds all data in process memory and loses it on shutdown")
+		return &storage{
+			users:        memory.NewUserRepository(),
+			secrets:      memory.NewSecretRepository(),
+			attachments:  memory.NewAttachmentRepository(),
+			versions:     memory.NewSecretVersionRepository(),
+			audit:        memory.NewAuditRepository(),
+			outbox:       memory.NewOutboxRepository(),
+			shares:       memory.NewShareRepository(),
+			secretShares: memory.NewSecretShareRepository(),
+			devices:      memory.NewDeviceRepository(),
+			orgs:         memory.NewOrgRepository(),
+			emergency:    memory.NewEmergencyRepository(),
+			webhooks:     memory.NewWebhookRepository(),
+			tm:           repository.NoopTransactionManager{},
+			close:        func() {},
+		}, nil
+	case "sqlite":
+		dbh, err := sqlite.Open(ctx, cfg.DBPath)
+		if err != nil {
+			return nil, err
+		}
+		return &storage{
+			users:        sqlite.NewUserRepository(dbh),
+			secrets:      sqlite.NewSecretRepository(dbh),
+			attachments:  sqlite.NewAttachmentRepository(dbh),
+			versions:     sqlite.NewSecretVersionRepository(dbh),
+			audit:        sqlite.NewAuditRepository(dbh),
+			outbox:       sqlite.NewOutboxRepository(dbh),
+			shares:       sqlite.NewShareRepository(dbh),
+			secretShares: sqlite.NewSecretShareRepository(dbh),
+			devices:      sqlite.NewDeviceRepository(dbh),
+			orgs:         sqlite.NewOrgRepository(dbh),
+			emergency:    sqlite.NewEmergencyRepository(dbh),
+			webhooks:     sqlite.NewWebhookRepository(dbh),
+			tm:           sqlite.NewTransactionManager(dbh),
+			close:        func() { dbh.Close() },
+			health: []handler.HealthCheck{
+				{Name: "database", Check: dbh.PingContext},
+			},
+		}, nil
+	default:
+		settings := postgres.PoolSettings{
+			MaxConns:          int32(cfg.DBMaxConns),
+			MaxConnIdleTime:   cfg.DBMaxConnIdleTime,
+			HealthCheckPeriod: cfg.DBHealthCheckPeriod,
+		}
+		primary, err := postgres.New(ctx, cfg.DatabaseURI, settings)
+		if err != nil {
+			return nil, err
+		}
+		var replica *pgxpool.Pool
+		if cfg.DatabaseReplicaURI != "" {
+			replica, err = postgres.New(ctx, cfg.DatabaseReplicaURI, settings)
+			if err != nil {
+				primary.Close()
+				return nil, err
+			}
+		}
+		if cfg.MigrateOnStart {
+			applied, err := postgres.Migrate(ctx, primary)
+			if err != nil {
+				primary.Close()
+				return nil, err
+			}
+			if applied > 0 {
+				slog.Info("applied migrations", "count", applied)
+			}
+		}
+		pool := postgres.NewPool(primary, replica)
+		return &storage{
+			users:        postgres.NewUserRepository(pool),
+			secrets:      postgres.NewSecretRepository(pool),
+			attachments:  postgres.NewAttachmentRepository(pool),
+			versions:     postgres.NewSecretVersionRepository(pool),
+			audit:        postgres.NewAuditRepository(pool),
+			outbox:       postgres.NewOutboxRepository(pool),
+			shares:       postgres.NewShareRepository(pool),
+			secretShares: postgres.NewSecretShareRepository(pool),
+			devices:      postgres.NewDeviceRepository(pool),
+			orgs:         postgres.NewOrgRepository(pool),
+			emergency:    postgres.NewEmergencyRepository(pool),
+			webhooks:     postgres.NewWebhookRepository(pool),
+			tm:           postgres.NewTransactionManager(primary),
+			close: func() {
+				primary.Close()
+				if replica != nil {
+					replica.Close()
+				}
+			},
+			health: []handler.HealthCheck{
+				{Name: "database", Check: primary.Ping},
+				{Name: "migrations", Check: func(ctx context.Context) error {
+					return postgres.MigrationsApplied(ctx, primary)
+				}},
+			},
+		}, nil
+	}
+}
+
+// resolveMasterKey returns the server master secret: taken verbatim from
+// the configuration, or unwrapped through the configured KMS so the
+// plaintext never has to live in the environment.
+func resolveMasterKey(ctx context.Context, cfg *config.Config) ([]byte, error) {
+	if cfg.KMSProvider == "" {
+		return []byte(cfg.EncryptionKey), nil
+	}
+	wrapper := kms.NewVaultTransit(cfg.VaultAddr, cfg.VaultToken, cfg.VaultTransitKey)
+	masterKey, err := wrapper.Unwrap(ctx, []byte(cfg.EncryptionKeyWrapped))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap master key: %w", err)
+	}
+	return masterKey, nil
+}
+
+func run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := crypto.SetCipherSuite(cfg.CipherSuite); err != nil {
+		return err
+	}
+	masterKey, err := resolveMasterKey(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	st, err := newStorage(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer st.close()
+
+	users := st.users
+	secrets := st.secrets
+	if cfg.CacheRedisAddr != "" {
+		secrets = cached.NewSecretRepository(secrets, cache.NewRedis(cfg.CacheRedisAddr))
+	}
+	attachments := st.attachments
+	versions := st.versions
+	auditRepo := st.audit
+	outbox := st.outbox
+	tm := st.tm
+
+	var forwarder *service.SyslogForwarder
+	if cfg.AuditSyslogAddr != "" {
+		forwarder = service.NewSyslogForwarder(cfg.AuditSyslogNetwork, cfg.AuditSyslogAddr)
+	}
+	blobs, err := newBlobStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if blobs != nil {
+		st.health = append(st.health, handler.HealthCheck{Name: "blob", Check: blobs.Ping})
+	}
+	var providers []notify.Provider
+	if cfg.SMTPAddr != "" {
+		providers = append(providers, notify.NewSMTP(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUsername, cfg.SMTPPassword))
+	}
+	notifications := service.NewNotificationService(users, providers...)
+	audit := service.NewAuditService(auditRepo, forwarder, masterKey)
+	auth := service.NewAuthService(users, []byte(cfg.JWTSecret), masterKey, audit)
+	// JWT_SECRET may carry several kid=secret pairs for zero-downtime key
+	// rotation; a malformed list is a startup error, not a silent lockout.
+	jwtKeys, err := service.ParseJWTKeys(cfg.JWTSecret)
+	if err != nil {
+		return err
+	}
+	if cfg.JWTPrivateKey != "" {
+		if err := jwtKeys.AddKeyPair([]byte(cfg.JWTPrivateKey)); err != nil {
+			return err
+		}
+	}
+	auth.SetJWTKeys(jwtKeys)
+	auth.SetNotifier(notifications)
+	auth.SetPasswordPolicy(service.PasswordPolicy{
+		MinLength:  cfg.PasswordMinLength,
+		MinClasses: cfg.PasswordMinClasses,
+		MinScore:   cfg.PasswordMinScore,
+	})
+	auth.SetDeletionGrace(cfg.AccountDeleteGrace)
+	secretsSvc := service.NewSecretsService(service.SecretsServiceDeps{
+		Secrets:         secrets,
+		Attachments:     attachments,
+		Versions:        versions,
+		Outbox:          outbox,
+		TM:              tm,
+		Blobs:           blobs,
+		OffloadAll:      cfg.BlobResidency,
+		MasterKey:       masterKey,
+		EncryptMetadata: cfg.EncryptMetadata,
+		Audit:           audit,
+
+		MaxSecrets:      cfg.MaxSecretsPerUser,
+		MaxStorageBytes: cfg.MaxStorageBytesPerUser,
+	})
+	shareRepo := st.secretShares
+	reports := service.NewReportService(secretsSvc, users, shareRepo)
+	userShares := service.NewUserShareService(shareRepo, users, secretsSvc, audit)
+	deviceRepo := st.devices
+	devices := service.NewDeviceService(deviceRepo, audit)
+	devices.SetNotifier(notifications)
+	auth.SetDeviceGate(devices)
+	sync := service.NewSyncService(secretsSvc, audit, deviceRepo)
+	share := service.NewShareService(st.shares, secretsSvc, audit)
+	merge := service.NewMergeService(users, secretsSvc, tm, audit)
+	orgs := service.NewOrgService(st.orgs, users, secretsSvc, audit)
+	emergency := service.NewEmergencyService(st.emergency, users, secretsSvc, audit, notifications)
+	webhooks := service.NewWebhookService(st.webhooks, audit)
+	admin := service.NewAdminService(users, audit)
+	if err := admin.PromoteAdmins(ctx, cfg.AdminLogins); err != nil {
+		return err
+	}
+	var rotation handler.RotationService
+	if cfg.NewEncryptionKey != "" {
+		rotation = service.NewRotationService(users, st.orgs, secretsSvc, []byte(cfg.NewEncryptionKey), audit)
+	}
+
+	archiver := service.NewArchiver(users, secrets, cfg.ArchiveDryRun)
+	go archiver.Run(ctx)
+
+	reaper := service.NewReaper(users)
+	go reaper.Run(ctx)
+
+	if cfg.DebugAddr != "" {
+		go runDebugServer(ctx, cfg.DebugAddr)
+	}
+
+	// The dynamic configuration file carries the settings that reload
+	// live: log level, rate limits and CORS origins.
+	var limiter *middleware.RateLimiter
+	var cors *middleware.CORS
+	if cfg.ConfigFile != "" {
+		limiter = middleware.NewRateLimiter()
+		cors = middleware.NewCORS()
+		go config.WatchDynamic(ctx, cfg.ConfigFile, func(d *config.Dynamic) {
+			if lvl, err := logging.ParseLevel(d.LogLevel); err != nil {
+				slog.Warn("dynamic config", "err", err)
+			} else {
+				logging.SetLevel(lvl)
+			}
+			limiter.SetLimit(d.RateLimitRPS, d.RateLimitBurst)
+			cors.SetOrigins(d.CORSAllowedOrigins)
+			slog.Info("dynamic config applied",
+				"log_level", d.LogLevel, "rate_limit_rps", d.RateLimitRPS, "cors_origins", len(d.CORSAllowedOrigins))
+		})
+	}
+
+	// Every publisher must accept an event before it is marked published,
+	// so a broken webhook receiver is retried on the next dispatcher sweep.
+	hub := service.NewStreamHub()
+	dispatcher := service.NewOutboxDispatcher(outbox, hub, webhooks)
+	go dispatcher.Run(ctx)
+	// Login events enter the same feed so webhooks can observe sign-ins.
+	auth.SetOutbox(outbox)
+
+	srv := &http.Server{
+		Addr: cfg.RunAddress,
+		Handler: handler.NewRouter(handler.Services{
+			Auth:     auth,
+			Tokens:   auth,
+			Secrets:  secretsSvc,
+			Audit:    audit,
+			Reports:  reports,
+			Sync:     sync,
+			Identity: service.NewIdentityService(masterKey),
+			Share:    share,
+			Merge:    merge,
+			Stream:   hub,
+			Devices:  devices,
+			Webhooks: webhooks,
+			Admin:    admin,
+
+			Notifications: notifications,
+
+			Rotation:   rotation,
+			Orgs:       orgs,
+			UserShares: userShares,
+			Emergency:  emergency,
+			ShareBrand: cfg.ShareBrand,
+
+			RequestTimeout: cfg.RequestTimeout,
+			SyncTimeout:    cfg.SyncTimeout,
+
+			MaxBodyBytes:     cfg.MaxBodyBytes,
+			MaxSyncBodyBytes: cfg.MaxSyncBodyBytes,
+
+			HealthChecks: st.health,
+
+			RateLimit: limiter,
+			CORS:      cors,
+			JWKS:      jwtKeys.JWKS(),
+		}),
+	}
+
+	tlsCfg, err := newTLSConfig(cfg)
+	if err != nil {
+		return err
+	}
+	srv.TLSConfig = tlsCfg
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "addr", cfg.RunAddress)
+		if tlsCfg != nil {
+			errCh <- srv.ListenAndServeTLS("", "")
+			return
+		}
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/postgres"
+)
+
+// runMigrate handles the "migrate" subcommand: "up" (the default) applies
+// pending embedded migrations, "status" lists every script and whether it
+// has run. Migrations are forward-only, so there is no "down".
+func runMigrate(args []string) error {
+	verb := "up"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		verb, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dsn := fs.String("d", "", "postgres connection string")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	pool, err := connect(dsn)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	ctx := context.Background()
+
+	switch verb {
+	case "up":
+		applied, err := postgres.Migrate(ctx, pool)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migrations\n", applied)
+		return nil
+	case "status":
+		status, err := postgres.MigrationStatus(ctx, pool)
+		if err != nil {
+			return err
+		}
+		for _, m := range status {
+			if m.Applied {
+				fmt.Printf("%s\tapplied %s\n", m.Filename, m.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("%s\tpending\n", m.Filename)
+			}
+		}
+		return nil
+	case "down":
+		return errors.New("migrate: migrations are forward-only, restore from a backup instead")
+	default:
+		return fmt.Errorf("migrate: unknown subcommand %q, want up or status", verb)
+	}
+}
+
+// connect resolves the connection string from the flag or DATABASE_URI
+// and opens a pool for a maintenance subcommand.
+func connect(dsn *string) (*pgxpool.Pool, error) {
+	if *dsn == "" {
+		*dsn = os.Getenv("DATABASE_URI")
+	}
+	if *dsn == "" {
+		return nil, errors.New("a database URI is required (-d or DATABASE_URI)")
+	}
+	return postgres.New(context.Background(), *dsn, postgres.PoolSettings{})
+}
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+var accountCmd = &Command{
+	Name:        "account",
+	Usage:       "account <subcommand>",
+	Description: "manage account settings",
+	Run:         runAccount,
+}
+
+func runAccount(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gophkeeper account <hint|merge|e2e|logins|delete|restore>")
+	}
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "hint":
+		return accountHint(ctx, app, rest)
+	case "logins":
+		return accountLogins(ctx, app)
+	case "merge":
+		return accountMerge(ctx, app, rest)
+	case "e2e":
+		return accountE2E(ctx, app, rest)
+	case "delete":
+		return accountDelete(ctx, app, rest)
+	case "restore":
+		return accountRestore(ctx, app)
+	default:
+		return fmt.Errorf("unknown account subcommand %q", sub)
+	}
+}
+
+// accountMerge absorbs another account the user owns into this one. The
+// server re-encrypts and moves every secret, then deactivates the source,
+// so both passwords are asked up front.
+func accountMerge(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	from := fs.String("from", "", "login of the account to absorb")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	sourceLogin := *from
+	if sourceLogin == "" {
+		line, err := readLine("Login of the account to absorb: ")
+		if err != nil {
+			return err
+		}
+		sourceLogin = line
+	}
+	password, err := readPassword("Your password: ")
+	if err != nil {
+		return err
+	}
+	sourcePassword, err := readPassword(fmt.Sprintf("Password of %s: ", sourceLogin))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "All secrets of %s will move here and that account will be deactivated.\n", sourceLogin)
+	answer, err := readLine("Continue? (y/N): ")
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+		return errors.New("merge cancelled")
+	}
+	moved, err := app.API.MergeAccount(ctx, password, sourceLogin, sourcePassword)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("merged %s: %d secret(s) moved; run `gophkeeper secrets list` to refresh the cache\n", sourceLogin, moved)
+	return nil
+}
+
+// accountDelete schedules the account for permanent deletion. The server
+// enforces a grace period during which `account restore` can revert it;
+// after that everything the account owns is purged.
+func accountDelete(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	password, err := readPassword("Your password: ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "This schedules the account and every secret in it for permanent deletion.")
+	answer, err := readLine("Continue? (y/N): ")
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+		return errors.New("deletion cancelled")
+	}
+	deleteAfter, err := app.API.DeleteAccount(ctx, password)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("deletion scheduled for %s; run `gophkeeper account restore` before then to keep the account\n",
+		deleteAfter.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+// accountRestore cancels a pending account deletion.
+func accountRestore(ctx context.Context, app *App) error {
+	if err := app.API.RestoreAccount(ctx); err != nil {
+		return err
+	}
+	fmt.Println("account deletion cancelled")
+	return nil
+}
+
+// accountHint manages the vault hint: a note to your future self about
+// which master password scheme this profile uses. It is stored encrypted
+// on the server and cached locally so login can show it.
+func accountHint(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gophkeeper account hint <set|show|clear>")
+	}
+	switch args[0] {
+	case "set":
+		fs := flag.NewFlagSet("hint set", flag.ContinueOnError)
+		text := fs.String("text", "", "hint text (prompted if omitted)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		hint := *text
+		if hint == "" {
+			line, err := readLine("Hint (never the password itself): ")
+			if err != nil {
+				return err
+			}
+			hint = line
+		}
+		if err := app.API.SetHint(ctx, hint); err != nil {
+			return err
+		}
+		app.cacheHint(hint)
+		fmt.Println("hint saved")
+		return nil
+	case "show":
+		hint, err := app.API.GetHint(ctx)
+		if err != nil {
+			return err
+		}
+		if hint == "" {
+			fmt.Println("no hint set")
+			return nil
+		}
+		fmt.Println(hint)
+		app.cacheHint(hint)
+		return nil
+	case "clear":
+		if err := app.API.SetHint(ctx, ""); err != nil {
+			return err
+		}
+		app.cacheHint("")
+		fmt.Println("hint cleared")
+		return nil
+	default:
+		return fmt.Errorf("unknown hint subcommand %q", args[0])
+	}
+}
+
+// cacheHint mirrors the hint into the data directory so login can show it
+// before the user has a session.
+func (a *App) cacheHint(hint string) {
+	path := a.Config.HintPath()
+	var err error
+	if hint == "" {
+		err = os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	} else {
+		err = os.WriteFile(path, []byte(hint+"\n"), 0o600)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not update cached hint: %v\n", err)
+	}
+}
+
+// showCachedHint prints the locally cached hint, if any, before the
+// password prompt.
+func (a *App) showCachedHint() {
+	data, err := os.ReadFile(a.Config.HintPath())
+	if err != nil || len(data) == 0 {
+		return
+	}
+	fmt.Printf("Hint: %s\n", strings.TrimSpace(string(data)))
+}
+
+// accountLogins prints the account's recent sign-in attempts so
+// suspicious activity is easy to spot.
+func accountLogins(ctx context.Context, app *App) error {
+	records, err := app.API.LoginHistory(ctx, 0)
+	if err != nil {
+		return err
+	}
+	if done, err := app.emit(records); done || err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tIP\tCLIENT\tRESULT")
+	for _, rec := range records {
+		result := "ok"
+		if !rec.Success {
+			result = "FAILED"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", rec.At.Local().Format("2006-01-02 15:04"), rec.IP, rec.UserAgent, result)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/client/config"
+)
+
+var aliasCmd = &Command{
+	Name:        "alias",
+	Usage:       "alias [name expansion... | -d name]",
+	Description: "list, set or delete command aliases",
+	Run:         runAlias,
+}
+
+// runAlias manages the alias table in the settings file. Without arguments
+// it lists the aliases; `alias ls secrets list` defines one and `alias -d
+// ls` removes it.
+func runAlias(_ context.Context, app *App, args []string) error {
+	settings, err := config.LoadSettings(app.Config.SettingsPath())
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(args) == 0:
+		names := make([]string, 0, len(settings.Aliases))
+		for name := range settings.Aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s = %s\n", name, settings.Aliases[name])
+		}
+		return nil
+	case args[0] == "-d":
+		if len(args) != 2 {
+			return errors.New("usage: gophkeeper alias -d <name>")
+		}
+		if _, ok := settings.Aliases[args[1]]; !ok {
+			return fmt.Errorf("no alias named %q", args[1])
+		}
+		delete(settings.Aliases, args[1])
+		return settings.Save(app.Config.SettingsPath())
+	case len(args) >= 2:
+		name := args[0]
+		if _, taken := app.commands[name]; taken {
+			return fmt.Errorf("%q is a command and cannot be an alias", name)
+		}
+		if settings.Aliases == nil {
+			settings.Aliases = make(map[string]string)
+		}
+		settings.Aliases[name] = strings.Join(args[1:], " ")
+		return settings.Save(app.Config.SettingsPath())
+	default:
+		return errors.New("usage: gophkeeper alias [name expansion... | -d name]")
+	}
+}
+
+// expandAlias rewrites args when args[0] is a defined alias. Aliases never
+// shadow commands and do not expand recursively.
+func (a *App) expandAlias(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	if _, isCommand := a.commands[args[0]]; isCommand {
+		return args
+	}
+	settings, err := config.LoadSettings(a.Config.SettingsPath())
+	if err != nil {
+		return args
+	}
+	expansion, ok := settings.Aliases[args[0]]
+	if !ok {
+		return args
+	}
+	return append(strings.Fields(expansion), args[1:]...)
+}
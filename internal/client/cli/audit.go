@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/pkg/api"
+)
+
+// PasswordMaxAge is how old a password may get before `secrets audit` flags
+// it for rotation.
+const PasswordMaxAge = 180 * 24 * time.Hour
+
+// passwordStrength buckets, weakest first.
+const (
+	strengthVeryWeak = iota
+	strengthWeak
+	strengthFair
+	strengthStrong
+)
+
+var strengthLabels = [...]string{"very weak", "weak", "fair", "strong"}
+
+// commonPasswords are passwords so frequent that any hit is an instant
+// very-weak verdict regardless of length.
+var commonPasswords = map[string]struct{}{
+	"password": {}, "password1": {}, "123456": {}, "12345678": {},
+	"123456789": {}, "qwerty": {}, "abc123": {}, "letmein": {},
+	"monkey": {}, "dragon": {}, "iloveyou": {}, "admin": {},
+	"welcome": {}, "login": {}, "princess": {}, "sunshine": {},
+}
+
+// scorePassword rates a password into one of the strength buckets. The
+// heuristic follows the zxcvbn idea in miniature: length and character
+// variety raise the score, dictionary hits and repetition sink it.
+func scorePassword(password string) int {
+	if password == "" {
+		return strengthVeryWeak
+	}
+	if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+		return strengthVeryWeak
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, ok := range []bool{lower, upper, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+
+	// A password made of one repeated rune has almost no entropy no matter
+	// how long it is.
+	if strings.Count(password, password[:1]) == len(password) {
+		return strengthVeryWeak
+	}
+
+	switch {
+	case len(password) < 8:
+		return strengthVeryWeak
+	case len(password) < 12 && classes < 3:
+		return strengthWeak
+	case len(password) < 12 || classes < 3:
+		return strengthFair
+	default:
+		return strengthStrong
+	}
+}
+
+// passwordSetAt reports when the current password of a credentials payload
+// was last changed. The newest history entry's ReplacedAt is when the
+// current password took over; without history the secret's creation time is
+// the best estimate.
+func passwordSetAt(secret *models.Secret, payload *models.CredentialsPayload) time.Time {
+	if len(payload.History) > 0 {
+		return payload.History[0].ReplacedAt
+	}
+	return secret.CreatedAt
+}
+
+// secretsAudit scans every credentials secret for reused, weak and old
+// passwords and prints a per-vault report.
+func secretsAudit(ctx context.Context, app *App, _ []string) error {
+	secrets, err := app.API.GetSecrets(ctx, api.ListOptions{IncludeArchived: true})
+	if err != nil {
+		return err
+	}
+
+	type entry struct {
+		secret  models.Secret
+		payload models.CredentialsPayload
+	}
+	var entries []entry
+	byPassword := make(map[string][]string)
+	for _, s := range secrets {
+		if s.Type != models.SecretTypeCredentials {
+			continue
+		}
+		var p models.CredentialsPayload
+		if err := s.DecodePayload(&p); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping %s: %v\n", s.ID, err)
+			continue
+		}
+		entries = append(entries, entry{secret: s, payload: p})
+		byPassword[p.Password] = append(byPassword[p.Password], s.Name)
+	}
+	if len(entries) == 0 {
+		fmt.Println("no credentials secrets to audit")
+		return nil
+	}
+
+	now := time.Now()
+	issues := 0
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tISSUES")
+	for _, e := range entries {
+		var problems []string
+		if names := byPassword[e.payload.Password]; len(names) > 1 {
+			problems = append(problems, fmt.Sprintf("reused by %s", strings.Join(others(names, e.secret.Name), ", ")))
+		}
+		if score := scorePassword(e.payload.Password); score <= strengthWeak {
+			problems = append(problems, strengthLabels[score]+" password")
+		}
+		if age := now.Sub(passwordSetAt(&e.secret, &e.payload)); age > PasswordMaxAge {
+			problems = append(problems, fmt.Sprintf("unchanged for %d days", int(age.Hours()/24)))
+		}
+		if len(problems) == 0 {
+			continue
+		}
+		issues++
+		fmt.Fprintf(w, "%s\t%s\n", e.secret.Name, strings.Join(problems, "; "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if issues == 0 {
+		fmt.Println("all passwords look healthy")
+	} else {
+		fmt.Fprintf(os.Stderr, "%d of %d credentials need attention\n", issues, len(entries))
+	}
+	return nil
+}
+
+// others returns names with the first occurrence of self removed.
+func others(names []string, self string) []string {
+	rest := make([]string, 0, len(names)-1)
+	skipped := false
+	for _, n := range names {
+		if n == self && !skipped {
+			skipped = true
+			continue
+		}
+		rest = append(rest, n)
+	}
+	return rest
+}
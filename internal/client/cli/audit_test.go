@@ -0,0 +1,31 @@
+package cli
+
+import "testing"
+
+func TestScorePassword(t *testing.T) {
+	tests := []struct {
+		password string
+		want     int
+	}{
+		{"", strengthVeryWeak},
+		{"Password1", strengthVeryWeak}, // dictionary hit, case-insensitive
+		{"aaaaaaaaaaaaaaaa", strengthVeryWeak},
+		{"short1A", strengthVeryWeak},
+		{"abcdefgh", strengthWeak},
+		{"Abcdefgh1", strengthFair},
+		{"abcdefghijklmnop", strengthFair},
+		{"Tr0ub4dor&Horse!", strengthStrong},
+	}
+	for _, tt := range tests {
+		if got := scorePassword(tt.password); got != tt.want {
+			t.Errorf("scorePassword(%q) = %s, want %s", tt.password, strengthLabels[got], strengthLabels[tt.want])
+		}
+	}
+}
+
+func TestOthers(t *testing.T) {
+	got := others([]string{"a", "b", "a"}, "a")
+	if len(got) != 2 || got[0] != "b" || got[1] != "a" {
+		t.Fatalf("others = %v, want [b a]", got)
+	}
+}
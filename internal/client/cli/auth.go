@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/client/credstore"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/i18n"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/storage"
+)
+
+var registerCmd = &Command{
+	Name:        "register",
+	Usage:       "register",
+	Description: "create an account on the server",
+	Run: func(ctx context.Context, app *App, args []string) error {
+		login, password, remember, err := promptCredentials(args, "register")
+		if err != nil {
+			return err
+		}
+		if err := app.verifyServerIdentity(ctx); err != nil {
+			return err
+		}
+		if err := app.API.Register(ctx, login, password); err != nil {
+			return err
+		}
+		if err := app.saveToken(); err != nil {
+			return err
+		}
+		app.Storage.SetPassphrase(password)
+		app.rememberPassword(password, remember)
+		fmt.Println("registered and logged in")
+		return nil
+	},
+}
+
+var loginCmd = &Command{
+	Name:        "login",
+	Usage:       "login",
+	Description: "log in to the server",
+	Run: func(ctx context.Context, app *App, args []string) error {
+		app.showCachedHint()
+		login, password, remember, err := promptCredentials(args, "login")
+		if err != nil {
+			return err
+		}
+		if err := app.verifyServerIdentity(ctx); err != nil {
+			return err
+		}
+		if err := app.API.Login(ctx, login, password); err != nil {
+			return err
+		}
+		if err := app.saveToken(); err != nil {
+			return err
+		}
+		// The master password seals the local cache from here on; a
+		// plaintext cache from before login migrates forward in place.
+		app.Storage.SetPassphrase(password)
+		app.rememberPassword(password, remember)
+		if v, _ := app.Storage.Version(); v == storage.FormatJSON {
+			if _, err := app.Storage.Migrate(storage.FormatEncrypted); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: could not encrypt local cache: %v\n", err)
+			}
+		}
+		fmt.Println("logged in")
+		// Refresh the cached hint so the next login shows the latest one.
+		if hint, err := app.API.GetHint(ctx); err == nil {
+			app.cacheHint(hint)
+		}
+		return nil
+	},
+}
+
+// promptCredentials reads login and password from flags or interactively.
+// The password is only accepted as a flag for scripting; interactive entry
+// is hidden.
+func promptCredentials(args []string, cmd string) (login, password string, remember bool, err error) {
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	loginFlag := fs.String("login", "", "account login")
+	passwordFlag := fs.String("password", "", "account password (prompted if omitted)")
+	rememberFlag := fs.Bool("remember", false, "keep the master password in the credential store")
+	if err := fs.Parse(args); err != nil {
+		return "", "", false, err
+	}
+	if *loginFlag == "" {
+		fmt.Print("Login: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return "", "", false, err
+		}
+		*loginFlag = strings.TrimSpace(line)
+	}
+	if *passwordFlag == "" {
+		pw, err := readPassword(i18n.T("Password: "))
+		if err != nil {
+			return "", "", false, err
+		}
+		*passwordFlag = pw
+	}
+	return *loginFlag, *passwordFlag, *rememberFlag, nil
+}
+
+// rememberPassword stores the master password in the credential store when
+// asked to, so later commands can unlock the local cache without a prompt.
+func (a *App) rememberPassword(password string, remember bool) {
+	if !remember {
+		return
+	}
+	if err := a.Creds.Set(credstore.MasterPassword, password); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not store master password: %v\n", err)
+	}
+}
+
+// readPassword reads a password without echo when stdin is a terminal.
+func readPassword(prompt string) (string, error) {
+	fmt.Print(prompt)
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		data, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
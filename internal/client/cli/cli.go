@@ -0,0 +1,305 @@
+// Package cli implements the gophkeeper command-line client.
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/client/config"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/credstore"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/i18n"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/plugin"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/storage"
+	"github.com/alexuryumtsev/gophkeeper/pkg/api"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// Command is a top-level CLI command.
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+	Run         func(ctx context.Context, app *App, args []string) error
+}
+
+// App carries the dependencies shared by all commands.
+type App struct {
+	Config  *config.Config
+	API     *api.Client
+	Storage *storage.LocalStorage
+	Journal *storage.Journal
+	Creds   credstore.Store
+	// Output is the format requested with the global -output flag; commands
+	// that print results honor it via emit.
+	Output string
+
+	commands  map[string]*Command
+	renderers map[string]*plugin.Plugin
+}
+
+// NewApp wires up the client from its configuration.
+func NewApp(cfg *config.Config) *App {
+	app := &App{
+		Config:    cfg,
+		API:       api.New(cfg.ServerURL),
+		Storage:   storage.NewLocalStorage(cfg.CachePath()),
+		Journal:   storage.NewJournal(cfg.JournalPath()),
+		Creds:     credstore.Open(cfg.DataDir),
+		Output:    OutputTable,
+		commands:  make(map[string]*Command),
+		renderers: make(map[string]*plugin.Plugin),
+	}
+	if tlsCfg, err := newTLSConfig(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: tls configuration: %v\n", err)
+	} else if tlsCfg != nil {
+		app.API.SetTLSConfig(tlsCfg)
+	}
+	if settings, err := config.LoadSettings(cfg.SettingsPath()); err == nil {
+		if settings.E2E {
+			app.API.SetPayloadCipher(&e2eCipher{app: app})
+		}
+		applyKDFSettings(settings)
+		if err := crypto.SetCipherSuite(settings.Cipher); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: cipher setting: %v\n", err)
+		}
+	}
+	app.register(initCmd, registerCmd, loginCmd, secretsCmd, generateCmd, kubeCmd, reportCmd,
+		accountCmd, aliasCmd, cacheCmd, configCmd, lockCmd, unlockCmd, daemonCmd, devicesCmd, emergencyCmd, promptStatusCmd,
+		completionCmd, completeCmd, helpCmd)
+	app.loadPlugins()
+	return app
+}
+
+// newTLSConfig assembles the TLS settings of the configuration: client
+// certificate, custom CA bundle, pinned fingerprints and the dev-only
+// verification skip. It returns nil when none are set, leaving the
+// default system trust in place.
+func newTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	if cfg.ClientCertPath == "" && cfg.ClientKeyPath == "" && cfg.CAPath == "" &&
+		len(cfg.TLSPins) == 0 && !cfg.InsecureSkipVerify {
+		return nil, nil
+	}
+	tlsCfg, err := api.NewTLSConfig(api.TLSSettings{
+		CAFile:             cfg.CAPath,
+		Pins:               cfg.TLSPins,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ClientCertPath != "" || cfg.ClientKeyPath != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertPath, cfg.ClientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
+
+// applyKDFSettings overrides the Argon2id cost of newly sealed data with
+// any tuned values from the settings file, keeping the defaults for the
+// rest. Invalid values are reported and ignored rather than breaking the
+// client: existing data still opens with its recorded parameters.
+func applyKDFSettings(settings *config.Settings) {
+	if settings.KDFTime == 0 && settings.KDFMemoryKiB == 0 && settings.KDFThreads == 0 {
+		return
+	}
+	p := crypto.DefaultKDFParams
+	if settings.KDFTime > 0 {
+		p.Time = uint32(settings.KDFTime)
+	}
+	if settings.KDFMemoryKiB > 0 {
+		p.Memory = uint32(settings.KDFMemoryKiB)
+	}
+	if settings.KDFThreads > 0 {
+		p.Threads = uint8(settings.KDFThreads)
+	}
+	if err := crypto.SetKDFParams(p); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: kdf settings: %v\n", err)
+	}
+}
+
+// loadPlugins registers the commands and renderers of discovered plugins.
+// Built-in commands always win a name clash, and plugin trouble never
+// breaks the client.
+func (a *App) loadPlugins() {
+	plugins, err := plugin.Discover(context.Background(), a.Config.PluginsDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: discover plugins: %v\n", err)
+		return
+	}
+	for i := range plugins {
+		p := &plugins[i]
+		for _, spec := range p.Manifest.Commands {
+			if _, taken := a.commands[spec.Name]; taken {
+				fmt.Fprintf(os.Stderr, "warning: plugin %s: command %q already exists\n", p.Manifest.Name, spec.Name)
+				continue
+			}
+			spec := spec
+			a.register(&Command{
+				Name:        spec.Name,
+				Usage:       spec.Usage,
+				Description: fmt.Sprintf("%s (plugin %s)", spec.Description, p.Manifest.Name),
+				Run: func(ctx context.Context, app *App, args []string) error {
+					return p.RunCommand(ctx, spec.Name, args, []string{
+						"GOPHKEEPER_SERVER=" + app.Config.ServerURL,
+						"GOPHKEEPER_DATA_DIR=" + app.Config.DataDir,
+						"GOPHKEEPER_TOKEN=" + app.API.Token(),
+					})
+				},
+			})
+		}
+		for _, secretType := range p.Manifest.Renderers {
+			a.renderers[secretType] = p
+		}
+	}
+}
+
+// renderWithPlugin renders a payload of a type no built-in renderer knows.
+// It reports whether a plugin volunteered.
+func (a *App) renderWithPlugin(ctx context.Context, secretType string, payload []byte) (string, bool, error) {
+	p, ok := a.renderers[secretType]
+	if !ok {
+		return "", false, nil
+	}
+	out, err := p.Render(ctx, secretType, payload)
+	return out, true, err
+}
+
+func (a *App) register(cmds ...*Command) {
+	for _, cmd := range cmds {
+		a.commands[cmd.Name] = cmd
+	}
+}
+
+// Run dispatches the command named by args[0], after pulling out global
+// flags and expanding aliases.
+func (a *App) Run(ctx context.Context, args []string) error {
+	i18n.Detect()
+	var err error
+	if args, err = extractLocaleFlag(args); err != nil {
+		return err
+	}
+	if a.Output, args, err = extractOutputFlag(args); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		a.printUsage()
+		return errors.New(i18n.T("no command given"))
+	}
+	args = a.expandAlias(args)
+	cmd, ok := a.commands[args[0]]
+	if !ok {
+		a.printUsage()
+		return fmt.Errorf(i18n.T("unknown command %q"), args[0])
+	}
+	a.enforceIdleLock()
+	if err := a.loadToken(); err != nil {
+		return err
+	}
+	return localizeError(cmd.Run(ctx, a, args[1:]))
+}
+
+// extractLocaleFlag removes a global `-locale <lang>` (or `--locale`,
+// `=`-joined) from args and applies it, overriding the environment.
+func extractLocaleFlag(args []string) ([]string, error) {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, joined := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "locale" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !joined {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag %s needs a value", arg)
+			}
+			i++
+			value = args[i]
+		}
+		i18n.SetLocale(value)
+	}
+	return rest, nil
+}
+
+// localizeError rewrites the well-known server error categories in the
+// selected locale. Under English, or for errors outside the taxonomy, the
+// original message passes through untouched.
+func localizeError(err error) error {
+	if err == nil || i18n.Locale() == "en" {
+		return err
+	}
+	switch {
+	case errors.Is(err, api.ErrNotFound):
+		return errors.New(i18n.T("not found"))
+	case errors.Is(err, api.ErrConflict):
+		return errors.New(i18n.T("already exists"))
+	case errors.Is(err, api.ErrUnauthorized):
+		return errors.New(i18n.T("invalid credentials"))
+	case errors.Is(err, api.ErrForbidden):
+		return errors.New(i18n.T("permission denied"))
+	case errors.Is(err, api.ErrTimeout):
+		return errors.New(i18n.T("the request timed out"))
+	}
+	return err
+}
+
+func (a *App) printUsage() {
+	fmt.Fprintln(os.Stderr, i18n.T("Usage: gophkeeper <command> [arguments]"))
+	fmt.Fprintln(os.Stderr, "\n"+i18n.T("Commands:"))
+	names := make([]string, 0, len(a.commands))
+	for name := range a.commands {
+		// Hidden helpers like __complete stay out of the listing.
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd := a.commands[name]
+		fmt.Fprintf(os.Stderr, "  %-24s %s\n", cmd.Usage, cmd.Description)
+	}
+}
+
+// loadToken restores a saved session token, if any. Tokens saved by older
+// clients straight into the data directory are still honored.
+func (a *App) loadToken() error {
+	token, err := a.Creds.Get(credstore.Token)
+	if errors.Is(err, credstore.ErrNotFound) {
+		data, readErr := os.ReadFile(a.Config.TokenPath())
+		if errors.Is(readErr, os.ErrNotExist) {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+		token = strings.TrimSpace(string(data))
+	} else if err != nil {
+		return err
+	}
+	a.API.SetToken(token)
+	return nil
+}
+
+// saveToken persists the session token for subsequent invocations.
+func (a *App) saveToken() error {
+	return a.Creds.Set(credstore.Token, a.API.Token())
+}
+
+var helpCmd = &Command{
+	Name:        "help",
+	Usage:       "help",
+	Description: "print this help",
+	Run: func(_ context.Context, app *App, _ []string) error {
+		app.printUsage()
+		return nil
+	},
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// defaultListColumns are the columns `secrets list` prints when neither the
+// -columns flag nor the saved settings choose others.
+var defaultListColumns = []string{"id", "name", "type", "updated"}
+
+// listColumn renders one column of the `secrets list` table. New columns
+// (tags, folder, favorite, expiry) are added here as those features land.
+type listColumn struct {
+	header string
+	value  func(s *models.Secret) string
+}
+
+var listColumns = map[string]listColumn{
+	"id":   {"ID", func(s *models.Secret) string { return s.ID.String() }},
+	"name": {"NAME", func(s *models.Secret) string { return s.Name }},
+	"type": {"TYPE", func(s *models.Secret) string { return string(s.Type) }},
+	"metadata": {"METADATA", func(s *models.Secret) string {
+		return s.Metadata
+	}},
+	"protected": {"PROTECTED", func(s *models.Secret) string { return yesNo(s.Protected) }},
+	"archived":  {"ARCHIVED", func(s *models.Secret) string { return yesNo(s.Archived) }},
+	"created": {"CREATED", func(s *models.Secret) string {
+		return s.CreatedAt.Local().Format(time.DateTime)
+	}},
+	"updated": {"UPDATED", func(s *models.Secret) string {
+		return s.UpdatedAt.Local().Format(time.DateTime)
+	}},
+	"accessed": {"ACCESSED", func(s *models.Secret) string {
+		return s.AccessedAt.Local().Format(time.DateTime)
+	}},
+}
+
+// parseColumns validates a comma-separated column list against the known
+// columns.
+func parseColumns(spec string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := listColumns[name]; !ok {
+			return nil, fmt.Errorf("unknown column %q (known: %s)", name, strings.Join(knownColumns(), ", "))
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no columns given")
+	}
+	return names, nil
+}
+
+func knownColumns() []string {
+	names := make([]string, 0, len(listColumns))
+	for name := range listColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var completionCmd = &Command{
+	Name:        "completion",
+	Usage:       "completion <bash|zsh|fish|powershell>",
+	Description: "print a shell completion script",
+	Run:         runCompletion,
+}
+
+// completeCmd is the hidden helper the completion scripts call to obtain
+// candidates for the word being completed.
+var completeCmd = &Command{
+	Name: "__complete",
+	Run:  runComplete,
+}
+
+// Completion scripts. Each one delegates candidate generation to the
+// hidden __complete command with the words typed so far, so the scripts
+// never need to know the command tree.
+const (
+	bashCompletion = `_gophkeeper() {
+	local candidates
+	candidates="$(gophkeeper __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)"
+	COMPREPLY=($(compgen -W "$candidates" -- "${COMP_WORDS[COMP_CWORD]}"))
+}
+complete -F _gophkeeper gophkeeper
+`
+	zshCompletion = `#compdef gophkeeper
+_gophkeeper() {
+	local -a candidates
+	candidates=(${(f)"$(gophkeeper __complete "${words[@]:2:-1}" 2>/dev/null)"})
+	compadd -- $candidates
+}
+compdef _gophkeeper gophkeeper
+`
+	fishCompletion = `function __gophkeeper_complete
+	gophkeeper __complete (commandline -opc)[2..-1] 2>/dev/null
+end
+complete -c gophkeeper -f -a "(__gophkeeper_complete)"
+`
+	powershellCompletion = `Register-ArgumentCompleter -Native -CommandName gophkeeper -ScriptBlock {
+	param($wordToComplete, $commandAst, $cursorPosition)
+	$words = $commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() }
+	if ($wordToComplete) { $words = $words[0..($words.Count - 2)] }
+	gophkeeper __complete @words 2>$null | Where-Object { $_ -like "$wordToComplete*" } |
+		ForEach-Object { [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_) }
+}
+`
+)
+
+func runCompletion(_ context.Context, _ *App, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: gophkeeper completion <bash|zsh|fish|powershell>")
+	}
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print(zshCompletion)
+	case "fish":
+		fmt.Print(fishCompletion)
+	case "powershell":
+		fmt.Print(powershellCompletion)
+	default:
+		return fmt.Errorf("unknown shell %q", args[0])
+	}
+	return nil
+}
+
+// secretsSubcommands lists the `secrets` subcommands for completion, with
+// the ones taking a secret ID marked.
+var secretsSubcommands = map[string]bool{
+	"list": false, "find": false, "add": false, "get": true, "update": true,
+	"edit": true, "delete": true, "env": true, "use-code": true, "attach": true,
+	"detach": true, "download": true, "archive-policy": false, "audit": false,
+	"history": true, "convert": true, "export": false, "import": false,
+	"connect": true, "share": true, "unshare": true, "shares": true, "shared": false,
+	"link": true,
+}
+
+// runComplete prints completion candidates for the words typed so far, one
+// per line. The shell filters them against the current prefix.
+func runComplete(_ context.Context, app *App, args []string) error {
+	switch {
+	case len(args) == 0:
+		for name := range app.commands {
+			if !strings.HasPrefix(name, "__") {
+				fmt.Println(name)
+			}
+		}
+	case args[0] == "secrets" && len(args) == 1:
+		for name := range secretsSubcommands {
+			fmt.Println(name)
+		}
+	case args[0] == "secrets" && len(args) == 2 && secretsSubcommands[args[1]]:
+		return completeSecretIDs(app)
+	case args[0] == "kube" && len(args) == 1:
+		fmt.Println("use")
+		fmt.Println("clean")
+	case args[0] == "kube" && len(args) == 2 && args[1] == "use":
+		return completeSecretIDs(app)
+	case args[0] == "account" && len(args) == 1:
+		fmt.Println("hint\nmerge\ne2e\nlogins\ndelete\nrestore")
+	case args[0] == "account" && len(args) == 2 && args[1] == "hint":
+		fmt.Println("set\nshow\nclear")
+	case args[0] == "account" && len(args) == 2 && args[1] == "e2e":
+		fmt.Println("on\noff\nstatus")
+	case args[0] == "devices" && len(args) == 1:
+		fmt.Println("list\napprove\nrevoke")
+	case args[0] == "emergency" && len(args) == 1:
+		fmt.Println("add\nremove\nlist\ndeny\ntrusts\nrequest\nvault")
+	case args[0] == "config" && len(args) == 1:
+		fmt.Println("profile")
+	case args[0] == "config" && len(args) == 2 && args[1] == "profile":
+		fmt.Println("add\nlist\nuse")
+	case args[0] == "cache" && len(args) == 1:
+		fmt.Println("status\nmigrate")
+	case args[0] == "completion" && len(args) == 1:
+		fmt.Println("bash\nzsh\nfish\npowershell")
+	}
+	return nil
+}
+
+// completeSecretIDs offers the IDs and single-word names of locally cached
+// secrets. Names with whitespace are skipped: they would split into
+// multiple candidates.
+func completeSecretIDs(app *App) error {
+	// Completion must never prompt; a locked cache just offers nothing.
+	if err := app.unlockStorage(false); err != nil {
+		return nil
+	}
+	secrets, _, err := app.Storage.Load()
+	if err != nil {
+		return err
+	}
+	for i := range secrets {
+		fmt.Println(secrets[i].ID)
+		if name := secrets[i].Name; name != "" && !strings.ContainsAny(name, " \t") {
+			fmt.Println(name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/client/config"
+)
+
+var configCmd = &Command{
+	Name:        "config",
+	Usage:       "config profile <add|list|use>",
+	Description: "manage client configuration profiles",
+	Run:         runConfig,
+}
+
+// ExtractProfileFlag removes a global `-profile <name>` (or `--profile`,
+// `=`-joined) from args. It is called from main rather than Run, because
+// the profile decides which configuration the app is built from.
+func ExtractProfileFlag(args []string) (string, []string, error) {
+	var profile string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, joined := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "profile" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !joined {
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("flag %s needs a value", arg)
+			}
+			i++
+			value = args[i]
+		}
+		profile = value
+	}
+	return profile, rest, nil
+}
+
+func runConfig(_ context.Context, app *App, args []string) error {
+	if len(args) == 0 || args[0] != "profile" {
+		return errors.New("usage: gophkeeper config profile <add|list|use>")
+	}
+	args = args[1:]
+	if len(args) == 0 {
+		return errors.New("usage: gophkeeper config profile <add|list|use>")
+	}
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "add":
+		return profileAdd(app, rest)
+	case "list":
+		return profileList(app)
+	case "use":
+		return profileUse(app, rest)
+	default:
+		return fmt.Errorf("unknown profile subcommand %q", sub)
+	}
+}
+
+// profileAdd saves a named profile. The profile gets its own data
+// directory unless -data-dir points elsewhere, so its token and local
+// cache stay separate from every other profile.
+func profileAdd(app *App, args []string) error {
+	fs := flag.NewFlagSet("profile add", flag.ContinueOnError)
+	server := fs.String("server", "", "base URL of the server")
+	dataDir := fs.String("data-dir", "", "data directory (default: one per profile)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: gophkeeper config profile add <name> -server <url> [-data-dir <dir>]")
+	}
+	name := fs.Arg(0)
+	// The name doubles as the default directory name.
+	if name == "" || strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+	if *server == "" {
+		return errors.New("-server is required")
+	}
+	path := app.Config.ProfilesPath()
+	set, err := config.LoadProfiles(path)
+	if err != nil {
+		return err
+	}
+	if set.Profiles == nil {
+		set.Profiles = make(map[string]config.Profile)
+	}
+	set.Profiles[name] = config.Profile{ServerURL: *server, DataDir: *dataDir}
+	if err := set.Save(path); err != nil {
+		return err
+	}
+	fmt.Printf("profile %s saved; select it with --profile %s or `gophkeeper config profile use %s`\n",
+		name, name, name)
+	return nil
+}
+
+// profileRow is one line of `config profile list`.
+type profileRow struct {
+	Name      string `json:"name"`
+	ServerURL string `json:"server_url"`
+	DataDir   string `json:"data_dir"`
+	Current   bool   `json:"current,omitempty"`
+}
+
+func profileList(app *App) error {
+	set, err := config.LoadProfiles(app.Config.ProfilesPath())
+	if err != nil {
+		return err
+	}
+	rows := make([]profileRow, 0, len(set.Profiles))
+	for name, p := range set.Profiles {
+		dir := p.DataDir
+		if dir == "" {
+			dir = app.Config.ProfileDataDir(name)
+		}
+		rows = append(rows, profileRow{
+			Name:      name,
+			ServerURL: p.ServerURL,
+			DataDir:   dir,
+			Current:   name == set.Current,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	if done, err := app.emit(rows); done || err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSERVER\tDATA DIR")
+	for _, row := range rows {
+		name := row.Name
+		if row.Current {
+			name += " *"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, row.ServerURL, row.DataDir)
+	}
+	return w.Flush()
+}
+
+// profileUse makes the named profile the default for invocations without
+// an explicit --profile flag.
+func profileUse(app *App, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: gophkeeper config profile use <name>")
+	}
+	name := args[0]
+	path := app.Config.ProfilesPath()
+	set, err := config.LoadProfiles(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := set.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	set.Current = name
+	if err := set.Save(path); err != nil {
+		return err
+	}
+	fmt.Printf("now using profile %s\n", name)
+	return nil
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// secretsConnect launches the matching interactive client for a database
+// secret. The password travels via the client's password environment
+// variable (PGPASSWORD, MYSQL_PWD), never on the command line, and only
+// into the child process.
+func secretsConnect(ctx context.Context, app *App, args []string) error {
+	id, err := app.parseIDArg(args)
+	if err != nil {
+		return err
+	}
+	result, err := app.API.GetSecret(ctx, id)
+	if err != nil {
+		return err
+	}
+	secret := result.Secret
+	if secret.Type != models.SecretTypeDatabase {
+		return fmt.Errorf("secret %s is %s, not %s", id, secret.Type, models.SecretTypeDatabase)
+	}
+	var p models.DatabasePayload
+	if err := secret.DecodePayload(&p); err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	switch p.Engine {
+	case models.DatabaseEnginePostgres:
+		cmd = exec.CommandContext(ctx, "psql", postgresURI(&p))
+		cmd.Env = append(os.Environ(), "PGPASSWORD="+p.Password)
+	case models.DatabaseEngineMySQL:
+		mysqlArgs := []string{"-h", p.Host, "-u", p.User}
+		if p.Port != 0 {
+			mysqlArgs = append(mysqlArgs, "-P", strconv.Itoa(p.Port))
+		}
+		if p.Database != "" {
+			mysqlArgs = append(mysqlArgs, p.Database)
+		}
+		cmd = exec.CommandContext(ctx, "mysql", mysqlArgs...)
+		cmd.Env = append(os.Environ(), "MYSQL_PWD="+p.Password)
+	default:
+		return fmt.Errorf("unknown database engine %q", p.Engine)
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// postgresURI renders the payload as a psql connection URI, without the
+// password.
+func postgresURI(p *models.DatabasePayload) string {
+	u := url.URL{
+		Scheme: "postgres",
+		Host:   p.Host,
+		Path:   "/" + p.Database,
+	}
+	if p.Port != 0 {
+		u.Host = fmt.Sprintf("%s:%d", p.Host, p.Port)
+	}
+	if p.User != "" {
+		u.User = url.User(p.User)
+	}
+	if p.Options != "" {
+		u.RawQuery = p.Options
+	}
+	return u.String()
+}
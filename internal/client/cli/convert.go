@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// secretsConvert re-types a secret, mapping fields where possible and
+// prompting for required ones that cannot be derived. The server keeps the
+// old payload as a version, so nothing is lost.
+func secretsConvert(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	to := fs.String("to", "", "target type (text or credentials)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := app.parseIDArg(fs.Args())
+	if err != nil {
+		return err
+	}
+	target := models.SecretType(*to)
+	if target != models.SecretTypeText && target != models.SecretTypeCredentials {
+		return fmt.Errorf("usage: gophkeeper secrets convert <id> -to <text|credentials>")
+	}
+
+	current, err := app.API.GetSecret(ctx, id)
+	if err != nil {
+		return err
+	}
+	secret := current.Secret
+	if secret.Type == target {
+		return fmt.Errorf("secret is already of type %s", target)
+	}
+
+	switch target {
+	case models.SecretTypeText:
+		err = convertToText(secret)
+	case models.SecretTypeCredentials:
+		err = convertToCredentials(secret)
+	}
+	if err != nil {
+		return err
+	}
+	secret.Type = target
+	if _, err := app.API.ConvertSecret(ctx, secret); err != nil {
+		return err
+	}
+	fmt.Printf("converted secret %s to %s (previous payload kept as a version)\n", id, target)
+	return nil
+}
+
+// convertToText renders the current payload as key: value lines.
+func convertToText(secret *models.Secret) error {
+	var lines []string
+	switch secret.Type {
+	case models.SecretTypeCredentials:
+		var p models.CredentialsPayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		lines = append(lines, "login: "+p.Login, "password: "+p.Password)
+		if p.URL != "" {
+			lines = append(lines, "url: "+p.URL)
+		}
+	case models.SecretTypeCard:
+		var p models.CardPayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		lines = append(lines, "number: "+p.Number, "holder: "+p.Holder, "expiry: "+p.Expiry, "cvv: "+p.CVV)
+	case models.SecretTypeAPIToken:
+		var p models.APITokenPayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		lines = append(lines, "token: "+p.Token)
+		if p.EnvVar != "" {
+			lines = append(lines, "env var: "+p.EnvVar)
+		}
+		if len(p.Scopes) > 0 {
+			lines = append(lines, "scopes: "+strings.Join(p.Scopes, ", "))
+		}
+	default:
+		// Fall back to the raw payload for shapes without a natural text
+		// form.
+		lines = append(lines, string(secret.Data))
+	}
+	return secret.EncodePayload(&models.TextPayload{Content: strings.Join(lines, "\n")})
+}
+
+// convertToCredentials extracts login, password and URL from a text payload
+// ("key: value" lines), prompting for whatever is missing.
+func convertToCredentials(secret *models.Secret) error {
+	var p models.CredentialsPayload
+	if secret.Type == models.SecretTypeText {
+		var text models.TextPayload
+		if err := secret.DecodePayload(&text); err != nil {
+			return err
+		}
+		for _, line := range strings.Split(text.Content, "\n") {
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "login", "user", "username":
+				p.Login = value
+			case "password", "pass":
+				p.Password = value
+			case "url", "website", "site":
+				p.URL = value
+			}
+		}
+	}
+	var err error
+	if p.Login == "" {
+		if p.Login, err = readLine("Login: "); err != nil {
+			return err
+		}
+	}
+	if p.Password == "" {
+		if p.Password, err = readPassword("Password: "); err != nil {
+			return err
+		}
+	}
+	return secret.EncodePayload(&p)
+}
+
+// readLine reads one trimmed line from stdin.
+func readLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+var daemonCmd = &Command{
+	Name:        "daemon",
+	Usage:       "daemon [run|status|sync]",
+	Description: "background sync loop with a local control socket",
+	Run:         runDaemon,
+}
+
+func runDaemon(ctx context.Context, app *App, args []string) error {
+	sub := "run"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	switch sub {
+	case "run":
+		return daemonRun(ctx, app, args)
+	case "status", "sync":
+		reply, err := daemonRequest(app, sub)
+		if err != nil {
+			return fmt.Errorf("daemon not reachable: %w", err)
+		}
+		fmt.Println(reply)
+		return nil
+	default:
+		return fmt.Errorf("unknown daemon subcommand %q", sub)
+	}
+}
+
+// daemon is the state shared between the sync loop and the control socket.
+type daemon struct {
+	app *App
+
+	// kick wakes the sync loop ahead of schedule; see listen.
+	kick chan struct{}
+
+	mu       sync.Mutex
+	started  time.Time
+	lastSync time.Time
+	lastErr  error
+	changed  int // remote changes applied by the last sync
+}
+
+func daemonRun(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	interval := fs.Duration("interval", 5*time.Minute, "time between syncs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// The loop rewrites the cache, so the master password is needed up
+	// front rather than on the first tick.
+	if err := app.unlockStorage(true); err != nil {
+		return err
+	}
+
+	ln, err := listenControl(app.Config.SocketPath())
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	defer os.Remove(app.Config.SocketPath())
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	d := &daemon{app: app, started: time.Now(), kick: make(chan struct{}, 1)}
+	go d.serve(ctx, ln)
+	go d.listen(ctx, *interval)
+
+	d.syncOnce(ctx)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			d.syncOnce(ctx)
+		case <-d.kick:
+			d.syncOnce(ctx)
+		}
+	}
+}
+
+// listen keeps a push connection to the server and kicks a sync whenever a
+// change event arrives, so remote edits show up without waiting for the
+// next tick. The polling loop stays as the fallback while the stream is
+// down.
+func (d *daemon) listen(ctx context.Context, retryAfter time.Duration) {
+	for {
+		err := d.app.API.SyncStream(ctx, func(models.OutboxEvent) {
+			select {
+			case d.kick <- struct{}{}:
+			default:
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: sync stream: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryAfter):
+		}
+	}
+}
+
+// listenControl binds the control socket, reclaiming one left behind by a
+// crashed daemon but refusing to evict a live one.
+func listenControl(path string) (net.Listener, error) {
+	ln, err := net.Listen("unix", path)
+	if err == nil {
+		return ln, nil
+	}
+	if conn, dialErr := net.DialTimeout("unix", path, time.Second); dialErr == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another daemon is already running on %s", path)
+	}
+	if rmErr := os.Remove(path); rmErr != nil {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// serve answers one-line requests on the control socket: "status", "sync"
+// and "ping". Each connection carries a single request.
+func (d *daemon) serve(ctx context.Context, ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(10 * time.Second))
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch strings.TrimSpace(line) {
+			case "status":
+				fmt.Fprintln(conn, d.status())
+			case "sync":
+				d.syncOnce(ctx)
+				fmt.Fprintln(conn, d.status())
+			case "ping":
+				fmt.Fprintln(conn, "pong")
+			default:
+				fmt.Fprintln(conn, "error: unknown request")
+			}
+		}()
+	}
+}
+
+func (d *daemon) status() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	parts := []string{"up=" + compactAge(time.Since(d.started))}
+	if d.lastSync.IsZero() {
+		parts = append(parts, "synced=never")
+	} else {
+		parts = append(parts, "synced="+compactAge(time.Since(d.lastSync)), fmt.Sprintf("changed=%d", d.changed))
+	}
+	if d.lastErr != nil {
+		parts = append(parts, "error="+strconv.Quote(d.lastErr.Error()))
+	}
+	return strings.Join(parts, " ")
+}
+
+// syncOnce replays queued offline writes, pulls remote changes since the
+// cache was last written and folds them in. A notification goes out when
+// anything changed remotely.
+func (d *daemon) syncOnce(ctx context.Context) {
+	changed, err := d.pull(ctx)
+	d.mu.Lock()
+	d.lastSync = time.Now()
+	d.lastErr = err
+	d.changed = changed
+	d.mu.Unlock()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: sync: %v\n", err)
+		return
+	}
+	if changed > 0 {
+		notifyDesktop("gophkeeper", fmt.Sprintf("%d secret(s) changed on the server", changed))
+	}
+}
+
+func (d *daemon) pull(ctx context.Context) (int, error) {
+	d.app.replayJournal(ctx)
+	cached, syncedAt, err := d.app.Storage.Load()
+	if err != nil {
+		return 0, err
+	}
+	hashes := make(map[uuid.UUID]string, len(cached))
+	for i := range cached {
+		hashes[cached[i].ID] = cached[i].SyncHash()
+	}
+	resp, err := d.app.API.Sync(ctx, models.SyncRequest{
+		Since:        syncedAt,
+		ClientHashes: hashes,
+		DeviceID:     d.app.deviceID(ctx),
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, w := range resp.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: sync: %s\n", w)
+	}
+
+	byID := make(map[uuid.UUID]int, len(cached))
+	for i := range cached {
+		byID[cached[i].ID] = i
+	}
+	changed := 0
+	for _, secret := range resp.Secrets {
+		if i, ok := byID[secret.ID]; ok {
+			if !cached[i].UpdatedAt.Equal(secret.UpdatedAt) {
+				cached[i] = secret
+				changed++
+			}
+			continue
+		}
+		byID[secret.ID] = len(cached)
+		cached = append(cached, secret)
+		changed++
+	}
+	for _, id := range resp.DeletedSecrets {
+		if i, ok := byID[id]; ok {
+			delete(byID, id)
+			cached = append(cached[:i], cached[i+1:]...)
+			for j := i; j < len(cached); j++ {
+				byID[cached[j].ID] = j
+			}
+			changed++
+		}
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, d.app.Storage.Save(cached)
+}
+
+// daemonRequest sends one control request to a running daemon and returns
+// its reply line.
+func daemonRequest(app *App, request string) (string, error) {
+	conn, err := net.DialTimeout("unix", app.Config.SocketPath(), time.Second)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	if _, err := fmt.Fprintln(conn, request); err != nil {
+		return "", err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(reply), nil
+}
+
+// notifyDesktop sends a best-effort desktop notification using whatever the
+// platform offers; missing tools are silently tolerated.
+func notifyDesktop(title, body string) {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		_ = exec.Command("osascript", "-e", script).Run()
+	case "linux":
+		if _, err := exec.LookPath("notify-send"); err == nil {
+			_ = exec.Command("notify-send", title, body).Run()
+		}
+	}
+}
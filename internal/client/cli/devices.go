@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var devicesCmd = &Command{
+	Name:        "devices",
+	Usage:       "devices <list|approve|revoke>",
+	Description: "manage registered sync devices",
+	Run:         runDevices,
+}
+
+func runDevices(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gophkeeper devices <list|approve|revoke>")
+	}
+	switch args[0] {
+	case "list":
+		devices, err := app.API.ListDevices(ctx)
+		if err != nil {
+			return err
+		}
+		if done, err := app.emit(devices); done || err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPLATFORM\tLAST SYNC\tREVOKED")
+		for _, d := range devices {
+			lastSync := "never"
+			if !d.LastSyncedAt.IsZero() {
+				lastSync = compactAge(time.Since(d.LastSyncedAt)) + " ago"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\n", d.ID, d.Name, d.Platform, lastSync, d.Revoked)
+		}
+		return w.Flush()
+	case "approve":
+		if len(args) < 2 {
+			return errors.New("usage: gophkeeper devices approve <id>")
+		}
+		id, err := uuid.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid device id %q", args[1])
+		}
+		if err := app.API.ApproveDevice(ctx, id); err != nil {
+			return err
+		}
+		fmt.Printf("approved device %s\n", id)
+		return nil
+	case "revoke":
+		if len(args) < 2 {
+			return errors.New("usage: gophkeeper devices revoke <id>")
+		}
+		id, err := uuid.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid device id %q", args[1])
+		}
+		if err := app.API.RevokeDevice(ctx, id); err != nil {
+			return err
+		}
+		fmt.Printf("revoked device %s\n", id)
+		return nil
+	default:
+		return fmt.Errorf("unknown devices subcommand %q", args[0])
+	}
+}
+
+// deviceID returns this client's registered device ID, registering under
+// the hostname on first use. Registration failures degrade to anonymous
+// syncs rather than blocking.
+func (a *App) deviceID(ctx context.Context) uuid.UUID {
+	path := a.Config.DeviceIDPath()
+	if data, err := os.ReadFile(path); err == nil {
+		if id, err := uuid.Parse(strings.TrimSpace(string(data))); err == nil {
+			return id
+		}
+	}
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		name = "unnamed device"
+	}
+	device, err := a.API.RegisterDevice(ctx, name, runtime.GOOS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: register device: %v\n", err)
+		return uuid.Nil
+	}
+	if err := os.WriteFile(path, []byte(device.ID.String()), 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: save device id: %v\n", err)
+	}
+	return device.ID
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/client/config"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/credstore"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// e2eMagic marks a payload sealed client-side; everything after it opens
+// with the master password.
+var e2eMagic = []byte("GKE2E1")
+
+// e2eCipher seals secret payloads with the master password before they
+// leave the machine, so the server only ever stores ciphertext it cannot
+// read. Payloads without the magic prefix — stored before the mode was
+// enabled — pass through Open untouched, so mixed vaults keep working.
+type e2eCipher struct {
+	app *App
+	// passphrase caches the master password in a wipeable buffer rather
+	// than a string, so locking can actually scrub it.
+	passphrase *crypto.SecureString
+}
+
+// Seal encrypts a payload under the master password.
+func (c *e2eCipher) Seal(data []byte) ([]byte, error) {
+	passphrase, err := c.masterPassword()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := crypto.SealWithPassphrase(passphrase, data)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(make([]byte, 0, len(e2eMagic)+len(sealed)), e2eMagic...), sealed...), nil
+}
+
+// Open decrypts a sealed payload; unsealed payloads pass through.
+func (c *e2eCipher) Open(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, e2eMagic) {
+		return data, nil
+	}
+	passphrase, err := c.masterPassword()
+	if err != nil {
+		return nil, err
+	}
+	opened, err := crypto.OpenWithPassphrase(passphrase, data[len(e2eMagic):])
+	if err != nil {
+		return nil, fmt.Errorf("open end-to-end payload (wrong master password?): %w", err)
+	}
+	return opened, nil
+}
+
+// masterPassword returns the passphrase sealing payloads, from the
+// credential store when unlocked and prompting otherwise.
+func (c *e2eCipher) masterPassword() (string, error) {
+	if !c.passphrase.IsZero() {
+		return c.passphrase.Reveal(), nil
+	}
+	if saved, err := c.app.Creds.Get(credstore.MasterPassword); err == nil && saved != "" {
+		c.passphrase = crypto.NewSecureString(saved)
+		return saved, nil
+	}
+	password, err := readPassword("Master password for end-to-end encryption: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", errors.New("a master password is required in end-to-end mode")
+	}
+	if err := c.checkVerifier(password); err != nil {
+		return "", err
+	}
+	c.passphrase = crypto.NewSecureString(password)
+	return password, nil
+}
+
+// checkVerifier tests an entered master password against the verifier
+// stored server-side, so a typo fails with one clear error instead of a
+// decryption failure on every secret. Accounts without a verifier — or
+// servers without the endpoint — skip the check.
+func (c *e2eCipher) checkVerifier(password string) error {
+	verifier, err := c.app.API.GetMasterVerifier(context.Background())
+	if err != nil || len(verifier) == 0 {
+		return nil
+	}
+	ok, err := crypto.CheckPassphraseVerifier(password, verifier)
+	if err != nil {
+		return nil
+	}
+	if !ok {
+		return errors.New("wrong master password")
+	}
+	return nil
+}
+
+// accountE2E toggles end-to-end encryption for this profile.
+func accountE2E(ctx context.Context, app *App, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: gophkeeper account e2e <on|off|status>")
+	}
+	settings, err := config.LoadSettings(app.Config.SettingsPath())
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "on":
+		cipher := &e2eCipher{app: app}
+		password, err := cipher.masterPassword()
+		if err != nil {
+			return err
+		}
+		// Store a verifier so future unlocks can reject a mistyped
+		// password before touching any secret.
+		verifier, err := crypto.NewPassphraseVerifier(password)
+		if err != nil {
+			return err
+		}
+		if err := app.API.SetMasterVerifier(ctx, verifier); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not store the master-password verifier: %v\n", err)
+		}
+		settings.E2E = true
+		if err := settings.Save(app.Config.SettingsPath()); err != nil {
+			return err
+		}
+		app.API.SetPayloadCipher(cipher)
+		fmt.Println("end-to-end encryption enabled; secrets are sealed as they are written")
+		fmt.Println("note: recovery codes, share links and org vaults need the server to read payloads and will not work on sealed secrets")
+		return nil
+	case "off":
+		settings.E2E = false
+		if err := settings.Save(app.Config.SettingsPath()); err != nil {
+			return err
+		}
+		fmt.Println("end-to-end encryption disabled; already-sealed secrets stay unreadable until it is re-enabled or they are rewritten")
+		return nil
+	case "status":
+		if settings.E2E {
+			fmt.Println("end-to-end encryption is on")
+		} else {
+			fmt.Println("end-to-end encryption is off")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown e2e subcommand %q", args[0])
+	}
+}
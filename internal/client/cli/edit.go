@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// editPayload returns an edited copy of the payload, via the configured
+// editor or interactive prompts.
+func editPayload(ctx context.Context, secret *models.Secret) ([]byte, error) {
+	if editor := lookupEditor(); editor != "" {
+		return editInEditor(ctx, editor, secret.Data)
+	}
+	return editByPrompt(secret)
+}
+
+// secretsEdit opens the payload of a secret in $EDITOR as pretty-printed
+// JSON and saves the edited result with UpdateSecret. Without an editor
+// configured it prompts for each string field instead.
+func secretsEdit(ctx context.Context, app *App, args []string) error {
+	id, err := app.parseIDArg(args)
+	if err != nil {
+		return err
+	}
+	current, err := app.API.GetSecret(ctx, id)
+	if err != nil {
+		return err
+	}
+	secret := current.Secret
+
+	edited, err := editPayload(ctx, secret)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(edited, secret.Data) {
+		fmt.Println("no changes")
+		return nil
+	}
+	secret.Data = edited
+	result, err := app.API.UpdateSecret(ctx, secret)
+	if err != nil {
+		return err
+	}
+	printWarnings(result.Warnings)
+	fmt.Printf("updated secret %s\n", id)
+	return nil
+}
+
+// lookupEditor returns the user's editor command, preferring $VISUAL over
+// $EDITOR as is conventional.
+func lookupEditor() string {
+	if v := os.Getenv("VISUAL"); v != "" {
+		return v
+	}
+	return os.Getenv("EDITOR")
+}
+
+// editInEditor round-trips the payload through a temporary file opened in
+// the given editor command. The file lives in the private data directory
+// only for the duration of the edit.
+func editInEditor(ctx context.Context, editor string, payload []byte) ([]byte, error) {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, payload, "", "  "); err != nil {
+		return nil, fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+	pretty.WriteByte('\n')
+
+	f, err := os.CreateTemp("", "gophkeeper-edit-*.json")
+	if err != nil {
+		return nil, err
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	if err := os.Chmod(path, 0o600); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Write(pretty.Bytes()); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	// $EDITOR may carry arguments ("code -w"), so split it like a shell
+	// word list.
+	words := strings.Fields(editor)
+	cmd := exec.CommandContext(ctx, words[0], append(words[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run editor: %w", err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, edited); err != nil {
+		return nil, fmt.Errorf("edited payload is not valid JSON: %w", err)
+	}
+	return compact.Bytes(), nil
+}
+
+// editByPrompt asks for a new value of every top-level string field, keeping
+// the current one on an empty answer. Non-string fields (history, code
+// lists) are left untouched.
+func editByPrompt(secret *models.Secret) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(secret.Data, &fields); err != nil {
+		return nil, fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var value string
+		if json.Unmarshal(fields[name], &value) != nil {
+			continue
+		}
+		answer, err := readLine(fmt.Sprintf("%s [%s]: ", name, value))
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			continue
+		}
+		raw, err := json.Marshal(answer)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = raw
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+var emergencyCmd = &Command{
+	Name:        "emergency",
+	Usage:       "emergency <add|remove|list|deny|trusts|request|vault>",
+	Description: "manage emergency access to your vault",
+	Run:         runEmergency,
+}
+
+func runEmergency(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gophkeeper emergency <add|remove|list|deny|trusts|request|vault>")
+	}
+	switch args[0] {
+	case "add":
+		return emergencyAdd(ctx, app, args[1:])
+	case "remove":
+		if len(args) != 2 {
+			return errors.New("usage: gophkeeper emergency remove <login>")
+		}
+		if err := app.API.RemoveEmergencyContact(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("removed emergency contact %s\n", args[1])
+		return nil
+	case "list":
+		contacts, err := app.API.EmergencyContacts(ctx)
+		if err != nil {
+			return err
+		}
+		return emitContacts(app, contacts)
+	case "deny":
+		if len(args) != 2 {
+			return errors.New("usage: gophkeeper emergency deny <login>")
+		}
+		if err := app.API.DenyEmergencyAccess(ctx, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("denied %s's access request\n", args[1])
+		return nil
+	case "trusts":
+		trusts, err := app.API.EmergencyTrusts(ctx)
+		if err != nil {
+			return err
+		}
+		return emitContacts(app, trusts)
+	case "request":
+		if len(args) != 2 {
+			return errors.New("usage: gophkeeper emergency request <owner-login>")
+		}
+		designation, err := app.API.RequestEmergencyAccess(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if done, err := app.emit(designation); done {
+			return err
+		}
+		if designation.Status == models.EmergencyStatusGranted {
+			fmt.Println("access granted; run `gophkeeper emergency vault` to read the secrets")
+			return nil
+		}
+		fmt.Printf("request recorded; access unlocks %s unless %s denies it\n",
+			designation.AvailableAt().UTC().Format("2006-01-02 15:04 UTC"), args[1])
+		return nil
+	case "vault":
+		if len(args) != 2 {
+			return errors.New("usage: gophkeeper emergency vault <owner-login>")
+		}
+		secrets, err := app.API.EmergencyVault(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if done, err := app.emit(secrets); done {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tTYPE")
+		for i := range secrets {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", secrets[i].ID, secrets[i].Name, secrets[i].Type)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown emergency subcommand %q", args[0])
+	}
+}
+
+// emergencyAdd handles `emergency add <login> [wait-days]`; the wait
+// period defaults to seven days.
+func emergencyAdd(ctx context.Context, app *App, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New("usage: gophkeeper emergency add <login> [wait-days]")
+	}
+	waitDays := 7
+	if len(args) == 2 {
+		var err error
+		if waitDays, err = strconv.Atoi(args[1]); err != nil {
+			return fmt.Errorf("invalid wait period %q", args[1])
+		}
+	}
+	contact, err := app.API.AddEmergencyContact(ctx, args[0], waitDays)
+	if err != nil {
+		return err
+	}
+	if done, err := app.emit(contact); done {
+		return err
+	}
+	fmt.Printf("added %s as emergency contact with a %d-day wait period\n", contact.Login, contact.WaitDays)
+	return nil
+}
+
+// emitContacts prints designations as a table: contacts on the owner
+// side, owners on the contact side.
+func emitContacts(app *App, contacts []models.EmergencyContact) error {
+	if done, err := app.emit(contacts); done || err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LOGIN\tWAIT\tSTATUS\tUNLOCKS")
+	for _, c := range contacts {
+		unlocks := "-"
+		if c.RequestedAt != nil {
+			unlocks = c.AvailableAt().UTC().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%dd\t%s\t%s\n", c.Login, c.WaitDays, c.Status, unlocks)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/pkg/api"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// Export formats accepted by `secrets export`.
+const (
+	ExportFormatCSV       = "csv"
+	ExportFormatJSON      = "json"
+	ExportFormatEncrypted = "encrypted"
+)
+
+// exportFields is the full flat field set of an export record, in output
+// order. -fields selects a subset of it.
+var exportFields = []string{"id", "name", "type", "metadata", "protected", "archived", "created", "updated", "payload"}
+
+// secretsExport dumps all secrets to a file or stdout. Plaintext formats
+// require confirmation; the encrypted format seals the dump under a
+// passphrase for backups.
+func secretsExport(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", ExportFormatJSON, "output format: csv, json or encrypted")
+	out := fs.String("o", "-", "output path ('-' for stdout)")
+	fields := fs.String("fields", "", "comma-separated fields to export (csv and json)")
+	yes := fs.Bool("yes", false, "skip the plaintext confirmation prompt")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	selected := exportFields
+	if *fields != "" {
+		var err error
+		if selected, err = parseExportFields(*fields); err != nil {
+			return err
+		}
+	}
+
+	secrets, err := app.API.GetSecrets(ctx, api.ListOptions{IncludeArchived: true})
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		return errors.New("nothing to export")
+	}
+
+	if *format != ExportFormatEncrypted && !*yes {
+		answer, err := readLine(fmt.Sprintf("Export %d secrets in plaintext? [y/N]: ", len(secrets)))
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+			return errors.New("export cancelled")
+		}
+	}
+
+	var data []byte
+	switch *format {
+	case ExportFormatCSV:
+		data, err = exportCSV(secrets, selected)
+	case ExportFormatJSON:
+		data, err = exportJSON(secrets, selected)
+	case ExportFormatEncrypted:
+		data, err = exportEncrypted(secrets)
+	default:
+		err = fmt.Errorf("unknown export format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *out == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d secrets to %s\n", len(secrets), *out)
+	return nil
+}
+
+// parseExportFields validates a comma-separated field list against
+// exportFields, preserving canonical order.
+func parseExportFields(s string) ([]string, error) {
+	requested := make(map[string]bool)
+	for _, name := range strings.Split(s, ",") {
+		requested[strings.TrimSpace(name)] = true
+	}
+	var selected []string
+	for _, name := range exportFields {
+		if requested[name] {
+			selected = append(selected, name)
+			delete(requested, name)
+		}
+	}
+	for name := range requested {
+		return nil, fmt.Errorf("unknown export field %q (known: %s)", name, strings.Join(exportFields, ", "))
+	}
+	return selected, nil
+}
+
+// exportRecord flattens a secret into the exportFields values.
+func exportRecord(secret *models.Secret) map[string]string {
+	return map[string]string{
+		"id":        secret.ID.String(),
+		"name":      secret.Name,
+		"type":      string(secret.Type),
+		"metadata":  secret.Metadata,
+		"protected": yesNo(secret.Protected),
+		"archived":  yesNo(secret.Archived),
+		"created":   secret.CreatedAt.Format(time.RFC3339),
+		"updated":   secret.UpdatedAt.Format(time.RFC3339),
+		"payload":   string(secret.Data),
+	}
+}
+
+func exportCSV(secrets []models.Secret, fields []string) ([]byte, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write(fields); err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		record := exportRecord(&secrets[i])
+		row := make([]string, len(fields))
+		for j, name := range fields {
+			row[j] = record[name]
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return []byte(b.String()), w.Error()
+}
+
+func exportJSON(secrets []models.Secret, fields []string) ([]byte, error) {
+	records := make([]map[string]string, len(secrets))
+	for i := range secrets {
+		record := exportRecord(&secrets[i])
+		filtered := make(map[string]string, len(fields))
+		for _, name := range fields {
+			filtered[name] = record[name]
+		}
+		records[i] = filtered
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// exportEncrypted seals the full secret list under a passphrase asked for
+// interactively, twice to catch typos.
+func exportEncrypted(secrets []models.Secret) ([]byte, error) {
+	passphrase, err := readPassword("Archive passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, errors.New("passphrase must not be empty")
+	}
+	confirm, err := readPassword("Repeat passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if passphrase != confirm {
+		return nil, errors.New("passphrases do not match")
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.SealWithPassphrase(passphrase, plaintext)
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+func TestParseExportFields(t *testing.T) {
+	fields, err := parseExportFields("payload, name,id")
+	if err != nil {
+		t.Fatalf("parseExportFields: %v", err)
+	}
+	// Canonical order, not request order.
+	if got := strings.Join(fields, ","); got != "id,name,payload" {
+		t.Fatalf("fields = %s, want id,name,payload", got)
+	}
+	if _, err := parseExportFields("id,password"); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	secret := models.Secret{ID: uuid.New(), Name: "with,comma", Type: models.SecretTypeText}
+	out, err := exportCSV([]models.Secret{secret}, []string{"id", "name", "type"})
+	if err != nil {
+		t.Fatalf("exportCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want header and one row:\n%s", len(lines), out)
+	}
+	if lines[0] != "id,name,type" {
+		t.Fatalf("header = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], `"with,comma"`) {
+		t.Fatalf("comma not quoted: %q", lines[1])
+	}
+}
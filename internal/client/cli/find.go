@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// findMatch pairs a cached secret with its best fuzzy score.
+type findMatch struct {
+	secret models.Secret
+	score  int
+}
+
+// secretsFind fuzzy-matches the query against the names, logins, URLs and
+// metadata of locally cached secrets. A single hit is printed directly;
+// several hits open a numbered picker.
+func secretsFind(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gophkeeper secrets find <query>")
+	}
+	query := strings.Join(args, " ")
+
+	if err := app.unlockStorage(true); err != nil {
+		return err
+	}
+	secrets, syncedAt, err := app.Storage.Load()
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		return errors.New("local cache is empty, run `gophkeeper secrets list` first")
+	}
+
+	var matches []findMatch
+	for _, secret := range secrets {
+		if score := matchSecret(query, &secret); score >= 0 {
+			matches = append(matches, findMatch{secret: secret, score: score})
+		}
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no secrets match %q", query)
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	chosen := &matches[0].secret
+	if len(matches) > 1 {
+		if chosen, err = pickSecret(matches); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "from local cache of %s\n", syncedAt.Local().Format("2006-01-02 15:04"))
+	return printSecret(chosen, false)
+}
+
+// matchSecret returns the best fuzzy score of the query over the searchable
+// fields of a secret, or -1 when nothing matches.
+func matchSecret(query string, secret *models.Secret) int {
+	fields := []string{secret.Name, secret.Metadata}
+	if secret.Type == models.SecretTypeCredentials {
+		var p models.CredentialsPayload
+		if secret.DecodePayload(&p) == nil {
+			fields = append(fields, p.Login, p.URL)
+		}
+	}
+	best := -1
+	for _, field := range fields {
+		if score := fuzzyScore(query, field); score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// fuzzyScore matches query as a case-insensitive subsequence of s and scores
+// the match: consecutive characters count double and substrings get a flat
+// bonus, so tighter matches rank first. It returns -1 when query is not a
+// subsequence of s.
+func fuzzyScore(query, s string) int {
+	query, s = strings.ToLower(query), strings.ToLower(s)
+	if query == "" || s == "" {
+		return -1
+	}
+	score, prev := 0, -2
+	pos := 0
+	for _, r := range query {
+		idx := strings.IndexRune(s[pos:], r)
+		if idx < 0 {
+			return -1
+		}
+		if pos+idx == prev+1 {
+			score += 2
+		} else {
+			score++
+		}
+		prev = pos + idx
+		pos += idx + 1
+	}
+	if strings.Contains(s, query) {
+		score += len(query)
+	}
+	return score
+}
+
+// pickSecret asks the user to choose one of several matches.
+func pickSecret(matches []findMatch) (*models.Secret, error) {
+	for i := range matches {
+		s := &matches[i].secret
+		line := fmt.Sprintf("%2d. %s (%s)", i+1, s.Name, s.Type)
+		if s.Metadata != "" {
+			line += "  " + s.Metadata
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+	answer, err := readLine(fmt.Sprintf("Select [1-%d]: ", len(matches)))
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || n < 1 || n > len(matches) {
+		return nil, fmt.Errorf("invalid selection %q", answer)
+	}
+	return &matches[n-1].secret, nil
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	if fuzzyScore("ghub", "example.com") >= 0 {
+		t.Error("non-subsequence must not match")
+	}
+	if fuzzyScore("", "anything") >= 0 {
+		t.Error("empty query must not match")
+	}
+	if fuzzyScore("GITHUB", "github.com") < 0 {
+		t.Error("matching must be case-insensitive")
+	}
+	substring := fuzzyScore("hub", "github.com")
+	scattered := fuzzyScore("hub", "have-u-been.com")
+	if substring <= scattered {
+		t.Errorf("substring score %d should beat scattered score %d", substring, scattered)
+	}
+}
+
+func TestMatchSecretSearchesCredentialFields(t *testing.T) {
+	secret := &models.Secret{Name: "work", Type: models.SecretTypeCredentials}
+	err := secret.EncodePayload(&models.CredentialsPayload{
+		Login:    "alice@example.com",
+		Password: "hunter2",
+		URL:      "https://mail.example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matchSecret("alice", secret) < 0 {
+		t.Error("query should match the login")
+	}
+	if matchSecret("mail", secret) < 0 {
+		t.Error("query should match the URL")
+	}
+	if matchSecret("zzz", secret) >= 0 {
+		t.Error("query should not match anything")
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/crypto/password"
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+var generateCmd = &Command{
+	Name:        "generate",
+	Usage:       "generate [flags]",
+	Description: "generate a random password or passphrase",
+	Run:         runGenerate,
+}
+
+func runGenerate(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	length := fs.Int("length", 0, "password length (default 20)")
+	charset := fs.String("charset", "", "character classes to use: any of l,u,d,s (default all)")
+	pronounceable := fs.Bool("pronounceable", false, "alternate consonants and vowels for readability")
+	passphrase := fs.Bool("passphrase", false, "generate a diceware-style passphrase instead")
+	words := fs.Int("words", 6, "passphrase word count")
+	sep := fs.String("sep", "-", "passphrase word separator")
+	save := fs.String("save", "", "immediately create a credentials secret with this name")
+	login := fs.String("login", "", "login for the saved secret")
+	url := fs.String("url", "", "website URL for the saved secret")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var generated string
+	var err error
+	switch {
+	case *passphrase:
+		generated, err = password.Passphrase(*words, *sep)
+	case *pronounceable:
+		generated, err = password.Pronounceable(*length)
+	default:
+		opts := password.Options{Length: *length}
+		for _, c := range *charset {
+			switch c {
+			case 'l':
+				opts.Lower = true
+			case 'u':
+				opts.Upper = true
+			case 'd':
+				opts.Digits = true
+			case 's':
+				opts.Symbols = true
+			default:
+				return fmt.Errorf("unknown charset class %q (use l, u, d, s)", c)
+			}
+		}
+		generated, err = password.Generate(opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *save == "" {
+		fmt.Println(generated)
+		return nil
+	}
+	secret := &models.Secret{Name: *save, Type: models.SecretTypeCredentials}
+	if err := secret.EncodePayload(&models.CredentialsPayload{
+		Login:    *login,
+		Password: generated,
+		URL:      *url,
+	}); err != nil {
+		return err
+	}
+	result, err := app.API.CreateSecret(ctx, secret)
+	if err != nil {
+		return err
+	}
+	fmt.Println(generated)
+	fmt.Printf("saved as credentials secret %s (%s)\n", result.Secret.Name, result.Secret.ID)
+	return nil
+}
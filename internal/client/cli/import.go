@@ -0,0 +1,376 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/pkg/api"
+)
+
+// Import formats accepted by `secrets import`.
+const (
+	ImportFormatBitwarden = "bitwarden"
+	ImportFormatKeePass   = "keepass"
+	ImportFormat1Password = "1password"
+	ImportFormatChrome    = "chrome"
+	ImportFormatFirefox   = "firefox"
+)
+
+// importParsers maps a format to its file parser. Parsers return plaintext
+// secrets ready for CreateSecret and the number of entries they could not
+// map.
+var importParsers = map[string]func(data []byte) ([]models.Secret, int, error){
+	ImportFormatBitwarden: parseBitwarden,
+	ImportFormatKeePass:   parseKeePassCSV,
+	ImportFormat1Password: parse1PasswordCSV,
+	ImportFormatChrome:    parseBrowserCSV,
+	ImportFormatFirefox:   parseBrowserCSV,
+}
+
+// secretsImport imports entries from another password manager's export
+// file, skipping duplicates by name, and prints a summary.
+func secretsImport(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "source format: bitwarden, keepass, 1password, chrome or firefox")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	parse, ok := importParsers[*format]
+	if !ok || fs.NArg() != 1 {
+		return errors.New("usage: gophkeeper secrets import -format <bitwarden|keepass|1password|chrome|firefox> <file>")
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	parsed, unsupported, err := parse(data)
+	if err != nil {
+		return fmt.Errorf("parse %s export: %w", *format, err)
+	}
+
+	existing, err := app.API.GetSecrets(ctx, api.ListOptions{IncludeArchived: true})
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]bool, len(existing))
+	creds := make(map[credentialKey]*models.Secret)
+	for i := range existing {
+		taken[existing[i].Name] = true
+		if key, ok := credentialKeyOf(&existing[i]); ok {
+			creds[key] = &existing[i]
+		}
+	}
+
+	var imported, merged, duplicates, failed int
+	for i := range parsed {
+		secret := &parsed[i]
+		// An entry for a known URL+username pair merges into the existing
+		// secret instead of creating a sibling.
+		if key, ok := credentialKeyOf(secret); ok {
+			if current, found := creds[key]; found {
+				switch changed, err := mergeCredential(ctx, app, current, secret); {
+				case err != nil:
+					fmt.Fprintf(os.Stderr, "warning: merge %q: %v\n", current.Name, err)
+					failed++
+				case changed:
+					merged++
+				default:
+					duplicates++
+				}
+				continue
+			}
+		}
+		if taken[secret.Name] {
+			duplicates++
+			continue
+		}
+		if _, err := app.API.CreateSecret(ctx, secret); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: import %q: %v\n", secret.Name, err)
+			failed++
+			continue
+		}
+		taken[secret.Name] = true
+		imported++
+	}
+
+	fmt.Printf("imported %d secrets", imported)
+	if merged > 0 {
+		fmt.Printf(", merged %d into existing entries", merged)
+	}
+	if duplicates > 0 {
+		fmt.Printf(", skipped %d duplicates", duplicates)
+	}
+	if unsupported > 0 {
+		fmt.Printf(", skipped %d unsupported entries", unsupported)
+	}
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+	return nil
+}
+
+// credentialKey identifies a credential by where and as whom it logs in.
+type credentialKey struct {
+	url   string
+	login string
+}
+
+// credentialKeyOf extracts the merge key of a credentials secret. Secrets
+// of other types, or without a URL, do not participate in merging.
+func credentialKeyOf(secret *models.Secret) (credentialKey, bool) {
+	if secret.Type != models.SecretTypeCredentials {
+		return credentialKey{}, false
+	}
+	var p models.CredentialsPayload
+	if secret.DecodePayload(&p) != nil || p.URL == "" {
+		return credentialKey{}, false
+	}
+	return credentialKey{url: p.URL, login: p.Login}, true
+}
+
+// mergeCredential updates an existing credential with the imported
+// password. It reports whether anything actually changed.
+func mergeCredential(ctx context.Context, app *App, current, imported *models.Secret) (bool, error) {
+	var cur, imp models.CredentialsPayload
+	if err := current.DecodePayload(&cur); err != nil {
+		return false, err
+	}
+	if err := imported.DecodePayload(&imp); err != nil {
+		return false, err
+	}
+	if cur.Password == imp.Password {
+		return false, nil
+	}
+	update := &models.Secret{
+		ID:        current.ID,
+		Name:      current.Name,
+		Type:      current.Type,
+		Metadata:  current.Metadata,
+		Protected: current.Protected,
+		Data:      imported.Data,
+	}
+	if _, err := app.API.UpdateSecret(ctx, update); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Bitwarden JSON export item types.
+const (
+	bitwardenTypeLogin      = 1
+	bitwardenTypeSecureNote = 2
+	bitwardenTypeCard       = 3
+)
+
+// parseBitwarden maps a Bitwarden JSON export: logins become credentials,
+// secure notes text and cards card secrets. Other item types are counted as
+// unsupported.
+func parseBitwarden(data []byte) ([]models.Secret, int, error) {
+	var export struct {
+		Items []struct {
+			Type  int    `json:"type"`
+			Name  string `json:"name"`
+			Notes string `json:"notes"`
+			Login struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+				URIs     []struct {
+					URI string `json:"uri"`
+				} `json:"uris"`
+			} `json:"login"`
+			Card struct {
+				CardholderName string `json:"cardholderName"`
+				Number         string `json:"number"`
+				ExpMonth       string `json:"expMonth"`
+				ExpYear        string `json:"expYear"`
+				Code           string `json:"code"`
+			} `json:"card"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, 0, err
+	}
+
+	var secrets []models.Secret
+	var unsupported int
+	for _, item := range export.Items {
+		secret := models.Secret{Name: item.Name, Metadata: item.Notes}
+		var err error
+		switch item.Type {
+		case bitwardenTypeLogin:
+			secret.Type = models.SecretTypeCredentials
+			var url string
+			if len(item.Login.URIs) > 0 {
+				url = item.Login.URIs[0].URI
+			}
+			err = secret.EncodePayload(&models.CredentialsPayload{
+				Login:    item.Login.Username,
+				Password: item.Login.Password,
+				URL:      url,
+			})
+		case bitwardenTypeSecureNote:
+			secret.Type = models.SecretTypeText
+			secret.Metadata = ""
+			err = secret.EncodePayload(&models.TextPayload{Content: item.Notes})
+		case bitwardenTypeCard:
+			secret.Type = models.SecretTypeCard
+			expiry := item.Card.ExpMonth
+			if year := item.Card.ExpYear; len(year) >= 2 {
+				expiry = fmt.Sprintf("%02s/%s", item.Card.ExpMonth, year[len(year)-2:])
+			}
+			err = secret.EncodePayload(&models.CardPayload{
+				Number: item.Card.Number,
+				Holder: item.Card.CardholderName,
+				Expiry: expiry,
+				CVV:    item.Card.Code,
+			})
+		default:
+			unsupported++
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, unsupported, nil
+}
+
+// parseKeePassCSV maps a KeePassXC CSV export (Group, Title, Username,
+// Password, URL, Notes columns) onto credentials secrets.
+func parseKeePassCSV(data []byte) ([]models.Secret, int, error) {
+	return parseCredentialsCSV(data, "title", "username", "password", "url", "notes")
+}
+
+// parse1PasswordCSV maps a 1Password CSV export (Title, Url, Username,
+// Password, Notes columns) onto credentials secrets.
+func parse1PasswordCSV(data []byte) ([]models.Secret, int, error) {
+	return parseCredentialsCSV(data, "title", "username", "password", "url", "notes")
+}
+
+// parseBrowserCSV maps a Chrome or Firefox password CSV onto credentials
+// secrets. Both ship url/username/password columns; Chrome adds name and
+// note. Entries without a name take the URL host.
+func parseBrowserCSV(data []byte) ([]models.Secret, int, error) {
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) < 2 {
+		return nil, 0, errors.New("export has no entries")
+	}
+	index := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index["url"]; !ok {
+		return nil, 0, errors.New(`export has no "url" column`)
+	}
+	cell := func(row []string, col string) string {
+		i, ok := index[col]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+
+	var secrets []models.Secret
+	var unsupported int
+	for _, row := range rows[1:] {
+		rawURL := cell(row, "url")
+		name := cell(row, "name")
+		if name == "" {
+			name = urlHost(rawURL)
+		}
+		if name == "" {
+			unsupported++
+			continue
+		}
+		secret := models.Secret{
+			Name:     name,
+			Type:     models.SecretTypeCredentials,
+			Metadata: cell(row, "note"),
+		}
+		err := secret.EncodePayload(&models.CredentialsPayload{
+			Login:    cell(row, "username"),
+			Password: cell(row, "password"),
+			URL:      rawURL,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, unsupported, nil
+}
+
+// urlHost extracts the host of a URL for use as a secret name.
+func urlHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return u.Host
+}
+
+// parseCredentialsCSV reads a header-addressed CSV export into credentials
+// secrets. Column lookup is case-insensitive, so the same mapping covers
+// exports that only differ in header casing. Rows without a title are
+// counted as unsupported.
+func parseCredentialsCSV(data []byte, titleCol, loginCol, passwordCol, urlCol, notesCol string) ([]models.Secret, int, error) {
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) < 2 {
+		return nil, 0, errors.New("export has no entries")
+	}
+	index := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index[titleCol]; !ok {
+		return nil, 0, fmt.Errorf("export has no %q column", titleCol)
+	}
+	cell := func(row []string, col string) string {
+		i, ok := index[col]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+
+	var secrets []models.Secret
+	var unsupported int
+	for _, row := range rows[1:] {
+		title := cell(row, titleCol)
+		if title == "" {
+			unsupported++
+			continue
+		}
+		secret := models.Secret{
+			Name:     title,
+			Type:     models.SecretTypeCredentials,
+			Metadata: cell(row, notesCol),
+		}
+		err := secret.EncodePayload(&models.CredentialsPayload{
+			Login:    cell(row, loginCol),
+			Password: cell(row, passwordCol),
+			URL:      cell(row, urlCol),
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, unsupported, nil
+}
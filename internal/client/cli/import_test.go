@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+func TestParseBitwarden(t *testing.T) {
+	data := []byte(`{"items":[
+		{"type":1,"name":"github","notes":"work","login":{"username":"alice","password":"pw","uris":[{"uri":"https://github.com"}]}},
+		{"type":2,"name":"note","notes":"remember this"},
+		{"type":3,"name":"visa","card":{"cardholderName":"ALICE","number":"4111","expMonth":"4","expYear":"2027","code":"123"}},
+		{"type":4,"name":"identity"}
+	]}`)
+	secrets, unsupported, err := parseBitwarden(data)
+	if err != nil {
+		t.Fatalf("parseBitwarden: %v", err)
+	}
+	if len(secrets) != 3 || unsupported != 1 {
+		t.Fatalf("got %d secrets and %d unsupported, want 3 and 1", len(secrets), unsupported)
+	}
+
+	var creds models.CredentialsPayload
+	if err := secrets[0].DecodePayload(&creds); err != nil {
+		t.Fatal(err)
+	}
+	if creds.Login != "alice" || creds.Password != "pw" || creds.URL != "https://github.com" {
+		t.Fatalf("credentials payload = %+v", creds)
+	}
+	if secrets[1].Type != models.SecretTypeText {
+		t.Fatalf("secure note mapped to %s, want text", secrets[1].Type)
+	}
+	var card models.CardPayload
+	if err := secrets[2].DecodePayload(&card); err != nil {
+		t.Fatal(err)
+	}
+	if card.Expiry != "04/27" {
+		t.Fatalf("card expiry = %q, want 04/27", card.Expiry)
+	}
+}
+
+func TestParseBrowserCSV(t *testing.T) {
+	data := []byte("name,url,username,password,note\n" +
+		"GitHub,https://github.com,alice,pw,work\n" +
+		",https://mail.example.com,bob,pw2,\n")
+	secrets, unsupported, err := parseBrowserCSV(data)
+	if err != nil {
+		t.Fatalf("parseBrowserCSV: %v", err)
+	}
+	if len(secrets) != 2 || unsupported != 0 {
+		t.Fatalf("got %d secrets and %d unsupported, want 2 and 0", len(secrets), unsupported)
+	}
+	if secrets[0].Name != "GitHub" || secrets[0].Metadata != "work" {
+		t.Fatalf("first secret = %+v", secrets[0])
+	}
+	// A nameless row is named after the URL host.
+	if secrets[1].Name != "mail.example.com" {
+		t.Fatalf("second secret name = %q, want mail.example.com", secrets[1].Name)
+	}
+
+	// Firefox layout: no name column at all.
+	data = []byte("url,username,password,guid\nhttps://news.example.com,carol,pw3,x\n")
+	secrets, _, err = parseBrowserCSV(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 1 || secrets[0].Name != "news.example.com" {
+		t.Fatalf("firefox secrets = %+v", secrets)
+	}
+}
+
+func TestCredentialKeyOf(t *testing.T) {
+	secret := &models.Secret{Type: models.SecretTypeCredentials}
+	if err := secret.EncodePayload(&models.CredentialsPayload{Login: "alice", Password: "pw", URL: "https://a"}); err != nil {
+		t.Fatal(err)
+	}
+	key, ok := credentialKeyOf(secret)
+	if !ok || key.url != "https://a" || key.login != "alice" {
+		t.Fatalf("key = %+v ok=%v", key, ok)
+	}
+	// No URL means no merge key.
+	if err := secret.EncodePayload(&models.CredentialsPayload{Login: "alice", Password: "pw"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := credentialKeyOf(secret); ok {
+		t.Fatal("secret without URL must not merge")
+	}
+}
+
+func TestParseKeePassCSV(t *testing.T) {
+	data := []byte("\"Group\",\"Title\",\"Username\",\"Password\",\"URL\",\"Notes\"\n" +
+		"\"Root\",\"mail\",\"bob\",\"hunter2\",\"https://mail.example.com\",\"personal\"\n" +
+		"\"Root\",\"\",\"\",\"\",\"\",\"\"\n")
+	secrets, unsupported, err := parseKeePassCSV(data)
+	if err != nil {
+		t.Fatalf("parseKeePassCSV: %v", err)
+	}
+	if len(secrets) != 1 || unsupported != 1 {
+		t.Fatalf("got %d secrets and %d unsupported, want 1 and 1", len(secrets), unsupported)
+	}
+	if secrets[0].Name != "mail" || secrets[0].Metadata != "personal" {
+		t.Fatalf("secret = %+v", secrets[0])
+	}
+	var creds models.CredentialsPayload
+	if err := secrets[0].DecodePayload(&creds); err != nil {
+		t.Fatal(err)
+	}
+	if creds.Login != "bob" || creds.Password != "hunter2" {
+		t.Fatalf("credentials payload = %+v", creds)
+	}
+}
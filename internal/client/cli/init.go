@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/client/config"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/credstore"
+	"github.com/alexuryumtsev/gophkeeper/pkg/api"
+)
+
+var initCmd = &Command{
+	Name:        "init",
+	Usage:       "init",
+	Description: "interactive first-run setup",
+	Run:         runInit,
+}
+
+// runInit walks a new user through the first-run setup: where the server
+// is, which account to use, and where local data lives. Every answer is
+// validated before moving on, and the result is saved as the current
+// profile so later invocations need no flags.
+func runInit(ctx context.Context, app *App, _ []string) error {
+	fmt.Println("This sets up gophkeeper on this machine. Press Enter to accept a default.")
+
+	var name string
+	for {
+		var err error
+		if name, err = promptDefault("Profile name", "default"); err != nil {
+			return err
+		}
+		// The name doubles as the default directory name.
+		if !strings.ContainsAny(name, "/\\") {
+			break
+		}
+		fmt.Fprintln(os.Stderr, "profile names cannot contain path separators")
+	}
+
+	// Server URL, re-asked until a server answers.
+	var client *api.Client
+	serverURL := app.Config.ServerURL
+	for {
+		var err error
+		if serverURL, err = promptDefault("Server URL", serverURL); err != nil {
+			return err
+		}
+		parsed, err := url.Parse(serverURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			fmt.Fprintln(os.Stderr, "the server URL must start with http:// or https://")
+			continue
+		}
+		client = api.New(serverURL)
+		if tlsCfg, err := newTLSConfig(app.Config); err == nil && tlsCfg != nil {
+			client.SetTLSConfig(tlsCfg)
+		}
+		if err := client.Health(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "could not reach %s: %v\n", serverURL, err)
+			continue
+		}
+		fmt.Println("server is reachable")
+		break
+	}
+
+	dataDir, err := promptDefault("Data directory", app.Config.ProfileDataDir(name))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+		return err
+	}
+
+	// The account password doubles as the master password that seals
+	// local data, so registration asks for it twice.
+	answer, err := readLine("Do you already have an account on this server? (y/N): ")
+	if err != nil {
+		return err
+	}
+	existing := strings.EqualFold(answer, "y")
+	var login string
+	for login == "" {
+		if login, err = readLine("Login: "); err != nil {
+			return err
+		}
+	}
+	var password string
+	if existing {
+		if password, err = readPassword("Master password: "); err != nil {
+			return err
+		}
+		if err := client.Login(ctx, login, password); err != nil {
+			return err
+		}
+		fmt.Println("logged in")
+	} else {
+		for {
+			if password, err = readPassword("Choose a master password: "); err != nil {
+				return err
+			}
+			confirm, err := readPassword("Repeat it: ")
+			if err != nil {
+				return err
+			}
+			if password == confirm {
+				break
+			}
+			fmt.Fprintln(os.Stderr, "the passwords do not match")
+		}
+		if err := client.Register(ctx, login, password); err != nil {
+			return err
+		}
+		fmt.Println("account created")
+	}
+
+	// The session goes into the profile's own credential store, so
+	// switching profiles switches accounts too.
+	creds := credstore.Open(dataDir)
+	if err := creds.Set(credstore.Token, client.Token()); err != nil {
+		return err
+	}
+	answer, err = readLine("Remember the master password on this device? (y/N): ")
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(answer, "y") {
+		if err := creds.Set(credstore.MasterPassword, password); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not store master password: %v\n", err)
+		}
+	}
+
+	// Save the answers as a profile and make it the default.
+	path := app.Config.ProfilesPath()
+	set, err := config.LoadProfiles(path)
+	if err != nil {
+		return err
+	}
+	if set.Profiles == nil {
+		set.Profiles = make(map[string]config.Profile)
+	}
+	profile := config.Profile{ServerURL: serverURL}
+	if dataDir != app.Config.ProfileDataDir(name) {
+		profile.DataDir = dataDir
+	}
+	set.Profiles[name] = profile
+	set.Current = name
+	if err := set.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Printf("profile %s is set up and selected; run `gophkeeper secrets list` to start\n", name)
+	return nil
+}
+
+// promptDefault reads one line, returning def when the user just presses
+// Enter.
+func promptDefault(prompt, def string) (string, error) {
+	line, err := readLine(fmt.Sprintf("%s [%s]: ", prompt, def))
+	if err != nil {
+		return "", err
+	}
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
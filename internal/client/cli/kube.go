@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+var kubeCmd = &Command{
+	Name:        "kube",
+	Usage:       "kube <use|clean>",
+	Description: "use kubeconfigs from the vault with kubectl",
+	Run:         runKube,
+}
+
+func runKube(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gophkeeper kube <use|clean>")
+	}
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "use":
+		return kubeUse(ctx, app, rest)
+	case "clean":
+		return kubeClean(app)
+	default:
+		return fmt.Errorf("unknown kube subcommand %q", sub)
+	}
+}
+
+// kubeUse materializes a kubeconfig secret as a private file and prints the
+// export line for eval, so cluster credentials live in the vault instead of
+// ~/.kube:
+//
+//	eval "$(gophkeeper kube use <id>)"
+func kubeUse(ctx context.Context, app *App, args []string) error {
+	id, err := app.parseIDArg(args)
+	if err != nil {
+		return err
+	}
+	result, err := app.API.GetSecret(ctx, id)
+	if err != nil {
+		return err
+	}
+	secret := result.Secret
+	if secret.Type != models.SecretTypeKubeconfig {
+		return fmt.Errorf("secret %s is %s, not %s", id, secret.Type, models.SecretTypeKubeconfig)
+	}
+	var p models.KubeconfigPayload
+	if err := secret.DecodePayload(&p); err != nil {
+		return err
+	}
+
+	dir := kubeconfigDir(app)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, id.String()+".yaml")
+	if err := os.WriteFile(path, []byte(p.Config), 0o600); err != nil {
+		return err
+	}
+
+	fmt.Printf("export KUBECONFIG='%s'\n", path)
+	if p.Context != "" {
+		fmt.Printf("kubectl config use-context '%s' >/dev/null\n", p.Context)
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s; run `gophkeeper kube clean` when done\n", path)
+	return nil
+}
+
+// kubeClean removes every materialized kubeconfig.
+func kubeClean(app *App) error {
+	dir := kubeconfigDir(app)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("removed %d kubeconfigs\n", len(entries))
+	return nil
+}
+
+// kubeconfigDir is where `kube use` materializes configs, inside the
+// private data directory.
+func kubeconfigDir(app *App) string {
+	return filepath.Join(app.Config.DataDir, "kubeconfigs")
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ANSI escape sequences used by the Markdown renderer.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiItalic    = "\x1b[3m"
+	ansiUnderline = "\x1b[4m"
+	ansiDim       = "\x1b[2m"
+)
+
+var (
+	mdBoldRe   = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	mdItalicRe = regexp.MustCompile(`\*([^*]+)\*`)
+	mdCodeRe   = regexp.MustCompile("`([^`]+)`")
+	mdLinkRe   = regexp.MustCompile(`\[([^]]+)]\(([^)]+)\)`)
+)
+
+// renderMarkdown converts a small, pragmatic subset of Markdown (headings,
+// bullet lists, fenced code blocks, bold/italic/inline code, links) into
+// ANSI-styled terminal output. Anything it does not understand passes
+// through unchanged.
+func renderMarkdown(src string) string {
+	var out strings.Builder
+	inCode := false
+	for _, line := range strings.Split(src, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out.WriteString(ansiDim + "    " + line + ansiReset + "\n")
+			continue
+		}
+		switch {
+		case strings.HasPrefix(trimmed, "# "):
+			out.WriteString(ansiBold + ansiUnderline + strings.TrimPrefix(trimmed, "# ") + ansiReset + "\n")
+		case strings.HasPrefix(trimmed, "## "):
+			out.WriteString(ansiBold + strings.TrimPrefix(trimmed, "## ") + ansiReset + "\n")
+		case strings.HasPrefix(trimmed, "### "):
+			out.WriteString(ansiBold + strings.TrimPrefix(trimmed, "### ") + ansiReset + "\n")
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+			out.WriteString("  • " + renderInline(trimmed[2:]) + "\n")
+		default:
+			out.WriteString(renderInline(line) + "\n")
+		}
+	}
+	return out.String()
+}
+
+// renderInline styles bold, italic, inline code and links within one line.
+func renderInline(s string) string {
+	s = mdCodeRe.ReplaceAllString(s, ansiDim+"$1"+ansiReset)
+	s = mdBoldRe.ReplaceAllString(s, ansiBold+"$1"+ansiReset)
+	s = mdItalicRe.ReplaceAllString(s, ansiItalic+"$1"+ansiReset)
+	s = mdLinkRe.ReplaceAllString(s, fmt.Sprintf("%s$1%s (%s$2%s)", ansiUnderline, ansiReset, ansiDim, ansiReset))
+	return s
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownHeadingsAndLists(t *testing.T) {
+	out := renderMarkdown("# Title\n- item one\nplain line")
+	if !strings.Contains(out, ansiBold+ansiUnderline+"Title"+ansiReset) {
+		t.Errorf("heading not styled:\n%q", out)
+	}
+	if !strings.Contains(out, "  • item one") {
+		t.Errorf("list item not rendered:\n%q", out)
+	}
+	if !strings.Contains(out, "plain line\n") {
+		t.Errorf("plain line mangled:\n%q", out)
+	}
+}
+
+func TestRenderMarkdownCodeBlock(t *testing.T) {
+	out := renderMarkdown("```\ncode here\n```\nafter")
+	if strings.Contains(out, "```") {
+		t.Errorf("fence markers leaked:\n%q", out)
+	}
+	if !strings.Contains(out, "code here") || !strings.Contains(out, "after") {
+		t.Errorf("content lost:\n%q", out)
+	}
+}
+
+func TestRenderInline(t *testing.T) {
+	out := renderInline("**bold** and *italic* and `code`")
+	for _, want := range []string{ansiBold + "bold" + ansiReset, ansiItalic + "italic" + ansiReset, ansiDim + "code" + ansiReset} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in %q", want, out)
+		}
+	}
+}
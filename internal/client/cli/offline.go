@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/client/i18n"
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// isUnreachable reports whether err means the server could not be reached
+// at all, as opposed to rejecting the request. Only then is the local
+// cache an acceptable substitute.
+func isUnreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// staleWarning tells the user they are looking at cached data.
+func staleWarning(syncedAt time.Time) {
+	if syncedAt.IsZero() {
+		fmt.Fprintln(os.Stderr, i18n.T("warning: server unreachable; using local cache"))
+		return
+	}
+	fmt.Fprintln(os.Stderr, i18n.Tf("warning: server unreachable; using local cache (stale since %s)",
+		syncedAt.Local().Format(time.DateTime)))
+}
+
+// cachedSecret looks the secret up in the local cache, for reads while the
+// server is down.
+func (a *App) cachedSecret(id uuid.UUID) (*models.Secret, time.Time, bool) {
+	if err := a.unlockStorage(true); err != nil {
+		return nil, time.Time{}, false
+	}
+	secrets, syncedAt, err := a.Storage.Load()
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	for i := range secrets {
+		if secrets[i].ID == id {
+			return &secrets[i], syncedAt, true
+		}
+	}
+	return nil, time.Time{}, false
+}
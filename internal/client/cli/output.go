@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Output formats understood by the global -output flag.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// extractOutputFlag removes a global `-output <format>` (or `--output`,
+// `=`-joined) from args, wherever it appears, and returns the requested
+// format. Commands keep their hand-formatted text under OutputTable.
+func extractOutputFlag(args []string) (string, []string, error) {
+	format := OutputTable
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, joined := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "output" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !joined {
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("flag %s needs a value", arg)
+			}
+			i++
+			value = args[i]
+		}
+		switch value {
+		case OutputTable, OutputJSON, OutputYAML:
+			format = value
+		default:
+			return "", nil, fmt.Errorf("unknown output format %q (want table, json or yaml)", value)
+		}
+	}
+	return format, rest, nil
+}
+
+// emit writes v to stdout in the requested structured format. It reports
+// whether it did, so callers fall through to their text rendering under
+// OutputTable.
+func (a *App) emit(v any) (bool, error) {
+	switch a.Output {
+	case OutputJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return true, enc.Encode(v)
+	case OutputYAML:
+		data, err := yamlMarshal(v)
+		if err != nil {
+			return true, err
+		}
+		_, err = os.Stdout.Write(data)
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+// yamlMarshal renders v as YAML. It covers what JSON can express — maps
+// with sorted keys, lists and scalars — which keeps the client free of a
+// YAML dependency.
+func yamlMarshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	yamlValue(&buf, decoded, 0)
+	return buf.Bytes(), nil
+}
+
+func yamlValue(buf *bytes.Buffer, v any, indent int) {
+	prefix := strings.Repeat("  ", indent)
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 0 {
+			buf.WriteString(prefix + "{}\n")
+			return
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if child, nested := val[k].(map[string]any); nested && len(child) > 0 {
+				buf.WriteString(prefix + yamlScalar(k) + ":\n")
+				yamlValue(buf, child, indent+1)
+				continue
+			}
+			if child, nested := val[k].([]any); nested && len(child) > 0 {
+				buf.WriteString(prefix + yamlScalar(k) + ":\n")
+				yamlValue(buf, child, indent+1)
+				continue
+			}
+			buf.WriteString(prefix + yamlScalar(k) + ": " + yamlFlat(val[k]) + "\n")
+		}
+	case []any:
+		if len(val) == 0 {
+			buf.WriteString(prefix + "[]\n")
+			return
+		}
+		for _, item := range val {
+			switch item.(type) {
+			case map[string]any, []any:
+				buf.WriteString(prefix + "-\n")
+				yamlValue(buf, item, indent+1)
+			default:
+				buf.WriteString(prefix + "- " + yamlFlat(item) + "\n")
+			}
+		}
+	default:
+		buf.WriteString(prefix + yamlFlat(v) + "\n")
+	}
+}
+
+// yamlFlat renders a leaf value inline.
+func yamlFlat(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(val)
+	case json.Number:
+		return val.String()
+	case string:
+		return yamlScalar(val)
+	case map[string]any:
+		return "{}"
+	case []any:
+		return "[]"
+	default:
+		return yamlScalar(fmt.Sprint(val))
+	}
+}
+
+// yamlScalar quotes a string unless it is safe as a plain YAML scalar.
+func yamlScalar(s string) string {
+	if s == "" {
+		return `""`
+	}
+	plain := true
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == '/' || r == '@':
+		default:
+			plain = false
+		}
+		if !plain {
+			break
+		}
+	}
+	// Plain scalars must not parse as another YAML type.
+	switch strings.ToLower(s) {
+	case "null", "true", "false", "yes", "no", "on", "off", "~":
+		plain = false
+	}
+	if plain {
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			plain = false
+		}
+	}
+	if plain {
+		return s
+	}
+	return strconv.Quote(s)
+}
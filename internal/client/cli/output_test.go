@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractOutputFlag(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		format string
+		rest   []string
+	}{
+		{"absent", []string{"secrets", "list"}, OutputTable, []string{"secrets", "list"}},
+		{"trailing", []string{"secrets", "list", "-output", "json"}, OutputJSON, []string{"secrets", "list"}},
+		{"leading joined", []string{"--output=yaml", "secrets", "get", "x"}, OutputYAML, []string{"secrets", "get", "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, rest, err := extractOutputFlag(tt.args)
+			if err != nil {
+				t.Fatalf("extractOutputFlag: %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("format = %q, want %q", format, tt.format)
+			}
+			if !reflect.DeepEqual(rest, tt.rest) {
+				t.Errorf("rest = %v, want %v", rest, tt.rest)
+			}
+		})
+	}
+
+	if _, _, err := extractOutputFlag([]string{"-output", "xml"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if _, _, err := extractOutputFlag([]string{"-output"}); err == nil {
+		t.Error("expected error for missing value")
+	}
+}
+
+func TestYAMLMarshal(t *testing.T) {
+	v := map[string]any{
+		"name":  "prod db",
+		"port":  5432,
+		"tags":  []string{"a", "b"},
+		"empty": "",
+		"extra": map[string]any{"tls": true},
+	}
+	got, err := yamlMarshal(v)
+	if err != nil {
+		t.Fatalf("yamlMarshal: %v", err)
+	}
+	want := "empty: \"\"\n" +
+		"extra:\n" +
+		"  tls: true\n" +
+		"name: \"prod db\"\n" +
+		"port: 5432\n" +
+		"tags:\n" +
+		"  - a\n" +
+		"  - b\n"
+	if string(got) != want {
+		t.Errorf("yamlMarshal =\n%s\nwant:\n%s", got, want)
+	}
+}
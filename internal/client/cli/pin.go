@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alexuryumtsev/gophkeeper/pkg/api"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// verifyServerIdentity checks the server's identity key against the pinned
+// fingerprint before credentials are sent, on top of whatever TLS provides.
+// On first contact the identity is pinned (trust on first use); afterwards
+// a changed identity aborts with instructions, since it may mean the
+// connection is intercepted.
+func (a *App) verifyServerIdentity(ctx context.Context) error {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	publicKey, signature, err := a.API.Identity(ctx, nonce)
+	if errors.Is(err, api.ErrNotFound) {
+		// Older server without the identity endpoint: nothing to verify,
+		// but an existing pin disappearing is exactly what an interceptor
+		// would look like.
+		if _, pinErr := os.ReadFile(a.Config.PinPath()); pinErr == nil {
+			return fmt.Errorf("server no longer offers its pinned identity; remove %s if this is expected", a.Config.PinPath())
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(publicKey) != ed25519.PublicKeySize || !ed25519.Verify(publicKey, nonce, signature) {
+		return errors.New("server identity signature is invalid")
+	}
+
+	sum := sha256.Sum256(publicKey)
+	fingerprint := hex.EncodeToString(sum[:])
+	pinned, err := os.ReadFile(a.Config.PinPath())
+	if errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(a.Config.PinPath(), []byte(fingerprint+"\n"), 0o600); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "pinned server identity %s\n", fingerprint)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !crypto.ConstantTimeEqual([]byte(strings.TrimSpace(string(pinned))), []byte(fingerprint)) {
+		return fmt.Errorf("SERVER IDENTITY CHANGED: pinned %s, got %s\n"+
+			"The connection may be intercepted. If the server was legitimately reinstalled,\n"+
+			"remove %s and log in again",
+			strings.TrimSpace(string(pinned)), fingerprint, a.Config.PinPath())
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var promptStatusCmd = &Command{
+	Name:        "prompt-status",
+	Usage:       "prompt-status",
+	Description: "print a one-line status summary for shell prompts",
+	Run:         runPromptStatus,
+}
+
+// runPromptStatus emits a single line of key=value pairs — lock state,
+// queued offline operations, cache age and secret count — reading only
+// local state so it is fast enough for a shell prompt and works offline.
+// It never prompts and never fails.
+func runPromptStatus(_ context.Context, app *App, _ []string) error {
+	parts := []string{}
+
+	locked := app.unlockStorage(false) != nil
+	if locked {
+		parts = append(parts, "locked=yes")
+	} else {
+		parts = append(parts, "locked=no")
+	}
+
+	ops, err := app.Journal.Load()
+	if err == nil {
+		parts = append(parts, fmt.Sprintf("pending=%d", len(ops)))
+	}
+
+	if !locked {
+		secrets, syncedAt, err := app.Storage.Load()
+		if err == nil {
+			parts = append(parts, fmt.Sprintf("secrets=%d", len(secrets)))
+			if syncedAt.IsZero() {
+				parts = append(parts, "synced=never")
+			} else {
+				parts = append(parts, "synced="+compactAge(time.Since(syncedAt)))
+			}
+		}
+	}
+
+	fmt.Println(strings.Join(parts, " "))
+	return nil
+}
+
+// compactAge renders a duration in the shortest unit that still reads
+// naturally in a prompt: 42s, 5m, 3h, 2d.
+func compactAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
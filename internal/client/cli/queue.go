@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/client/storage"
+	"github.com/alexuryumtsev/gophkeeper/pkg/api"
+)
+
+// queueWrite journals a write that could not reach the server, to be
+// replayed by the next command that can.
+func (a *App) queueWrite(op storage.Operation) error {
+	op.QueuedAt = time.Now().UTC()
+	if err := a.Journal.Append(op); err != nil {
+		return fmt.Errorf("queue offline %s: %w", op.Kind, err)
+	}
+	fmt.Fprintf(os.Stderr, "server unreachable; queued %s for replay on reconnect\n", op.Kind)
+	return nil
+}
+
+// replayJournal pushes queued offline writes to the server, in order. A
+// still-unreachable server keeps the queue intact; an operation the server
+// rejects, or whose secret changed on the server since it was queued, is
+// dropped with a warning so one bad entry cannot wedge the queue.
+func (a *App) replayJournal(ctx context.Context) {
+	ops, err := a.Journal.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: read offline journal: %v\n", err)
+		return
+	}
+	if len(ops) == 0 {
+		return
+	}
+	replayed := 0
+	for len(ops) > 0 {
+		err := a.replayOne(ctx, ops[0])
+		if isUnreachable(err) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: dropping queued %s from %s: %v\n",
+				ops[0].Kind, ops[0].QueuedAt.Local().Format(time.DateTime), err)
+		} else {
+			replayed++
+		}
+		ops = ops[1:]
+	}
+	if err := a.Journal.Save(ops); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: update offline journal: %v\n", err)
+	}
+	if replayed > 0 {
+		fmt.Fprintf(os.Stderr, "replayed %d queued operation(s)\n", replayed)
+	}
+}
+
+func (a *App) replayOne(ctx context.Context, op storage.Operation) error {
+	switch op.Kind {
+	case storage.OpCreate:
+		_, err := a.API.CreateSecret(ctx, op.Secret)
+		return err
+	case storage.OpUpdate:
+		current, err := a.API.GetSecret(ctx, op.SecretID)
+		if err != nil {
+			return err
+		}
+		if current.Secret.UpdatedAt.After(op.BaseUpdatedAt) {
+			return fmt.Errorf("conflict: secret changed on the server at %s, keeping the server version",
+				current.Secret.UpdatedAt.Local().Format(time.DateTime))
+		}
+		_, err = a.API.UpdateSecret(ctx, op.Secret)
+		return err
+	case storage.OpDelete:
+		current, err := a.API.GetSecret(ctx, op.SecretID)
+		if errors.Is(err, api.ErrNotFound) {
+			return nil // already gone
+		}
+		if err != nil {
+			return err
+		}
+		if !op.BaseUpdatedAt.IsZero() && current.Secret.UpdatedAt.After(op.BaseUpdatedAt) {
+			return fmt.Errorf("conflict: secret changed on the server at %s, keeping it",
+				current.Secret.UpdatedAt.Local().Format(time.DateTime))
+		}
+		return a.API.DeleteSecret(ctx, op.SecretID, false)
+	default:
+		return fmt.Errorf("unknown operation kind %q", op.Kind)
+	}
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+var reportCmd = &Command{
+	Name:        "report",
+	Usage:       "report access",
+	Description: "access review reports",
+	Run:         runReport,
+}
+
+func runReport(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 || args[0] != "access" {
+		return errors.New("usage: gophkeeper report access")
+	}
+	report, err := app.API.AccessReport(ctx)
+	if err != nil {
+		return err
+	}
+	if done, err := app.emit(report); done {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SECRET\tUSER\tREAD\tWRITE\tVIA")
+	for _, s := range report.Secrets {
+		for _, g := range s.Grants {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				s.SecretName, g.Login, yesNo(g.CanRead), yesNo(g.CanWrite), g.Via)
+		}
+	}
+	return w.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
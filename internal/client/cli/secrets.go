@@ -0,0 +1,945 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/client/config"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/i18n"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/storage"
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/pkg/api"
+)
+
+var secretsCmd = &Command{
+	Name:        "secrets",
+	Usage:       "secrets <subcommand>",
+	Description: "manage stored secrets (list, add, get, update, delete)",
+	Run:         runSecrets,
+}
+
+func runSecrets(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gophkeeper secrets <list|find|add|get|update|edit|delete|env|use-code|attach|detach|download|archive-policy|audit|history|convert|export|import|connect|share|unshare|shares|shared|link>")
+	}
+	sub, rest := args[0], args[1:]
+	// Writes queued while offline go out before anything else touches the
+	// server.
+	app.replayJournal(ctx)
+	switch sub {
+	case "list":
+		return secretsList(ctx, app, rest)
+	case "find":
+		return secretsFind(ctx, app, rest)
+	case "archive-policy":
+		return secretsArchivePolicy(ctx, app, rest)
+	case "audit":
+		return secretsAudit(ctx, app, rest)
+	case "history":
+		return secretsHistory(ctx, app, rest)
+	case "convert":
+		return secretsConvert(ctx, app, rest)
+	case "export":
+		return secretsExport(ctx, app, rest)
+	case "import":
+		return secretsImport(ctx, app, rest)
+	case "add":
+		return secretsAdd(ctx, app, rest)
+	case "get":
+		return secretsGet(ctx, app, rest)
+	case "update":
+		return secretsUpdate(ctx, app, rest)
+	case "edit":
+		return secretsEdit(ctx, app, rest)
+	case "delete":
+		return secretsDelete(ctx, app, rest)
+	case "env":
+		return secretsEnv(ctx, app, rest)
+	case "use-code":
+		return secretsUseCode(ctx, app, rest)
+	case "attach":
+		return secretsAttach(ctx, app, rest)
+	case "detach":
+		return secretsDetach(ctx, app, rest)
+	case "download":
+		return secretsDownload(ctx, app, rest)
+	case "connect":
+		return secretsConnect(ctx, app, rest)
+	case "share":
+		return secretsShare(ctx, app, rest)
+	case "unshare":
+		return secretsUnshare(ctx, app, rest)
+	case "shares":
+		return secretsShares(ctx, app, rest)
+	case "shared":
+		return secretsShared(ctx, app, rest)
+	case "link":
+		return secretsLink(ctx, app, rest)
+	default:
+		return fmt.Errorf("unknown secrets subcommand %q", sub)
+	}
+}
+
+func secretsList(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	archived := fs.Bool("archived", false, "include auto-archived secrets")
+	columnsFlag := fs.String("columns", "", "comma-separated columns to print (e.g. id,name,type,updated)")
+	saveColumns := fs.Bool("save-columns", false, "persist -columns as the default")
+	sortBy := fs.String("sort", "", "server-side sort: updated_at, created_at, name or type")
+	order := fs.String("order", "", "sort direction: asc or desc")
+	limit := fs.Int("limit", 0, "fetch at most this many secrets")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	settings, err := config.LoadSettings(app.Config.SettingsPath())
+	if err != nil {
+		return err
+	}
+	columns := defaultListColumns
+	if len(settings.ListColumns) > 0 {
+		columns = settings.ListColumns
+	}
+	if *columnsFlag != "" {
+		if columns, err = parseColumns(*columnsFlag); err != nil {
+			return err
+		}
+		if *saveColumns {
+			settings.ListColumns = columns
+			if err := settings.Save(app.Config.SettingsPath()); err != nil {
+				return err
+			}
+		}
+	}
+
+	var secrets []models.Secret
+	list, err := app.API.GetSecretsWithErrors(ctx, api.ListOptions{
+		IncludeArchived: *archived,
+		Sort:            *sortBy,
+		Order:           *order,
+		Limit:           *limit,
+	})
+	switch {
+	case err == nil:
+		secrets = list.Secrets
+		for _, e := range list.Errors {
+			fmt.Fprintf(os.Stderr, "warning: secret %s could not be returned: %s\n", e.SecretID, e.Error)
+		}
+		saveErr := app.unlockStorage(true)
+		if saveErr == nil {
+			saveErr = app.Storage.Save(secrets)
+		}
+		if saveErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not update local cache: %v\n", saveErr)
+		}
+	case isUnreachable(err):
+		if unlockErr := app.unlockStorage(true); unlockErr != nil {
+			return err
+		}
+		cached, syncedAt, cacheErr := app.Storage.Load()
+		if cacheErr != nil {
+			return err
+		}
+		staleWarning(syncedAt)
+		secrets = secrets[:0]
+		for i := range cached {
+			if cached[i].Archived && !*archived {
+				continue
+			}
+			secrets = append(secrets, cached[i])
+		}
+	default:
+		return err
+	}
+	if done, err := app.emit(secrets); done {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, name := range columns {
+		headers[i] = listColumns[name].header
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for i := range secrets {
+		cells := make([]string, len(columns))
+		for j, name := range columns {
+			cells[j] = listColumns[name].value(&secrets[i])
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return w.Flush()
+}
+
+// secretsArchivePolicy sets after how many days of inactivity secrets are
+// auto-archived on the server.
+func secretsArchivePolicy(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("archive-policy", flag.ContinueOnError)
+	days := fs.Int("days", 0, "archive secrets unaccessed for this many days (0 disables)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := app.API.SetArchivePolicy(ctx, *days); err != nil {
+		return err
+	}
+	if *days == 0 {
+		fmt.Println("auto-archival disabled")
+	} else {
+		fmt.Printf("secrets unaccessed for %d days will be archived\n", *days)
+	}
+	return nil
+}
+
+func secretsAdd(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gophkeeper secrets add <credentials|text|card|binary|api_token|recovery_codes|kubeconfig> [flags]")
+	}
+	typ := models.SecretType(args[0])
+	secret := &models.Secret{Type: typ}
+	if err := fillPayload(secret, args[1:]); err != nil {
+		return err
+	}
+	result, err := app.API.CreateSecret(ctx, secret)
+	if isUnreachable(err) {
+		return app.queueWrite(storage.Operation{Kind: storage.OpCreate, Secret: secret})
+	}
+	if err != nil {
+		return err
+	}
+	printWarnings(result.Warnings)
+	fmt.Printf("created %s secret %s (%s)\n", result.Secret.Type, result.Secret.Name, result.Secret.ID)
+	return nil
+}
+
+func secretsGet(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	showHistory := fs.Bool("password-history", false, "show previous passwords of a credentials secret")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := app.parseIDArg(fs.Args())
+	if err != nil {
+		return err
+	}
+	result, err := app.API.GetSecret(ctx, id)
+	offline := false
+	if isUnreachable(err) {
+		if cached, syncedAt, ok := app.cachedSecret(id); ok {
+			staleWarning(syncedAt)
+			result, err = &api.SecretResult{Secret: cached}, nil
+			offline = true
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if app.Output != OutputTable {
+		// The payload is JSON already; embed it instead of base64 bytes.
+		view := struct {
+			models.Secret
+			Data json.RawMessage `json:"data,omitempty"`
+		}{*result.Secret, json.RawMessage(result.Secret.Data)}
+		done, err := app.emit(view)
+		if done {
+			return err
+		}
+	}
+	// A plugin that claims this secret type takes over payload rendering.
+	if out, ok, err := app.renderWithPlugin(ctx, string(result.Secret.Type), result.Secret.Data); ok {
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Name:     %s\n", result.Secret.Name)
+		fmt.Printf("Type:     %s\n", result.Secret.Type)
+		fmt.Print(out)
+	} else if err := printSecret(result.Secret, *showHistory); err != nil {
+		return err
+	}
+	if offline {
+		return nil
+	}
+	attachments, err := app.API.ListAttachments(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(attachments) > 0 {
+		fmt.Println("Attachments:")
+		for _, a := range attachments {
+			fmt.Printf("  %s  %s (%d bytes)\n", a.ID, a.Filename, a.Size)
+		}
+	}
+	return nil
+}
+
+func secretsUpdate(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gophkeeper secrets update <id> [flags]")
+	}
+	id, err := app.parseIDArg(args[:1])
+	if err != nil {
+		return err
+	}
+	current, err := app.API.GetSecret(ctx, id)
+	if isUnreachable(err) {
+		cached, syncedAt, ok := app.cachedSecret(id)
+		if !ok {
+			return err
+		}
+		staleWarning(syncedAt)
+		if err := fillPayload(cached, args[1:]); err != nil {
+			return err
+		}
+		return app.queueWrite(storage.Operation{
+			Kind:          storage.OpUpdate,
+			SecretID:      id,
+			BaseUpdatedAt: cached.UpdatedAt,
+			Secret:        cached,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	secret := current.Secret
+	if err := fillPayload(secret, args[1:]); err != nil {
+		return err
+	}
+	result, err := app.API.UpdateSecret(ctx, secret)
+	if err != nil {
+		return err
+	}
+	printWarnings(result.Warnings)
+	fmt.Printf("updated secret %s\n", id)
+	return nil
+}
+
+// secretsEnv prints an api_token secret as an `export NAME=value` line
+// suitable for eval in a shell.
+func secretsEnv(ctx context.Context, app *App, args []string) error {
+	id, err := app.parseIDArg(args)
+	if err != nil {
+		return err
+	}
+	result, err := app.API.GetSecret(ctx, id)
+	if err != nil {
+		return err
+	}
+	secret := result.Secret
+	if secret.Type != models.SecretTypeAPIToken {
+		return fmt.Errorf("secret %s is %s, not %s", id, secret.Type, models.SecretTypeAPIToken)
+	}
+	var p models.APITokenPayload
+	if err := secret.DecodePayload(&p); err != nil {
+		return err
+	}
+	if p.Expired(time.Now()) {
+		fmt.Fprintln(os.Stderr, "warning: token has expired")
+	}
+	name := p.EnvVar
+	if name == "" {
+		name = envVarName(secret.Name)
+	}
+	fmt.Printf("export %s='%s'\n", name, strings.ReplaceAll(p.Token, "'", `'\''`))
+	return nil
+}
+
+// secretsHistory lists the stored versions of a secret with what each
+// update changed, and shows one full snapshot with -version.
+func secretsHistory(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	version := fs.Int("version", 0, "show the full payload of this version")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := app.parseIDArg(fs.Args())
+	if err != nil {
+		return err
+	}
+	if *version > 0 {
+		v, err := app.API.GetSecretVersion(ctx, id, *version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Version:  %d\n", v.Version)
+		fmt.Printf("Saved:    %s\n", v.CreatedAt.Local().Format(time.DateTime))
+		if v.Summary != "" {
+			fmt.Printf("Changes:  %s\n", v.Summary)
+		}
+		fmt.Println(string(v.Data))
+		return nil
+	}
+	versions, err := app.API.GetSecretVersions(ctx, id)
+	if err != nil {
+		return err
+	}
+	if done, err := app.emit(versions); done {
+		return err
+	}
+	if len(versions) == 0 {
+		fmt.Println("no previous versions")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSAVED\tCHANGES")
+	for _, v := range versions {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", v.Version, v.CreatedAt.Local().Format(time.DateTime), v.Summary)
+	}
+	return w.Flush()
+}
+
+// secretsUseCode consumes the next unused recovery code and prints it.
+func secretsUseCode(ctx context.Context, app *App, args []string) error {
+	id, err := app.parseIDArg(args)
+	if err != nil {
+		return err
+	}
+	result, err := app.API.UseRecoveryCode(ctx, id)
+	if err != nil {
+		return err
+	}
+	printWarnings(result.Warnings)
+	fmt.Println(result.Code)
+	fmt.Fprintf(os.Stderr, "%d unused codes remain\n", result.Remaining)
+	return nil
+}
+
+// secretsAttach uploads a file as an attachment to any secret.
+func secretsAttach(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("attach", flag.ContinueOnError)
+	file := fs.String("file", "", "path of the file to attach")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := app.parseIDArg(fs.Args())
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("usage: gophkeeper secrets attach <id> -file <path>")
+	}
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	attachment, err := app.API.UploadAttachment(ctx, id, filepath.Base(*file), content)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("attached %s (%d bytes) as %s\n", attachment.Filename, attachment.Size, attachment.ID)
+	return nil
+}
+
+// secretsDetach removes an attachment from a secret.
+func secretsDetach(ctx context.Context, app *App, args []string) error {
+	secretID, attachmentID, err := parseTwoIDs(args, "usage: gophkeeper secrets detach <secret-id> <attachment-id>")
+	if err != nil {
+		return err
+	}
+	if err := app.API.DeleteAttachment(ctx, secretID, attachmentID); err != nil {
+		return err
+	}
+	fmt.Printf("detached attachment %s\n", attachmentID)
+	return nil
+}
+
+// secretsDownload saves an attachment to disk.
+func secretsDownload(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("download", flag.ContinueOnError)
+	out := fs.String("o", "", "output path (defaults to the attachment filename)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	secretID, attachmentID, err := parseTwoIDs(fs.Args(), "usage: gophkeeper secrets download <secret-id> <attachment-id> [-o path]")
+	if err != nil {
+		return err
+	}
+	content, err := app.API.DownloadAttachment(ctx, secretID, attachmentID)
+	if err != nil {
+		return err
+	}
+	path := *out
+	if path == "" {
+		attachments, err := app.API.ListAttachments(ctx, secretID)
+		if err != nil {
+			return err
+		}
+		for _, a := range attachments {
+			if a.ID == attachmentID {
+				path = a.Filename
+				break
+			}
+		}
+		if path == "" {
+			path = attachmentID.String()
+		}
+	}
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("saved %d bytes to %s\n", len(content), path)
+	return nil
+}
+
+func parseTwoIDs(args []string, usage string) (uuid.UUID, uuid.UUID, error) {
+	if len(args) != 2 {
+		return uuid.Nil, uuid.Nil, errors.New(usage)
+	}
+	first, err := uuid.Parse(args[0])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid id %q", args[0])
+	}
+	second, err := uuid.Parse(args[1])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid id %q", args[1])
+	}
+	return first, second, nil
+}
+
+// readContentFrom reads a note body from a file, or from stdin when path
+// is "-".
+func readContentFrom(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// envVarName derives a shell-safe variable name from a secret name.
+func envVarName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	s := b.String()
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		s = "_" + s
+	}
+	return s
+}
+
+func secretsDelete(ctx context.Context, app *App, args []string) error {
+	id, err := app.parseIDArg(args)
+	if err != nil {
+		return err
+	}
+	err = app.API.DeleteSecret(ctx, id, false)
+	if isUnreachable(err) {
+		op := storage.Operation{Kind: storage.OpDelete, SecretID: id}
+		if cached, _, ok := app.cachedSecret(id); ok {
+			op.BaseUpdatedAt = cached.UpdatedAt
+		}
+		return app.queueWrite(op)
+	}
+	if errors.Is(err, api.ErrProtected) {
+		// Protected secret: require re-entry of the master password
+		// before confirming the deletion.
+		fmt.Fprintln(os.Stderr, i18n.T("This secret is protected."))
+		password, err := readPassword(i18n.T("Re-enter master password to delete: "))
+		if err != nil {
+			return err
+		}
+		if err := app.API.VerifyPassword(ctx, password); err != nil {
+			return err
+		}
+		err = app.API.DeleteSecret(ctx, id, true)
+		if err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	fmt.Printf("deleted secret %s\n", id)
+	return nil
+}
+
+// fillPayload parses type-specific flags into the secret payload. For
+// updates the flags default to the current payload values.
+func fillPayload(secret *models.Secret, args []string) error {
+	fs := flag.NewFlagSet("secret", flag.ContinueOnError)
+	name := fs.String("name", secret.Name, "secret name")
+	metadata := fs.String("metadata", secret.Metadata, "free-form metadata")
+	protected := fs.Bool("protected", secret.Protected, "require confirmation to delete")
+
+	switch secret.Type {
+	case models.SecretTypeCredentials:
+		var cur models.CredentialsPayload
+		if secret.Data != nil {
+			if err := secret.DecodePayload(&cur); err != nil {
+				return err
+			}
+		}
+		login := fs.String("login", cur.Login, "login")
+		password := fs.String("password", cur.Password, "password")
+		url := fs.String("url", cur.URL, "website URL")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		cur.Login, cur.Password, cur.URL = *login, *password, *url
+		if err := secret.EncodePayload(&cur); err != nil {
+			return err
+		}
+	case models.SecretTypeText:
+		var cur models.TextPayload
+		if secret.Data != nil {
+			if err := secret.DecodePayload(&cur); err != nil {
+				return err
+			}
+		}
+		content := fs.String("content", cur.Content, "text content")
+		file := fs.String("file", "", "read content from this file ('-' for stdin)")
+		markdown := fs.Bool("markdown", cur.Markdown, "render content as Markdown on display")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		cur.Content = *content
+		if *file != "" {
+			data, err := readContentFrom(*file)
+			if err != nil {
+				return err
+			}
+			cur.Content = string(data)
+		}
+		cur.Markdown = *markdown
+		if err := secret.EncodePayload(&cur); err != nil {
+			return err
+		}
+	case models.SecretTypeCard:
+		var cur models.CardPayload
+		if secret.Data != nil {
+			if err := secret.DecodePayload(&cur); err != nil {
+				return err
+			}
+		}
+		number := fs.String("number", cur.Number, "card number")
+		holder := fs.String("holder", cur.Holder, "card holder")
+		expiry := fs.String("expiry", cur.Expiry, "expiry MM/YY")
+		cvv := fs.String("cvv", cur.CVV, "CVV code")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		cur.Number, cur.Holder, cur.Expiry, cur.CVV = *number, *holder, *expiry, *cvv
+		if err := secret.EncodePayload(&cur); err != nil {
+			return err
+		}
+	case models.SecretTypeAPIToken:
+		var cur models.APITokenPayload
+		if secret.Data != nil {
+			if err := secret.DecodePayload(&cur); err != nil {
+				return err
+			}
+		}
+		envVar := fs.String("env-var", cur.EnvVar, "environment variable name for `secrets env`")
+		token := fs.String("token", cur.Token, "token value")
+		scopes := fs.String("scopes", strings.Join(cur.Scopes, ","), "comma-separated token scopes")
+		curExpiry := ""
+		if cur.ExpiresAt != nil {
+			curExpiry = cur.ExpiresAt.Format(time.RFC3339)
+		}
+		expiry := fs.String("expires", curExpiry, "token expiry (RFC 3339, empty for none)")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		cur.EnvVar, cur.Token = *envVar, *token
+		cur.Scopes = nil
+		if *scopes != "" {
+			cur.Scopes = strings.Split(*scopes, ",")
+		}
+		cur.ExpiresAt = nil
+		if *expiry != "" {
+			t, err := time.Parse(time.RFC3339, *expiry)
+			if err != nil {
+				return fmt.Errorf("invalid expiry %q: %w", *expiry, err)
+			}
+			cur.ExpiresAt = &t
+		}
+		if err := secret.EncodePayload(&cur); err != nil {
+			return err
+		}
+	case models.SecretTypeRecoveryCodes:
+		codes := fs.String("codes", "", "comma-separated one-time codes")
+		file := fs.String("codes-file", "", "file with one code per line ('-' for stdin)")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		var raw []string
+		switch {
+		case *file != "":
+			data, err := readContentFrom(*file)
+			if err != nil {
+				return err
+			}
+			raw = strings.Split(strings.TrimSpace(string(data)), "\n")
+		case *codes != "":
+			raw = strings.Split(*codes, ",")
+		}
+		if len(raw) > 0 {
+			var payload models.RecoveryCodesPayload
+			for _, code := range raw {
+				if code = strings.TrimSpace(code); code != "" {
+					payload.Codes = append(payload.Codes, models.RecoveryCode{Code: code})
+				}
+			}
+			if err := secret.EncodePayload(&payload); err != nil {
+				return err
+			}
+		}
+	case models.SecretTypeKubeconfig:
+		var cur models.KubeconfigPayload
+		if secret.Data != nil {
+			if err := secret.DecodePayload(&cur); err != nil {
+				return err
+			}
+		}
+		file := fs.String("file", "", "kubeconfig file to store ('-' for stdin)")
+		kubeContext := fs.String("context", cur.Context, "context `kube use` should select")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if *file != "" {
+			data, err := readContentFrom(*file)
+			if err != nil {
+				return err
+			}
+			cur.Config = string(data)
+		}
+		cur.Context = *kubeContext
+		if err := secret.EncodePayload(&cur); err != nil {
+			return err
+		}
+	case models.SecretTypeDatabase:
+		var cur models.DatabasePayload
+		if secret.Data != nil {
+			if err := secret.DecodePayload(&cur); err != nil {
+				return err
+			}
+		}
+		engine := fs.String("engine", cur.Engine, "database engine (postgres or mysql)")
+		host := fs.String("host", cur.Host, "database host")
+		port := fs.Int("port", cur.Port, "database port (0 for the engine default)")
+		database := fs.String("db", cur.Database, "database name")
+		user := fs.String("user", cur.User, "database user")
+		password := fs.String("password", cur.Password, "database password")
+		options := fs.String("options", cur.Options, "extra connection parameters (e.g. sslmode=require)")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		switch *engine {
+		case models.DatabaseEnginePostgres, models.DatabaseEngineMySQL:
+		default:
+			return fmt.Errorf("unknown database engine %q", *engine)
+		}
+		payload := models.DatabasePayload{
+			Engine:   *engine,
+			Host:     *host,
+			Port:     *port,
+			Database: *database,
+			User:     *user,
+			Password: *password,
+			Options:  *options,
+		}
+		if err := secret.EncodePayload(&payload); err != nil {
+			return err
+		}
+	case models.SecretTypeBinary:
+		file := fs.String("file", "", "path of the file to store")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if *file != "" {
+			content, err := os.ReadFile(*file)
+			if err != nil {
+				return err
+			}
+			if err := secret.EncodePayload(&models.BinaryPayload{
+				Filename: *file,
+				Content:  content,
+			}); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown secret type %q", secret.Type)
+	}
+
+	secret.Name = *name
+	secret.Metadata = *metadata
+	secret.Protected = *protected
+	return nil
+}
+
+func printSecret(secret *models.Secret, showHistory bool) error {
+	fmt.Printf("Name:     %s\n", secret.Name)
+	fmt.Printf("Type:     %s\n", secret.Type)
+	if secret.Protected {
+		fmt.Println("Protected: yes")
+	}
+	if secret.Metadata != "" {
+		fmt.Printf("Metadata: %s\n", secret.Metadata)
+	}
+	fmt.Printf("Updated:  %s\n", secret.UpdatedAt.Local().Format(time.DateTime))
+
+	switch secret.Type {
+	case models.SecretTypeCredentials:
+		var p models.CredentialsPayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		fmt.Printf("Login:    %s\n", p.Login)
+		fmt.Printf("Password: %s\n", p.Password)
+		if p.URL != "" {
+			fmt.Printf("URL:      %s\n", p.URL)
+		}
+		if showHistory {
+			if len(p.History) == 0 {
+				fmt.Println("No previous passwords.")
+				return nil
+			}
+			fmt.Println("Previous passwords:")
+			for _, h := range p.History {
+				fmt.Printf("  %s  (replaced %s)\n", h.Password, h.ReplacedAt.Local().Format(time.DateTime))
+			}
+		}
+	case models.SecretTypeText:
+		var p models.TextPayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		if p.Markdown {
+			fmt.Print(renderMarkdown(p.Content))
+		} else {
+			fmt.Println(p.Content)
+		}
+	case models.SecretTypeCard:
+		var p models.CardPayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		fmt.Printf("Number:   %s\n", p.Number)
+		fmt.Printf("Holder:   %s\n", p.Holder)
+		fmt.Printf("Expiry:   %s\n", p.Expiry)
+		fmt.Printf("CVV:      %s\n", p.CVV)
+	case models.SecretTypeBinary:
+		var p models.BinaryPayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		fmt.Printf("File:     %s (%d bytes)\n", p.Filename, len(p.Content))
+	case models.SecretTypeRecoveryCodes:
+		var p models.RecoveryCodesPayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		fmt.Printf("Codes:    %d unused of %d\n", p.Remaining(), len(p.Codes))
+		for _, c := range p.Codes {
+			state := "unused"
+			if c.Used {
+				state = "used"
+				if c.UsedAt != nil {
+					state += " " + c.UsedAt.Local().Format(time.DateTime)
+				}
+			}
+			fmt.Printf("  %-16s %s\n", c.Code, state)
+		}
+		if p.Remaining() <= models.LowRecoveryCodesThreshold {
+			fmt.Fprintln(os.Stderr, "warning: few unused recovery codes remain")
+		}
+	case models.SecretTypeKubeconfig:
+		var p models.KubeconfigPayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		if p.Context != "" {
+			fmt.Printf("Context:  %s\n", p.Context)
+		}
+		fmt.Printf("Config:   %d bytes (use `gophkeeper kube use %s`)\n", len(p.Config), secret.ID)
+	case models.SecretTypeDatabase:
+		var p models.DatabasePayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		fmt.Printf("Engine:   %s\n", p.Engine)
+		fmt.Printf("Host:     %s\n", p.Host)
+		if p.Port != 0 {
+			fmt.Printf("Port:     %d\n", p.Port)
+		}
+		if p.Database != "" {
+			fmt.Printf("Database: %s\n", p.Database)
+		}
+		fmt.Printf("User:     %s\n", p.User)
+		fmt.Printf("Password: %s\n", p.Password)
+		if p.Options != "" {
+			fmt.Printf("Options:  %s\n", p.Options)
+		}
+		fmt.Printf("Connect:  gophkeeper secrets connect %s\n", secret.ID)
+	case models.SecretTypeAPIToken:
+		var p models.APITokenPayload
+		if err := secret.DecodePayload(&p); err != nil {
+			return err
+		}
+		fmt.Printf("Env var:  %s\n", p.EnvVar)
+		fmt.Printf("Token:    %s\n", p.Token)
+		if len(p.Scopes) > 0 {
+			fmt.Printf("Scopes:   %s\n", strings.Join(p.Scopes, ", "))
+		}
+		if p.ExpiresAt != nil {
+			fmt.Printf("Expires:  %s\n", p.ExpiresAt.Local().Format(time.DateTime))
+			if p.Expired(time.Now()) {
+				fmt.Fprintln(os.Stderr, "warning: token has expired")
+			}
+		}
+	}
+	return nil
+}
+
+func printWarnings(warnings []string) {
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+}
+
+// parseIDArg parses the single secret argument of a subcommand. A value
+// that is not a UUID is resolved as a secret name against the local cache
+// when it matches exactly one cached secret.
+func (a *App) parseIDArg(args []string) (uuid.UUID, error) {
+	if len(args) != 1 {
+		return uuid.Nil, errors.New("expected exactly one secret id or name argument")
+	}
+	if id, err := uuid.Parse(args[0]); err == nil {
+		return id, nil
+	}
+	if err := a.unlockStorage(true); err != nil {
+		return uuid.Nil, err
+	}
+	secrets, _, err := a.Storage.Load()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	var matches []uuid.UUID
+	for i := range secrets {
+		if secrets[i].Name == args[0] {
+			matches = append(matches, secrets[i].ID)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return uuid.Nil, fmt.Errorf("no secret named %q in the local cache; pass an id", args[0])
+	default:
+		return uuid.Nil, fmt.Errorf("%d secrets are named %q; pass an id", len(matches), args[0])
+	}
+}
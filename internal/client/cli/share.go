@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// secretsShare handles `secrets share <id|name> <login> [read|write]`.
+func secretsShare(ctx context.Context, app *App, args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return errors.New("usage: gophkeeper secrets share <id|name> <login> [read|write]")
+	}
+	id, err := app.parseIDArg(args[:1])
+	if err != nil {
+		return err
+	}
+	mode := models.ShareModeRead
+	if len(args) == 3 {
+		mode = args[2]
+	}
+	share, err := app.API.ShareSecret(ctx, id, args[1], mode)
+	if err != nil {
+		return err
+	}
+	if done, err := app.emit(share); done {
+		return err
+	}
+	fmt.Printf("shared %s with %s (%s)\n", id, share.Login, share.Mode)
+	return nil
+}
+
+// secretsUnshare handles `secrets unshare <id|name> <login>`.
+func secretsUnshare(ctx context.Context, app *App, args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: gophkeeper secrets unshare <id|name> <login>")
+	}
+	id, err := app.parseIDArg(args[:1])
+	if err != nil {
+		return err
+	}
+	if err := app.API.UnshareSecret(ctx, id, args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("revoked %s's access to %s\n", args[1], id)
+	return nil
+}
+
+// secretsShares handles `secrets shares <id|name>`, listing who a secret
+// is shared with.
+func secretsShares(ctx context.Context, app *App, args []string) error {
+	id, err := app.parseIDArg(args)
+	if err != nil {
+		return err
+	}
+	shares, err := app.API.ListShares(ctx, id)
+	if err != nil {
+		return err
+	}
+	if done, err := app.emit(shares); done {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LOGIN\tMODE\tSINCE")
+	for _, s := range shares {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Login, s.Mode, s.CreatedAt.Format("2006-01-02"))
+	}
+	return w.Flush()
+}
+
+// secretsLink handles `secrets link <id|name> [ttl]`, minting a one-time
+// public link for someone without an account. ttl is a Go duration like
+// 24h; omitted picks the server default.
+func secretsLink(ctx context.Context, app *App, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New("usage: gophkeeper secrets link <id|name> [ttl]")
+	}
+	id, err := app.parseIDArg(args[:1])
+	if err != nil {
+		return err
+	}
+	var ttl time.Duration
+	if len(args) == 2 {
+		if ttl, err = time.ParseDuration(args[1]); err != nil {
+			return fmt.Errorf("invalid ttl %q: %w", args[1], err)
+		}
+	}
+	link, err := app.API.CreateShareLink(ctx, id, ttl)
+	if err != nil {
+		return err
+	}
+	if done, err := app.emit(link); done {
+		return err
+	}
+	fmt.Println(app.API.BaseURL() + link.Path)
+	fmt.Printf("single use, expires %s\n", link.ExpiresAt.UTC().Format("2006-01-02 15:04 UTC"))
+	return nil
+}
+
+// secretsShared handles `secrets shared [id]`: without arguments it lists
+// the secrets other accounts share with this one, with an ID it shows one.
+func secretsShared(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		shared, err := app.API.SharedSecrets(ctx)
+		if err != nil {
+			return err
+		}
+		if done, err := app.emit(shared); done {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tTYPE\tOWNER\tMODE")
+		for _, s := range shared {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Secret.ID, s.Secret.Name, s.Secret.Type, s.Owner, s.Mode)
+		}
+		return w.Flush()
+	}
+	id, err := app.parseIDArg(args)
+	if err != nil {
+		return err
+	}
+	shared, err := app.API.GetSharedSecret(ctx, id)
+	if err != nil {
+		return err
+	}
+	if done, err := app.emit(shared); done {
+		return err
+	}
+	fmt.Printf("Shared by %s (%s)\n", shared.Owner, shared.Mode)
+	return printSecret(&shared.Secret, false)
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"github.com/alexuryumtsev/gophkeeper/internal/client/credstore"
+	"github.com/alexuryumtsev/gophkeeper/internal/client/storage"
+)
+
+// unlockStorage makes the encrypted local cache readable, using the
+// credential store when the master password was saved there and prompting
+// otherwise. Non-interactive callers (shell completion) get
+// storage.ErrLocked instead of a prompt.
+func (a *App) unlockStorage(interactive bool) error {
+	if !a.Storage.Locked() {
+		return nil
+	}
+	if saved, err := a.Creds.Get(credstore.MasterPassword); err == nil {
+		a.Storage.SetPassphrase(saved)
+		if _, _, err := a.Storage.Load(); err == nil {
+			return nil
+		}
+		a.Storage.SetPassphrase("")
+	}
+	if !interactive {
+		return storage.ErrLocked
+	}
+	password, err := readPassword("Master password to unlock local cache: ")
+	if err != nil {
+		return err
+	}
+	a.Storage.SetPassphrase(password)
+	if _, _, err := a.Storage.Load(); err != nil {
+		a.Storage.SetPassphrase("")
+		return err
+	}
+	return nil
+}
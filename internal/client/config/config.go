@@ -0,0 +1,97 @@
+// Package config loads the client configuration.
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config holds the client configuration.
+type Config struct {
+	// ServerURL is the base URL of the gophkeeper server.
+	ServerURL string
+	// DataDir is where the client keeps its token and local cache.
+	DataDir string
+	// ClientCertPath and ClientKeyPath hold the certificate presented to
+	// servers enforcing mutual TLS. Both must be set together.
+	ClientCertPath string
+	ClientKeyPath  string
+	// CAPath is a PEM bundle that replaces the system roots when the
+	// server's certificate comes from a private CA.
+	CAPath string
+	// TLSPins are hex-encoded SHA-256 public key fingerprints the server
+	// must match; see the pin command of the server for obtaining one.
+	TLSPins []string
+	// InsecureSkipVerify disables server certificate verification.
+	// Development only.
+	InsecureSkipVerify bool
+
+	// baseDir is the data directory before any profile overlay; the
+	// profiles file lives there.
+	baseDir string
+}
+
+// Load builds the client configuration from environment variables with
+// sensible defaults.
+func Load() (*Config, error) {
+	cfg := &Config{
+		ServerURL: "http://localhost:8080",
+	}
+	if v := os.Getenv("GOPHKEEPER_SERVER"); v != "" {
+		cfg.ServerURL = v
+	}
+	if v := os.Getenv("GOPHKEEPER_DATA_DIR"); v != "" {
+		cfg.DataDir = v
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		cfg.DataDir = filepath.Join(home, ".gophkeeper")
+	}
+	cfg.baseDir = cfg.DataDir
+	cfg.ClientCertPath = os.Getenv("GOPHKEEPER_CLIENT_CERT")
+	cfg.ClientKeyPath = os.Getenv("GOPHKEEPER_CLIENT_KEY")
+	cfg.CAPath = os.Getenv("GOPHKEEPER_CA_CERT")
+	if v := os.Getenv("GOPHKEEPER_TLS_PINS"); v != "" {
+		for _, pin := range strings.Split(v, ",") {
+			if pin = strings.TrimSpace(pin); pin != "" {
+				cfg.TLSPins = append(cfg.TLSPins, pin)
+			}
+		}
+	}
+	cfg.InsecureSkipVerify = os.Getenv("GOPHKEEPER_INSECURE_SKIP_VERIFY") == "true"
+	if err := os.MkdirAll(cfg.DataDir, 0o700); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// TokenPath is the file the access token is stored in.
+func (c *Config) TokenPath() string { return filepath.Join(c.DataDir, "token") }
+
+// CachePath is the file the local secrets cache is stored in.
+func (c *Config) CachePath() string { return filepath.Join(c.DataDir, "cache.json") }
+
+// PluginsDir is the directory scanned for plugin executables.
+func (c *Config) PluginsDir() string { return filepath.Join(c.DataDir, "plugins") }
+
+// PinPath is the file the pinned server identity fingerprint is stored in.
+func (c *Config) PinPath() string { return filepath.Join(c.DataDir, "server.pin") }
+
+// HintPath is the file the cached vault hint is stored in.
+func (c *Config) HintPath() string { return filepath.Join(c.DataDir, "hint") }
+
+// JournalPath is the file queueing writes made while offline.
+func (c *Config) JournalPath() string { return filepath.Join(c.DataDir, "journal.json") }
+
+// ActivityPath is the file whose modification time records the last client
+// activity, for the idle auto-lock.
+func (c *Config) ActivityPath() string { return filepath.Join(c.DataDir, "last-activity") }
+
+// SocketPath is the Unix socket the sync daemon listens on.
+func (c *Config) SocketPath() string { return filepath.Join(c.DataDir, "daemon.sock") }
+
+// DeviceIDPath is the file holding this client's registered device ID.
+func (c *Config) DeviceIDPath() string { return filepath.Join(c.DataDir, "device-id") }
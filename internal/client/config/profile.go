@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Profile is a named server/account combination. Each profile keeps its
+// own data directory, so tokens and local caches never mix.
+type Profile struct {
+	// ServerURL overrides the server for this profile; empty keeps the
+	// default.
+	ServerURL string `json:"server_url,omitempty"`
+	// DataDir overrides where the profile keeps its token and cache; empty
+	// means a per-profile directory under the default data directory.
+	DataDir string `json:"data_dir,omitempty"`
+}
+
+// ProfileSet is the saved profiles and which one is active when no
+// --profile flag is given.
+type ProfileSet struct {
+	Current  string             `json:"current,omitempty"`
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+}
+
+// ProfilesPath is the file the profiles are stored in. It lives in the
+// default data directory so every profile sees the same set.
+func (c *Config) ProfilesPath() string { return filepath.Join(c.baseDir, "profiles.json") }
+
+// ProfileDataDir is the default data directory of the named profile.
+func (c *Config) ProfileDataDir(name string) string {
+	return filepath.Join(c.baseDir, "profiles", name)
+}
+
+// LoadProfiles reads the profiles file. A missing file yields an empty
+// set.
+func LoadProfiles(path string) (*ProfileSet, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &ProfileSet{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var set ProfileSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// Save writes the profiles file.
+func (p *ProfileSet) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// UseProfile overlays the named profile onto the configuration. An empty
+// name falls back to the profile marked current; if there is none either,
+// the configuration stays as loaded.
+func (c *Config) UseProfile(name string) error {
+	set, err := LoadProfiles(c.ProfilesPath())
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = set.Current
+	}
+	if name == "" {
+		return nil
+	}
+	p, ok := set.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	if p.ServerURL != "" {
+		c.ServerURL = p.ServerURL
+	}
+	c.DataDir = p.DataDir
+	if c.DataDir == "" {
+		c.DataDir = c.ProfileDataDir(name)
+	}
+	return os.MkdirAll(c.DataDir, 0o700)
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Settings are persistent client preferences, kept separate from the
+// environment-driven Config so commands can rewrite them.
+type Settings struct {
+	// ListColumns are the default columns of `secrets list`. Empty means
+	// the built-in default.
+	ListColumns []string `json:"list_columns,omitempty"`
+	// Aliases maps a shorthand command name to the words it expands to,
+	// e.g. "ls" -> "secrets list".
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// LockAfterMinutes wipes the remembered master password after this
+	// much idle time. 0 uses the default; negative disables auto-lock.
+	LockAfterMinutes int `json:"lock_after_minutes,omitempty"`
+	// E2E seals secret payloads with the master password before upload, so
+	// the server only ever stores ciphertext it cannot read.
+	E2E bool `json:"e2e,omitempty"`
+	// KDFTime, KDFMemoryKiB and KDFThreads tune the Argon2id cost of
+	// passphrase-derived keys, e.g. down on slow devices. 0 keeps the
+	// built-in default for that parameter. Changing them only affects
+	// newly sealed data; existing data records its own parameters.
+	KDFTime      int `json:"kdf_time,omitempty"`
+	KDFMemoryKiB int `json:"kdf_memory_kib,omitempty"`
+	KDFThreads   int `json:"kdf_threads,omitempty"`
+	// Cipher selects the cipher newly sealed data is written with:
+	// "aes-gcm" (the default) or "xchacha20-poly1305", which is faster on
+	// devices without AES hardware. Existing data opens regardless.
+	Cipher string `json:"cipher,omitempty"`
+}
+
+// SettingsPath is the file the client preferences are stored in.
+func (c *Config) SettingsPath() string { return filepath.Join(c.DataDir, "settings.json") }
+
+// LoadSettings reads the preferences file. A missing file yields zero-value
+// settings.
+func LoadSettings(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Settings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes the preferences file.
+func (s *Settings) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,31 @@
+// Package credstore abstracts where client credentials — the session
+// token and, optionally, the master password — are kept: the platform
+// keychain when one is available, files in the data directory otherwise.
+package credstore
+
+import "errors"
+
+// Names of the credentials the client stores.
+const (
+	Token          = "token"
+	MasterPassword = "master-password"
+)
+
+// ErrNotFound is returned when a credential has not been stored.
+var ErrNotFound = errors.New("credstore: not found")
+
+// Store keeps named credentials.
+type Store interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+}
+
+// Open returns the platform keychain when one is usable, falling back to
+// plain files under dir.
+func Open(dir string) Store {
+	if kc := openKeychain(); kc != nil {
+		return kc
+	}
+	return &fileStore{dir: dir}
+}
@@ -0,0 +1,42 @@
+package credstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileStore keeps credentials in files under the data directory, one per
+// name, readable only by the owner. It is the fallback when no platform
+// keychain is available.
+type fileStore struct {
+	dir string
+}
+
+func (s *fileStore) path(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+func (s *fileStore) Get(name string) (string, error) {
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (s *fileStore) Set(name, value string) error {
+	return os.WriteFile(s.path(name), []byte(value+"\n"), 0o600)
+}
+
+func (s *fileStore) Delete(name string) error {
+	err := os.Remove(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
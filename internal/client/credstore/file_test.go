@@ -0,0 +1,43 @@
+package credstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	s := &fileStore{dir: dir}
+
+	if _, err := s.Get(Token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get before Set = %v, want ErrNotFound", err)
+	}
+
+	if err := s.Set(Token, "abc123"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err := s.Get(Token)
+	if err != nil || got != "abc123" {
+		t.Errorf("Get = %q, %v; want abc123", got, err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, Token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("credential file mode = %o, want 600", perm)
+	}
+
+	if err := s.Delete(Token); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(Token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(Token); err != nil {
+		t.Errorf("Delete of absent credential = %v, want nil", err)
+	}
+}
@@ -0,0 +1,78 @@
+package credstore
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// service is the account/service label credentials are filed under in the
+// platform keychain.
+const service = "gophkeeper"
+
+// openKeychain returns the platform keychain store, or nil when none is
+// usable on this system.
+func openKeychain() Store {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return macKeychain{}
+		}
+	case "linux":
+		if _, err := exec.LookPath("secret-tool"); err == nil {
+			return libsecret{}
+		}
+	}
+	return nil
+}
+
+// macKeychain drives the macOS Keychain through the security(1) tool.
+type macKeychain struct{}
+
+func (macKeychain) Get(name string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password",
+		"-s", service, "-a", name, "-w").Output()
+	if err != nil {
+		return "", ErrNotFound
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (macKeychain) Set(name, value string) error {
+	// -U updates an existing item instead of failing on a duplicate.
+	return exec.Command("security", "add-generic-password",
+		"-U", "-s", service, "-a", name, "-w", value).Run()
+}
+
+func (macKeychain) Delete(name string) error {
+	// Deleting an absent item fails; that is fine.
+	_ = exec.Command("security", "delete-generic-password",
+		"-s", service, "-a", name).Run()
+	return nil
+}
+
+// libsecret drives the freedesktop secret service through secret-tool(1).
+type libsecret struct{}
+
+func (libsecret) Get(name string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup",
+		"service", service, "name", name).Output()
+	if err != nil {
+		return "", ErrNotFound
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (libsecret) Set(name, value string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", service+" "+name,
+		"service", service, "name", name)
+	cmd.Stdin = bytes.NewBufferString(value)
+	return cmd.Run()
+}
+
+func (libsecret) Delete(name string) error {
+	_ = exec.Command("secret-tool", "clear",
+		"service", service, "name", name).Run()
+	return nil
+}
@@ -0,0 +1,74 @@
+// Package i18n translates the client's user-facing strings. The catalog
+// is keyed by the English text, so call sites stay readable and any
+// string without a translation falls through to English unchanged. New
+// strings are added to the catalog as the commands using them are
+// touched.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+var locale = "en"
+
+// Detect picks the locale from GOPHKEEPER_LOCALE, falling back to the
+// standard LANG variable.
+func Detect() {
+	if v := os.Getenv("GOPHKEEPER_LOCALE"); v != "" {
+		SetLocale(v)
+		return
+	}
+	SetLocale(os.Getenv("LANG"))
+}
+
+// SetLocale selects the language. It accepts both a bare code ("ru") and
+// a full LANG value ("ru_RU.UTF-8"); unknown languages keep English.
+func SetLocale(value string) {
+	lang, _, _ := strings.Cut(value, "_")
+	lang, _, _ = strings.Cut(lang, ".")
+	lang = strings.ToLower(lang)
+	if lang == "en" || catalogs[lang] != nil {
+		locale = lang
+	}
+}
+
+// Locale returns the selected language code.
+func Locale() string { return locale }
+
+// T returns the translation of the English message msg in the selected
+// locale, or msg itself when there is none.
+func T(msg string) string {
+	if translated, ok := catalogs[locale][msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// Tf is T for format strings: it translates format, then applies the
+// arguments.
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// catalogs holds the translations per language, keyed by the English
+// text.
+var catalogs = map[string]map[string]string{
+	"ru": {
+		"Usage: gophkeeper <command> [arguments]": "Использование: gophkeeper <команда> [аргументы]",
+		"Commands:":                                      "Команды:",
+		"no command given":                               "команда не указана",
+		"unknown command %q":                             "неизвестная команда %q",
+		"This secret is protected.":                      "Этот секрет защищён.",
+		"Re-enter master password to delete: ":           "Повторите мастер-пароль для удаления: ",
+		"warning: server unreachable; using local cache": "внимание: сервер недоступен; используется локальный кэш",
+		"warning: server unreachable; using local cache (stale since %s)": "внимание: сервер недоступен; используется локальный кэш (устарел с %s)",
+		"Password: ":            "Пароль: ",
+		"not found":             "не найдено",
+		"already exists":        "уже существует",
+		"invalid credentials":   "неверные учётные данные",
+		"permission denied":     "доступ запрещён",
+		"the request timed out": "истекло время ожидания запроса",
+	},
+}
@@ -0,0 +1,124 @@
+// Package plugin discovers external executables that extend the CLI with
+// new subcommands and secret-type renderers, so niche formats can be
+// supported without patching the core client.
+//
+// A plugin is an executable named gophkeeper-<name> in the plugins
+// directory. Invoked with the single argument "describe" it must print a
+// JSON Manifest on stdout. Declared commands are run as
+// `gophkeeper-<name> run <command> [args...]`; declared renderers as
+// `gophkeeper-<name> render <secret-type>` with the payload JSON on stdin.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// prefix marks executables in the plugins directory as plugins.
+const prefix = "gophkeeper-"
+
+// describeTimeout bounds how long a plugin may take to print its manifest.
+const describeTimeout = 5 * time.Second
+
+// CommandSpec declares one subcommand a plugin adds to the CLI.
+type CommandSpec struct {
+	Name        string `json:"name"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+}
+
+// Manifest is the self-description a plugin prints for "describe".
+type Manifest struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Commands    []CommandSpec `json:"commands,omitempty"`
+	// Renderers lists the secret types the plugin can render.
+	Renderers []string `json:"renderers,omitempty"`
+}
+
+// Plugin is one discovered executable.
+type Plugin struct {
+	Path     string
+	Manifest Manifest
+}
+
+// Discover loads the manifests of all plugins in dir. A missing directory
+// yields no plugins; a plugin that fails to describe itself is skipped with
+// a warning, never breaking the client.
+func Discover(ctx context.Context, dir string) ([]Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var plugins []Plugin
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Mode()&0o111 == 0 {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		manifest, err := describe(ctx, path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: skipping plugin %s: %v\n", entry.Name(), err)
+			continue
+		}
+		if manifest.Name == "" {
+			manifest.Name = strings.TrimPrefix(entry.Name(), prefix)
+		}
+		plugins = append(plugins, Plugin{Path: path, Manifest: manifest})
+	}
+	return plugins, nil
+}
+
+func describe(ctx context.Context, path string) (Manifest, error) {
+	ctx, cancel := context.WithTimeout(ctx, describeTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "describe").Output()
+	if err != nil {
+		return Manifest{}, fmt.Errorf("describe: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(out, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("bad manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// RunCommand executes one of the plugin's declared commands with the
+// terminal attached. env entries are appended to the client's environment.
+func (p *Plugin) RunCommand(ctx context.Context, command string, args []string, env []string) error {
+	cmd := exec.CommandContext(ctx, p.Path, append([]string{"run", command}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// Render pipes a payload through the plugin's renderer for the given secret
+// type and returns what it printed.
+func (p *Plugin) Render(ctx context.Context, secretType string, payload []byte) (string, error) {
+	cmd := exec.CommandContext(ctx, p.Path, "render", secretType)
+	cmd.Stdin = bytes.NewReader(payload)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("render %s: %s", secretType, msg)
+		}
+		return "", fmt.Errorf("render %s: %w", secretType, err)
+	}
+	return string(out), nil
+}
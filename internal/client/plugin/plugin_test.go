@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fixture is a minimal plugin speaking the describe/render protocol.
+const fixture = `#!/bin/sh
+case "$1" in
+describe)
+	echo '{"name":"kube","description":"kubeconfig helper","commands":[{"name":"kube","usage":"kube","description":"kube things"}],"renderers":["kubeconfig"]}'
+	;;
+render)
+	echo "rendered $2: $(cat)"
+	;;
+esac
+`
+
+func writeFixture(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverAndRender(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "gophkeeper-kube", fixture)
+	// Ignored: wrong prefix and not executable.
+	writeFixture(t, dir, "unrelated", fixture)
+	if err := os.WriteFile(filepath.Join(dir, "gophkeeper-plain"), []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, err := Discover(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if len(plugins) != 1 {
+		t.Fatalf("got %d plugins, want 1", len(plugins))
+	}
+	p := plugins[0]
+	if p.Manifest.Name != "kube" || len(p.Manifest.Commands) != 1 {
+		t.Fatalf("manifest = %+v", p.Manifest)
+	}
+
+	out, err := p.Render(context.Background(), "kubeconfig", []byte("payload"))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if out != "rendered kubeconfig: payload\n" {
+		t.Fatalf("render output = %q", out)
+	}
+}
+
+func TestDiscoverMissingDir(t *testing.T) {
+	plugins, err := Discover(context.Background(), filepath.Join(t.TempDir(), "nope"))
+	if err != nil || plugins != nil {
+		t.Fatalf("missing dir: plugins=%v err=%v, want none", plugins, err)
+	}
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// OperationKind discriminates journaled write operations.
+type OperationKind string
+
+// Kinds of journaled operations.
+const (
+	OpCreate OperationKind = "create"
+	OpUpdate OperationKind = "update"
+	OpDelete OperationKind = "delete"
+)
+
+// Operation is one write performed while the server was unreachable,
+// persisted for replay on reconnect.
+type Operation struct {
+	Kind     OperationKind `json:"kind"`
+	QueuedAt time.Time     `json:"queued_at"`
+	// BaseUpdatedAt is the server state the operation was made against;
+	// replay reports a conflict when the server has moved past it.
+	BaseUpdatedAt time.Time      `json:"base_updated_at,omitempty"`
+	SecretID      uuid.UUID      `json:"secret_id,omitempty"`
+	Secret        *models.Secret `json:"secret,omitempty"`
+}
+
+// journalFile is the on-disk layout of the journal.
+type journalFile struct {
+	Version    int         `json:"version"`
+	Operations []Operation `json:"operations"`
+}
+
+// Journal is a JSON file queueing offline writes in order.
+type Journal struct {
+	path string
+}
+
+// NewJournal creates a Journal at path. The file is created on first
+// append and removed when the queue drains.
+func NewJournal(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Append adds an operation to the end of the queue.
+func (j *Journal) Append(op Operation) error {
+	ops, err := j.Load()
+	if err != nil {
+		return err
+	}
+	return j.Save(append(ops, op))
+}
+
+// Load returns the queued operations in order. A missing journal yields an
+// empty list.
+func (j *Journal) Load() ([]Operation, error) {
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f journalFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return f.Operations, nil
+}
+
+// Save replaces the queue; an empty queue removes the file.
+func (j *Journal) Save(ops []Operation) error {
+	if len(ops) == 0 {
+		err := os.Remove(j.path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	data, err := json.MarshalIndent(journalFile{Version: FormatVersion, Operations: ops}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.path, data, 0o600)
+}
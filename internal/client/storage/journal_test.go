@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+func TestJournalRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	j := NewJournal(path)
+
+	ops, err := j.Load()
+	if err != nil || len(ops) != 0 {
+		t.Fatalf("Load on missing file = %v, %v; want empty", ops, err)
+	}
+
+	id := uuid.New()
+	if err := j.Append(Operation{Kind: OpCreate, QueuedAt: time.Now(), Secret: &models.Secret{Name: "a"}}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := j.Append(Operation{Kind: OpDelete, QueuedAt: time.Now(), SecretID: id}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	ops, err = j.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(ops) != 2 || ops[0].Kind != OpCreate || ops[1].SecretID != id {
+		t.Fatalf("Load = %+v, want create then delete of %s", ops, id)
+	}
+
+	// Draining the queue removes the file.
+	if err := j.Save(nil); err != nil {
+		t.Fatalf("Save(nil): %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("journal file still exists after drain: %v", err)
+	}
+}
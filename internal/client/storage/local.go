@@ -0,0 +1,180 @@
+// Package storage implements the local client-side cache of secrets used
+// for listing without a round-trip and as groundwork for offline access.
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// FormatVersion is bumped when the JSON layout inside the cache changes.
+const FormatVersion = 1
+
+// On-disk container formats of the cache, in migration order. A future
+// version may move to a database file once the client grows one.
+const (
+	FormatJSON      = 1 // plain JSON
+	FormatEncrypted = 2 // JSON sealed with the master password
+)
+
+// cacheMagic marks an encrypted cache file; everything after it is sealed
+// with the master password.
+var cacheMagic = []byte("GKCACHE1")
+
+// ErrLocked is returned when the cache on disk is encrypted and no
+// passphrase has been provided yet.
+var ErrLocked = errors.New("storage: cache is locked")
+
+// LocalStorage is a file holding the last known server state, sealed with
+// the master password once one has been provided.
+type LocalStorage struct {
+	path       string
+	passphrase string
+}
+
+// cacheFile is the on-disk layout of the cache.
+type cacheFile struct {
+	Version  int             `json:"version"`
+	SyncedAt time.Time       `json:"synced_at"`
+	Secrets  []models.Secret `json:"secrets"`
+}
+
+// NewLocalStorage creates a LocalStorage at path. The file is created on
+// first save.
+func NewLocalStorage(path string) *LocalStorage {
+	return &LocalStorage{path: path}
+}
+
+// SetPassphrase provides the master password the cache is sealed with.
+func (s *LocalStorage) SetPassphrase(passphrase string) { s.passphrase = passphrase }
+
+// Locked reports whether the cache on disk is encrypted and cannot be read
+// until SetPassphrase is called.
+func (s *LocalStorage) Locked() bool {
+	if s.passphrase != "" {
+		return false
+	}
+	data, err := os.ReadFile(s.path)
+	return err == nil && bytes.HasPrefix(data, cacheMagic)
+}
+
+// Save replaces the cached secrets, sealed with the passphrase when one is
+// set. Without a passphrase an already-encrypted cache is left alone
+// rather than downgraded to plaintext.
+func (s *LocalStorage) Save(secrets []models.Secret) error {
+	if s.passphrase == "" && s.Locked() {
+		return ErrLocked
+	}
+	f := cacheFile{
+		Version:  FormatVersion,
+		SyncedAt: time.Now().UTC(),
+		Secrets:  secrets,
+	}
+	return s.write(f, s.passphrase != "")
+}
+
+// write stores the cache file in the requested container format.
+func (s *LocalStorage) write(f cacheFile, encrypted bool) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		return os.WriteFile(s.path, data, 0o600)
+	}
+	sealed, err := crypto.SealWithPassphrase(s.passphrase, data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(append([]byte(nil), cacheMagic...), sealed...), 0o600)
+}
+
+// Version reports the container format of the cache on disk, or 0 when no
+// cache exists.
+func (s *LocalStorage) Version() (int, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if bytes.HasPrefix(data, cacheMagic) {
+		return FormatEncrypted, nil
+	}
+	return FormatJSON, nil
+}
+
+// Migrate converts the cache to container format to, keeping the previous
+// file as <path>.v<N>.bak so a bad migration never loses the cache. It
+// returns the backup path. Migrating to FormatEncrypted requires the
+// passphrase; migrating back to FormatJSON writes plaintext and exists for
+// downgrades only.
+func (s *LocalStorage) Migrate(to int) (string, error) {
+	if to != FormatJSON && to != FormatEncrypted {
+		return "", fmt.Errorf("unknown cache format version %d", to)
+	}
+	from, err := s.Version()
+	if err != nil {
+		return "", err
+	}
+	if from == 0 {
+		return "", errors.New("no cache file to migrate")
+	}
+	if from == to {
+		return "", nil
+	}
+	if to == FormatEncrypted && s.passphrase == "" {
+		return "", ErrLocked
+	}
+	secrets, syncedAt, err := s.Load()
+	if err != nil {
+		return "", err
+	}
+	backup := fmt.Sprintf("%s.v%d.bak", s.path, from)
+	if err := os.Rename(s.path, backup); err != nil {
+		return "", err
+	}
+	f := cacheFile{Version: FormatVersion, SyncedAt: syncedAt, Secrets: secrets}
+	if err := s.write(f, to == FormatEncrypted); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// Load returns the cached secrets and the time they were synced. A missing
+// cache file yields an empty list; an encrypted one requires the
+// passphrase first.
+func (s *LocalStorage) Load() ([]models.Secret, time.Time, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if bytes.HasPrefix(data, cacheMagic) {
+		if s.passphrase == "" {
+			return nil, time.Time{}, ErrLocked
+		}
+		data, err = crypto.OpenWithPassphrase(s.passphrase, data[len(cacheMagic):])
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("unlock cache file %s (wrong master password?): %w", s.path, err)
+		}
+	}
+	var f cacheFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, time.Time{}, fmt.Errorf("corrupt cache file %s: %w", s.path, err)
+	}
+	if f.Version != FormatVersion {
+		return nil, time.Time{}, fmt.Errorf("unsupported cache version %d in %s", f.Version, s.path)
+	}
+	return f.Secrets, f.SyncedAt, nil
+}
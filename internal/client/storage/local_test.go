@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+func TestEncryptedCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	s := NewLocalStorage(path)
+	s.SetPassphrase("master password")
+
+	secrets := []models.Secret{{Name: "example.com", Type: models.SecretTypeCredentials, Data: []byte(`{"login":"user"}`)}}
+	if err := s.Save(secrets); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(raw, cacheMagic) {
+		t.Fatal("cache file is missing the encryption magic")
+	}
+	if bytes.Contains(raw, []byte("example.com")) {
+		t.Error("cache file contains plaintext secret data")
+	}
+
+	got, _, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "example.com" {
+		t.Errorf("Load = %+v, want the saved secret", got)
+	}
+}
+
+func TestLockedCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	s := NewLocalStorage(path)
+	s.SetPassphrase("master password")
+	if err := s.Save([]models.Secret{{Name: "a"}}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// A fresh process without the passphrase must neither read the cache
+	// nor overwrite it with plaintext.
+	locked := NewLocalStorage(path)
+	if !locked.Locked() {
+		t.Error("Locked = false, want true")
+	}
+	if _, _, err := locked.Load(); !errors.Is(err, ErrLocked) {
+		t.Errorf("Load = %v, want ErrLocked", err)
+	}
+	if err := locked.Save([]models.Secret{{Name: "b"}}); !errors.Is(err, ErrLocked) {
+		t.Errorf("Save = %v, want ErrLocked", err)
+	}
+
+	locked.SetPassphrase("wrong password")
+	if _, _, err := locked.Load(); err == nil {
+		t.Error("Load with wrong passphrase succeeded")
+	}
+}
+
+func TestCacheMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	plain := NewLocalStorage(path)
+	if err := plain.Save([]models.Secret{{Name: "example.com"}}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if v, err := plain.Version(); err != nil || v != FormatJSON {
+		t.Fatalf("Version = %d, %v; want FormatJSON", v, err)
+	}
+
+	// Forward migration needs the passphrase and keeps a backup.
+	if _, err := plain.Migrate(FormatEncrypted); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Migrate without passphrase = %v, want ErrLocked", err)
+	}
+	plain.SetPassphrase("master password")
+	backup, err := plain.Migrate(FormatEncrypted)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if backup != path+".v1.bak" {
+		t.Errorf("backup = %q, want %q", backup, path+".v1.bak")
+	}
+	if _, err := os.Stat(backup); err != nil {
+		t.Errorf("backup file missing: %v", err)
+	}
+	if v, _ := plain.Version(); v != FormatEncrypted {
+		t.Errorf("Version after migration = %d, want FormatEncrypted", v)
+	}
+	secrets, _, err := plain.Load()
+	if err != nil || len(secrets) != 1 || secrets[0].Name != "example.com" {
+		t.Errorf("Load after migration = %+v, %v", secrets, err)
+	}
+
+	// Migrating to the current format is a no-op.
+	if backup, err := plain.Migrate(FormatEncrypted); err != nil || backup != "" {
+		t.Errorf("no-op Migrate = %q, %v", backup, err)
+	}
+}
@@ -0,0 +1,141 @@
+// Package password generates random passwords and passphrases using
+// crypto/rand. It backs the `generate` CLI command and is reusable by any
+// feature that needs to propose a credential.
+package password
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// Character classes for Options.
+const (
+	Lower   = "abcdefghijklmnopqrstuvwxyz"
+	Upper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	Digits  = "0123456789"
+	Symbols = "!@#$%^&*()-_=+[]{};:,.<>?"
+)
+
+// Options selects the shape of a generated password.
+type Options struct {
+	Length int
+	// Classes to draw from; all four when none is set.
+	Lower, Upper, Digits, Symbols bool
+}
+
+// DefaultLength is used when Options.Length is zero.
+const DefaultLength = 20
+
+// Generate builds a random password. Every selected character class is
+// guaranteed to appear at least once when the length allows it.
+func Generate(opts Options) (string, error) {
+	if opts.Length == 0 {
+		opts.Length = DefaultLength
+	}
+	if opts.Length < 4 {
+		return "", errors.New("password length must be at least 4")
+	}
+	if !opts.Lower && !opts.Upper && !opts.Digits && !opts.Symbols {
+		opts.Lower, opts.Upper, opts.Digits, opts.Symbols = true, true, true, true
+	}
+	var classes []string
+	for _, c := range []struct {
+		enabled bool
+		chars   string
+	}{{opts.Lower, Lower}, {opts.Upper, Upper}, {opts.Digits, Digits}, {opts.Symbols, Symbols}} {
+		if c.enabled {
+			classes = append(classes, c.chars)
+		}
+	}
+	alphabet := strings.Join(classes, "")
+
+	out := make([]byte, opts.Length)
+	// One character from each class first, the rest from the full alphabet.
+	for i := range out {
+		source := alphabet
+		if i < len(classes) {
+			source = classes[i]
+		}
+		n, err := randInt(len(source))
+		if err != nil {
+			return "", err
+		}
+		out[i] = source[n]
+	}
+	if err := shuffle(out); err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// vowels and consonants build pronounceable alternating syllables.
+const (
+	vowels     = "aeiou"
+	consonants = "bcdfghjklmnprstvwz"
+)
+
+// Pronounceable builds a password of alternating consonants and vowels that
+// is easier to read out or type on a phone. It trades entropy for usability;
+// prefer Generate for high-value accounts.
+func Pronounceable(length int) (string, error) {
+	if length == 0 {
+		length = DefaultLength
+	}
+	if length < 4 {
+		return "", errors.New("password length must be at least 4")
+	}
+	out := make([]byte, length)
+	for i := range out {
+		source := consonants
+		if i%2 == 1 {
+			source = vowels
+		}
+		n, err := randInt(len(source))
+		if err != nil {
+			return "", err
+		}
+		out[i] = source[n]
+	}
+	return string(out), nil
+}
+
+// Passphrase builds a diceware-style phrase of count random words joined by
+// separator.
+func Passphrase(count int, separator string) (string, error) {
+	if count == 0 {
+		count = 6
+	}
+	if count < 3 {
+		return "", errors.New("passphrase needs at least 3 words")
+	}
+	words := make([]string, count)
+	for i := range words {
+		n, err := randInt(len(wordList))
+		if err != nil {
+			return "", err
+		}
+		words[i] = wordList[n]
+	}
+	return strings.Join(words, separator), nil
+}
+
+func randInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, err
+	}
+	return int(n.Int64()), nil
+}
+
+func shuffle(b []byte) error {
+	for i := len(b) - 1; i > 0; i-- {
+		j, err := randInt(i + 1)
+		if err != nil {
+			return err
+		}
+		b[i], b[j] = b[j], b[i]
+	}
+	return nil
+}
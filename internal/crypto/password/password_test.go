@@ -0,0 +1,85 @@
+package password
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateCoversSelectedClasses(t *testing.T) {
+	got, err := Generate(Options{Length: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 16 {
+		t.Fatalf("length = %d, want 16", len(got))
+	}
+	for _, class := range []string{Lower, Upper, Digits, Symbols} {
+		if !strings.ContainsAny(got, class) {
+			t.Errorf("password %q misses class %q", got, class)
+		}
+	}
+}
+
+func TestGenerateRestrictedCharset(t *testing.T) {
+	got, err := Generate(Options{Length: 12, Lower: true, Digits: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range got {
+		if !strings.ContainsRune(Lower+Digits, r) {
+			t.Fatalf("password %q contains %q outside the selected classes", got, r)
+		}
+	}
+}
+
+func TestGenerateRejectsTinyLength(t *testing.T) {
+	if _, err := Generate(Options{Length: 3}); err == nil {
+		t.Fatal("expected error for length 3")
+	}
+}
+
+func TestPronounceableAlternates(t *testing.T) {
+	got, err := Pronounceable(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range got {
+		source := consonants
+		if i%2 == 1 {
+			source = vowels
+		}
+		if !strings.ContainsRune(source, r) {
+			t.Fatalf("position %d of %q breaks the consonant/vowel pattern", i, got)
+		}
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	got, err := Passphrase(6, "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := strings.Split(got, "-")
+	if len(words) != 6 {
+		t.Fatalf("got %d words, want 6", len(words))
+	}
+	for _, w := range words {
+		if w == "" {
+			t.Fatalf("empty word in %q", got)
+		}
+	}
+}
+
+func TestWordListSize(t *testing.T) {
+	// The doc comment promises 8 bits of entropy per word.
+	if len(wordList) != 256 {
+		t.Fatalf("word list has %d entries, want 256", len(wordList))
+	}
+	seen := make(map[string]bool, len(wordList))
+	for _, w := range wordList {
+		if seen[w] {
+			t.Fatalf("duplicate word %q", w)
+		}
+		seen[w] = true
+	}
+}
@@ -0,0 +1,38 @@
+package password
+
+// wordList is a compact diceware-style list of common, unambiguous words.
+// 256 entries give 8 bits of entropy per word; a six-word phrase is 48 bits.
+var wordList = []string{
+	"acid", "acorn", "actor", "adobe", "aging", "agony", "alarm", "album",
+	"alien", "alley", "amber", "angle", "ankle", "apple", "april", "arena",
+	"argue", "armor", "aroma", "arrow", "asset", "atlas", "attic", "audio",
+	"award", "bacon", "badge", "bagel", "baker", "bamboo", "banjo", "barn",
+	"basil", "beach", "beard", "begin", "bench", "berry", "bison", "blade",
+	"blank", "blast", "blaze", "blend", "blink", "bloom", "board", "bonus",
+	"booth", "brave", "bread", "brick", "bride", "brook", "broom", "brush",
+	"buddy", "bugle", "bunny", "cabin", "cable", "cadet", "camel", "canal",
+	"candy", "canoe", "cargo", "carol", "cedar", "chain", "chalk", "charm",
+	"chess", "chief", "chili", "choir", "cider", "cigar", "civic", "claim",
+	"clamp", "clash", "clerk", "cliff", "climb", "cloak", "clock", "cloud",
+	"clown", "coach", "coast", "cobra", "cocoa", "colon", "comet", "coral",
+	"couch", "cougar", "count", "court", "cover", "crane", "crate", "creek",
+	"crisp", "crown", "crumb", "crust", "cubic", "curve", "cycle", "dairy",
+	"daisy", "dance", "delta", "denim", "depot", "derby", "diary", "diner",
+	"disco", "ditch", "diver", "dodge", "donor", "dough", "dozen", "draft",
+	"drama", "drift", "drill", "drum", "dusty", "eagle", "early", "earth",
+	"easel", "ebony", "edge", "elbow", "elder", "elf", "elm", "ember",
+	"empty", "envoy", "epic", "equal", "essay", "evoke", "exact", "exile",
+	"fable", "fancy", "fang", "feast", "fence", "fern", "ferry", "fiber",
+	"field", "fifth", "finch", "flame", "flask", "fleet", "flint", "flock",
+	"flora", "flute", "foam", "forge", "fort", "fox", "frame", "frost",
+	"fruit", "fudge", "gala", "gates", "gavel", "gecko", "genie", "giant",
+	"ginger", "glade", "glass", "globe", "gloss", "glove", "gorge", "grace",
+	"grain", "grape", "grasp", "grave", "grill", "grove", "guard", "guest",
+	"guide", "gulf", "gust", "habit", "harbor", "hatch", "haven", "hawk",
+	"hazel", "heron", "hill", "hinge", "honey", "horse", "hotel", "humor",
+	"igloo", "inch", "index", "iris", "irony", "ivory", "jaguar", "jelly",
+	"jewel", "jolly", "judge", "juice", "jumbo", "jungle", "kayak", "kettle",
+	"kiosk", "kiwi", "knee", "knife", "koala", "ladle", "lagoon", "lance",
+	"lapel", "lava", "layer", "lemon", "lever", "lilac", "lime", "linen",
+	"lion", "lodge", "lotus", "lunar", "lyric", "magma", "maple", "march",
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is an encrypted file attached to a secret of any type.
+// Content is ciphertext at the repository layer, like Secret.Data.
+type Attachment struct {
+	ID        uuid.UUID `json:"id"`
+	SecretID  uuid.UUID `json:"secret_id"`
+	UserID    uuid.UUID `json:"-"`
+	Filename  string    `json:"filename"`
+	Size      int64     `json:"size"`
+	Content   []byte    `json:"content,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions recorded by the server.
+const (
+	AuditActionRegister                = "user.register"
+	AuditActionLogin                   = "user.login"
+	AuditActionLoginFailed             = "user.login_failed"
+	AuditActionSecretCreated           = "secret.created"
+	AuditActionSecretRead              = "secret.read"
+	AuditActionSync                    = "sync.completed"
+	AuditActionSecretUpdated           = "secret.updated"
+	AuditActionSecretDeleted           = "secret.deleted"
+	AuditActionShareCreated            = "share.created"
+	AuditActionShareRevealed           = "share.revealed"
+	AuditActionShareGranted            = "share.granted"
+	AuditActionShareRevoked            = "share.revoked"
+	AuditActionAccountMerged           = "account.merged"
+	AuditActionDeleteScheduled         = "account.delete_scheduled"
+	AuditActionDeleteCancelled         = "account.delete_cancelled"
+	AuditActionDeviceAdded             = "device.added"
+	AuditActionDeviceRevoked           = "device.revoked"
+	AuditActionDevicePending           = "device.approval_requested"
+	AuditActionDeviceApproved          = "device.approved"
+	AuditActionOrgCreated              = "org.created"
+	AuditActionOrgMemberAdded          = "org.member_added"
+	AuditActionOrgSecretMoved          = "org.secret_moved"
+	AuditActionOrgSecretTaken          = "org.secret_taken"
+	AuditActionEmergencyContactAdded   = "emergency.contact_added"
+	AuditActionEmergencyContactRemoved = "emergency.contact_removed"
+	AuditActionEmergencyRequested      = "emergency.requested"
+	AuditActionEmergencyDenied         = "emergency.denied"
+	AuditActionEmergencyAccessed       = "emergency.accessed"
+	AuditActionUserDisabled            = "admin.user_disabled"
+	AuditActionUserEnabled             = "admin.user_enabled"
+	AuditActionUserDeleted             = "admin.user_deleted"
+	AuditActionKeyRotated              = "admin.key_rotated"
+	AuditActionWebhookCreated          = "webhook.created"
+	AuditActionWebhookDeleted          = "webhook.deleted"
+)
+
+// AuditEvent is one security-relevant event. ID is a monotonically
+// increasing sequence number used as the export cursor.
+type AuditEvent struct {
+	ID       int64     `json:"id"`
+	UserID   uuid.UUID `json:"user_id"`
+	Action   string    `json:"action"`
+	Resource string    `json:"resource,omitempty"`
+	// IP is the client address the request came from, when known.
+	IP string `json:"ip,omitempty"`
+	// UserAgent is the client software the request reported.
+	UserAgent string    `json:"user_agent,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginRecord is one sign-in attempt in the login history, derived from
+// the audit trail.
+type LoginRecord struct {
+	At        time.Time `json:"at"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	Success   bool      `json:"success"`
+}
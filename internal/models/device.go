@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device is a registered sync client of a user. The server keeps each
+// device's sync point authoritative so client clocks cannot drift the
+// delta window, and a stolen device can be revoked.
+type Device struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   uuid.UUID `json:"-"`
+	Name     string    `json:"name"`
+	Platform string    `json:"platform,omitempty"`
+	// LastSyncedAt and LastCursor are the server-recorded sync point of
+	// the device, updated after every successful sync.
+	LastSyncedAt time.Time `json:"last_synced_at"`
+	LastCursor   int64     `json:"last_cursor"`
+	Revoked      bool      `json:"revoked"`
+	// Fingerprint identifies the client installation that logs in from
+	// this device; empty for devices registered before fingerprinting.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Pending marks a device awaiting approval; logins from pending
+	// devices are refused until a trusted device or the emailed link
+	// approves them.
+	Pending bool `json:"pending,omitempty"`
+	// ApprovalToken is the secret of the emailed approval link; it never
+	// leaves the server except inside that email.
+	ApprovalToken string    `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+}
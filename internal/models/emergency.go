@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// States an emergency-access designation moves through.
+const (
+	// EmergencyStatusIdle means no access request is pending.
+	EmergencyStatusIdle = "idle"
+	// EmergencyStatusRequested means the contact asked for access and the
+	// waiting period is running.
+	EmergencyStatusRequested = "requested"
+	// EmergencyStatusGranted means the waiting period elapsed without the
+	// owner denying, so the contact may read the vault.
+	EmergencyStatusGranted = "granted"
+)
+
+// EmergencyContact designates another account that may request access to
+// the owner's vault. A request starts a waiting period; unless the owner
+// denies it before the period ends, the contact gains read access.
+type EmergencyContact struct {
+	OwnerID   uuid.UUID `json:"-"`
+	ContactID uuid.UUID `json:"contact_id"`
+	// Login is resolved by the service for display: the contact's login on
+	// the owner's side, the owner's login on the contact's side.
+	Login string `json:"login,omitempty"`
+	// WaitDays is how long a pending request stays open for the owner to
+	// deny it before access is granted.
+	WaitDays int `json:"wait_days"`
+	// RequestedAt is when the contact asked for access; nil when no
+	// request is pending.
+	RequestedAt *time.Time `json:"requested_at,omitempty"`
+	// Status is derived from RequestedAt and WaitDays by the service.
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AvailableAt returns when a pending request unlocks, or the zero time
+// when no request is pending.
+func (c *EmergencyContact) AvailableAt() time.Time {
+	if c.RequestedAt == nil {
+		return time.Time{}
+	}
+	return c.RequestedAt.Add(time.Duration(c.WaitDays) * 24 * time.Hour)
+}
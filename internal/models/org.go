@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles a user can hold inside an organization.
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleMember = "member"
+)
+
+// Organization is a shared vault. Its secrets are encrypted under a key
+// derived from the organization ID, so every member reads them through the
+// org endpoints regardless of who moved them in.
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrgMember ties a user to an organization with a role.
+type OrgMember struct {
+	OrgID  uuid.UUID `json:"-"`
+	UserID uuid.UUID `json:"user_id"`
+	// Login is resolved by the service for display; it is not stored with
+	// the membership.
+	Login     string    `json:"login,omitempty"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is a change notification written in the same transaction as
+// the mutation it describes, so a crash can never lose one. ID doubles as
+// the dispatch order.
+type OutboxEvent struct {
+	ID        int64     `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Action    string    `json:"action"`
+	Resource  string    `json:"resource"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "github.com/google/uuid"
+
+// Grant sources reported by the access report. Sharing features extend
+// this list as they land.
+const (
+	GrantViaOwner = "owner"
+	GrantViaShare = "share"
+	GrantViaOrg   = "org"
+	GrantViaLink  = "link"
+)
+
+// AccessGrant describes one principal's access to a secret and the
+// mechanism granting it.
+type AccessGrant struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Login    string    `json:"login"`
+	CanRead  bool      `json:"can_read"`
+	CanWrite bool      `json:"can_write"`
+	Via      string    `json:"via"`
+}
+
+// SecretAccess is the full grant list of one secret.
+type SecretAccess struct {
+	SecretID   uuid.UUID     `json:"secret_id"`
+	SecretName string        `json:"secret_name"`
+	Grants     []AccessGrant `json:"grants"`
+}
+
+// AccessReport lists, per secret, everyone who can read or write it. It is
+// meant for periodic access reviews.
+type AccessReport struct {
+	Secrets []SecretAccess `json:"secrets"`
+}
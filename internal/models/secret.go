@@ -0,0 +1,250 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// SecretType discriminates the shape of a secret payload.
+type SecretType string
+
+const (
+	SecretTypeCredentials   SecretType = "credentials"
+	SecretTypeText          SecretType = "text"
+	SecretTypeCard          SecretType = "card"
+	SecretTypeBinary        SecretType = "binary"
+	SecretTypeAPIToken      SecretType = "api_token"
+	SecretTypeRecoveryCodes SecretType = "recovery_codes"
+	SecretTypeKubeconfig    SecretType = "kubeconfig"
+	SecretTypeDatabase      SecretType = "database"
+)
+
+// Valid reports whether t is one of the known secret types.
+func (t SecretType) Valid() bool {
+	switch t {
+	case SecretTypeCredentials, SecretTypeText, SecretTypeCard, SecretTypeBinary, SecretTypeAPIToken,
+		SecretTypeRecoveryCodes, SecretTypeKubeconfig, SecretTypeDatabase:
+		return true
+	}
+	return false
+}
+
+// Secret is a single stored item. Data holds the type-specific payload as
+// JSON; it is encrypted at rest by the service layer, repositories only ever
+// see ciphertext.
+type Secret struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"-"`
+	// OrgID is the organization vault the secret lives in; zero means the
+	// owner's personal vault. Org secrets are encrypted under the org key
+	// and served only through the org endpoints.
+	OrgID    uuid.UUID  `json:"-"`
+	Name     string     `json:"name"`
+	Type     SecretType `json:"type"`
+	Metadata string     `json:"metadata,omitempty"`
+	// Protected secrets require explicit confirmation (and re-entry of the
+	// master password in the CLI) before deletion.
+	Protected bool   `json:"protected,omitempty"`
+	Data      []byte `json:"data,omitempty"`
+	// BlobRef points at the encrypted payload in the blob store when it is
+	// too large for the database row. Internal to the server.
+	BlobRef string `json:"-"`
+	// NameIndex is a keyed hash of the name, maintained when metadata
+	// encryption is on so exact-name lookups stay indexed without the
+	// plaintext. Internal to the server.
+	NameIndex []byte `json:"-"`
+	// Archived secrets are hidden from listings by default. The archiver
+	// job sets this for secrets unaccessed past the user's policy.
+	Archived   bool      `json:"archived,omitempty"`
+	AccessedAt time.Time `json:"accessed_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SecretError reports a secret a listing could not return, typically
+// because its payload failed to decrypt.
+type SecretError struct {
+	SecretID uuid.UUID `json:"id"`
+	Error    string    `json:"error"`
+}
+
+// SyncHash fingerprints the client-visible content of a secret so delta
+// sync can skip entries both sides already agree on. It must be computed
+// over the decrypted payload; fields are length-prefixed so no two
+// combinations collide.
+func (s *Secret) SyncHash() string {
+	h := sha256.New()
+	for _, field := range [][]byte{[]byte(s.Name), []byte(s.Type), []byte(s.Metadata), s.Data} {
+		binary.Write(h, binary.LittleEndian, int64(len(field)))
+		h.Write(field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// MaxPasswordHistory bounds the number of previous passwords kept inside a
+// credentials payload.
+const MaxPasswordHistory = 10
+
+// PasswordHistoryEntry is a previous password of a credentials secret,
+// retained inside the encrypted payload for recovery after an overwrite.
+type PasswordHistoryEntry struct {
+	Password   string    `json:"password"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// CredentialsPayload is the payload of a SecretTypeCredentials secret.
+type CredentialsPayload struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+	URL      string `json:"url,omitempty"`
+	// History holds previous passwords, most recent first, capped at
+	// MaxPasswordHistory entries.
+	History []PasswordHistoryEntry `json:"history,omitempty"`
+}
+
+// PushHistory records the current password into History before it is
+// replaced, trimming the list to MaxPasswordHistory entries.
+func (p *CredentialsPayload) PushHistory(now time.Time) {
+	p.History = append([]PasswordHistoryEntry{{Password: p.Password, ReplacedAt: now}}, p.History...)
+	if len(p.History) > MaxPasswordHistory {
+		p.History = p.History[:MaxPasswordHistory]
+	}
+}
+
+// UsedBefore reports whether password matches the current password or any
+// entry in the history. Comparisons are constant-time: the history holds
+// live passwords.
+func (p *CredentialsPayload) UsedBefore(password string) bool {
+	used := crypto.ConstantTimeEqual([]byte(p.Password), []byte(password))
+	for _, h := range p.History {
+		if crypto.ConstantTimeEqual([]byte(h.Password), []byte(password)) {
+			used = true
+		}
+	}
+	return used
+}
+
+// TextPayload is the payload of a SecretTypeText secret. Multi-line notes
+// are stored as-is; Markdown marks the content for terminal rendering on
+// display.
+type TextPayload struct {
+	Content  string `json:"content"`
+	Markdown bool   `json:"markdown,omitempty"`
+}
+
+// CardPayload is the payload of a SecretTypeCard secret.
+type CardPayload struct {
+	Number string `json:"number"`
+	Holder string `json:"holder"`
+	Expiry string `json:"expiry"`
+	CVV    string `json:"cvv"`
+}
+
+// APITokenPayload is the payload of a SecretTypeAPIToken secret.
+type APITokenPayload struct {
+	// EnvVar is the environment variable name used by `secrets env`.
+	EnvVar string   `json:"env_var"`
+	Token  string   `json:"token"`
+	Scopes []string `json:"scopes,omitempty"`
+	// ExpiresAt is when the token stops working, if known.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether the token has a known expiry in the past.
+func (p *APITokenPayload) Expired(now time.Time) bool {
+	return p.ExpiresAt != nil && p.ExpiresAt.Before(now)
+}
+
+// LowRecoveryCodesThreshold is the number of unused codes at or below which
+// clients are warned to regenerate them.
+const LowRecoveryCodesThreshold = 3
+
+// RecoveryCode is one one-time code with its consumption state.
+type RecoveryCode struct {
+	Code   string     `json:"code"`
+	Used   bool       `json:"used,omitempty"`
+	UsedAt *time.Time `json:"used_at,omitempty"`
+}
+
+// RecoveryCodesPayload is the payload of a SecretTypeRecoveryCodes secret.
+type RecoveryCodesPayload struct {
+	Codes []RecoveryCode `json:"codes"`
+}
+
+// Remaining counts the codes not yet consumed.
+func (p *RecoveryCodesPayload) Remaining() int {
+	n := 0
+	for _, c := range p.Codes {
+		if !c.Used {
+			n++
+		}
+	}
+	return n
+}
+
+// Consume marks the first unused code as used and returns it. It returns
+// false when every code has already been consumed.
+func (p *RecoveryCodesPayload) Consume(now time.Time) (string, bool) {
+	for i := range p.Codes {
+		if !p.Codes[i].Used {
+			p.Codes[i].Used = true
+			p.Codes[i].UsedAt = &now
+			return p.Codes[i].Code, true
+		}
+	}
+	return "", false
+}
+
+// KubeconfigPayload is the payload of a SecretTypeKubeconfig secret. The
+// config is kept verbatim; Context optionally pins the context `kube use`
+// selects.
+type KubeconfigPayload struct {
+	Config  string `json:"config"`
+	Context string `json:"context,omitempty"`
+}
+
+// Database engines understood by `secrets connect`.
+const (
+	DatabaseEnginePostgres = "postgres"
+	DatabaseEngineMySQL    = "mysql"
+)
+
+// DatabasePayload is the payload of a SecretTypeDatabase secret. Options
+// holds extra connection parameters verbatim (e.g. "sslmode=require").
+type DatabasePayload struct {
+	Engine   string `json:"engine"`
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Database string `json:"database,omitempty"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	Options  string `json:"options,omitempty"`
+}
+
+// BinaryPayload is the payload of a SecretTypeBinary secret.
+type BinaryPayload struct {
+	Filename string `json:"filename"`
+	Content  []byte `json:"content"`
+}
+
+// DecodePayload unmarshals the secret data into dst.
+func (s *Secret) DecodePayload(dst any) error {
+	return json.Unmarshal(s.Data, dst)
+}
+
+// EncodePayload marshals src into the secret data.
+func (s *Secret) EncodePayload(src any) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	s.Data = data
+	return nil
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareLink is a single-use, expiring public link to one secret. The
+// payload and name are encrypted with a key derived from the URL token,
+// and the token itself is stored only as a hash, so the database alone
+// cannot reveal what was shared.
+type ShareLink struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	SecretID  uuid.UUID
+	TokenHash []byte
+	Name      []byte
+	Type      SecretType
+	Payload   []byte
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// Modes a secret can be shared with another account under.
+const (
+	ShareModeRead  = "read"
+	ShareModeWrite = "write"
+)
+
+// SecretShare grants another account access to one secret. The payload
+// stays encrypted under the owner's key; the server re-encrypts it for the
+// recipient on every read, so grants never go stale and revocation is
+// immediate.
+type SecretShare struct {
+	SecretID    uuid.UUID `json:"secret_id"`
+	OwnerID     uuid.UUID `json:"-"`
+	RecipientID uuid.UUID `json:"recipient_id"`
+	// Login is resolved by the service for display: the recipient's login
+	// on the owner's side, the owner's login on the recipient's side.
+	Login     string    `json:"login,omitempty"`
+	Mode      string    `json:"mode"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SharedSecret is a secret received through a share, together with what
+// the recipient may do with it.
+type SharedSecret struct {
+	Secret Secret `json:"secret"`
+	Mode   string `json:"mode"`
+	// Owner is the login of the account sharing the secret.
+	Owner string `json:"owner,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncRequest asks for everything that changed since the client's last
+// sync: secrets by modification time and operations by audit cursor.
+type SyncRequest struct {
+	Since       time.Time `json:"since"`
+	AuditCursor int64     `json:"audit_cursor"`
+	// RetryToken from a previous partial SyncResponse; when set it
+	// overrides the fields above so the client refetches exactly what was
+	// missing.
+	RetryToken string `json:"retry_token,omitempty"`
+	// DeviceID names a registered device. When set, the server-recorded
+	// sync point of the device overrides Since and AuditCursor, so a
+	// drifting client clock cannot widen or narrow the delta window.
+	DeviceID uuid.UUID `json:"device_id,omitempty"`
+	// ClientHashes maps secret IDs the client holds to their SyncHash.
+	// Secrets whose server-side hash matches are left out of the response
+	// even when their timestamp moved.
+	ClientHashes map[uuid.UUID]string `json:"client_hashes,omitempty"`
+	// Changes are client-side edits to upsert before the response is
+	// computed, so one round trip both pushes and pulls.
+	Changes []Secret `json:"changes,omitempty"`
+}
+
+// SyncResponse carries the changes since the requested point. A response
+// can be partial: Warnings names what could not be fetched and RetryToken
+// re-requests only that part, so one failing stream never blocks the other.
+type SyncResponse struct {
+	Secrets []Secret `json:"secrets"`
+	// DeletedSecrets are tombstones: IDs deleted since the client's sync
+	// point, derived from the operations stream.
+	DeletedSecrets []uuid.UUID  `json:"deleted_secrets,omitempty"`
+	Operations     []AuditEvent `json:"operations"`
+	Warnings       []string     `json:"warnings,omitempty"`
+	RetryToken     string       `json:"retry_token,omitempty"`
+	// SyncedAt and NextCursor seed the next regular SyncRequest.
+	SyncedAt   time.Time `json:"synced_at"`
+	NextCursor int64     `json:"next_cursor"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// StorageUsage reports how much of their quota an account consumes.
+// Max values of 0 mean the server does not enforce that limit.
+type StorageUsage struct {
+	SecretsCount    int   `json:"secrets_count"`
+	StorageBytes    int64 `json:"storage_bytes"`
+	MaxSecrets      int   `json:"max_secrets,omitempty"`
+	MaxStorageBytes int64 `json:"max_storage_bytes,omitempty"`
+}
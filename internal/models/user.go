@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User roles. Admins can manage other accounts through the admin API.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// User is a registered account. PasswordHash is a bcrypt hash of the
+// account password; the plaintext never leaves the auth service.
+type User struct {
+	ID    uuid.UUID `json:"id"`
+	Login string    `json:"login"`
+	// Email is an optional contact address. When set it is unique across
+	// accounts, case-insensitively.
+	Email        string `json:"email,omitempty"`
+	PasswordHash string `json:"-"`
+	// Role is RoleUser or RoleAdmin.
+	Role string `json:"-"`
+	// ArchiveAfterDays auto-archives secrets unaccessed for this many
+	// days. 0 opts the user out.
+	ArchiveAfterDays int `json:"archive_after_days"`
+	// Disabled accounts cannot log in. Set when an account is merged into
+	// another one or disabled by an admin.
+	Disabled bool `json:"-"`
+	// DeleteAfter is when a scheduled account deletion becomes due; nil
+	// means none is pending. The reaper purges the account once it passes.
+	DeleteAfter *time.Time `json:"delete_after,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Credentials is the register/login request body. Email is optional and
+// only read on registration.
+type Credentials struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+	Email    string `json:"email,omitempty"`
+	// Fingerprint identifies the client installation on login; servers
+	// with device approval enabled refuse unknown fingerprints until
+	// they are approved.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SecretVersion is a snapshot of a secret payload taken before an update
+// replaced it. Data holds the snapshot together with its change summary; it
+// is encrypted at rest like the live payload. Summary is populated by the
+// service for listings; clients never see Data unless they open the version.
+type SecretVersion struct {
+	ID       uuid.UUID `json:"id"`
+	SecretID uuid.UUID `json:"secret_id"`
+	UserID   uuid.UUID `json:"-"`
+	// Version numbers count up from 1 per secret.
+	Version int    `json:"version"`
+	Data    []byte `json:"data,omitempty"`
+	// Summary describes what the update that superseded this snapshot
+	// changed, e.g. "password changed, url added".
+	Summary   string    `json:"summary,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook is a user-registered endpoint that receives signed event
+// notifications, typically for SIEM ingestion. Secret signs every
+// delivery and is only returned when the hook is created.
+type Webhook struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"-"`
+	URL    string    `json:"url"`
+	Secret string    `json:"secret,omitempty"`
+	// Events are the actions delivered to this hook (for example
+	// "secret.created"); empty means every event.
+	Events    []string  `json:"events,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is one delivery attempt recorded for a webhook, kept so
+// users can debug their receivers. StatusCode is 0 when the request never
+// reached the endpoint; Error holds the transport failure then.
+type WebhookDelivery struct {
+	ID         int64     `json:"id"`
+	WebhookID  uuid.UUID `json:"-"`
+	EventID    int64     `json:"event_id"`
+	Action     string    `json:"action"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
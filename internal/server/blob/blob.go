@@ -0,0 +1,26 @@
+// Package blob abstracts storage of large encrypted payloads outside the
+// database row. Keys are opaque references stored in the secrets table.
+package blob
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when a key does not exist in the store.
+var ErrNotFound = errors.New("blob: not found")
+
+// Store holds encrypted blobs by key. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	// Ping reports whether the store is reachable; the readiness probe
+	// calls it, so it must be cheap.
+	Ping(ctx context.Context) error
+}
+
+// InlineThreshold is the encrypted payload size in bytes above which the
+// data moves from the database row into the blob store.
+const InlineThreshold = 64 * 1024
@@ -0,0 +1,87 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DiskStore keeps blobs as files under a root directory. It suits
+// single-node deployments and tests.
+type DiskStore struct {
+	root string
+}
+
+// NewDiskStore creates the root directory if needed.
+func NewDiskStore(root string) (*DiskStore, error) {
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("blob: create root: %w", err)
+	}
+	return &DiskStore{root: root}, nil
+}
+
+// Ping reports whether the root directory is still accessible.
+func (s *DiskStore) Ping(_ context.Context) error {
+	if _, err := os.Stat(s.root); err != nil {
+		return fmt.Errorf("blob: stat root: %w", err)
+	}
+	return nil
+}
+
+// Put writes a blob atomically via a temporary file.
+func (s *DiskStore) Put(_ context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.root, "put-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get reads a blob.
+func (s *DiskStore) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Delete removes a blob. Deleting a missing key is not an error.
+func (s *DiskStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path validates the key against traversal and maps it to a file path.
+func (s *DiskStore) path(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, "/\\") || strings.Contains(key, "..") {
+		return "", fmt.Errorf("blob: invalid key %q", key)
+	}
+	return filepath.Join(s.root, key), nil
+}
@@ -0,0 +1,21 @@
+// Package cache provides the small cache interface hot read paths sit
+// behind, with a Redis implementation that speaks the wire protocol
+// directly, so no client library is needed.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores opaque values under string keys for a bounded time. A
+// failed or missing lookup is a miss, never an outage: callers fall
+// through to the database.
+type Cache interface {
+	// Get returns the cached value, or nil when the key is absent.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the given keys; absent keys are not an error.
+	Delete(ctx context.Context, keys ...string) error
+}
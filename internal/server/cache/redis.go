@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dialTimeout bounds both connecting to Redis and each command, so a
+// stalled cache slows a request down, never hangs it.
+const dialTimeout = 2 * time.Second
+
+// Redis is a minimal Redis client covering the three commands the cache
+// needs: GET, SET with expiry and DEL. It keeps one connection and
+// redials after an error.
+type Redis struct {
+	addr string
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// NewRedis creates a Redis cache client for addr (host:port). The
+// connection is established lazily on first use.
+func NewRedis(addr string) *Redis {
+	return &Redis{addr: addr}
+}
+
+// Get returns the cached value, or nil when the key is absent.
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := c.do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	value, _ := reply.([]byte)
+	return value, nil
+}
+
+// Set stores value under key for ttl.
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := c.do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+// Delete removes the given keys; absent keys are not an error.
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := c.do(ctx, append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+// do sends one command and reads its reply, redialing on the next call
+// after any transport error.
+func (c *Redis) do(ctx context.Context, args ...string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		conn, err := (&net.Dialer{Timeout: dialTimeout}).DialContext(ctx, "tcp", c.addr)
+		if err != nil {
+			return nil, fmt.Errorf("redis: dial: %w", err)
+		}
+		c.conn = conn
+		c.r = bufio.NewReader(conn)
+	}
+
+	deadline := time.Now().Add(dialTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, c.fail(err)
+	}
+
+	var req strings.Builder
+	fmt.Fprintf(&req, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&req, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, req.String()); err != nil {
+		return nil, c.fail(err)
+	}
+	reply, err := c.readReply()
+	if err != nil {
+		var replyErr *replyError
+		if errors.As(err, &replyErr) {
+			return nil, err
+		}
+		return nil, c.fail(err)
+	}
+	return reply, nil
+}
+
+// fail drops the connection so the next command redials.
+func (c *Redis) fail(err error) error {
+	c.conn.Close()
+	c.conn, c.r = nil, nil
+	return fmt.Errorf("redis: %w", err)
+}
+
+// replyError is an error Redis itself reported; the connection is fine.
+type replyError struct {
+	message string
+}
+
+func (e *replyError) Error() string {
+	return "redis: " + e.message
+}
+
+// readReply parses one RESP reply: simple string, error, integer or bulk
+// string. The commands this client sends return nothing else.
+func (c *Redis) readReply() (any, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	if line == "" {
+		return nil, errors.New("empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, &replyError{message: line[1:]}
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		value := make([]byte, size+2)
+		if _, err := io.ReadFull(c.r, value); err != nil {
+			return nil, err
+		}
+		return value[:size], nil
+	default:
+		return nil, fmt.Errorf("unexpected reply %q", line)
+	}
+}
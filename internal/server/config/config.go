@@ -0,0 +1,468 @@
+// Package config loads server configuration from flags and environment
+// variables. Environment variables take precedence over flags, and every
+// variable can also be namespaced with a GOPHKEEPER_ prefix, which wins
+// over the bare name.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the server configuration.
+type Config struct {
+	// RunAddress is the host:port the HTTP server listens on.
+	RunAddress string
+	// DebugAddr, when set, serves /debug/pprof on its own listener (for
+	// example "localhost:6060"). Keep it on localhost or behind a
+	// firewall: profiles expose server internals.
+	DebugAddr string
+	// ConfigFile, when set, points at a JSON file holding the settings
+	// that can change without a restart: log level, rate limits and CORS
+	// origins. The server watches it and applies changes live; any other
+	// key found there is logged as requiring a restart.
+	ConfigFile string
+	// DatabaseURI is the Postgres connection string.
+	DatabaseURI string
+	// DatabaseReplicaURI, when set, points listing reads that tolerate
+	// replication lag at a read replica. Writes and read-for-update
+	// lookups stay on DatabaseURI.
+	DatabaseReplicaURI string
+	// DBMaxConns caps the connections of each pool; 0 keeps the pgx
+	// default.
+	DBMaxConns int
+	// DBMaxConnIdleTime closes connections idle for longer than this;
+	// zero keeps the pgx default.
+	DBMaxConnIdleTime time.Duration
+	// DBHealthCheckPeriod is how often idle connections are checked; zero
+	// keeps the pgx default.
+	DBHealthCheckPeriod time.Duration
+	// DBDriver selects the storage backend: "postgres" (the default),
+	// "sqlite" for single-user deployments without a database server, or
+	// "memory" for development and tests, which keeps everything in process
+	// memory and loses it on shutdown. SQLite support must be compiled in
+	// with -tags sqlite.
+	DBDriver string
+	// DBPath is the SQLite database file, created on first start.
+	DBPath string
+	// MigrateOnStart applies pending embedded Postgres migrations at
+	// startup. The sqlite driver always migrates its own file.
+	MigrateOnStart bool
+	// JWTSecret signs and verifies access tokens: either a single bare
+	// secret, or a comma-separated list of kid=secret pairs with the
+	// newest first. With a list, new tokens are signed with the first
+	// key and tokens signed with any listed key stay valid, so keys
+	// rotate without logging everyone out.
+	JWTSecret string
+	// JWTPrivateKey is a PEM-encoded RSA or Ed25519 private key; when
+	// set, new tokens are signed with it (RS256 or EdDSA) and the public
+	// half is published at /.well-known/jwks.json. JWTSecret may stay
+	// set alongside it so existing HMAC tokens keep verifying.
+	JWTPrivateKey string
+	// EncryptionKey is the server master secret from which per-user data
+	// keys are derived.
+	EncryptionKey string
+	// NewEncryptionKey stages a replacement master secret for key
+	// rotation. The admin rotation endpoint re-encrypts stored data from
+	// EncryptionKey to this value; once the run succeeds the deployment
+	// restarts with this value as ENCRYPTION_KEY.
+	NewEncryptionKey string
+	// CipherSuite selects the cipher new data is encrypted with:
+	// "aes-gcm" (the default) or "xchacha20-poly1305". Existing data
+	// decrypts regardless of the setting; the ciphertext is tagged with
+	// the suite that wrote it.
+	CipherSuite string
+	// EncryptMetadata seals secret names and metadata at rest, with a keyed
+	// name index keeping exact-name lookups fast. Sorting by name then
+	// orders ciphertext rather than titles. Existing rows are sealed on
+	// their next write; sealed rows keep decrypting after it is turned off.
+	EncryptMetadata bool
+	// KMSProvider enables external key wrapping: "" keeps the master
+	// secret in ENCRYPTION_KEY, "vault" unwraps EncryptionKeyWrapped
+	// through a Vault transit engine at startup, so the plaintext master
+	// secret never appears in the environment and a database dump cannot
+	// be decrypted without KMS access.
+	KMSProvider string
+	// EncryptionKeyWrapped is the master secret wrapped by the KMS.
+	EncryptionKeyWrapped string
+	// Vault settings for KMSProvider "vault".
+	VaultAddr       string
+	VaultToken      string
+	VaultTransitKey string
+	// ArchiveDryRun makes the retention archiver log what it would archive
+	// instead of archiving it.
+	ArchiveDryRun bool
+	// AuditSyslogAddr, when set, enables forwarding of audit events to a
+	// syslog collector at this host:port.
+	AuditSyslogAddr string
+	// AuditSyslogNetwork is the transport for the forwarder, "udp" or
+	// "tcp". Defaults to "udp".
+	AuditSyslogNetwork string
+	// SMTPAddr, when set, enables email security alerts through the mail
+	// relay at this host:port.
+	SMTPAddr string
+	// SMTPFrom is the sender address of the alerts; required when
+	// SMTPAddr is set.
+	SMTPFrom string
+	// SMTPUsername and SMTPPassword authenticate against the relay; empty
+	// for relays that restrict by network instead.
+	SMTPUsername string
+	SMTPPassword string
+	// RequestTimeout bounds each API request with a context deadline, so a
+	// stuck query cannot hold a database connection past it. Zero disables
+	// the deadline.
+	RequestTimeout time.Duration
+	// SyncTimeout bounds /sync separately, since full-vault pushes need
+	// more room than ordinary requests. Zero disables the deadline.
+	SyncTimeout time.Duration
+	// PasswordMinLength, PasswordMinClasses and PasswordMinScore shape the
+	// password policy enforced on registration; zero disables the
+	// respective check.
+	PasswordMinLength  int
+	PasswordMinClasses int
+	PasswordMinScore   int
+	// AccountDeleteGrace is how long DELETE /me waits before the account
+	// is purged; during the grace period the deletion can be cancelled.
+	AccountDeleteGrace time.Duration
+	// MaxBodyBytes caps the request body size of ordinary API calls; 0
+	// disables the cap.
+	MaxBodyBytes int64
+	// MaxSyncBodyBytes caps /sync separately, since full-vault pushes are
+	// legitimately large. 0 disables the cap.
+	MaxSyncBodyBytes int64
+	// CacheRedisAddr, when set, enables caching of hot secret reads in a
+	// Redis server at this host:port.
+	CacheRedisAddr string
+	// ShareBrand is the title shown on public share pages. Empty uses the
+	// built-in default.
+	ShareBrand string
+	// MaxSecretsPerUser and MaxStorageBytesPerUser cap how much a single
+	// account may store; 0 disables the respective limit.
+	MaxSecretsPerUser      int
+	MaxStorageBytesPerUser int64
+	// AdminLogins are accounts granted the admin role at startup, so a
+	// fresh deployment can bootstrap its first admin. Logins that have not
+	// registered yet are picked up on the next restart.
+	AdminLogins []string
+	// TLSCertFile and TLSKeyFile enable HTTPS when both are set.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSSelfSigned serves HTTPS with a certificate generated at startup.
+	// Development only: clients must pin or skip verification.
+	TLSSelfSigned bool
+	// TLSClientCA, when set, is a PEM bundle of CAs client certificates
+	// must chain to; requests without a valid client certificate are
+	// rejected at the handshake (mutual TLS).
+	TLSClientCA string
+	// BlobStore selects where oversized encrypted payloads live: "" keeps
+	// everything in Postgres, "disk" uses BlobDir, "s3" uses the S3 settings.
+	BlobStore string
+	// BlobDir is the root directory of the disk blob store.
+	BlobDir string
+	// BlobResidency forces every encrypted payload into the blob store
+	// regardless of size, so Postgres holds metadata only. Deployments with
+	// data-residency requirements point the blob store at the required
+	// region while sharing the control-plane database.
+	BlobResidency bool
+	// S3 settings for BlobStore "s3". S3Endpoint is host:port; S3Region is
+	// passed to the object store and selects where payloads live.
+	S3Endpoint  string
+	S3Bucket    string
+	S3Region    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+}
+
+// getenv looks a setting up in the environment. The GOPHKEEPER_-prefixed
+// name wins, so deployments can namespace every setting; the bare name
+// stays supported for existing deployments.
+func getenv(name string) string {
+	if v := os.Getenv("GOPHKEEPER_" + name); v != "" {
+		return v
+	}
+	return os.Getenv(name)
+}
+
+// getenvSecret looks a credential up like getenv, but also honors a
+// _FILE-suffixed variant naming a file that holds the value, so secrets
+// can be mounted from Docker or Kubernetes secret stores instead of
+// living in the environment. Setting both forms is rejected as
+// ambiguous.
+func getenvSecret(name string) (string, error) {
+	value := getenv(name)
+	path := getenv(name + "_FILE")
+	if path == "" {
+		return value, nil
+	}
+	if value != "" {
+		return "", errors.New("config: " + name + " and " + name + "_FILE are mutually exclusive")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("config: read %s: %w", name+"_FILE", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Load parses flags and environment variables into a Config.
+func Load() (*Config, error) {
+	cfg := &Config{}
+	flag.StringVar(&cfg.RunAddress, "a", "localhost:8080", "address to listen on")
+	flag.StringVar(&cfg.DatabaseURI, "d", "", "postgres connection string")
+	flag.StringVar(&cfg.DBDriver, "db-driver", "postgres", "database driver: postgres, sqlite or memory")
+	flag.StringVar(&cfg.DBPath, "db-path", "", "sqlite database file")
+	flag.BoolVar(&cfg.MigrateOnStart, "migrate", false, "apply pending migrations on startup")
+	flag.Parse()
+
+	if v := getenv("RUN_ADDRESS"); v != "" {
+		cfg.RunAddress = v
+	}
+	cfg.DebugAddr = getenv("DEBUG_ADDRESS")
+	cfg.ConfigFile = getenv("CONFIG_FILE")
+	dbURI, err := getenvSecret("DATABASE_URI")
+	if err != nil {
+		return nil, err
+	}
+	if dbURI != "" {
+		cfg.DatabaseURI = dbURI
+	}
+	if cfg.DatabaseReplicaURI, err = getenvSecret("DATABASE_REPLICA_URI"); err != nil {
+		return nil, err
+	}
+	if v := getenv("DB_MAX_CONNS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, errors.New("config: DB_MAX_CONNS must be a positive integer")
+		}
+		cfg.DBMaxConns = n
+	}
+	if v := getenv("DB_MAX_CONN_IDLE_TIME"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, errors.New("config: DB_MAX_CONN_IDLE_TIME must be a positive duration")
+		}
+		cfg.DBMaxConnIdleTime = d
+	}
+	if v := getenv("DB_HEALTH_CHECK_PERIOD"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, errors.New("config: DB_HEALTH_CHECK_PERIOD must be a positive duration")
+		}
+		cfg.DBHealthCheckPeriod = d
+	}
+	if v := getenv("DB_DRIVER"); v != "" {
+		cfg.DBDriver = v
+	}
+	if v := getenv("DB_PATH"); v != "" {
+		cfg.DBPath = v
+	}
+	if getenv("MIGRATE_ON_START") == "true" {
+		cfg.MigrateOnStart = true
+	}
+	// Credentials may arrive via a _FILE-suffixed variant instead, so
+	// they can be mounted from Docker or Kubernetes secrets.
+	for _, secret := range []struct {
+		name  string
+		field *string
+	}{
+		{"JWT_SECRET", &cfg.JWTSecret},
+		{"JWT_PRIVATE_KEY", &cfg.JWTPrivateKey},
+		{"ENCRYPTION_KEY", &cfg.EncryptionKey},
+		{"NEW_ENCRYPTION_KEY", &cfg.NewEncryptionKey},
+		{"ENCRYPTION_KEY_WRAPPED", &cfg.EncryptionKeyWrapped},
+		{"VAULT_TOKEN", &cfg.VaultToken},
+		{"SMTP_PASSWORD", &cfg.SMTPPassword},
+		{"S3_ACCESS_KEY", &cfg.S3AccessKey},
+		{"S3_SECRET_KEY", &cfg.S3SecretKey},
+	} {
+		if *secret.field, err = getenvSecret(secret.name); err != nil {
+			return nil, err
+		}
+	}
+	cfg.CipherSuite = getenv("CIPHER_SUITE")
+	cfg.EncryptMetadata = getenv("ENCRYPT_METADATA") == "true"
+	cfg.KMSProvider = getenv("KMS_PROVIDER")
+	cfg.VaultAddr = getenv("VAULT_ADDR")
+	cfg.VaultTransitKey = getenv("VAULT_TRANSIT_KEY")
+	cfg.ArchiveDryRun = getenv("ARCHIVE_DRY_RUN") == "true"
+	cfg.AuditSyslogAddr = getenv("AUDIT_SYSLOG_ADDR")
+	cfg.AuditSyslogNetwork = getenv("AUDIT_SYSLOG_NETWORK")
+	if cfg.AuditSyslogNetwork == "" {
+		cfg.AuditSyslogNetwork = "udp"
+	}
+	cfg.SMTPAddr = getenv("SMTP_ADDRESS")
+	cfg.SMTPFrom = getenv("SMTP_FROM")
+	cfg.SMTPUsername = getenv("SMTP_USERNAME")
+	if cfg.SMTPAddr != "" && cfg.SMTPFrom == "" {
+		return nil, errors.New("config: SMTP_FROM is required when SMTP_ADDRESS is set")
+	}
+	cfg.RequestTimeout = 30 * time.Second
+	if v := getenv("REQUEST_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, errors.New("config: REQUEST_TIMEOUT must be a non-negative duration")
+		}
+		cfg.RequestTimeout = d
+	}
+	cfg.SyncTimeout = 2 * time.Minute
+	if v := getenv("SYNC_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, errors.New("config: SYNC_TIMEOUT must be a non-negative duration")
+		}
+		cfg.SyncTimeout = d
+	}
+	cfg.AccountDeleteGrace = 30 * 24 * time.Hour
+	if v := getenv("ACCOUNT_DELETE_GRACE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, errors.New("config: ACCOUNT_DELETE_GRACE must be a non-negative duration")
+		}
+		cfg.AccountDeleteGrace = d
+	}
+	cfg.PasswordMinLength = 8
+	for _, setting := range []struct {
+		name  string
+		field *int
+	}{
+		{"PASSWORD_MIN_LENGTH", &cfg.PasswordMinLength},
+		{"PASSWORD_MIN_CLASSES", &cfg.PasswordMinClasses},
+		{"PASSWORD_MIN_SCORE", &cfg.PasswordMinScore},
+	} {
+		if v := getenv(setting.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return nil, errors.New("config: " + setting.name + " must be a non-negative integer")
+			}
+			*setting.field = n
+		}
+	}
+	cfg.MaxBodyBytes = 16 << 20
+	if v := getenv("MAX_BODY_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return nil, errors.New("config: MAX_BODY_BYTES must be a non-negative integer")
+		}
+		cfg.MaxBodyBytes = n
+	}
+	cfg.MaxSyncBodyBytes = 64 << 20
+	if v := getenv("MAX_SYNC_BODY_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return nil, errors.New("config: MAX_SYNC_BODY_BYTES must be a non-negative integer")
+		}
+		cfg.MaxSyncBodyBytes = n
+	}
+	cfg.CacheRedisAddr = getenv("CACHE_REDIS_ADDR")
+	cfg.ShareBrand = getenv("SHARE_BRAND")
+	if v := getenv("MAX_SECRETS_PER_USER"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, errors.New("config: MAX_SECRETS_PER_USER must be a non-negative integer")
+		}
+		cfg.MaxSecretsPerUser = n
+	}
+	if v := getenv("MAX_STORAGE_BYTES_PER_USER"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return nil, errors.New("config: MAX_STORAGE_BYTES_PER_USER must be a non-negative integer")
+		}
+		cfg.MaxStorageBytesPerUser = n
+	}
+	if v := getenv("ADMIN_LOGINS"); v != "" {
+		for _, login := range strings.Split(v, ",") {
+			if login = strings.TrimSpace(login); login != "" {
+				cfg.AdminLogins = append(cfg.AdminLogins, login)
+			}
+		}
+	}
+	cfg.TLSCertFile = getenv("TLS_CERT_FILE")
+	cfg.TLSKeyFile = getenv("TLS_KEY_FILE")
+	cfg.TLSSelfSigned = getenv("TLS_SELF_SIGNED") == "true"
+	cfg.TLSClientCA = getenv("TLS_CLIENT_CA")
+	cfg.BlobStore = getenv("BLOB_STORE")
+	cfg.BlobDir = getenv("BLOB_DIR")
+	cfg.BlobResidency = getenv("BLOB_RESIDENCY") == "true"
+	cfg.S3Endpoint = getenv("S3_ENDPOINT")
+	cfg.S3Bucket = getenv("S3_BUCKET")
+	cfg.S3Region = getenv("S3_REGION")
+	cfg.S3UseSSL = getenv("S3_USE_SSL") == "true"
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	switch c.DBDriver {
+	case "postgres":
+		if c.DatabaseURI == "" {
+			return errors.New("config: database URI is required")
+		}
+	case "sqlite":
+		if c.DBPath == "" {
+			return errors.New("config: DB_PATH is required for the sqlite driver")
+		}
+	case "memory":
+	default:
+		return errors.New("config: DB_DRIVER must be \"postgres\", \"sqlite\" or \"memory\"")
+	}
+	if c.JWTSecret == "" && c.JWTPrivateKey == "" {
+		return errors.New("config: JWT_SECRET or JWT_PRIVATE_KEY is required")
+	}
+	switch c.KMSProvider {
+	case "":
+		if c.EncryptionKey == "" {
+			return errors.New("config: ENCRYPTION_KEY is required")
+		}
+		if c.EncryptionKeyWrapped != "" {
+			return errors.New("config: ENCRYPTION_KEY_WRAPPED requires a KMS_PROVIDER")
+		}
+	case "vault":
+		if c.EncryptionKeyWrapped == "" {
+			return errors.New("config: ENCRYPTION_KEY_WRAPPED is required with a KMS_PROVIDER")
+		}
+		if c.EncryptionKey != "" {
+			return errors.New("config: ENCRYPTION_KEY conflicts with ENCRYPTION_KEY_WRAPPED")
+		}
+		if c.VaultAddr == "" || c.VaultToken == "" || c.VaultTransitKey == "" {
+			return errors.New("config: VAULT_ADDR, VAULT_TOKEN and VAULT_TRANSIT_KEY are required for the vault KMS")
+		}
+	default:
+		return errors.New("config: KMS_PROVIDER must be empty or \"vault\"")
+	}
+	if c.NewEncryptionKey != "" && c.NewEncryptionKey == c.EncryptionKey {
+		return errors.New("config: NEW_ENCRYPTION_KEY must differ from ENCRYPTION_KEY")
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return errors.New("config: TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if c.TLSSelfSigned && c.TLSCertFile != "" {
+		return errors.New("config: TLS_SELF_SIGNED conflicts with TLS_CERT_FILE")
+	}
+	if c.TLSClientCA != "" && c.TLSCertFile == "" && !c.TLSSelfSigned {
+		return errors.New("config: TLS_CLIENT_CA requires TLS to be enabled")
+	}
+	switch c.BlobStore {
+	case "", "disk", "s3":
+	default:
+		return errors.New("config: BLOB_STORE must be empty, \"disk\" or \"s3\"")
+	}
+	if c.BlobStore == "disk" && c.BlobDir == "" {
+		return errors.New("config: BLOB_DIR is required for the disk blob store")
+	}
+	if c.BlobStore == "s3" && (c.S3Endpoint == "" || c.S3Bucket == "") {
+		return errors.New("config: S3_ENDPOINT and S3_BUCKET are required for the s3 blob store")
+	}
+	if c.BlobResidency && c.BlobStore == "" {
+		return errors.New("config: BLOB_RESIDENCY requires a blob store")
+	}
+	return nil
+}
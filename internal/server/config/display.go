@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// redactedFields are always masked by Display. Fields whose name
+// contains Secret, Password or Token are masked too, so a future secret
+// cannot leak by being forgotten here.
+var redactedFields = map[string]bool{
+	"JWTPrivateKey":        true,
+	"EncryptionKey":        true,
+	"NewEncryptionKey":     true,
+	"EncryptionKeyWrapped": true,
+	"S3AccessKey":          true,
+}
+
+// Display renders the effective configuration as sorted NAME=value
+// lines with secrets redacted, for `gophkeeper-server config validate`.
+func (c *Config) Display() string {
+	v := reflect.ValueOf(*c)
+	t := v.Type()
+	lines := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		lines = append(lines, name+"="+formatValue(name, v.Field(i).Interface()))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func formatValue(name string, value any) string {
+	if redactedFields[name] || strings.Contains(name, "Secret") ||
+		strings.Contains(name, "Password") || strings.Contains(name, "Token") {
+		if fmt.Sprint(value) == "" {
+			return ""
+		}
+		return "[redacted]"
+	}
+	switch v := value.(type) {
+	case time.Duration:
+		return v.String()
+	case []string:
+		return strings.Join(v, ",")
+	case string:
+		if strings.HasSuffix(name, "URI") {
+			return redactURI(v)
+		}
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// redactURI masks the password of a connection string while keeping the
+// parts an operator needs to check.
+func redactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.User == nil {
+		return uri
+	}
+	if _, has := u.User.Password(); has {
+		u.User = url.UserPassword(u.User.Username(), "redacted")
+	}
+	return u.String()
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+)
+
+// Dynamic holds the settings that may change while the server runs. They
+// live in a watched file rather than the environment, because a running
+// process cannot observe changes to its environment.
+type Dynamic struct {
+	// LogLevel is debug, info, warn or error; empty means info.
+	LogLevel string `json:"log_level,omitempty"`
+	// RateLimitRPS and RateLimitBurst shape the per-client request
+	// limit; a zero rate disables limiting.
+	RateLimitRPS   int `json:"rate_limit_rps,omitempty"`
+	RateLimitBurst int `json:"rate_limit_burst,omitempty"`
+	// CORSAllowedOrigins are the browser origins allowed to call the
+	// API; empty sends no CORS headers at all.
+	CORSAllowedOrigins []string `json:"cors_allowed_origins,omitempty"`
+}
+
+// dynamicKeys are the file keys that reload live. Anything else found in
+// the file is reported as requiring a restart.
+var dynamicKeys = map[string]bool{
+	"log_level":            true,
+	"rate_limit_rps":       true,
+	"rate_limit_burst":     true,
+	"cors_allowed_origins": true,
+}
+
+// LoadDynamic reads the dynamic settings file. The second return value
+// lists the keys in the file that are not dynamic, sorted, so the caller
+// can tell the operator a restart is needed for them.
+func LoadDynamic(path string) (*Dynamic, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	var static []string
+	for key := range raw {
+		if !dynamicKeys[key] {
+			static = append(static, key)
+		}
+	}
+	sort.Strings(static)
+	var d Dynamic
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	if d.RateLimitRPS < 0 || d.RateLimitBurst < 0 {
+		return nil, nil, errors.New("config: rate limits must be non-negative")
+	}
+	return &d, static, nil
+}
+
+// dynamicPollInterval is how often the dynamic settings file is checked
+// for a new modification time. Polling needs no platform watcher and is
+// cheap at this cadence.
+const dynamicPollInterval = 5 * time.Second
+
+// WatchDynamic watches the dynamic settings file until ctx is cancelled,
+// calling apply once at startup and again after every change. A
+// malformed update is logged and skipped, so the last good settings stay
+// in effect; keys that cannot reload are logged explicitly.
+func WatchDynamic(ctx context.Context, path string, apply func(*Dynamic)) {
+	var lastMod time.Time
+	load := func() {
+		info, err := os.Stat(path)
+		if err != nil {
+			slog.Warn("dynamic config", "err", err)
+			return
+		}
+		if !info.ModTime().After(lastMod) {
+			return
+		}
+		lastMod = info.ModTime()
+		d, static, err := LoadDynamic(path)
+		if err != nil {
+			slog.Warn("dynamic config not applied", "err", err)
+			return
+		}
+		for _, key := range static {
+			slog.Warn("dynamic config: setting requires a restart to change", "key", key)
+		}
+		apply(d)
+	}
+	load()
+	ticker := time.NewTicker(dynamicPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			load()
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// AdminService is the part of the admin service the handlers use.
+type AdminService interface {
+	middleware.RoleChecker
+	ListUsers(ctx context.Context) ([]models.User, error)
+	SetUserDisabled(ctx context.Context, adminID, userID uuid.UUID, disabled bool) error
+	DeleteUser(ctx context.Context, adminID, userID uuid.UUID) error
+}
+
+// AdminHandler serves the account management API. The router guards every
+// route with the admin role.
+type AdminHandler struct {
+	admin AdminService
+}
+
+// NewAdminHandler creates an AdminHandler.
+func NewAdminHandler(admin AdminService) *AdminHandler {
+	return &AdminHandler{admin: admin}
+}
+
+// adminUser is the account view returned to admins; it exposes fields the
+// user-facing API hides but never the password hash.
+type adminUser struct {
+	ID        uuid.UUID `json:"id"`
+	Login     string    `json:"login"`
+	Role      string    `json:"role"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// List handles GET /api/v1/admin/users.
+func (h *AdminHandler) List(w http.ResponseWriter, r *http.Request) {
+	users, err := h.admin.ListUsers(r.Context())
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	out := make([]adminUser, 0, len(users))
+	for _, u := range users {
+		out = append(out, adminUser{
+			ID:        u.ID,
+			Login:     u.Login,
+			Role:      u.Role,
+			Disabled:  u.Disabled,
+			CreatedAt: u.CreatedAt,
+		})
+	}
+	writeJSON(r.Context(), w, http.StatusOK, out)
+}
+
+// SetDisabled handles PUT /api/v1/admin/users/{id}/disabled.
+func (h *AdminHandler) SetDisabled(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	var req struct {
+		Disabled bool `json:"disabled"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if err := h.admin.SetUserDisabled(r.Context(), adminID, userID, req.Disabled); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Delete handles DELETE /api/v1/admin/users/{id}.
+func (h *AdminHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	if err := h.admin.DeleteUser(r.Context(), adminID, userID); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// AttachmentUpload handles POST /api/v1/secrets/{id}/attachments. The body
+// is JSON with filename and base64 content.
+func (h *SecretsHandler) AttachmentUpload(w http.ResponseWriter, r *http.Request) {
+	userID, secretID, ok := h.userAndID(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Filename string `json:"filename"`
+		Content  []byte `json:"content"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	attachment, err := h.secrets.AddAttachment(r.Context(), userID, secretID, body.Filename, body.Content)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusCreated, attachment)
+}
+
+// AttachmentList handles GET /api/v1/secrets/{id}/attachments.
+func (h *SecretsHandler) AttachmentList(w http.ResponseWriter, r *http.Request) {
+	userID, secretID, ok := h.userAndID(w, r)
+	if !ok {
+		return
+	}
+	attachments, err := h.secrets.ListAttachments(r.Context(), userID, secretID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, attachments)
+}
+
+// AttachmentDownload handles GET /api/v1/secrets/{id}/attachments/{attachmentID}.
+// It streams the decrypted file content.
+func (h *SecretsHandler) AttachmentDownload(w http.ResponseWriter, r *http.Request) {
+	userID, attachmentID, ok := h.userAndAttachmentID(w, r)
+	if !ok {
+		return
+	}
+	attachment, err := h.secrets.GetAttachment(r.Context(), userID, attachmentID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.Filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(attachment.Content) //nolint:errcheck
+}
+
+// AttachmentDelete handles DELETE /api/v1/secrets/{id}/attachments/{attachmentID}.
+func (h *SecretsHandler) AttachmentDelete(w http.ResponseWriter, r *http.Request) {
+	userID, attachmentID, ok := h.userAndAttachmentID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.secrets.DeleteAttachment(r.Context(), userID, attachmentID); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SecretsHandler) userAndAttachmentID(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, _, ok := h.userAndID(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	attachmentID, err := uuid.Parse(chi.URLParam(r, "attachmentID"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid attachment id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, attachmentID, true
+}
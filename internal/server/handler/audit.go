@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// AuditService is the part of the audit service the handlers use.
+type AuditService interface {
+	Export(ctx context.Context, userID uuid.UUID, after int64, format string) (string, int64, error)
+	ListAfter(ctx context.Context, userID uuid.UUID, after int64, limit int) ([]models.AuditEvent, error)
+	LoginHistory(ctx context.Context, userID uuid.UUID, limit int) ([]models.LoginRecord, error)
+}
+
+// AuditHandler serves audit log export.
+type AuditHandler struct {
+	audit AuditService
+}
+
+// NewAuditHandler creates an AuditHandler.
+func NewAuditHandler(audit AuditService) *AuditHandler {
+	return &AuditHandler{audit: audit}
+}
+
+// auditListLimit caps one page of GET /api/v1/audit.
+const (
+	auditListDefaultLimit = 100
+	auditListMaxLimit     = 1000
+)
+
+// List handles GET /api/v1/audit?after=<cursor>&limit=<n>. It returns one
+// JSON page of the caller's audit trail, oldest first; pass the last event
+// ID as `after` for the next page.
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	after, ok := cursorParam(w, r, "after")
+	if !ok {
+		return
+	}
+	limit := auditListDefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > auditListMaxLimit {
+			writeError(r.Context(), w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = parsed
+	}
+	events, err := h.audit.ListAfter(r.Context(), userID, after, limit)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if events == nil {
+		events = []models.AuditEvent{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, events)
+}
+
+// cursorParam parses a non-negative int64 query parameter, writing the
+// error response itself on bad input.
+func cursorParam(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, true
+	}
+	parsed, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || parsed < 0 {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid "+name+" cursor")
+		return 0, false
+	}
+	return parsed, true
+}
+
+// Export handles GET /api/v1/audit/export?after=<cursor>&format=<jsonl|cef>.
+// It streams one page of the caller's audit trail; the X-Next-Cursor header
+// carries the cursor for the following page.
+func (h *AuditHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	after, ok := cursorParam(w, r, "after")
+	if !ok {
+		return
+	}
+	body, next, err := h.audit.Export(r.Context(), userID, after, r.URL.Query().Get("format"))
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Next-Cursor", strconv.FormatInt(next, 10))
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(body)) //nolint:errcheck
+}
+
+// loginHistoryLimit caps one page of GET /api/v1/me/logins.
+const (
+	loginHistoryDefaultLimit = 50
+	loginHistoryMaxLimit     = 500
+)
+
+// Logins handles GET /api/v1/me/logins?limit=<n>. It returns the caller's
+// newest sign-in attempts, successful and failed, newest first.
+func (h *AuditHandler) Logins(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	limit := loginHistoryDefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > loginHistoryMaxLimit {
+			writeError(r.Context(), w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = parsed
+	}
+	records, err := h.audit.LoginHistory(r.Context(), userID, limit)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if records == nil {
+		records = []models.LoginRecord{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, records)
+}
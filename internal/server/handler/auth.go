@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// AuthService is the part of the auth service the handlers use.
+type AuthService interface {
+	Register(ctx context.Context, login, password, email string) (string, error)
+	Login(ctx context.Context, login, password, fingerprint string) (string, error)
+	VerifyPassword(ctx context.Context, userID uuid.UUID, password string) error
+	SetArchivePolicy(ctx context.Context, userID uuid.UUID, days int) error
+	SetHint(ctx context.Context, userID uuid.UUID, hint string) error
+	GetHint(ctx context.Context, userID uuid.UUID) (string, error)
+	SetMasterVerifier(ctx context.Context, userID uuid.UUID, verifier []byte) error
+	GetMasterVerifier(ctx context.Context, userID uuid.UUID) ([]byte, error)
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, password string) (time.Time, error)
+	CancelDeletion(ctx context.Context, userID uuid.UUID) error
+}
+
+// AuthHandler serves registration and login.
+type AuthHandler struct {
+	auth AuthService
+}
+
+// NewAuthHandler creates an AuthHandler.
+func NewAuthHandler(auth AuthService) *AuthHandler {
+	return &AuthHandler{auth: auth}
+}
+
+type tokenResponse struct {
+	Token string `json:"token"`
+}
+
+// Register handles POST /api/v1/auth/register.
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var creds models.Credentials
+	if !readJSON(w, r, &creds) {
+		return
+	}
+	token, err := h.auth.Register(r.Context(), creds.Login, creds.Password, creds.Email)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, tokenResponse{Token: token})
+}
+
+// Login handles POST /api/v1/auth/login.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var creds models.Credentials
+	if !readJSON(w, r, &creds) {
+		return
+	}
+	token, err := h.auth.Login(r.Context(), creds.Login, creds.Password, creds.Fingerprint)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, tokenResponse{Token: token})
+}
+
+// Verify handles POST /api/v1/auth/verify. It re-checks the account
+// password of the authenticated user before sensitive operations.
+func (h *AuthHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var body struct {
+		Password string `json:"password"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if err := h.auth.VerifyPassword(r.Context(), userID, body.Password); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ArchivePolicy handles PUT /api/v1/user/archive-policy. It sets after how
+// many days of inactivity the user's secrets are auto-archived; 0 opts out.
+func (h *AuthHandler) ArchivePolicy(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var body struct {
+		ArchiveAfterDays int `json:"archive_after_days"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if err := h.auth.SetArchivePolicy(r.Context(), userID, body.ArchiveAfterDays); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type deleteAccountResponse struct {
+	DeleteAfter time.Time `json:"delete_after"`
+}
+
+// DeleteAccount handles DELETE /api/v1/me. It re-checks the account
+// password and schedules the account for permanent deletion once the
+// grace period passes, returning the scheduled time.
+func (h *AuthHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var body struct {
+		Password string `json:"password"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	at, err := h.auth.ScheduleDeletion(r.Context(), userID, body.Password)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, deleteAccountResponse{DeleteAfter: at})
+}
+
+// RestoreAccount handles POST /api/v1/me/restore. It cancels a pending
+// account deletion while the grace period lasts.
+func (h *AuthHandler) RestoreAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if err := h.auth.CancelDeletion(r.Context(), userID); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type hintBody struct {
+	Hint string `json:"hint"`
+}
+
+type verifierBody struct {
+	Verifier []byte `json:"verifier"`
+}
+
+// SetMasterVerifier handles PUT /api/v1/user/master-verifier. An empty
+// verifier clears it.
+func (h *AuthHandler) SetMasterVerifier(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var body verifierBody
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if err := h.auth.SetMasterVerifier(r.Context(), userID, body.Verifier); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetMasterVerifier handles GET /api/v1/user/master-verifier.
+func (h *AuthHandler) GetMasterVerifier(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	verifier, err := h.auth.GetMasterVerifier(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, verifierBody{Verifier: verifier})
+}
+
+// SetHint handles PUT /api/v1/user/hint. An empty hint clears it.
+func (h *AuthHandler) SetHint(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var body hintBody
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if err := h.auth.SetHint(r.Context(), userID, body.Hint); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetHint handles GET /api/v1/user/hint.
+func (h *AuthHandler) GetHint(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	hint, err := h.auth.GetHint(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, hintBody{Hint: hint})
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// DeviceService manages registered sync devices.
+type DeviceService interface {
+	Register(ctx context.Context, userID uuid.UUID, name, platform string) (*models.Device, error)
+	List(ctx context.Context, userID uuid.UUID) ([]models.Device, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	Approve(ctx context.Context, userID, id uuid.UUID) error
+	ApproveByToken(ctx context.Context, token string) error
+}
+
+// DeviceHandler serves the device registry endpoints.
+type DeviceHandler struct {
+	devices DeviceService
+}
+
+// NewDeviceHandler creates the handler.
+func NewDeviceHandler(devices DeviceService) *DeviceHandler {
+	return &DeviceHandler{devices: devices}
+}
+
+// Register handles POST /api/v1/devices.
+func (h *DeviceHandler) Register(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var body struct {
+		Name     string `json:"name"`
+		Platform string `json:"platform"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	device, err := h.devices.Register(r.Context(), userID, body.Name, body.Platform)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusCreated, device)
+}
+
+// List handles GET /api/v1/devices.
+func (h *DeviceHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	devices, err := h.devices.List(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if devices == nil {
+		devices = []models.Device{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, devices)
+}
+
+// Revoke handles DELETE /api/v1/devices/{id}.
+func (h *DeviceHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid device id")
+		return
+	}
+	if err := h.devices.Revoke(r.Context(), userID, id); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Approve handles POST /api/v1/devices/{id}/approve. It marks a pending
+// device as trusted from an already-trusted session.
+func (h *DeviceHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid device id")
+		return
+	}
+	if err := h.devices.Approve(r.Context(), userID, id); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ApproveByToken handles GET /device-approval/{token}, the emailed
+// approval link. It needs no session: the token is the proof.
+func (h *DeviceHandler) ApproveByToken(w http.ResponseWriter, r *http.Request) {
+	if err := h.devices.ApproveByToken(r.Context(), chi.URLParam(r, "token")); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, map[string]string{"status": "approved"})
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// EmergencyService is the part of the emergency access service the
+// handlers use.
+type EmergencyService interface {
+	AddContact(ctx context.Context, ownerID uuid.UUID, login string, waitDays int) (*models.EmergencyContact, error)
+	RemoveContact(ctx context.Context, ownerID uuid.UUID, login string) error
+	Contacts(ctx context.Context, ownerID uuid.UUID) ([]models.EmergencyContact, error)
+	Trusts(ctx context.Context, contactID uuid.UUID) ([]models.EmergencyContact, error)
+	RequestAccess(ctx context.Context, contactID uuid.UUID, ownerLogin string) (*models.EmergencyContact, error)
+	DenyAccess(ctx context.Context, ownerID uuid.UUID, login string) error
+	VaultSecrets(ctx context.Context, contactID uuid.UUID, ownerLogin string) ([]models.Secret, error)
+}
+
+// EmergencyHandler serves emergency access: the owner side under
+// /emergency/contacts and the contact side under /emergency/trusts.
+type EmergencyHandler struct {
+	emergency EmergencyService
+}
+
+// NewEmergencyHandler creates an EmergencyHandler.
+func NewEmergencyHandler(emergency EmergencyService) *EmergencyHandler {
+	return &EmergencyHandler{emergency: emergency}
+}
+
+// AddContact handles POST /api/v1/emergency/contacts. The body names the
+// contact by login and the waiting period in days.
+func (h *EmergencyHandler) AddContact(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var req struct {
+		Login    string `json:"login"`
+		WaitDays int    `json:"wait_days"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	contact, err := h.emergency.AddContact(r.Context(), userID, req.Login, req.WaitDays)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusCreated, contact)
+}
+
+// Contacts handles GET /api/v1/emergency/contacts.
+func (h *EmergencyHandler) Contacts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	contacts, err := h.emergency.Contacts(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if contacts == nil {
+		contacts = []models.EmergencyContact{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, contacts)
+}
+
+// RemoveContact handles DELETE /api/v1/emergency/contacts/{login}.
+func (h *EmergencyHandler) RemoveContact(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if err := h.emergency.RemoveContact(r.Context(), userID, chi.URLParam(r, "login")); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deny handles DELETE /api/v1/emergency/contacts/{login}/request,
+// cancelling a pending access request.
+func (h *EmergencyHandler) Deny(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if err := h.emergency.DenyAccess(r.Context(), userID, chi.URLParam(r, "login")); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Trusts handles GET /api/v1/emergency/trusts, listing the vaults the
+// caller is a contact for.
+func (h *EmergencyHandler) Trusts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	trusts, err := h.emergency.Trusts(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if trusts == nil {
+		trusts = []models.EmergencyContact{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, trusts)
+}
+
+// Request handles POST /api/v1/emergency/trusts/{login}/request, starting
+// the waiting period on the named owner's vault.
+func (h *EmergencyHandler) Request(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	designation, err := h.emergency.RequestAccess(r.Context(), userID, chi.URLParam(r, "login"))
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, designation)
+}
+
+// Secrets handles GET /api/v1/emergency/trusts/{login}/secrets, reading
+// the vault once the waiting period has elapsed.
+func (h *EmergencyHandler) Secrets(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	secrets, err := h.emergency.VaultSecrets(r.Context(), userID, chi.URLParam(r, "login"))
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if secrets == nil {
+		secrets = []models.Secret{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, secrets)
+}
@@ -0,0 +1,108 @@
+// Package handler contains the HTTP handlers and the router wiring them to
+// the services.
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/service"
+)
+
+// errorResponse is the JSON body of every non-2xx response. Code is a
+// stable machine-readable identifier, so clients match on it instead of
+// on message wording.
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+// errorCode maps an HTTP status to the error code sent alongside the
+// message. The mapping lives here, centrally, so the wire codes cannot
+// drift between handlers.
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "validation"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusPreconditionFailed:
+		return "protected"
+	case http.StatusRequestEntityTooLarge:
+		return "quota_exceeded"
+	case http.StatusGatewayTimeout:
+		return "timeout"
+	case http.StatusInternalServerError:
+		return "internal"
+	default:
+		return ""
+	}
+}
+
+func writeJSON(ctx context.Context, w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v != nil {
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			slog.WarnContext(ctx, "write response", "err", err)
+		}
+	}
+}
+
+// readJSON decodes the request body into v and answers the error itself
+// when decoding fails: 413 when the body ran over the configured size
+// limit, 400 otherwise. It reports whether decoding succeeded. Nesting
+// depth needs no extra guard; encoding/json caps it on its own.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return true
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(r.Context(), w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+		return false
+	}
+	writeError(r.Context(), w, http.StatusBadRequest, "invalid request body")
+	return false
+}
+
+func writeError(ctx context.Context, w http.ResponseWriter, status int, msg string) {
+	writeJSON(ctx, w, status, errorResponse{Error: msg, Code: errorCode(status)})
+}
+
+// writeServiceError maps service-level errors to HTTP statuses.
+func writeServiceError(ctx context.Context, w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		writeError(ctx, w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrInvalidCredentials):
+		writeError(ctx, w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrUserExists), errors.Is(err, service.ErrEmailExists):
+		writeError(ctx, w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrProtected):
+		writeError(ctx, w, http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, service.ErrQuotaExceeded):
+		writeError(ctx, w, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, service.ErrDeviceRevoked), errors.Is(err, service.ErrDevicePending):
+		writeError(ctx, w, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		writeError(ctx, w, http.StatusNotFound, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(ctx, w, http.StatusGatewayTimeout, "request timed out")
+	default:
+		slog.ErrorContext(ctx, "internal error", "err", err)
+		writeError(ctx, w, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// readyCheckTimeout bounds each dependency probe so a hung dependency
+// fails the readiness check instead of hanging it.
+const readyCheckTimeout = 5 * time.Second
+
+// HealthCheck probes one dependency for the readiness endpoint. Check
+// returns nil when the dependency is usable; Name labels it in the
+// response.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthHandler serves the liveness and readiness probes.
+type HealthHandler struct {
+	checks []HealthCheck
+}
+
+// NewHealthHandler creates a HealthHandler running the given dependency
+// checks on readiness.
+func NewHealthHandler(checks []HealthCheck) *HealthHandler {
+	return &HealthHandler{checks: checks}
+}
+
+type healthResponse struct {
+	Status string `json:"status"`
+	// Checks maps each dependency to "ok" or its failure message.
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Live handles GET /healthz. It only confirms the process serves HTTP, so
+// a failing dependency restarts nothing; that is what readiness is for.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(r.Context(), w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// Ready handles GET /readyz. It probes every registered dependency and
+// reports 503 with per-check details while any of them is unusable, so
+// orchestrators keep traffic away until the server can actually serve it.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	resp := healthResponse{Status: "ok", Checks: make(map[string]string, len(h.checks))}
+	status := http.StatusOK
+	for _, c := range h.checks {
+		ctx, cancel := context.WithTimeout(r.Context(), readyCheckTimeout)
+		err := c.Check(ctx)
+		cancel()
+		if err != nil {
+			resp.Status = "unavailable"
+			resp.Checks[c.Name] = err.Error()
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[c.Name] = "ok"
+	}
+	writeJSON(r.Context(), w, status, resp)
+}
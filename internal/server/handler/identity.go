@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/base64"
+	"net/http"
+)
+
+// IdentityService signs a client-chosen nonce with the server's identity
+// key.
+type IdentityService interface {
+	Sign(nonce []byte) (publicKey, signature []byte, err error)
+}
+
+// IdentityHandler serves the server identity for client-side pinning.
+type IdentityHandler struct {
+	identity IdentityService
+}
+
+// NewIdentityHandler creates the handler.
+func NewIdentityHandler(identity IdentityService) *IdentityHandler {
+	return &IdentityHandler{identity: identity}
+}
+
+type identityResponse struct {
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// Get signs the nonce from the query string and returns the public key
+// with the signature, both base64-encoded. The route is unauthenticated:
+// clients verify the server before trusting it with credentials.
+func (h *IdentityHandler) Get(w http.ResponseWriter, r *http.Request) {
+	nonce, err := base64.RawURLEncoding.DecodeString(r.URL.Query().Get("nonce"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "nonce must be base64url")
+		return
+	}
+	publicKey, signature, err := h.identity.Sign(nonce)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, identityResponse{
+		PublicKey: base64.StdEncoding.EncodeToString(publicKey),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	})
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/service"
+)
+
+// JWKSHandler serves the public JWT signing keys in JWKS form, so other
+// internal services can verify tokens without sharing a secret.
+type JWKSHandler struct {
+	document jwksDocument
+}
+
+type jwksDocument struct {
+	Keys []service.JWK `json:"keys"`
+}
+
+// NewJWKSHandler creates a JWKSHandler. The key set is fixed at startup:
+// changing keys means restarting anyway.
+func NewJWKSHandler(keys []service.JWK) *JWKSHandler {
+	if keys == nil {
+		keys = []service.JWK{}
+	}
+	return &JWKSHandler{document: jwksDocument{Keys: keys}}
+}
+
+// Get handles GET /.well-known/jwks.json. Only public keys appear here;
+// deployments signing with shared secrets publish an empty set.
+func (h *JWKSHandler) Get(w http.ResponseWriter, r *http.Request) {
+	writeJSON(r.Context(), w, http.StatusOK, h.document)
+}
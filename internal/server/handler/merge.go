@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// MergeService folds one account into another and reports how many secrets
+// moved.
+type MergeService interface {
+	Merge(ctx context.Context, targetID uuid.UUID, targetPassword, sourceLogin, sourcePassword string) (int, error)
+}
+
+// MergeHandler serves the account-merge endpoint.
+type MergeHandler struct {
+	merge MergeService
+}
+
+// NewMergeHandler creates the handler.
+func NewMergeHandler(merge MergeService) *MergeHandler {
+	return &MergeHandler{merge: merge}
+}
+
+type mergeRequest struct {
+	// Password re-authenticates the caller, who becomes the target.
+	Password       string `json:"password"`
+	SourceLogin    string `json:"source_login"`
+	SourcePassword string `json:"source_password"`
+}
+
+type mergeResponse struct {
+	MovedSecrets int `json:"moved_secrets"`
+}
+
+// Merge handles POST /api/v1/account/merge. The caller supplies their own
+// password plus the credentials of the account being absorbed.
+func (h *MergeHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var body mergeRequest
+	if !readJSON(w, r, &body) {
+		return
+	}
+	moved, err := h.merge.Merge(r.Context(), userID, body.Password, body.SourceLogin, body.SourcePassword)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, mergeResponse{MovedSecrets: moved})
+}
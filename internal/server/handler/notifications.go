@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// NotificationService manages per-user notification preferences.
+type NotificationService interface {
+	Preferences(ctx context.Context, userID uuid.UUID) ([]string, error)
+	SetPreferences(ctx context.Context, userID uuid.UUID, disabled []string) error
+}
+
+// NotificationHandler serves the notification preference endpoints.
+type NotificationHandler struct {
+	notifications NotificationService
+}
+
+// NewNotificationHandler creates the handler.
+func NewNotificationHandler(notifications NotificationService) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications}
+}
+
+// notifyPrefsBody lists the notification kinds the user opted out of; an
+// empty list means every alert is delivered.
+type notifyPrefsBody struct {
+	Disabled []string `json:"disabled"`
+}
+
+// Preferences handles GET /api/v1/user/notifications.
+func (h *NotificationHandler) Preferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	disabled, err := h.notifications.Preferences(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, notifyPrefsBody{Disabled: disabled})
+}
+
+// SetPreferences handles PUT /api/v1/user/notifications.
+func (h *NotificationHandler) SetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var body notifyPrefsBody
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if err := h.notifications.SetPreferences(r.Context(), userID, body.Disabled); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// OrgService is the part of the org service the handlers use.
+type OrgService interface {
+	CreateOrg(ctx context.Context, userID uuid.UUID, name string) (*models.Organization, error)
+	ListOrgs(ctx context.Context, userID uuid.UUID) ([]models.Organization, error)
+	InviteMember(ctx context.Context, actorID, orgID uuid.UUID, login string) (*models.OrgMember, error)
+	ListMembers(ctx context.Context, actorID, orgID uuid.UUID) ([]models.OrgMember, error)
+	ListOrgSecrets(ctx context.Context, userID, orgID uuid.UUID) ([]models.Secret, error)
+	MoveSecretToOrg(ctx context.Context, userID, orgID, secretID uuid.UUID) error
+	MoveSecretToPersonal(ctx context.Context, userID, orgID, secretID uuid.UUID) error
+}
+
+// OrgHandler serves organization vaults for the authenticated user.
+type OrgHandler struct {
+	orgs OrgService
+}
+
+// NewOrgHandler creates an OrgHandler.
+func NewOrgHandler(orgs OrgService) *OrgHandler {
+	return &OrgHandler{orgs: orgs}
+}
+
+// Create handles POST /api/v1/orgs. The caller becomes the org owner.
+func (h *OrgHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	org, err := h.orgs.CreateOrg(r.Context(), userID, req.Name)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusCreated, org)
+}
+
+// List handles GET /api/v1/orgs.
+func (h *OrgHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	orgs, err := h.orgs.ListOrgs(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if orgs == nil {
+		orgs = []models.Organization{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, orgs)
+}
+
+// Invite handles POST /api/v1/orgs/{id}/members. The body names the user
+// to add by login.
+func (h *OrgHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := h.userAndOrg(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		Login string `json:"login"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	member, err := h.orgs.InviteMember(r.Context(), userID, orgID, req.Login)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusCreated, member)
+}
+
+// Members handles GET /api/v1/orgs/{id}/members.
+func (h *OrgHandler) Members(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := h.userAndOrg(w, r)
+	if !ok {
+		return
+	}
+	members, err := h.orgs.ListMembers(r.Context(), userID, orgID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if members == nil {
+		members = []models.OrgMember{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, members)
+}
+
+// Secrets handles GET /api/v1/orgs/{id}/secrets.
+func (h *OrgHandler) Secrets(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := h.userAndOrg(w, r)
+	if !ok {
+		return
+	}
+	secrets, err := h.orgs.ListOrgSecrets(r.Context(), userID, orgID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if secrets == nil {
+		secrets = []models.Secret{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, secrets)
+}
+
+// AttachSecret handles PUT /api/v1/orgs/{id}/secrets/{secretID}, moving one
+// of the caller's personal secrets into the org vault.
+func (h *OrgHandler) AttachSecret(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := h.userAndOrg(w, r)
+	if !ok {
+		return
+	}
+	secretID, err := uuid.Parse(chi.URLParam(r, "secretID"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid secret id")
+		return
+	}
+	if err := h.orgs.MoveSecretToOrg(r.Context(), userID, orgID, secretID); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DetachSecret handles DELETE /api/v1/orgs/{id}/secrets/{secretID}, moving
+// an org secret into the caller's personal vault.
+func (h *OrgHandler) DetachSecret(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := h.userAndOrg(w, r)
+	if !ok {
+		return
+	}
+	secretID, err := uuid.Parse(chi.URLParam(r, "secretID"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid secret id")
+		return
+	}
+	if err := h.orgs.MoveSecretToPersonal(r.Context(), userID, orgID, secretID); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *OrgHandler) userAndOrg(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid org id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, orgID, true
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// ReportService is the part of the report service the handlers use.
+type ReportService interface {
+	AccessReport(ctx context.Context, userID uuid.UUID) (*models.AccessReport, error)
+}
+
+// ReportHandler serves access review reports.
+type ReportHandler struct {
+	reports ReportService
+}
+
+// NewReportHandler creates a ReportHandler.
+func NewReportHandler(reports ReportService) *ReportHandler {
+	return &ReportHandler{reports: reports}
+}
+
+// Access handles GET /api/v1/report/access.
+func (h *ReportHandler) Access(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	report, err := h.reports.AccessReport(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, report)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/service"
+)
+
+// RotationService is the part of the rotation service the handler uses.
+type RotationService interface {
+	Rotate(ctx context.Context, adminID uuid.UUID) (*service.RotationReport, error)
+}
+
+// RotateHandler serves the master key rotation endpoint. The router guards
+// it with the admin role.
+type RotateHandler struct {
+	rotation RotationService
+}
+
+// NewRotateHandler creates a RotateHandler.
+func NewRotateHandler(rotation RotationService) *RotateHandler {
+	return &RotateHandler{rotation: rotation}
+}
+
+// Rotate handles POST /api/v1/admin/crypto/rotate. The run is synchronous:
+// the response arrives once every vault is rewritten, carrying the counts.
+// Interrupted runs are safe to repeat — already rotated items are skipped.
+func (h *RotateHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	report, err := h.rotation.Rotate(r.Context(), adminID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, report)
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/service"
+)
+
+// Services bundles everything the router needs to wire the routes.
+type Services struct {
+	Auth     AuthService
+	Tokens   middleware.TokenParser
+	Secrets  SecretsService
+	Audit    AuditService
+	Reports  ReportService
+	Sync     SyncService
+	Identity IdentityService
+	Share    ShareService
+	Merge    MergeService
+	Stream   StreamHub
+	Devices  DeviceService
+	Webhooks WebhookService
+	// Notifications manages the per-user alert preferences.
+	Notifications NotificationService
+	Admin         AdminService
+	// Rotation re-encrypts stored data under a staged master key; nil
+	// disables the endpoint.
+	Rotation RotationService
+	Orgs     OrgService
+	// UserShares is the per-user sharing service; Share serves one-time
+	// public links.
+	UserShares UserShareService
+	Emergency  EmergencyService
+	// ShareBrand is the title of the public share pages; empty means the
+	// default.
+	ShareBrand string
+	// RequestTimeout bounds ordinary requests with a context deadline;
+	// zero disables it. The SSE stream is always exempt.
+	RequestTimeout time.Duration
+	// SyncTimeout bounds /sync, which pushes whole vaults and needs more
+	// room than ordinary requests; zero disables it.
+	SyncTimeout time.Duration
+	// MaxBodyBytes caps request bodies so an oversized upload is rejected
+	// with 413 before it is read; zero disables the cap. MaxSyncBodyBytes
+	// applies to /sync instead.
+	MaxBodyBytes     int64
+	MaxSyncBodyBytes int64
+	// HealthChecks are the dependency probes /readyz runs; /healthz needs
+	// none.
+	HealthChecks []HealthCheck
+	// RateLimit and CORS are the hot-reloadable request middlewares,
+	// driven by the watched dynamic configuration file; nil disables
+	// them.
+	RateLimit *middleware.RateLimiter
+	CORS      *middleware.CORS
+	// JWKS are the public JWT signing keys published at
+	// /.well-known/jwks.json; empty publishes an empty set.
+	JWKS []service.JWK
+}
+
+// NewRouter builds the chi router with all API routes.
+func NewRouter(s Services) http.Handler {
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Logger)
+	if s.CORS != nil {
+		r.Use(s.CORS.Handler)
+	}
+	if s.RateLimit != nil {
+		r.Use(s.RateLimit.Handler)
+	}
+	r.Use(chimiddleware.Recoverer)
+	// Large vaults make sync payloads heavy; compress both directions when
+	// the client asks for it.
+	r.Use(chimiddleware.Compress(5, "application/json"))
+	r.Use(middleware.DecompressRequest)
+
+	authHandler := NewAuthHandler(s.Auth)
+	secretsHandler := NewSecretsHandler(s.Secrets)
+	auditHandler := NewAuditHandler(s.Audit)
+	reportHandler := NewReportHandler(s.Reports)
+	syncHandler := NewSyncHandler(s.Sync)
+	identityHandler := NewIdentityHandler(s.Identity)
+	shareHandler := NewShareHandler(s.Share, s.ShareBrand)
+	mergeHandler := NewMergeHandler(s.Merge)
+	streamHandler := NewStreamHandler(s.Stream)
+	deviceHandler := NewDeviceHandler(s.Devices)
+	adminHandler := NewAdminHandler(s.Admin)
+	orgHandler := NewOrgHandler(s.Orgs)
+	userShareHandler := NewUserShareHandler(s.UserShares)
+	emergencyHandler := NewEmergencyHandler(s.Emergency)
+	webhookHandler := NewWebhookHandler(s.Webhooks)
+	notificationHandler := NewNotificationHandler(s.Notifications)
+
+	timeout := middleware.Timeout(s.RequestTimeout)
+	bodyLimit := middleware.MaxBody(s.MaxBodyBytes)
+
+	// Probes for orchestrators; readiness runs the dependency checks with
+	// its own per-check timeout.
+	healthHandler := NewHealthHandler(s.HealthChecks)
+	r.Get("/healthz", healthHandler.Live)
+	r.Get("/readyz", healthHandler.Ready)
+
+	// Public pages for recipients without the CLI.
+	r.With(timeout, bodyLimit).Get("/share/{token}", shareHandler.Confirm)
+	r.With(timeout, bodyLimit).Post("/share/{token}", shareHandler.Reveal)
+
+	// The emailed device-approval link; the token is the proof.
+	r.With(timeout, bodyLimit).Get("/device-approval/{token}", deviceHandler.ApproveByToken)
+
+	// Public signing keys for services validating our tokens themselves.
+	jwksHandler := NewJWKSHandler(s.JWKS)
+	r.With(timeout, bodyLimit).Get("/.well-known/jwks.json", jwksHandler.Get)
+
+	r.Route("/api/v1", func(r chi.Router) {
+		r.With(timeout, bodyLimit).Get("/identity", identityHandler.Get)
+		r.With(timeout, bodyLimit).Post("/auth/register", authHandler.Register)
+		r.With(timeout, bodyLimit).Post("/auth/login", authHandler.Login)
+
+		// The SSE stream lives until the client hangs up and /sync can push
+		// a whole vault, so they sit outside the default timeout and body
+		// limit.
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Auth(s.Tokens))
+			r.Get("/sync/stream", streamHandler.Stream)
+			r.With(middleware.Timeout(s.SyncTimeout), middleware.MaxBody(s.MaxSyncBodyBytes)).
+				Post("/sync", syncHandler.Sync)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Auth(s.Tokens))
+			r.Use(timeout)
+			r.Use(bodyLimit)
+			r.Post("/auth/verify", authHandler.Verify)
+			r.Put("/user/archive-policy", authHandler.ArchivePolicy)
+			r.Put("/user/hint", authHandler.SetHint)
+			r.Get("/user/hint", authHandler.GetHint)
+			r.Put("/user/master-verifier", authHandler.SetMasterVerifier)
+			r.Get("/user/master-verifier", authHandler.GetMasterVerifier)
+			r.Get("/user/notifications", notificationHandler.Preferences)
+			r.Put("/user/notifications", notificationHandler.SetPreferences)
+			r.Post("/account/merge", mergeHandler.Merge)
+			r.Get("/me/usage", secretsHandler.Usage)
+			r.Get("/me/logins", auditHandler.Logins)
+			r.Delete("/me", authHandler.DeleteAccount)
+			r.Post("/me/restore", authHandler.RestoreAccount)
+			r.Route("/secrets", func(r chi.Router) {
+				r.Get("/", secretsHandler.List)
+				r.Post("/", secretsHandler.Create)
+				r.Get("/{id}", secretsHandler.Get)
+				r.Put("/{id}", secretsHandler.Update)
+				r.Delete("/{id}", secretsHandler.Delete)
+				r.Post("/{id}/use-code", secretsHandler.UseRecoveryCode)
+				r.Get("/{id}/share", userShareHandler.List)
+				r.Post("/{id}/share", userShareHandler.Grant)
+				r.Delete("/{id}/share/{login}", userShareHandler.Revoke)
+				r.Post("/{id}/links", shareHandler.CreateLink)
+				r.Post("/{id}/convert", secretsHandler.Convert)
+				r.Get("/{id}/versions", secretsHandler.Versions)
+				r.Get("/{id}/versions/{version}", secretsHandler.Version)
+				r.Route("/{id}/attachments", func(r chi.Router) {
+					r.Get("/", secretsHandler.AttachmentList)
+					r.Post("/", secretsHandler.AttachmentUpload)
+					r.Get("/{attachmentID}", secretsHandler.AttachmentDownload)
+					r.Delete("/{attachmentID}", secretsHandler.AttachmentDelete)
+				})
+			})
+			r.Route("/shared", func(r chi.Router) {
+				r.Get("/", userShareHandler.Received)
+				r.Get("/{id}", userShareHandler.Get)
+				r.Put("/{id}", userShareHandler.Update)
+			})
+			r.Route("/devices", func(r chi.Router) {
+				r.Get("/", deviceHandler.List)
+				r.Post("/", deviceHandler.Register)
+				r.Post("/{id}/approve", deviceHandler.Approve)
+				r.Delete("/{id}", deviceHandler.Revoke)
+			})
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Get("/", webhookHandler.List)
+				r.Post("/", webhookHandler.Create)
+				r.Delete("/{id}", webhookHandler.Delete)
+				r.Get("/{id}/deliveries", webhookHandler.Deliveries)
+			})
+			r.Route("/orgs", func(r chi.Router) {
+				r.Get("/", orgHandler.List)
+				r.Post("/", orgHandler.Create)
+				r.Get("/{id}/members", orgHandler.Members)
+				r.Post("/{id}/members", orgHandler.Invite)
+				r.Get("/{id}/secrets", orgHandler.Secrets)
+				r.Put("/{id}/secrets/{secretID}", orgHandler.AttachSecret)
+				r.Delete("/{id}/secrets/{secretID}", orgHandler.DetachSecret)
+			})
+			r.Route("/emergency", func(r chi.Router) {
+				r.Get("/contacts", emergencyHandler.Contacts)
+				r.Post("/contacts", emergencyHandler.AddContact)
+				r.Delete("/contacts/{login}", emergencyHandler.RemoveContact)
+				r.Delete("/contacts/{login}/request", emergencyHandler.Deny)
+				r.Get("/trusts", emergencyHandler.Trusts)
+				r.Post("/trusts/{login}/request", emergencyHandler.Request)
+				r.Get("/trusts/{login}/secrets", emergencyHandler.Secrets)
+			})
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(middleware.RequireRole(s.Admin, models.RoleAdmin))
+				r.Get("/users", adminHandler.List)
+				r.Put("/users/{id}/disabled", adminHandler.SetDisabled)
+				r.Delete("/users/{id}", adminHandler.Delete)
+				if s.Rotation != nil {
+					rotateHandler := NewRotateHandler(s.Rotation)
+					r.Post("/crypto/rotate", rotateHandler.Rotate)
+				}
+			})
+			r.Get("/audit", auditHandler.List)
+			r.Get("/audit/export", auditHandler.Export)
+			r.Get("/report/access", reportHandler.Access)
+		})
+	})
+	return r
+}
@@ -0,0 +1,500 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/service"
+)
+
+// newTestServer wires the full router against in-memory repositories, so
+// the suite exercises the same stack as production minus Postgres and S3.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	master := []byte("test master key")
+	users := memory.NewUserRepository()
+	secretRepo := memory.NewSecretRepository()
+	audit := service.NewAuditService(memory.NewAuditRepository(), nil, master)
+	auth := service.NewAuthService(users, []byte("test jwt secret"), master, audit)
+	secrets := service.NewSecretsService(service.SecretsServiceDeps{
+		Secrets:     secretRepo,
+		Attachments: memory.NewAttachmentRepository(),
+		Versions:    memory.NewSecretVersionRepository(),
+		Outbox:      memory.NewOutboxRepository(),
+		TM:          repository.NoopTransactionManager{},
+		MasterKey:   master,
+		Audit:       audit,
+	})
+	shareRepo := memory.NewSecretShareRepository()
+	orgRepo := memory.NewOrgRepository()
+	srv := httptest.NewServer(NewRouter(Services{
+		Auth:       auth,
+		Tokens:     auth,
+		Secrets:    secrets,
+		Audit:      audit,
+		Reports:    service.NewReportService(secrets, users, shareRepo),
+		Sync:       service.NewSyncService(secrets, audit, memory.NewDeviceRepository()),
+		Identity:   service.NewIdentityService(master),
+		Share:      service.NewShareService(memory.NewShareRepository(), secrets, audit),
+		Merge:      service.NewMergeService(users, secrets, repository.NoopTransactionManager{}, audit),
+		Stream:     service.NewStreamHub(),
+		Devices:    service.NewDeviceService(memory.NewDeviceRepository(), audit),
+		Admin:      service.NewAdminService(users, audit),
+		Rotation:   service.NewRotationService(users, orgRepo, secrets, []byte("next master key"), audit),
+		Orgs:       service.NewOrgService(orgRepo, users, secrets, audit),
+		UserShares: service.NewUserShareService(shareRepo, users, secrets, audit),
+		Emergency:  service.NewEmergencyService(memory.NewEmergencyRepository(), users, secrets, audit, nil),
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// call sends a JSON request and returns the status code and decoded body.
+func call(t *testing.T, srv *httptest.Server, method, path, token string, body any) (int, []byte) {
+	t.Helper()
+	var reqBody io.Reader
+	if body != nil {
+		switch b := body.(type) {
+		case string:
+			reqBody = bytes.NewBufferString(b)
+		default:
+			data, err := json.Marshal(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reqBody = bytes.NewReader(data)
+		}
+	}
+	req, err := http.NewRequest(method, srv.URL+path, reqBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, data
+}
+
+func registerUser(t *testing.T, srv *httptest.Server, login string) string {
+	t.Helper()
+	status, body := call(t, srv, http.MethodPost, "/api/v1/auth/register", "",
+		models.Credentials{Login: login, Password: "password"})
+	if status != http.StatusOK {
+		t.Fatalf("register %s: status %d: %s", login, status, body)
+	}
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Token
+}
+
+func createSecret(t *testing.T, srv *httptest.Server, token string, secret *models.Secret) uuid.UUID {
+	t.Helper()
+	status, body := call(t, srv, http.MethodPost, "/api/v1/secrets", token, secret)
+	if status != http.StatusCreated {
+		t.Fatalf("create secret: status %d: %s", status, body)
+	}
+	var resp struct {
+		Secret models.Secret `json:"secret"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Secret.ID
+}
+
+func credentialsSecret(t *testing.T, name string) *models.Secret {
+	t.Helper()
+	secret := &models.Secret{Name: name, Type: models.SecretTypeCredentials}
+	if err := secret.EncodePayload(&models.CredentialsPayload{Login: "user", Password: "pw"}); err != nil {
+		t.Fatal(err)
+	}
+	return secret
+}
+
+// TestRoutesRequireAuth sweeps every protected route without a token.
+func TestRoutesRequireAuth(t *testing.T) {
+	srv := newTestServer(t)
+	id := uuid.New()
+	routes := []struct {
+		method, path string
+	}{
+		{http.MethodPost, "/api/v1/auth/verify"},
+		{http.MethodPut, "/api/v1/user/archive-policy"},
+		{http.MethodPut, "/api/v1/user/hint"},
+		{http.MethodGet, "/api/v1/user/hint"},
+		{http.MethodGet, "/api/v1/secrets"},
+		{http.MethodPost, "/api/v1/secrets"},
+		{http.MethodGet, "/api/v1/secrets/" + id.String()},
+		{http.MethodPut, "/api/v1/secrets/" + id.String()},
+		{http.MethodDelete, "/api/v1/secrets/" + id.String()},
+		{http.MethodPost, "/api/v1/secrets/" + id.String() + "/use-code"},
+		{http.MethodPost, "/api/v1/secrets/" + id.String() + "/convert"},
+		{http.MethodGet, "/api/v1/secrets/" + id.String() + "/versions"},
+		{http.MethodGet, "/api/v1/secrets/" + id.String() + "/versions/1"},
+		{http.MethodGet, "/api/v1/secrets/" + id.String() + "/attachments"},
+		{http.MethodPost, "/api/v1/secrets/" + id.String() + "/attachments"},
+		{http.MethodGet, "/api/v1/secrets/" + id.String() + "/attachments/" + id.String()},
+		{http.MethodDelete, "/api/v1/secrets/" + id.String() + "/attachments/" + id.String()},
+		{http.MethodPost, "/api/v1/sync"},
+		{http.MethodGet, "/api/v1/sync/stream"},
+		{http.MethodGet, "/api/v1/devices"},
+		{http.MethodPost, "/api/v1/devices"},
+		{http.MethodGet, "/api/v1/secrets/" + id.String() + "/share"},
+		{http.MethodPost, "/api/v1/secrets/" + id.String() + "/share"},
+		{http.MethodPost, "/api/v1/secrets/" + id.String() + "/links"},
+		{http.MethodGet, "/api/v1/shared"},
+		{http.MethodGet, "/api/v1/shared/" + id.String()},
+		{http.MethodGet, "/api/v1/orgs"},
+		{http.MethodPost, "/api/v1/orgs"},
+		{http.MethodGet, "/api/v1/orgs/" + id.String() + "/members"},
+		{http.MethodGet, "/api/v1/orgs/" + id.String() + "/secrets"},
+		{http.MethodGet, "/api/v1/emergency/contacts"},
+		{http.MethodPost, "/api/v1/emergency/contacts"},
+		{http.MethodGet, "/api/v1/emergency/trusts"},
+		{http.MethodGet, "/api/v1/emergency/trusts/alice/secrets"},
+		{http.MethodPost, "/api/v1/account/merge"},
+		{http.MethodGet, "/api/v1/audit"},
+		{http.MethodGet, "/api/v1/audit/export"},
+		{http.MethodGet, "/api/v1/report/access"},
+	}
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			status, _ := call(t, srv, route.method, route.path, "", nil)
+			if status != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", status, http.StatusUnauthorized)
+			}
+		})
+	}
+}
+
+// TestRoutesValidationAndErrors drives every route through its happy path
+// and its table of auth, validation and not-found cases.
+func TestRoutesValidationAndErrors(t *testing.T) {
+	srv := newTestServer(t)
+	token := registerUser(t, srv, "alice")
+	secretID := createSecret(t, srv, token, credentialsSecret(t, "example.com"))
+	recovery := &models.Secret{Name: "codes", Type: models.SecretTypeRecoveryCodes}
+	if err := recovery.EncodePayload(&models.RecoveryCodesPayload{
+		Codes: []models.RecoveryCode{{Code: "aaaa-bbbb"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	recoveryID := createSecret(t, srv, token, recovery)
+
+	updated := credentialsSecret(t, "example.com")
+	converted := &models.Secret{Name: "note", Type: models.SecretTypeText}
+	if err := converted.EncodePayload(&models.TextPayload{Content: "moved"}); err != nil {
+		t.Fatal(err)
+	}
+	attachment := struct {
+		Filename string `json:"filename"`
+		Content  []byte `json:"content"`
+	}{Filename: "a.txt", Content: []byte("hello")}
+
+	unknown := uuid.New().String()
+	tests := []struct {
+		name, method, path string
+		body               any
+		want               int
+	}{
+		{"identity ok", http.MethodGet, "/api/v1/identity?nonce=AAAAAAAAAAAAAAAAAAAAAA", nil, http.StatusOK},
+		{"identity bad encoding", http.MethodGet, "/api/v1/identity?nonce=!!!", nil, http.StatusBadRequest},
+		{"identity short nonce", http.MethodGet, "/api/v1/identity?nonce=AAAA", nil, http.StatusBadRequest},
+		{"register duplicate", http.MethodPost, "/api/v1/auth/register", models.Credentials{Login: "alice", Password: "x"}, http.StatusConflict},
+		{"register bad body", http.MethodPost, "/api/v1/auth/register", "{", http.StatusBadRequest},
+		{"register empty", http.MethodPost, "/api/v1/auth/register", models.Credentials{}, http.StatusUnauthorized},
+		{"login ok", http.MethodPost, "/api/v1/auth/login", models.Credentials{Login: "alice", Password: "password"}, http.StatusOK},
+		{"login wrong password", http.MethodPost, "/api/v1/auth/login", models.Credentials{Login: "alice", Password: "nope"}, http.StatusUnauthorized},
+		{"login bad body", http.MethodPost, "/api/v1/auth/login", "{", http.StatusBadRequest},
+		{"verify ok", http.MethodPost, "/api/v1/auth/verify", map[string]string{"password": "password"}, http.StatusNoContent},
+		{"verify wrong", http.MethodPost, "/api/v1/auth/verify", map[string]string{"password": "nope"}, http.StatusUnauthorized},
+		{"archive policy ok", http.MethodPut, "/api/v1/user/archive-policy", map[string]int{"archive_after_days": 30}, http.StatusNoContent},
+		{"archive policy negative", http.MethodPut, "/api/v1/user/archive-policy", map[string]int{"archive_after_days": -1}, http.StatusBadRequest},
+		{"hint set", http.MethodPut, "/api/v1/user/hint", map[string]string{"hint": "scheme"}, http.StatusNoContent},
+		{"hint get", http.MethodGet, "/api/v1/user/hint", nil, http.StatusOK},
+		{"secrets list", http.MethodGet, "/api/v1/secrets", nil, http.StatusOK},
+		{"secrets list bad order", http.MethodGet, "/api/v1/secrets?order=sideways", nil, http.StatusBadRequest},
+		{"secrets list bad limit", http.MethodGet, "/api/v1/secrets?limit=0", nil, http.StatusBadRequest},
+		{"secrets create bad type", http.MethodPost, "/api/v1/secrets", &models.Secret{Name: "x", Type: "bogus"}, http.StatusBadRequest},
+		{"secrets create bad body", http.MethodPost, "/api/v1/secrets", "{", http.StatusBadRequest},
+		{"secret get", http.MethodGet, "/api/v1/secrets/" + secretID.String(), nil, http.StatusOK},
+		{"secret get unknown", http.MethodGet, "/api/v1/secrets/" + unknown, nil, http.StatusNotFound},
+		{"secret get bad id", http.MethodGet, "/api/v1/secrets/not-a-uuid", nil, http.StatusBadRequest},
+		{"secret update", http.MethodPut, "/api/v1/secrets/" + secretID.String(), updated, http.StatusOK},
+		{"secret update unknown", http.MethodPut, "/api/v1/secrets/" + unknown, updated, http.StatusNotFound},
+		{"use-code ok", http.MethodPost, "/api/v1/secrets/" + recoveryID.String() + "/use-code", nil, http.StatusOK},
+		{"use-code wrong type", http.MethodPost, "/api/v1/secrets/" + secretID.String() + "/use-code", nil, http.StatusBadRequest},
+		{"convert", http.MethodPost, "/api/v1/secrets/" + secretID.String() + "/convert", converted, http.StatusOK},
+		{"versions list", http.MethodGet, "/api/v1/secrets/" + secretID.String() + "/versions", nil, http.StatusOK},
+		{"version bad number", http.MethodGet, "/api/v1/secrets/" + secretID.String() + "/versions/zero", nil, http.StatusBadRequest},
+		{"attachment upload", http.MethodPost, "/api/v1/secrets/" + secretID.String() + "/attachments", attachment, http.StatusCreated},
+		{"attachment list", http.MethodGet, "/api/v1/secrets/" + secretID.String() + "/attachments", nil, http.StatusOK},
+		{"attachment unknown", http.MethodGet, "/api/v1/secrets/" + secretID.String() + "/attachments/" + unknown, nil, http.StatusNotFound},
+		{"sync", http.MethodPost, "/api/v1/sync", models.SyncRequest{}, http.StatusOK},
+		{"sync bad body", http.MethodPost, "/api/v1/sync", "{", http.StatusBadRequest},
+		{"audit export", http.MethodGet, "/api/v1/audit/export", nil, http.StatusOK},
+		{"report access", http.MethodGet, "/api/v1/report/access", nil, http.StatusOK},
+		{"secret delete", http.MethodDelete, "/api/v1/secrets/" + recoveryID.String(), nil, http.StatusNoContent},
+		{"secret delete unknown", http.MethodDelete, "/api/v1/secrets/" + unknown, nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := call(t, srv, tt.method, tt.path, token, tt.body)
+			if status != tt.want {
+				t.Errorf("status = %d, want %d (body: %s)", status, tt.want, body)
+			}
+		})
+	}
+}
+
+// TestRouterConcurrentRequests hammers the stack from many goroutines so
+// `go test -race` can catch shared-state races in handlers and services.
+func TestRouterConcurrentRequests(t *testing.T) {
+	srv := newTestServer(t)
+	token := registerUser(t, srv, "alice")
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := createSecret(t, srv, token, credentialsSecret(t, fmt.Sprintf("site-%d", i)))
+			if status, body := call(t, srv, http.MethodGet, "/api/v1/secrets/"+id.String(), token, nil); status != http.StatusOK {
+				t.Errorf("get: status %d: %s", status, body)
+			}
+			if status, body := call(t, srv, http.MethodGet, "/api/v1/secrets", token, nil); status != http.StatusOK {
+				t.Errorf("list: status %d: %s", status, body)
+			}
+			if status, body := call(t, srv, http.MethodDelete, "/api/v1/secrets/"+id.String(), token, nil); status != http.StatusNoContent {
+				t.Errorf("delete: status %d: %s", status, body)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestCompression checks both negotiation directions: gzipped request
+// bodies are inflated, and responses come back gzipped when asked for.
+func TestCompression(t *testing.T) {
+	srv := newTestServer(t)
+	token := registerUser(t, srv, "alice")
+	createSecret(t, srv, token, credentialsSecret(t, "example.com"))
+
+	creds, err := json.Marshal(models.Credentials{Login: "alice", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(creds); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/v1/auth/login", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("gzipped login: status %d", resp.StatusCode)
+	}
+
+	req, err = http.NewRequest(http.MethodGet, srv.URL+"/api/v1/secrets", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	// Set Accept-Encoding by hand so the transport does not inflate the
+	// body behind our back.
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err = srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", resp.Header.Get("Content-Encoding"))
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var secrets []models.Secret
+	if err := json.NewDecoder(zr).Decode(&secrets); err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 1 {
+		t.Fatalf("got %d secrets, want 1", len(secrets))
+	}
+}
+
+// TestRequestID checks the correlation ID comes back in the response,
+// honoring one the client sent.
+func TestRequestID(t *testing.T) {
+	srv := newTestServer(t)
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/identity", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Request-ID", "test-id-42")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("X-Request-ID"); got != "test-id-42" {
+		t.Errorf("X-Request-ID = %q, want test-id-42", got)
+	}
+
+	resp, err = srv.Client().Get(srv.URL + "/api/v1/identity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("X-Request-ID") == "" {
+		t.Error("no X-Request-ID assigned")
+	}
+}
+
+// TestAuditList checks that reads land in the account's audit trail with
+// the client address attached.
+func TestAuditList(t *testing.T) {
+	srv := newTestServer(t)
+	token := registerUser(t, srv, "alice")
+	id := createSecret(t, srv, token, credentialsSecret(t, "example.com"))
+	if status, body := call(t, srv, http.MethodGet, "/api/v1/secrets/"+id.String(), token, nil); status != http.StatusOK {
+		t.Fatalf("get secret: status %d: %s", status, body)
+	}
+
+	status, body := call(t, srv, http.MethodGet, "/api/v1/audit", token, nil)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d: %s", status, body)
+	}
+	var events []models.AuditEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		t.Fatal(err)
+	}
+	actions := make(map[string]models.AuditEvent, len(events))
+	for _, e := range events {
+		actions[e.Action] = e
+	}
+	for _, want := range []string{models.AuditActionRegister, models.AuditActionSecretCreated, models.AuditActionSecretRead} {
+		e, ok := actions[want]
+		if !ok {
+			t.Errorf("no %s event in %d events", want, len(events))
+			continue
+		}
+		if e.IP == "" {
+			t.Errorf("%s event has no IP", want)
+		}
+	}
+	if actions[models.AuditActionSecretRead].Resource != id.String() {
+		t.Errorf("read resource = %q, want the secret id", actions[models.AuditActionSecretRead].Resource)
+	}
+
+	if status, _ := call(t, srv, http.MethodGet, "/api/v1/audit?limit=0", token, nil); status != http.StatusBadRequest {
+		t.Errorf("limit=0: status = %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+// TestAdminRoutesRequireRole checks regular accounts get 403 on the admin
+// API even with a valid token.
+func TestAdminRoutesRequireRole(t *testing.T) {
+	srv := newTestServer(t)
+	token := registerUser(t, srv, "alice")
+	if status, _ := call(t, srv, http.MethodGet, "/api/v1/admin/users", token, nil); status != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", status, http.StatusForbidden)
+	}
+	if status, _ := call(t, srv, http.MethodGet, "/api/v1/admin/users", "", nil); status != http.StatusUnauthorized {
+		t.Errorf("no token: status = %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status, _ := call(t, srv, http.MethodPost, "/api/v1/admin/crypto/rotate", token, nil); status != http.StatusForbidden {
+		t.Errorf("rotate: status = %d, want %d", status, http.StatusForbidden)
+	}
+}
+
+func TestHealthProbes(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := srv.Client().Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /healthz = %d, want 200", resp.StatusCode)
+	}
+	var live struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&live); err != nil {
+		t.Fatal(err)
+	}
+	if live.Status != "ok" {
+		t.Errorf("liveness status = %q, want ok", live.Status)
+	}
+
+	// Readiness reflects the registered dependency checks.
+	router := NewRouter(Services{HealthChecks: []HealthCheck{
+		{Name: "database", Check: func(context.Context) error { return nil }},
+		{Name: "blob", Check: func(context.Context) error { return errors.New("bucket gone") }},
+	}})
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("GET /readyz = %d, want 503", rec.Code)
+	}
+	var ready struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &ready); err != nil {
+		t.Fatal(err)
+	}
+	if ready.Status != "unavailable" {
+		t.Errorf("readiness status = %q, want unavailable", ready.Status)
+	}
+	if ready.Checks["database"] != "ok" {
+		t.Errorf(`checks["database"] = %q, want ok`, ready.Checks["database"])
+	}
+	if ready.Checks["blob"] != "bucket gone" {
+		t.Errorf(`checks["blob"] = %q, want the failure message`, ready.Checks["blob"])
+	}
+}
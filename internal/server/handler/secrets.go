@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+func boolPtr(v bool) *bool { return &v }
+
+// SecretsService is the part of the secrets service the handlers use.
+type SecretsService interface {
+	CreateSecret(ctx context.Context, secret *models.Secret) error
+	GetSecret(ctx context.Context, userID, id uuid.UUID) (*models.Secret, error)
+	GetSecrets(ctx context.Context, userID uuid.UUID, opts repository.SecretListOptions) ([]models.Secret, []models.SecretError, error)
+	UpdateSecret(ctx context.Context, secret *models.Secret) ([]string, error)
+	DeleteSecret(ctx context.Context, userID, id uuid.UUID, confirm bool) error
+	UseRecoveryCode(ctx context.Context, userID, id uuid.UUID) (code string, remaining int, warnings []string, err error)
+	ConvertSecret(ctx context.Context, secret *models.Secret) error
+	GetSecretVersions(ctx context.Context, userID, secretID uuid.UUID) ([]models.SecretVersion, error)
+	GetSecretVersion(ctx context.Context, userID, secretID uuid.UUID, version int) (*models.SecretVersion, error)
+	AddAttachment(ctx context.Context, userID, secretID uuid.UUID, filename string, content []byte) (*models.Attachment, error)
+	GetAttachment(ctx context.Context, userID, id uuid.UUID) (*models.Attachment, error)
+	ListAttachments(ctx context.Context, userID, secretID uuid.UUID) ([]models.Attachment, error)
+	DeleteAttachment(ctx context.Context, userID, id uuid.UUID) error
+	Usage(ctx context.Context, userID uuid.UUID) (models.StorageUsage, error)
+}
+
+// SecretsHandler serves secret CRUD for the authenticated user.
+type SecretsHandler struct {
+	secrets SecretsService
+}
+
+// NewSecretsHandler creates a SecretsHandler.
+func NewSecretsHandler(secrets SecretsService) *SecretsHandler {
+	return &SecretsHandler{secrets: secrets}
+}
+
+// secretResponse wraps a secret together with non-fatal warnings produced by
+// the operation.
+type secretResponse struct {
+	Secret   *models.Secret `json:"secret"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+// Create handles POST /api/v1/secrets.
+func (h *SecretsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var secret models.Secret
+	if !readJSON(w, r, &secret) {
+		return
+	}
+	secret.UserID = userID
+	if err := h.secrets.CreateSecret(r.Context(), &secret); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusCreated, secretResponse{Secret: &secret})
+}
+
+// secretListBody is the ?include_errors=true listing shape: the readable
+// secrets plus an entry for every secret that could not be returned.
+type secretListBody struct {
+	Secrets []models.Secret      `json:"secrets"`
+	Errors  []models.SecretError `json:"errors,omitempty"`
+}
+
+// List handles GET /api/v1/secrets. Archived secrets are included only when
+// ?include_archived=true is passed. sort, order, limit and after select a
+// server-sorted keyset page; clients reach the end when a page comes back
+// shorter than the limit.
+func (h *SecretsHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	q := r.URL.Query()
+	opts := repository.SecretListOptions{
+		IncludeArchived: q.Get("include_archived") == "true",
+		SortBy:          repository.SecretSortField(q.Get("sort")),
+		Name:            q.Get("name"),
+	}
+	switch q.Get("order") {
+	case "":
+	case "asc":
+		opts.Descending = boolPtr(false)
+	case "desc":
+		opts.Descending = boolPtr(true)
+	default:
+		writeError(r.Context(), w, http.StatusBadRequest, "order must be asc or desc")
+		return
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			writeError(r.Context(), w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		opts.Limit = limit
+	}
+	if v := q.Get("after"); v != "" {
+		after, err := uuid.Parse(v)
+		if err != nil {
+			writeError(r.Context(), w, http.StatusBadRequest, "invalid after cursor")
+			return
+		}
+		opts.After = after
+	}
+	secrets, failed, err := h.secrets.GetSecrets(r.Context(), userID, opts)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if secrets == nil {
+		secrets = []models.Secret{}
+	}
+	// The flat array stays the default for compatibility; clients opt in
+	// to seeing which secrets could not be returned.
+	if q.Get("include_errors") == "true" {
+		writeJSON(r.Context(), w, http.StatusOK, secretListBody{Secrets: secrets, Errors: failed})
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, secrets)
+}
+
+// Get handles GET /api/v1/secrets/{id}.
+func (h *SecretsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.userAndID(w, r)
+	if !ok {
+		return
+	}
+	secret, err := h.secrets.GetSecret(r.Context(), userID, id)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, secretResponse{Secret: secret})
+}
+
+// Update handles PUT /api/v1/secrets/{id}.
+func (h *SecretsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.userAndID(w, r)
+	if !ok {
+		return
+	}
+	var secret models.Secret
+	if !readJSON(w, r, &secret) {
+		return
+	}
+	secret.ID = id
+	secret.UserID = userID
+	warnings, err := h.secrets.UpdateSecret(r.Context(), &secret)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, secretResponse{Secret: &secret, Warnings: warnings})
+}
+
+// Delete handles DELETE /api/v1/secrets/{id}. Protected secrets are only
+// deleted when ?confirm=true is passed.
+func (h *SecretsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.userAndID(w, r)
+	if !ok {
+		return
+	}
+	confirm := r.URL.Query().Get("confirm") == "true"
+	if err := h.secrets.DeleteSecret(r.Context(), userID, id, confirm); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UseRecoveryCode handles POST /api/v1/secrets/{id}/use-code. It consumes
+// the next unused recovery code and returns it.
+func (h *SecretsHandler) UseRecoveryCode(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.userAndID(w, r)
+	if !ok {
+		return
+	}
+	code, remaining, warnings, err := h.secrets.UseRecoveryCode(r.Context(), userID, id)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, struct {
+		Code      string   `json:"code"`
+		Remaining int      `json:"remaining"`
+		Warnings  []string `json:"warnings,omitempty"`
+	}{Code: code, Remaining: remaining, Warnings: warnings})
+}
+
+// Convert handles POST /api/v1/secrets/{id}/convert. The body carries the
+// new type and the payload already mapped to it; the previous payload is
+// kept as a version.
+func (h *SecretsHandler) Convert(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.userAndID(w, r)
+	if !ok {
+		return
+	}
+	var secret models.Secret
+	if !readJSON(w, r, &secret) {
+		return
+	}
+	secret.ID = id
+	secret.UserID = userID
+	if err := h.secrets.ConvertSecret(r.Context(), &secret); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, secretResponse{Secret: &secret})
+}
+
+// Versions handles GET /api/v1/secrets/{id}/versions. It lists the stored
+// payload snapshots of a secret with their change summaries.
+func (h *SecretsHandler) Versions(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.userAndID(w, r)
+	if !ok {
+		return
+	}
+	versions, err := h.secrets.GetSecretVersions(r.Context(), userID, id)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if versions == nil {
+		versions = []models.SecretVersion{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, versions)
+}
+
+// Version handles GET /api/v1/secrets/{id}/versions/{version}. It returns
+// one snapshot including its payload.
+func (h *SecretsHandler) Version(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := h.userAndID(w, r)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+	v, err := h.secrets.GetSecretVersion(r.Context(), userID, id, version)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, v)
+}
+
+func (h *SecretsHandler) userAndID(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid secret id")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+// Usage handles GET /api/v1/me/usage, reporting the caller's storage
+// consumption against the configured quotas.
+func (h *SecretsHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	usage, err := h.secrets.Usage(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, usage)
+}
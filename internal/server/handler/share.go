@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/service"
+)
+
+// ShareService creates and resolves public share tokens. Peek describes a
+// live link without consuming it; Reveal burns the link and returns the
+// payload.
+type ShareService interface {
+	CreateLink(ctx context.Context, userID, secretID uuid.UUID, ttl time.Duration) (token string, link *models.ShareLink, err error)
+	Peek(ctx context.Context, token string) (name string, expiresAt time.Time, err error)
+	Reveal(ctx context.Context, token string) (name string, secretType models.SecretType, payload []byte, err error)
+}
+
+// ShareHandler serves the public share pages: plain server-rendered HTML
+// with no scripts, so recipients only need a browser.
+type ShareHandler struct {
+	share ShareService
+	brand string
+}
+
+// NewShareHandler creates the handler. Brand is the page title; empty
+// means "gophkeeper".
+func NewShareHandler(share ShareService, brand string) *ShareHandler {
+	if brand == "" {
+		brand = "gophkeeper"
+	}
+	return &ShareHandler{share: share, brand: brand}
+}
+
+var shareTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{.Brand}}</title>
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 4em auto; padding: 0 1em; color: #222; }
+h1 { font-size: 1.2em; }
+pre { background: #f4f4f4; padding: 1em; overflow-x: auto; white-space: pre-wrap; word-break: break-all; }
+button { font-size: 1em; padding: 0.5em 1.5em; }
+.muted { color: #777; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>{{.Brand}}</h1>
+{{if .Missing}}
+<p>This link is unknown, expired or has already been used.</p>
+{{else if .Revealed}}
+<p>Secret <strong>{{.Name}}</strong> ({{.Type}}):</p>
+<pre>{{.Payload}}</pre>
+<p class="muted">This link has now been used and will not work again. Copy the secret before closing this page.</p>
+{{else}}
+<p>Someone shared the secret <strong>{{.Name}}</strong> with you.</p>
+<p class="muted">The link can be used once and expires {{.Expires}}. Revealing it destroys the link.</p>
+<form method="post">
+<button type="submit">Reveal secret</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+// sharePage is the data behind shareTemplate.
+type sharePage struct {
+	Brand    string
+	Missing  bool
+	Revealed bool
+	Name     string
+	Type     models.SecretType
+	Payload  string
+	Expires  string
+}
+
+// CreateLink handles POST /api/v1/secrets/{id}/links. It mints a
+// single-use expiring link to the secret; the response carries the URL
+// path the recipient opens in a browser. ExpiresIn is in seconds, 0 picks
+// the server default.
+func (h *ShareHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := userAndParamID(w, r, "id", "invalid secret id")
+	if !ok {
+		return
+	}
+	var req struct {
+		ExpiresIn int64 `json:"expires_in,omitempty"`
+	}
+	if r.ContentLength != 0 {
+		if !readJSON(w, r, &req) {
+			return
+		}
+	}
+	token, link, err := h.share.CreateLink(r.Context(), userID, id, time.Duration(req.ExpiresIn)*time.Second)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusCreated, struct {
+		Token     string    `json:"token"`
+		Path      string    `json:"path"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}{Token: token, Path: "/share/" + token, ExpiresAt: link.ExpiresAt})
+}
+
+// Confirm renders the confirmation page for a live link.
+func (h *ShareHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	name, expiresAt, err := h.share.Peek(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		h.render(r.Context(), w, h.missingPage(r.Context(), err))
+		return
+	}
+	h.render(r.Context(), w, sharePage{
+		Brand:   h.brand,
+		Name:    name,
+		Expires: expiresAt.UTC().Format("2006-01-02 15:04 UTC"),
+	})
+}
+
+// Reveal consumes the link and renders the secret.
+func (h *ShareHandler) Reveal(w http.ResponseWriter, r *http.Request) {
+	name, secretType, payload, err := h.share.Reveal(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		h.render(r.Context(), w, h.missingPage(r.Context(), err))
+		return
+	}
+	h.render(r.Context(), w, sharePage{
+		Brand:    h.brand,
+		Revealed: true,
+		Name:     name,
+		Type:     secretType,
+		Payload:  string(payload),
+	})
+}
+
+// missingPage is what both routes show when a token does not resolve; real
+// failures are logged but not distinguished for the recipient.
+func (h *ShareHandler) missingPage(ctx context.Context, err error) sharePage {
+	if !errors.Is(err, service.ErrNotFound) {
+		slog.WarnContext(ctx, "share page", "err", err)
+	}
+	return sharePage{Brand: h.brand, Missing: true}
+}
+
+func (h *ShareHandler) render(ctx context.Context, w http.ResponseWriter, page sharePage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if page.Missing {
+		w.WriteHeader(http.StatusNotFound)
+	}
+	if err := shareTemplate.Execute(w, page); err != nil {
+		slog.WarnContext(ctx, "render share page", "err", err)
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// streamKeepaliveInterval is how often an idle stream sends a comment so
+// proxies do not time the connection out.
+const streamKeepaliveInterval = 30 * time.Second
+
+// StreamHub hands out per-user change event subscriptions.
+type StreamHub interface {
+	Subscribe(userID uuid.UUID) (<-chan models.OutboxEvent, func())
+}
+
+// StreamHandler serves the server-sent events stream that tells clients to
+// sync. SSE needs no extra dependencies and passes plain HTTP proxies,
+// which is why it was chosen over WebSocket.
+type StreamHandler struct {
+	hub StreamHub
+}
+
+// NewStreamHandler creates the handler.
+func NewStreamHandler(hub StreamHub) *StreamHandler {
+	return &StreamHandler{hub: hub}
+}
+
+// Stream handles GET /api/v1/sync/stream. Each secret change of the
+// authenticated user arrives as one "change" event; the payload is the
+// outbox event JSON.
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(r.Context(), w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	events, cancel := h.hub.Subscribe(userID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepalive := time.NewTicker(streamKeepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: change\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// SyncService is the part of the sync service the handlers use.
+type SyncService interface {
+	ProcessSyncRequest(ctx context.Context, userID uuid.UUID, req models.SyncRequest) (*models.SyncResponse, error)
+}
+
+// SyncHandler serves incremental sync.
+type SyncHandler struct {
+	sync SyncService
+}
+
+// NewSyncHandler creates a SyncHandler.
+func NewSyncHandler(sync SyncService) *SyncHandler {
+	return &SyncHandler{sync: sync}
+}
+
+// Sync handles POST /api/v1/sync. The response may be partial under
+// database trouble; warnings and the retry token tell the client what to
+// re-request.
+func (h *SyncHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var req models.SyncRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	resp, err := h.sync.ProcessSyncRequest(r.Context(), userID, req)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if resp.Secrets == nil {
+		resp.Secrets = []models.Secret{}
+	}
+	if resp.Operations == nil {
+		resp.Operations = []models.AuditEvent{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, resp)
+}
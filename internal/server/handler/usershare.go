@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// UserShareService is the part of the user share service the handlers use.
+type UserShareService interface {
+	Share(ctx context.Context, ownerID, secretID uuid.UUID, login, mode string) (*models.SecretShare, error)
+	Unshare(ctx context.Context, ownerID, secretID uuid.UUID, login string) error
+	Shares(ctx context.Context, ownerID, secretID uuid.UUID) ([]models.SecretShare, error)
+	SharedWithMe(ctx context.Context, recipientID uuid.UUID) ([]models.SharedSecret, error)
+	GetShared(ctx context.Context, recipientID, secretID uuid.UUID) (*models.SharedSecret, error)
+	UpdateShared(ctx context.Context, recipientID uuid.UUID, secret *models.Secret) ([]string, error)
+}
+
+// UserShareHandler serves per-user secret sharing: the owner side under
+// /secrets/{id}/share and the recipient side under /shared.
+type UserShareHandler struct {
+	shares UserShareService
+}
+
+// NewUserShareHandler creates a UserShareHandler.
+func NewUserShareHandler(shares UserShareService) *UserShareHandler {
+	return &UserShareHandler{shares: shares}
+}
+
+// Grant handles POST /api/v1/secrets/{id}/share. The body names the
+// recipient by login and the mode, read or write.
+func (h *UserShareHandler) Grant(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := userAndParamID(w, r, "id", "invalid secret id")
+	if !ok {
+		return
+	}
+	var req struct {
+		Login string `json:"login"`
+		Mode  string `json:"mode"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = models.ShareModeRead
+	}
+	share, err := h.shares.Share(r.Context(), userID, id, req.Login, req.Mode)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusCreated, share)
+}
+
+// List handles GET /api/v1/secrets/{id}/share.
+func (h *UserShareHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := userAndParamID(w, r, "id", "invalid secret id")
+	if !ok {
+		return
+	}
+	shares, err := h.shares.Shares(r.Context(), userID, id)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if shares == nil {
+		shares = []models.SecretShare{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, shares)
+}
+
+// Revoke handles DELETE /api/v1/secrets/{id}/share/{login}.
+func (h *UserShareHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := userAndParamID(w, r, "id", "invalid secret id")
+	if !ok {
+		return
+	}
+	if err := h.shares.Unshare(r.Context(), userID, id, chi.URLParam(r, "login")); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Received handles GET /api/v1/shared, listing the secrets shared with
+// the caller.
+func (h *UserShareHandler) Received(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	shared, err := h.shares.SharedWithMe(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if shared == nil {
+		shared = []models.SharedSecret{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, shared)
+}
+
+// Get handles GET /api/v1/shared/{id}.
+func (h *UserShareHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := userAndParamID(w, r, "id", "invalid secret id")
+	if !ok {
+		return
+	}
+	shared, err := h.shares.GetShared(r.Context(), userID, id)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, shared)
+}
+
+// Update handles PUT /api/v1/shared/{id}, for recipients holding a write
+// grant.
+func (h *UserShareHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := userAndParamID(w, r, "id", "invalid secret id")
+	if !ok {
+		return
+	}
+	var secret models.Secret
+	if !readJSON(w, r, &secret) {
+		return
+	}
+	secret.ID = id
+	warnings, err := h.shares.UpdateShared(r.Context(), userID, &secret)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusOK, secretResponse{Secret: &secret, Warnings: warnings})
+}
+
+// userAndParamID resolves the authenticated user and a UUID path
+// parameter.
+func userAndParamID(w http.ResponseWriter, r *http.Request, param, badRequestMsg string) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(chi.URLParam(r, param))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, badRequestMsg)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/middleware"
+)
+
+// WebhookService manages user-registered webhooks and their delivery
+// log.
+type WebhookService interface {
+	Create(ctx context.Context, userID uuid.UUID, url string, events []string) (*models.Webhook, error)
+	List(ctx context.Context, userID uuid.UUID) ([]models.Webhook, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	Deliveries(ctx context.Context, userID, id uuid.UUID) ([]models.WebhookDelivery, error)
+}
+
+// WebhookHandler serves the webhook registry endpoints.
+type WebhookHandler struct {
+	webhooks WebhookService
+}
+
+// NewWebhookHandler creates the handler.
+func NewWebhookHandler(webhooks WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks}
+}
+
+// Create handles POST /api/v1/webhooks. The response is the only place
+// the signing secret ever appears.
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var body struct {
+		URL    string   `json:"url"`
+		Events []string `json:"events"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	hook, err := h.webhooks.Create(r.Context(), userID, body.URL, body.Events)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	writeJSON(r.Context(), w, http.StatusCreated, hook)
+}
+
+// List handles GET /api/v1/webhooks.
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	hooks, err := h.webhooks.List(r.Context(), userID)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if hooks == nil {
+		hooks = []models.Webhook{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, hooks)
+}
+
+// Delete handles DELETE /api/v1/webhooks/{id}.
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid webhook id")
+		return
+	}
+	if err := h.webhooks.Delete(r.Context(), userID, id); err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deliveries handles GET /api/v1/webhooks/{id}/deliveries. It returns
+// the latest delivery attempts, newest first.
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserID(r.Context())
+	if !ok {
+		writeError(r.Context(), w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(r.Context(), w, http.StatusBadRequest, "invalid webhook id")
+		return
+	}
+	deliveries, err := h.webhooks.Deliveries(r.Context(), userID, id)
+	if err != nil {
+		writeServiceError(r.Context(), w, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	writeJSON(r.Context(), w, http.StatusOK, deliveries)
+}
@@ -0,0 +1,20 @@
+// Package kms integrates external key-management services that wrap key
+// material under a key the server never sees. With the master secret
+// stored only in wrapped form, a database dump — even together with the
+// server configuration — is not enough to decrypt anything: unwrapping
+// requires access to the KMS.
+package kms
+
+import "context"
+
+// Wrapper encrypts and decrypts key material under an external key.
+// Implementations must be safe for concurrent use. Vault Transit ships
+// in this package; cloud KMS adapters implement the same interface.
+type Wrapper interface {
+	// Wrap encrypts key material under the external key. The returned
+	// ciphertext is opaque and safe to store anywhere.
+	Wrap(ctx context.Context, plaintext []byte) ([]byte, error)
+	// Unwrap reverses Wrap. It fails when the caller has no access to
+	// the external key, which is the point.
+	Unwrap(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
@@ -0,0 +1,108 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultTransit wraps key material with the encrypt/decrypt endpoints of a
+// Hashicorp Vault transit engine. It speaks the plain HTTP API, so no
+// Vault SDK is needed.
+type VaultTransit struct {
+	addr   string
+	token  string
+	key    string
+	client *http.Client
+}
+
+// NewVaultTransit creates a VaultTransit for the named transit key. addr
+// is the Vault base URL, e.g. "https://vault.internal:8200".
+func NewVaultTransit(addr, token, key string) *VaultTransit {
+	return &VaultTransit{
+		addr:   strings.TrimRight(addr, "/"),
+		token:  token,
+		key:    key,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Wrap encrypts plaintext under the transit key. The returned ciphertext
+// is Vault's versioned token ("vault:v1:..."), so key rotation inside
+// Vault keeps old ciphertext unwrappable.
+func (v *VaultTransit) Wrap(ctx context.Context, plaintext []byte) ([]byte, error) {
+	resp, err := v.call(ctx, "encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, fmt.Errorf("kms: vault returned no ciphertext")
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+// Unwrap decrypts a ciphertext produced by Wrap.
+func (v *VaultTransit) Unwrap(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	resp, err := v.call(ctx, "decrypt", map[string]string{
+		"ciphertext": string(ciphertext),
+	})
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("kms: decode vault plaintext: %w", err)
+	}
+	return plaintext, nil
+}
+
+// vaultResponse is the part of a transit response the wrapper reads.
+type vaultResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  string `json:"plaintext"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func (v *VaultTransit) call(ctx context.Context, op string, body map[string]string) (*vaultResponse, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/v1/transit/%s/%s", v.addr, op, v.key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("kms: vault %s: %w", op, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("kms: read vault response: %w", err)
+	}
+	var parsed vaultResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("kms: parse vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(parsed.Errors) > 0 {
+			return nil, fmt.Errorf("kms: vault %s: %s", op, strings.Join(parsed.Errors, "; "))
+		}
+		return nil, fmt.Errorf("kms: vault %s: status %d", op, resp.StatusCode)
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,78 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newFakeVault serves a minimal transit engine: encrypt base64-reverses
+// the plaintext behind a "vault:v1:" prefix, decrypt reverses it back.
+func newFakeVault(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]any{"errors": []string{"permission denied"}})
+			return
+		}
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/app":
+			json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]string{"ciphertext": "vault:v1:" + req["plaintext"]},
+			})
+		case "/v1/transit/decrypt/app":
+			json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]string{"plaintext": strings.TrimPrefix(req["ciphertext"], "vault:v1:")},
+			})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVaultTransitRoundTrip(t *testing.T) {
+	srv := newFakeVault(t)
+	wrapper := NewVaultTransit(srv.URL, "test-token", "app")
+
+	wrapped, err := wrapper.Wrap(context.Background(), []byte("master key"))
+	if err != nil {
+		t.Fatalf("Wrap: %v", err)
+	}
+	if !bytes.HasPrefix(wrapped, []byte("vault:v1:")) {
+		t.Fatalf("wrapped = %q, want a vault token", wrapped)
+	}
+	want := "vault:v1:" + base64.StdEncoding.EncodeToString([]byte("master key"))
+	if string(wrapped) != want {
+		t.Fatalf("wrapped = %q, want %q", wrapped, want)
+	}
+
+	unwrapped, err := wrapper.Unwrap(context.Background(), wrapped)
+	if err != nil {
+		t.Fatalf("Unwrap: %v", err)
+	}
+	if !bytes.Equal(unwrapped, []byte("master key")) {
+		t.Fatalf("unwrapped = %q, want the master key", unwrapped)
+	}
+}
+
+func TestVaultTransitPermissionDenied(t *testing.T) {
+	srv := newFakeVault(t)
+	wrapper := NewVaultTransit(srv.URL, "wrong-token", "app")
+	_, err := wrapper.Wrap(context.Background(), []byte("master key"))
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Fatalf("err = %v, want the vault error", err)
+	}
+}
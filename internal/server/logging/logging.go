@@ -0,0 +1,104 @@
+// Package logging configures the server's structured logger and carries
+// the request correlation ID through contexts, so every log line written
+// while serving a request can be tied back to it.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+type ctxKey struct{}
+
+// WithRequestID stores a request correlation ID in the context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// RequestID returns the correlation ID stored by WithRequestID, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+type ipKey struct{}
+
+// WithClientIP stores the client address of the request in the context,
+// for the audit trail.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ipKey{}, ip)
+}
+
+// ClientIP returns the address stored by WithClientIP, or "".
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(ipKey{}).(string)
+	return ip
+}
+
+type uaKey struct{}
+
+// WithUserAgent stores the request's user agent in the context, for the
+// audit trail.
+func WithUserAgent(ctx context.Context, ua string) context.Context {
+	return context.WithValue(ctx, uaKey{}, ua)
+}
+
+// UserAgent returns the user agent stored by WithUserAgent, or "".
+func UserAgent(ctx context.Context) string {
+	ua, _ := ctx.Value(uaKey{}).(string)
+	return ua
+}
+
+// level is the minimum level of loggers built by New. It is shared so
+// SetLevel can change it at runtime.
+var level slog.LevelVar
+
+// New builds a JSON logger that stamps every record written with a
+// request-carrying context with its request_id. Install it with
+// slog.SetDefault so the *Context logging helpers pick it up everywhere.
+func New(w io.Writer) *slog.Logger {
+	return slog.New(handler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: &level})})
+}
+
+// SetLevel changes the minimum level of every logger built by New,
+// taking effect immediately.
+func SetLevel(l slog.Level) { level.Set(l) }
+
+// ParseLevel maps the configuration names debug, info, warn and error to
+// slog levels. The empty string means info.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
+
+// handler decorates records with the request ID from the context.
+type handler struct {
+	slog.Handler
+}
+
+func (h handler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return handler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h handler) WithGroup(name string) slog.Handler {
+	return handler{h.Handler.WithGroup(name)}
+}
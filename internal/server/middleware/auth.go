@@ -0,0 +1,48 @@
+// Package middleware provides the HTTP middleware shared by the server
+// routes.
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const userIDKey contextKey = "userID"
+
+// TokenParser validates an access token and returns its user ID.
+type TokenParser interface {
+	ParseToken(token string) (uuid.UUID, error)
+}
+
+// Auth rejects requests without a valid bearer token and stores the user ID
+// in the request context.
+func Auth(parser TokenParser) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || token == "" {
+				http.Error(w, "missing bearer token", http.StatusUnauthorized)
+				return
+			}
+			userID, err := parser.ParseToken(token)
+			if err != nil {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+			ctx := context.WithValue(r.Context(), userIDKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// UserID extracts the authenticated user ID stored by Auth.
+func UserID(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(userIDKey).(uuid.UUID)
+	return id, ok
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// MaxBody caps how much of a request body a handler will read, so an
+// oversized upload cannot exhaust memory. Bodies announcing a larger
+// Content-Length are rejected up front; chunked or compressed bodies are
+// cut off at the limit while being read, which handlers surface as 413
+// when decoding. Applied after DecompressRequest, the limit counts
+// decompressed bytes, so a gzip bomb is cut off too. A non-positive
+// limit disables the cap.
+func MaxBody(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limit <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
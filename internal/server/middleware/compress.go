@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+)
+
+// DecompressRequest transparently inflates request bodies sent with
+// Content-Encoding: gzip, so large sync pushes can go over the wire
+// compressed. Other encodings are rejected; response compression is
+// negotiated separately via Accept-Encoding.
+func DecompressRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Content-Encoding") {
+		case "", "identity":
+		case "gzip":
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				http.Error(w, "malformed gzip body", http.StatusBadRequest)
+				return
+			}
+			defer zr.Close()
+			r.Body = zr
+			r.Header.Del("Content-Encoding")
+			r.ContentLength = -1
+		default:
+			http.Error(w, "unsupported content encoding", http.StatusUnsupportedMediaType)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// CORS adds the headers that let browser applications from the allowed
+// origins call the API. The origin list can change at runtime; an empty
+// list, the initial state, sends no CORS headers at all.
+type CORS struct {
+	mu      sync.RWMutex
+	origins map[string]bool
+}
+
+// NewCORS creates a CORS middleware with no origins allowed.
+func NewCORS() *CORS {
+	return &CORS{}
+}
+
+// SetOrigins replaces the allowed origins, taking effect on the next
+// request. The single origin "*" allows every origin.
+func (c *CORS) SetOrigins(origins []string) {
+	set := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		set[strings.TrimSuffix(origin, "/")] = true
+	}
+	c.mu.Lock()
+	c.origins = set
+	c.mu.Unlock()
+}
+
+func (c *CORS) allowed(origin string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.origins[origin] || c.origins["*"]
+}
+
+// Handler stamps responses to allowed origins and answers their
+// preflight requests. Requests from other origins pass through
+// untouched: the browser, not the server, enforces the denial.
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !c.allowed(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
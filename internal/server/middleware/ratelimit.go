@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/logging"
+)
+
+const (
+	// rateLimiterMaxBuckets triggers a sweep of idle client buckets, so
+	// one-off clients cannot grow the map without bound.
+	rateLimiterMaxBuckets = 10000
+	// rateLimiterIdleTTL is how long a bucket may sit unused before a
+	// sweep may drop it; by then it is full again anyway.
+	rateLimiterIdleTTL = time.Minute
+)
+
+// RateLimiter enforces a per-client token-bucket limit on API requests.
+// The limit can change at runtime; a zero rate disables limiting, which
+// is also the initial state.
+type RateLimiter struct {
+	mu      sync.Mutex
+	rps     int
+	burst   int
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter with limiting disabled.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{buckets: make(map[string]*bucket)}
+}
+
+// SetLimit installs a new rate and burst, taking effect on the next
+// request. A non-positive rps disables limiting; a non-positive burst
+// defaults to the rate, allowing spikes of about one second.
+func (l *RateLimiter) SetLimit(rps, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rps == rps && l.burst == burst {
+		return
+	}
+	l.rps, l.burst = rps, burst
+	// Balances accumulated under the old limit mean nothing under the
+	// new one; start everyone fresh.
+	l.buckets = make(map[string]*bucket)
+}
+
+// Handler rejects requests over the limit with 429. Clients are keyed by
+// address, so one aggressive client cannot starve the rest.
+func (l *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := logging.ClientIP(r.Context())
+		if key == "" {
+			key = r.RemoteAddr
+		}
+		if !l.allow(key, time.Now()) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow takes one token from key's bucket, reporting whether one was
+// available.
+func (l *RateLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rps <= 0 {
+		return true
+	}
+	burst := l.burst
+	if burst <= 0 {
+		burst = l.rps
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= rateLimiterMaxBuckets {
+			l.sweep(now)
+		}
+		b = &bucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * float64(l.rps)
+	b.last = now
+	if b.tokens > float64(burst) {
+		b.tokens = float64(burst)
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sweep drops buckets idle for longer than the TTL. Callers hold the
+// lock.
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > rateLimiterIdleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
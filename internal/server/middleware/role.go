@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// RoleChecker resolves the role of an authenticated user.
+type RoleChecker interface {
+	Role(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+// RequireRole rejects requests whose authenticated user does not hold the
+// given role. It must run after Auth. The role is looked up per request,
+// so revoking it takes effect immediately rather than at token expiry.
+func RequireRole(checker RoleChecker, role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := UserID(r.Context())
+			if !ok {
+				http.Error(w, "missing bearer token", http.StatusUnauthorized)
+				return
+			}
+			got, err := checker.Role(r.Context(), userID)
+			if err != nil || got != role {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+// Package notify delivers security alerts to users over out-of-band
+// channels such as email. Providers are deliberately dumb transports: what
+// to send and to whom is decided by the notification service.
+package notify
+
+import "context"
+
+// Message is one alert addressed to a single recipient.
+type Message struct {
+	// To is the recipient address in the provider's own scheme — an email
+	// address for SMTP.
+	To      string
+	Subject string
+	Body    string
+}
+
+// Provider sends messages over one channel. Implementations must be safe
+// for concurrent use.
+type Provider interface {
+	Send(ctx context.Context, msg Message) error
+}
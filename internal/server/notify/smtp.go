@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// smtpDialTimeout bounds the connection attempt to the mail server; alert
+// volume is low, so a fresh connection per message keeps the provider
+// stateless, like the syslog forwarder.
+const smtpDialTimeout = 5 * time.Second
+
+// SMTPProvider sends messages through a mail relay. STARTTLS is used when
+// the server offers it; credentials are optional for relays that restrict
+// by network instead.
+type SMTPProvider struct {
+	addr     string
+	from     string
+	username string
+	password string
+}
+
+// NewSMTP creates an SMTPProvider for the relay at addr (host:port),
+// sending from the given address.
+func NewSMTP(addr, from, username, password string) *SMTPProvider {
+	return &SMTPProvider{addr: addr, from: from, username: username, password: password}
+}
+
+// Send delivers one message. The context deadline, if any, caps the whole
+// exchange.
+func (p *SMTPProvider) Send(ctx context.Context, msg Message) error {
+	conn, err := net.DialTimeout("tcp", p.addr, smtpDialTimeout)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", p.addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline) //nolint:errcheck // best effort
+	}
+	host, _, err := net.SplitHostPort(p.addr)
+	if err != nil {
+		host = p.addr
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if p.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", p.username, p.password, host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(p.from); err != nil {
+		return fmt.Errorf("mail from: %w", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("data: %w", err)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", p.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	b.WriteString("\r\n")
+	if _, err := w.Write([]byte(b.String())); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
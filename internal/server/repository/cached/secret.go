@@ -0,0 +1,219 @@
+// Package cached wraps repositories with a read-through cache for the
+// hottest queries. Cached rows stay ciphertext, exactly as the database
+// stores them, so the cache is no more sensitive than the database
+// itself. Cache failures are treated as misses and invalidation failures
+// are absorbed by the entry TTL, so a broken cache degrades to plain
+// database reads instead of breaking requests.
+package cached
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/cache"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// ttl bounds how stale a cache entry can get when invalidation is missed,
+// for example after ReplaceData, which only knows the secret ID.
+const ttl = 5 * time.Minute
+
+// SecretRepository caches GetByID and GetByUser in front of another
+// secret repository; every other method passes through, with writes
+// invalidating what they touch. Per-user listings are keyed through a
+// generation value that writes rotate, so one delete drops every cached
+// page of that user at once.
+type SecretRepository struct {
+	repository.SecretRepository
+	cache cache.Cache
+}
+
+// NewSecretRepository wraps next with cache c.
+func NewSecretRepository(next repository.SecretRepository, c cache.Cache) *SecretRepository {
+	return &SecretRepository{SecretRepository: next, cache: c}
+}
+
+// GetByID fetches one secret, from the cache when possible.
+func (r *SecretRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*models.Secret, error) {
+	key := secretKey(id)
+	if raw, err := r.cache.Get(ctx, key); err == nil && raw != nil {
+		var secret models.Secret
+		// The entry is keyed by ID alone, so check ownership after
+		// decoding; another user's lookup must still hit the database.
+		if gob.NewDecoder(bytes.NewReader(raw)).Decode(&secret) == nil && secret.UserID == userID {
+			return &secret, nil
+		}
+	}
+	secret, err := r.SecretRepository.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if gob.NewEncoder(&buf).Encode(secret) == nil {
+		r.cache.Set(ctx, key, buf.Bytes(), ttl) //nolint:errcheck
+	}
+	return secret, nil
+}
+
+// GetByUser lists a user's secrets, from the cache when possible.
+func (r *SecretRepository) GetByUser(ctx context.Context, userID uuid.UUID, opts repository.SecretListOptions) ([]models.Secret, error) {
+	gen := r.generation(ctx, userID)
+	if gen == "" {
+		return r.SecretRepository.GetByUser(ctx, userID, opts)
+	}
+	key := listKey(userID, gen, opts)
+	if raw, err := r.cache.Get(ctx, key); err == nil && raw != nil {
+		var secrets []models.Secret
+		if gob.NewDecoder(bytes.NewReader(raw)).Decode(&secrets) == nil {
+			return secrets, nil
+		}
+	}
+	secrets, err := r.SecretRepository.GetByUser(ctx, userID, opts)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if gob.NewEncoder(&buf).Encode(secrets) == nil {
+		r.cache.Set(ctx, key, buf.Bytes(), ttl) //nolint:errcheck
+	}
+	return secrets, nil
+}
+
+func (r *SecretRepository) Create(ctx context.Context, secret *models.Secret) error {
+	if err := r.SecretRepository.Create(ctx, secret); err != nil {
+		return err
+	}
+	r.invalidate(ctx, secret.ID, secret.UserID)
+	return nil
+}
+
+func (r *SecretRepository) Update(ctx context.Context, secret *models.Secret) error {
+	if err := r.SecretRepository.Update(ctx, secret); err != nil {
+		return err
+	}
+	r.invalidate(ctx, secret.ID, secret.UserID)
+	return nil
+}
+
+func (r *SecretRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if err := r.SecretRepository.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id, userID)
+	return nil
+}
+
+func (r *SecretRepository) MarkAccessed(ctx context.Context, userID, id uuid.UUID, at time.Time) error {
+	if err := r.SecretRepository.MarkAccessed(ctx, userID, id, at); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id, userID)
+	return nil
+}
+
+func (r *SecretRepository) ArchiveUnaccessed(ctx context.Context, userID uuid.UUID, before time.Time) (int64, error) {
+	archived, err := r.SecretRepository.ArchiveUnaccessed(ctx, userID, before)
+	if err != nil {
+		return 0, err
+	}
+	if archived > 0 {
+		// The IDs of the archived rows are not known here, so their
+		// per-ID entries age out through the TTL instead.
+		r.cache.Delete(ctx, generationKey(userID)) //nolint:errcheck
+	}
+	return archived, nil
+}
+
+func (r *SecretRepository) Reassign(ctx context.Context, id, fromUserID, toUserID uuid.UUID, data []byte, blobRef string) error {
+	if err := r.SecretRepository.Reassign(ctx, id, fromUserID, toUserID, data, blobRef); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id, fromUserID)
+	r.cache.Delete(ctx, generationKey(toUserID)) //nolint:errcheck
+	return nil
+}
+
+func (r *SecretRepository) AttachToOrg(ctx context.Context, id, userID, orgID uuid.UUID, data []byte, blobRef string) error {
+	if err := r.SecretRepository.AttachToOrg(ctx, id, userID, orgID, data, blobRef); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id, userID)
+	return nil
+}
+
+func (r *SecretRepository) DetachFromOrg(ctx context.Context, id, orgID, toUserID uuid.UUID, data []byte, blobRef string) error {
+	if err := r.SecretRepository.DetachFromOrg(ctx, id, orgID, toUserID, data, blobRef); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id, toUserID)
+	return nil
+}
+
+func (r *SecretRepository) ReplaceData(ctx context.Context, id uuid.UUID, data []byte, blobRef string) error {
+	if err := r.SecretRepository.ReplaceData(ctx, id, data, blobRef); err != nil {
+		return err
+	}
+	r.cache.Delete(ctx, secretKey(id)) //nolint:errcheck
+	return nil
+}
+
+func (r *SecretRepository) ReplaceMeta(ctx context.Context, id uuid.UUID, name, metadata string, nameIndex []byte) error {
+	if err := r.SecretRepository.ReplaceMeta(ctx, id, name, metadata, nameIndex); err != nil {
+		return err
+	}
+	r.cache.Delete(ctx, secretKey(id)) //nolint:errcheck
+	return nil
+}
+
+// invalidate drops the per-ID entry and the owner's list generation after
+// a write.
+func (r *SecretRepository) invalidate(ctx context.Context, id, userID uuid.UUID) {
+	r.cache.Delete(ctx, secretKey(id), generationKey(userID)) //nolint:errcheck
+}
+
+// generation returns the user's current list generation, creating one
+// when absent. It returns "" when the cache is unreachable, in which case
+// listings skip the cache entirely.
+func (r *SecretRepository) generation(ctx context.Context, userID uuid.UUID) string {
+	key := generationKey(userID)
+	if raw, err := r.cache.Get(ctx, key); err == nil && raw != nil {
+		return string(raw)
+	}
+	var seed [8]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return ""
+	}
+	gen := hex.EncodeToString(seed[:])
+	if err := r.cache.Set(ctx, key, []byte(gen), ttl); err != nil {
+		return ""
+	}
+	return gen
+}
+
+func secretKey(id uuid.UUID) string {
+	return "gophkeeper:secret:" + id.String()
+}
+
+func generationKey(userID uuid.UUID) string {
+	return "gophkeeper:secrets:gen:" + userID.String()
+}
+
+// listKey identifies one cached page: the owner, their current
+// generation, and the listing options with sort defaults resolved, so
+// that explicit options and their equivalent zero values share an entry.
+func listKey(userID uuid.UUID, gen string, opts repository.SecretListOptions) string {
+	field, descending := opts.Sort()
+	shape := fmt.Sprintf("%t|%s|%t|%d|%s|%s|%x",
+		opts.IncludeArchived, field, descending, opts.Limit, opts.After, opts.Name, opts.NameIndex)
+	sum := sha256.Sum256([]byte(shape))
+	return fmt.Sprintf("gophkeeper:secrets:%s:%s:%s", userID, gen, hex.EncodeToString(sum[:8]))
+}
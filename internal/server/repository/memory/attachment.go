@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// AttachmentRepository is an in-memory repository.AttachmentRepository.
+type AttachmentRepository struct {
+	mu          sync.RWMutex
+	attachments map[uuid.UUID]models.Attachment
+}
+
+// NewAttachmentRepository creates an empty AttachmentRepository.
+func NewAttachmentRepository() *AttachmentRepository {
+	return &AttachmentRepository{attachments: make(map[uuid.UUID]models.Attachment)}
+}
+
+// Create inserts an attachment.
+func (r *AttachmentRepository) Create(_ context.Context, a *models.Attachment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *a
+	stored.Content = append([]byte(nil), a.Content...)
+	r.attachments[a.ID] = stored
+	return nil
+}
+
+// GetByID fetches one attachment, including its content.
+func (r *AttachmentRepository) GetByID(_ context.Context, userID, id uuid.UUID) (*models.Attachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.attachments[id]
+	if !ok || a.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	a.Content = append([]byte(nil), a.Content...)
+	return &a, nil
+}
+
+// ListBySecret fetches the attachment metadata of one secret, without
+// content.
+func (r *AttachmentRepository) ListBySecret(_ context.Context, userID, secretID uuid.UUID) ([]models.Attachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var attachments []models.Attachment
+	for _, a := range r.attachments {
+		if a.UserID == userID && a.SecretID == secretID {
+			a.Content = nil
+			attachments = append(attachments, a)
+		}
+	}
+	return attachments, nil
+}
+
+// Delete removes an attachment.
+func (r *AttachmentRepository) Delete(_ context.Context, userID, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.attachments[id]
+	if !ok || a.UserID != userID {
+		return repository.ErrNotFound
+	}
+	delete(r.attachments, id)
+	return nil
+}
+
+// Reassign moves one attachment to another owner with re-encrypted content.
+func (r *AttachmentRepository) Reassign(_ context.Context, id, fromUserID, toUserID uuid.UUID, content []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.attachments[id]
+	if !ok || a.UserID != fromUserID {
+		return repository.ErrNotFound
+	}
+	a.UserID = toUserID
+	a.Content = append([]byte(nil), content...)
+	r.attachments[id] = a
+	return nil
+}
+
+// ReplaceContent rewrites the stored ciphertext of one attachment, for key
+// rotation.
+func (r *AttachmentRepository) ReplaceContent(_ context.Context, id uuid.UUID, content []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.attachments[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	a.Content = append([]byte(nil), content...)
+	r.attachments[id] = a
+	return nil
+}
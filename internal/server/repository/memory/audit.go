@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// AuditRepository is an in-memory repository.AuditRepository.
+type AuditRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	events []models.AuditEvent
+}
+
+// NewAuditRepository creates an empty AuditRepository.
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+// Append stores an event and fills in its assigned ID.
+func (r *AuditRepository) Append(_ context.Context, event *models.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	event.ID = r.nextID
+	r.events = append(r.events, *event)
+	return nil
+}
+
+// ListAfter returns up to limit events of a user past the given cursor.
+func (r *AuditRepository) ListAfter(_ context.Context, userID uuid.UUID, after int64, limit int) ([]models.AuditEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var events []models.AuditEvent
+	for _, e := range r.events {
+		if e.UserID == userID && e.ID > after {
+			events = append(events, e)
+			if len(events) == limit {
+				break
+			}
+		}
+	}
+	return events, nil
+}
+
+// ListRecentByActions returns up to limit of the user's newest events
+// matching one of the actions, newest first.
+func (r *AuditRepository) ListRecentByActions(_ context.Context, userID uuid.UUID, actions []string, limit int) ([]models.AuditEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var events []models.AuditEvent
+	for i := len(r.events) - 1; i >= 0 && len(events) < limit; i-- {
+		e := r.events[i]
+		if e.UserID != userID {
+			continue
+		}
+		for _, action := range actions {
+			if e.Action == action {
+				events = append(events, e)
+				break
+			}
+		}
+	}
+	return events, nil
+}
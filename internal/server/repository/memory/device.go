@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// DeviceRepository is an in-memory repository.DeviceRepository.
+type DeviceRepository struct {
+	mu      sync.RWMutex
+	devices map[uuid.UUID]models.Device
+}
+
+// NewDeviceRepository creates an empty DeviceRepository.
+func NewDeviceRepository() *DeviceRepository {
+	return &DeviceRepository{devices: make(map[uuid.UUID]models.Device)}
+}
+
+// Create inserts a device.
+func (r *DeviceRepository) Create(_ context.Context, device *models.Device) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.devices[device.ID] = *device
+	return nil
+}
+
+// Get fetches one device.
+func (r *DeviceRepository) Get(_ context.Context, userID, id uuid.UUID) (*models.Device, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.devices[id]
+	if !ok || d.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &d, nil
+}
+
+// ListByUser fetches a user's devices, oldest first.
+func (r *DeviceRepository) ListByUser(_ context.Context, userID uuid.UUID) ([]models.Device, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var devices []models.Device
+	for _, d := range r.devices {
+		if d.UserID == userID {
+			devices = append(devices, d)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].CreatedAt.Before(devices[j].CreatedAt) })
+	return devices, nil
+}
+
+// UpdateSyncState records the sync point a device reached.
+func (r *DeviceRepository) UpdateSyncState(_ context.Context, userID, id uuid.UUID, syncedAt time.Time, cursor int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.devices[id]
+	if !ok || d.UserID != userID {
+		return repository.ErrNotFound
+	}
+	d.LastSyncedAt = syncedAt
+	d.LastCursor = cursor
+	r.devices[id] = d
+	return nil
+}
+
+// SetRevoked marks a device as revoked.
+func (r *DeviceRepository) SetRevoked(_ context.Context, userID, id uuid.UUID, revoked bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.devices[id]
+	if !ok || d.UserID != userID {
+		return repository.ErrNotFound
+	}
+	d.Revoked = revoked
+	r.devices[id] = d
+	return nil
+}
+
+// GetByFingerprint fetches the user's device carrying the login
+// fingerprint.
+func (r *DeviceRepository) GetByFingerprint(_ context.Context, userID uuid.UUID, fingerprint string) (*models.Device, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, d := range r.devices {
+		if d.UserID == userID && d.Fingerprint == fingerprint {
+			return &d, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// Approve clears the pending flag and approval token of the user's
+// device.
+func (r *DeviceRepository) Approve(_ context.Context, userID, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.devices[id]
+	if !ok || d.UserID != userID {
+		return repository.ErrNotFound
+	}
+	d.Pending = false
+	d.ApprovalToken = ""
+	r.devices[id] = d
+	return nil
+}
+
+// ApproveByToken approves the pending device holding the approval token
+// and returns it.
+func (r *DeviceRepository) ApproveByToken(_ context.Context, token string) (*models.Device, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, d := range r.devices {
+		if d.Pending && d.ApprovalToken == token {
+			d.Pending = false
+			d.ApprovalToken = ""
+			r.devices[id] = d
+			return &d, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// EmergencyRepository is an in-memory repository.EmergencyRepository.
+type EmergencyRepository struct {
+	mu       sync.RWMutex
+	contacts map[uuid.UUID]map[uuid.UUID]models.EmergencyContact
+}
+
+// NewEmergencyRepository creates an empty EmergencyRepository.
+func NewEmergencyRepository() *EmergencyRepository {
+	return &EmergencyRepository{contacts: make(map[uuid.UUID]map[uuid.UUID]models.EmergencyContact)}
+}
+
+// Upsert inserts a designation or replaces the wait period and request
+// state of an existing one.
+func (r *EmergencyRepository) Upsert(_ context.Context, contact *models.EmergencyContact) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byContact, ok := r.contacts[contact.OwnerID]
+	if !ok {
+		byContact = make(map[uuid.UUID]models.EmergencyContact)
+		r.contacts[contact.OwnerID] = byContact
+	}
+	if existing, ok := byContact[contact.ContactID]; ok {
+		existing.WaitDays = contact.WaitDays
+		existing.RequestedAt = contact.RequestedAt
+		byContact[contact.ContactID] = existing
+		return nil
+	}
+	byContact[contact.ContactID] = *contact
+	return nil
+}
+
+// Get fetches one designation.
+func (r *EmergencyRepository) Get(_ context.Context, ownerID, contactID uuid.UUID) (*models.EmergencyContact, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.contacts[ownerID][contactID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &c, nil
+}
+
+// ListByOwner returns the contacts the owner has designated, oldest first.
+func (r *EmergencyRepository) ListByOwner(_ context.Context, ownerID uuid.UUID) ([]models.EmergencyContact, error) {
+	return r.list(func(c models.EmergencyContact) bool { return c.OwnerID == ownerID }), nil
+}
+
+// ListByContact returns the designations naming the user as contact,
+// oldest first.
+func (r *EmergencyRepository) ListByContact(_ context.Context, contactID uuid.UUID) ([]models.EmergencyContact, error) {
+	return r.list(func(c models.EmergencyContact) bool { return c.ContactID == contactID }), nil
+}
+
+func (r *EmergencyRepository) list(keep func(models.EmergencyContact) bool) []models.EmergencyContact {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var contacts []models.EmergencyContact
+	for _, byContact := range r.contacts {
+		for _, c := range byContact {
+			if keep(c) {
+				contacts = append(contacts, c)
+			}
+		}
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		if !contacts[i].CreatedAt.Equal(contacts[j].CreatedAt) {
+			return contacts[i].CreatedAt.Before(contacts[j].CreatedAt)
+		}
+		return contacts[i].ContactID.String() < contacts[j].ContactID.String()
+	})
+	return contacts
+}
+
+// Delete removes a designation.
+func (r *EmergencyRepository) Delete(_ context.Context, ownerID, contactID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.contacts[ownerID][contactID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.contacts[ownerID], contactID)
+	return nil
+}
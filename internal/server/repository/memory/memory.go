@@ -0,0 +1,583 @@
+// Package memory implements the repository interfaces in process memory.
+// It backs unit tests and is not meant for production use.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// UserRepository is an in-memory repository.UserRepository.
+type UserRepository struct {
+	mu        sync.RWMutex
+	users     map[uuid.UUID]models.User
+	hints     map[uuid.UUID][]byte
+	verifiers map[uuid.UUID][]byte
+	notify    map[uuid.UUID][]string
+}
+
+// NewUserRepository creates an empty UserRepository.
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users:     make(map[uuid.UUID]models.User),
+		hints:     make(map[uuid.UUID][]byte),
+		verifiers: make(map[uuid.UUID][]byte),
+		notify:    make(map[uuid.UUID][]string),
+	}
+}
+
+// Create inserts a user, reporting repository.ErrAlreadyExists on a
+// duplicate login and repository.ErrEmailExists on a duplicate email.
+func (r *UserRepository) Create(_ context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Login == user.Login {
+			return repository.ErrAlreadyExists
+		}
+		if user.Email != "" && strings.EqualFold(u.Email, user.Email) {
+			return repository.ErrEmailExists
+		}
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+// GetByLogin fetches a user by login.
+func (r *UserRepository) GetByLogin(_ context.Context, login string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, u := range r.users {
+		if u.Login == login {
+			u := u
+			return &u, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// GetByID fetches a user by ID.
+func (r *UserRepository) GetByID(_ context.Context, id uuid.UUID) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	u, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &u, nil
+}
+
+// SetArchiveAfterDays updates the user's auto-archival policy; 0 opts out.
+func (r *UserRepository) SetArchiveAfterDays(_ context.Context, id uuid.UUID, days int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	u.ArchiveAfterDays = days
+	r.users[id] = u
+	return nil
+}
+
+// SetHint replaces the user's encrypted vault hint; empty clears it.
+func (r *UserRepository) SetHint(_ context.Context, id uuid.UUID, hint []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return repository.ErrNotFound
+	}
+	if len(hint) == 0 {
+		delete(r.hints, id)
+		return nil
+	}
+	r.hints[id] = append([]byte(nil), hint...)
+	return nil
+}
+
+// GetHint returns the user's encrypted vault hint, empty when unset.
+func (r *UserRepository) GetHint(_ context.Context, id uuid.UUID) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, ok := r.users[id]; !ok {
+		return nil, repository.ErrNotFound
+	}
+	return append([]byte(nil), r.hints[id]...), nil
+}
+
+// SetMasterVerifier replaces the user's master-password verifier; empty
+// clears it.
+func (r *UserRepository) SetMasterVerifier(_ context.Context, id uuid.UUID, verifier []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return repository.ErrNotFound
+	}
+	if len(verifier) == 0 {
+		delete(r.verifiers, id)
+		return nil
+	}
+	r.verifiers[id] = append([]byte(nil), verifier...)
+	return nil
+}
+
+// GetMasterVerifier returns the user's master-password verifier, empty
+// when unset.
+func (r *UserRepository) GetMasterVerifier(_ context.Context, id uuid.UUID) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, ok := r.users[id]; !ok {
+		return nil, repository.ErrNotFound
+	}
+	return append([]byte(nil), r.verifiers[id]...), nil
+}
+
+// SetNotifyDisabled replaces the notification kinds the user opted out
+// of; empty re-enables everything.
+func (r *UserRepository) SetNotifyDisabled(_ context.Context, id uuid.UUID, disabled []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return repository.ErrNotFound
+	}
+	if len(disabled) == 0 {
+		delete(r.notify, id)
+		return nil
+	}
+	r.notify[id] = append([]string(nil), disabled...)
+	return nil
+}
+
+// GetNotifyDisabled returns the notification kinds the user opted out of.
+func (r *UserRepository) GetNotifyDisabled(_ context.Context, id uuid.UUID) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, ok := r.users[id]; !ok {
+		return nil, repository.ErrNotFound
+	}
+	return append([]string(nil), r.notify[id]...), nil
+}
+
+// SetDisabled marks an account as deactivated.
+func (r *UserRepository) SetDisabled(_ context.Context, id uuid.UUID, disabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	u.Disabled = disabled
+	r.users[id] = u
+	return nil
+}
+
+// SetRole changes an account's role.
+func (r *UserRepository) SetRole(_ context.Context, id uuid.UUID, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	u.Role = role
+	r.users[id] = u
+	return nil
+}
+
+// List returns every account, oldest first.
+func (r *UserRepository) List(_ context.Context) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	users := make([]models.User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].CreatedAt.Before(users[j].CreatedAt) })
+	return users, nil
+}
+
+// Delete removes an account.
+// SetDeleteAfter schedules the account for deletion once at passes; nil
+// cancels a pending schedule.
+func (r *UserRepository) SetDeleteAfter(_ context.Context, id uuid.UUID, at *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	u.DeleteAfter = at
+	r.users[id] = u
+	return nil
+}
+
+// ListDeletable returns the users whose scheduled deletion time has passed.
+func (r *UserRepository) ListDeletable(_ context.Context, now time.Time) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var users []models.User
+	for _, u := range r.users {
+		if u.DeleteAfter != nil && !u.DeleteAfter.After(now) {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+func (r *UserRepository) Delete(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.users, id)
+	delete(r.hints, id)
+	delete(r.verifiers, id)
+	return nil
+}
+
+// ListWithArchivePolicy returns the users with auto-archival enabled.
+func (r *UserRepository) ListWithArchivePolicy(_ context.Context) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var users []models.User
+	for _, u := range r.users {
+		if u.ArchiveAfterDays > 0 {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// SecretRepository is an in-memory repository.SecretRepository.
+type SecretRepository struct {
+	mu      sync.RWMutex
+	secrets map[uuid.UUID]models.Secret
+}
+
+// NewSecretRepository creates an empty SecretRepository.
+func NewSecretRepository() *SecretRepository {
+	return &SecretRepository{secrets: make(map[uuid.UUID]models.Secret)}
+}
+
+// Create inserts a secret.
+func (r *SecretRepository) Create(_ context.Context, secret *models.Secret) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.secrets[secret.ID] = clone(*secret)
+	return nil
+}
+
+// GetByID fetches one secret owned by userID.
+func (r *SecretRepository) GetByID(_ context.Context, userID, id uuid.UUID) (*models.Secret, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.secrets[id]
+	if !ok || s.UserID != userID || s.OrgID != uuid.Nil {
+		return nil, repository.ErrNotFound
+	}
+	s = clone(s)
+	return &s, nil
+}
+
+// GetByUser fetches the secrets owned by userID according to opts.
+func (r *SecretRepository) GetByUser(_ context.Context, userID uuid.UUID, opts repository.SecretListOptions) ([]models.Secret, error) {
+	field, descending := opts.Sort()
+	if !field.Valid() {
+		return nil, fmt.Errorf("invalid sort field %q", field)
+	}
+	r.mu.RLock()
+	var secrets []models.Secret
+	for _, s := range r.secrets {
+		if s.UserID == userID && s.OrgID == uuid.Nil && (!s.Archived || opts.IncludeArchived) && matchName(&s, opts) {
+			secrets = append(secrets, clone(s))
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(secrets, func(i, j int) bool {
+		a, b := sortKey(&secrets[i], field), sortKey(&secrets[j], field)
+		if a != b {
+			if descending {
+				return a > b
+			}
+			return a < b
+		}
+		if descending {
+			return secrets[i].ID.String() > secrets[j].ID.String()
+		}
+		return secrets[i].ID.String() < secrets[j].ID.String()
+	})
+	if opts.After != uuid.Nil {
+		rest := secrets[:0]
+		seen := false
+		for _, s := range secrets {
+			if seen {
+				rest = append(rest, s)
+			}
+			if s.ID == opts.After {
+				seen = true
+			}
+		}
+		secrets = rest
+	}
+	if opts.Limit > 0 && len(secrets) > opts.Limit {
+		secrets = secrets[:opts.Limit]
+	}
+	return secrets, nil
+}
+
+// matchName applies the exact-name filter of opts: the plaintext name or,
+// for sealed rows, the keyed name index.
+func matchName(s *models.Secret, opts repository.SecretListOptions) bool {
+	if opts.Name == "" {
+		return true
+	}
+	return s.Name == opts.Name || (len(opts.NameIndex) > 0 && bytes.Equal(s.NameIndex, opts.NameIndex))
+}
+
+// timeSortLayout is fixed-width so timestamp strings sort chronologically.
+const timeSortLayout = "2006-01-02 15:04:05.000000000"
+
+// sortKey renders the sort field of a secret as a comparable string.
+func sortKey(s *models.Secret, field repository.SecretSortField) string {
+	switch field {
+	case repository.SortByCreatedAt:
+		return s.CreatedAt.UTC().Format(timeSortLayout)
+	case repository.SortByName:
+		return s.Name
+	case repository.SortByType:
+		return string(s.Type)
+	default:
+		return s.UpdatedAt.UTC().Format(timeSortLayout)
+	}
+}
+
+// Update rewrites a secret owned by its user.
+func (r *SecretRepository) Update(_ context.Context, secret *models.Secret) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.secrets[secret.ID]
+	if !ok || existing.UserID != secret.UserID || existing.OrgID != uuid.Nil {
+		return repository.ErrNotFound
+	}
+	r.secrets[secret.ID] = clone(*secret)
+	return nil
+}
+
+// Reassign moves one secret to another owner with re-encrypted payload.
+func (r *SecretRepository) Reassign(_ context.Context, id, fromUserID, toUserID uuid.UUID, data []byte, blobRef string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.secrets[id]
+	if !ok || s.UserID != fromUserID || s.OrgID != uuid.Nil {
+		return repository.ErrNotFound
+	}
+	s.UserID = toUserID
+	s.Data = append([]byte(nil), data...)
+	s.BlobRef = blobRef
+	r.secrets[id] = s
+	return nil
+}
+
+// UsageByUser reports the user's secret count and total payload bytes.
+func (r *SecretRepository) UsageByUser(_ context.Context, userID uuid.UUID) (int, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var count int
+	var size int64
+	for _, s := range r.secrets {
+		if s.UserID == userID && s.OrgID == uuid.Nil {
+			count++
+			size += int64(len(s.Data))
+		}
+	}
+	return count, size, nil
+}
+
+// Delete removes a secret owned by userID.
+func (r *SecretRepository) Delete(_ context.Context, userID, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.secrets[id]
+	if !ok || s.UserID != userID || s.OrgID != uuid.Nil {
+		return repository.ErrNotFound
+	}
+	delete(r.secrets, id)
+	return nil
+}
+
+// GetModifiedAfter lists the user's secrets updated strictly after the
+// given time, oldest first.
+func (r *SecretRepository) GetModifiedAfter(_ context.Context, userID uuid.UUID, after time.Time) ([]models.Secret, error) {
+	r.mu.RLock()
+	var secrets []models.Secret
+	for _, s := range r.secrets {
+		if s.UserID == userID && s.OrgID == uuid.Nil && s.UpdatedAt.After(after) {
+			secrets = append(secrets, clone(s))
+		}
+	}
+	r.mu.RUnlock()
+	sort.Slice(secrets, func(i, j int) bool {
+		if !secrets[i].UpdatedAt.Equal(secrets[j].UpdatedAt) {
+			return secrets[i].UpdatedAt.Before(secrets[j].UpdatedAt)
+		}
+		return secrets[i].ID.String() < secrets[j].ID.String()
+	})
+	return secrets, nil
+}
+
+// MarkAccessed records that a secret was read, bringing it back out of the
+// archive if needed.
+func (r *SecretRepository) MarkAccessed(_ context.Context, userID, id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.secrets[id]
+	if !ok || s.UserID != userID || s.OrgID != uuid.Nil {
+		return repository.ErrNotFound
+	}
+	s.AccessedAt = at
+	s.Archived = false
+	r.secrets[id] = s
+	return nil
+}
+
+// ArchiveUnaccessed archives the user's unprotected secrets last accessed
+// before the cutoff and returns how many it touched.
+func (r *SecretRepository) ArchiveUnaccessed(_ context.Context, userID uuid.UUID, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, s := range r.secrets {
+		if s.UserID == userID && s.OrgID == uuid.Nil && !s.Archived && !s.Protected && s.AccessedAt.Before(before) {
+			s.Archived = true
+			r.secrets[id] = s
+			n++
+		}
+	}
+	return n, nil
+}
+
+// ListUnaccessed returns the IDs ArchiveUnaccessed would archive.
+func (r *SecretRepository) ListUnaccessed(_ context.Context, userID uuid.UUID, before time.Time) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var ids []uuid.UUID
+	for id, s := range r.secrets {
+		if s.UserID == userID && s.OrgID == uuid.Nil && !s.Archived && !s.Protected && s.AccessedAt.Before(before) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// GetByOrg lists the secrets of an organization vault, newest first.
+func (r *SecretRepository) GetByOrg(_ context.Context, orgID uuid.UUID) ([]models.Secret, error) {
+	r.mu.RLock()
+	var secrets []models.Secret
+	for _, s := range r.secrets {
+		if s.OrgID == orgID && orgID != uuid.Nil {
+			secrets = append(secrets, clone(s))
+		}
+	}
+	r.mu.RUnlock()
+	sort.Slice(secrets, func(i, j int) bool {
+		if !secrets[i].UpdatedAt.Equal(secrets[j].UpdatedAt) {
+			return secrets[i].UpdatedAt.After(secrets[j].UpdatedAt)
+		}
+		return secrets[i].ID.String() > secrets[j].ID.String()
+	})
+	return secrets, nil
+}
+
+// GetByOrgAndID fetches one secret from an organization vault.
+func (r *SecretRepository) GetByOrgAndID(_ context.Context, orgID, id uuid.UUID) (*models.Secret, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.secrets[id]
+	if !ok || s.OrgID != orgID || orgID == uuid.Nil {
+		return nil, repository.ErrNotFound
+	}
+	s = clone(s)
+	return &s, nil
+}
+
+// AttachToOrg moves a personal secret into an organization vault with a
+// payload re-encrypted under the org key.
+func (r *SecretRepository) AttachToOrg(_ context.Context, id, userID, orgID uuid.UUID, data []byte, blobRef string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.secrets[id]
+	if !ok || s.UserID != userID || s.OrgID != uuid.Nil {
+		return repository.ErrNotFound
+	}
+	s.OrgID = orgID
+	s.Data = append([]byte(nil), data...)
+	s.BlobRef = blobRef
+	r.secrets[id] = s
+	return nil
+}
+
+// DetachFromOrg moves an org secret into the personal vault of toUserID
+// with a payload re-encrypted under that user's key.
+func (r *SecretRepository) DetachFromOrg(_ context.Context, id, orgID, toUserID uuid.UUID, data []byte, blobRef string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.secrets[id]
+	if !ok || s.OrgID != orgID || orgID == uuid.Nil {
+		return repository.ErrNotFound
+	}
+	s.OrgID = uuid.Nil
+	s.UserID = toUserID
+	s.Data = append([]byte(nil), data...)
+	s.BlobRef = blobRef
+	r.secrets[id] = s
+	return nil
+}
+
+func clone(s models.Secret) models.Secret {
+	s.Data = append([]byte(nil), s.Data...)
+	s.NameIndex = append([]byte(nil), s.NameIndex...)
+	return s
+}
+
+// ReplaceData rewrites the stored ciphertext of any secret without
+// touching its other fields, for key rotation.
+func (r *SecretRepository) ReplaceData(_ context.Context, id uuid.UUID, data []byte, blobRef string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.secrets[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	s.Data = append([]byte(nil), data...)
+	s.BlobRef = blobRef
+	r.secrets[id] = s
+	return nil
+}
+
+// ReplaceMeta rewrites the stored name, metadata and name index of any
+// secret without touching its payload, for metadata encryption.
+func (r *SecretRepository) ReplaceMeta(_ context.Context, id uuid.UUID, name, metadata string, nameIndex []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.secrets[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	s.Name = name
+	s.Metadata = metadata
+	s.NameIndex = append([]byte(nil), nameIndex...)
+	r.secrets[id] = s
+	return nil
+}
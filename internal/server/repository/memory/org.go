@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// OrgRepository is an in-memory repository.OrgRepository.
+type OrgRepository struct {
+	mu      sync.RWMutex
+	orgs    map[uuid.UUID]models.Organization
+	members map[uuid.UUID]map[uuid.UUID]models.OrgMember
+}
+
+// NewOrgRepository creates an empty OrgRepository.
+func NewOrgRepository() *OrgRepository {
+	return &OrgRepository{
+		orgs:    make(map[uuid.UUID]models.Organization),
+		members: make(map[uuid.UUID]map[uuid.UUID]models.OrgMember),
+	}
+}
+
+// Create inserts an organization.
+func (r *OrgRepository) Create(_ context.Context, org *models.Organization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orgs[org.ID] = *org
+	return nil
+}
+
+// GetByID fetches one organization.
+func (r *OrgRepository) GetByID(_ context.Context, id uuid.UUID) (*models.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	org, ok := r.orgs[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &org, nil
+}
+
+// ListByUser returns the organizations the user is a member of, oldest
+// first.
+func (r *OrgRepository) ListByUser(_ context.Context, userID uuid.UUID) ([]models.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var orgs []models.Organization
+	for orgID, members := range r.members {
+		if _, ok := members[userID]; ok {
+			orgs = append(orgs, r.orgs[orgID])
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		if !orgs[i].CreatedAt.Equal(orgs[j].CreatedAt) {
+			return orgs[i].CreatedAt.Before(orgs[j].CreatedAt)
+		}
+		return orgs[i].ID.String() < orgs[j].ID.String()
+	})
+	return orgs, nil
+}
+
+// AddMember inserts a membership, reporting repository.ErrAlreadyExists
+// for a user who is already a member.
+func (r *OrgRepository) AddMember(_ context.Context, member *models.OrgMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	members, ok := r.members[member.OrgID]
+	if !ok {
+		members = make(map[uuid.UUID]models.OrgMember)
+		r.members[member.OrgID] = members
+	}
+	if _, ok := members[member.UserID]; ok {
+		return repository.ErrAlreadyExists
+	}
+	members[member.UserID] = *member
+	return nil
+}
+
+// GetMember fetches one membership.
+func (r *OrgRepository) GetMember(_ context.Context, orgID, userID uuid.UUID) (*models.OrgMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.members[orgID][userID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &m, nil
+}
+
+// ListMembers returns the members of an organization, oldest first.
+func (r *OrgRepository) ListMembers(_ context.Context, orgID uuid.UUID) ([]models.OrgMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	members := make([]models.OrgMember, 0, len(r.members[orgID]))
+	for _, m := range r.members[orgID] {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].CreatedAt.Equal(members[j].CreatedAt) {
+			return members[i].CreatedAt.Before(members[j].CreatedAt)
+		}
+		return members[i].UserID.String() < members[j].UserID.String()
+	})
+	return members, nil
+}
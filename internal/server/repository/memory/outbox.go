@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// OutboxRepository is an in-memory repository.OutboxRepository.
+type OutboxRepository struct {
+	mu        sync.Mutex
+	events    []models.OutboxEvent
+	published map[int64]bool
+	nextID    int64
+}
+
+// NewOutboxRepository creates an empty OutboxRepository.
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{published: make(map[int64]bool), nextID: 1}
+}
+
+// Append inserts an event and fills in its assigned ID.
+func (r *OutboxRepository) Append(_ context.Context, event *models.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.ID = r.nextID
+	r.nextID++
+	r.events = append(r.events, *event)
+	return nil
+}
+
+// ListUnpublished returns up to limit undispatched events in ID order.
+func (r *OutboxRepository) ListUnpublished(_ context.Context, limit int) ([]models.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []models.OutboxEvent
+	for _, e := range r.events {
+		if !r.published[e.ID] {
+			events = append(events, e)
+			if len(events) == limit {
+				break
+			}
+		}
+	}
+	return events, nil
+}
+
+// MarkPublished records that the events with the given IDs were delivered.
+func (r *OutboxRepository) MarkPublished(_ context.Context, ids []int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		r.published[id] = true
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// SecretShareRepository is an in-memory repository.SecretShareRepository.
+type SecretShareRepository struct {
+	mu     sync.RWMutex
+	shares map[uuid.UUID]map[uuid.UUID]models.SecretShare
+}
+
+// NewSecretShareRepository creates an empty SecretShareRepository.
+func NewSecretShareRepository() *SecretShareRepository {
+	return &SecretShareRepository{shares: make(map[uuid.UUID]map[uuid.UUID]models.SecretShare)}
+}
+
+// Upsert inserts a grant or updates the mode of an existing one.
+func (r *SecretShareRepository) Upsert(_ context.Context, share *models.SecretShare) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	grants, ok := r.shares[share.SecretID]
+	if !ok {
+		grants = make(map[uuid.UUID]models.SecretShare)
+		r.shares[share.SecretID] = grants
+	}
+	if existing, ok := grants[share.RecipientID]; ok {
+		existing.Mode = share.Mode
+		grants[share.RecipientID] = existing
+		return nil
+	}
+	grants[share.RecipientID] = *share
+	return nil
+}
+
+// Get fetches one grant.
+func (r *SecretShareRepository) Get(_ context.Context, secretID, recipientID uuid.UUID) (*models.SecretShare, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.shares[secretID][recipientID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &s, nil
+}
+
+// ListBySecret returns the grants of one secret, oldest first.
+func (r *SecretShareRepository) ListBySecret(_ context.Context, ownerID, secretID uuid.UUID) ([]models.SecretShare, error) {
+	return r.list(func(s models.SecretShare) bool {
+		return s.OwnerID == ownerID && s.SecretID == secretID
+	}), nil
+}
+
+// ListByOwner returns every grant the owner has given, oldest first.
+func (r *SecretShareRepository) ListByOwner(_ context.Context, ownerID uuid.UUID) ([]models.SecretShare, error) {
+	return r.list(func(s models.SecretShare) bool { return s.OwnerID == ownerID }), nil
+}
+
+// ListByRecipient returns the grants the user has received, oldest first.
+func (r *SecretShareRepository) ListByRecipient(_ context.Context, recipientID uuid.UUID) ([]models.SecretShare, error) {
+	return r.list(func(s models.SecretShare) bool { return s.RecipientID == recipientID }), nil
+}
+
+func (r *SecretShareRepository) list(keep func(models.SecretShare) bool) []models.SecretShare {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var shares []models.SecretShare
+	for _, grants := range r.shares {
+		for _, s := range grants {
+			if keep(s) {
+				shares = append(shares, s)
+			}
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if !shares[i].CreatedAt.Equal(shares[j].CreatedAt) {
+			return shares[i].CreatedAt.Before(shares[j].CreatedAt)
+		}
+		return shares[i].SecretID.String() < shares[j].SecretID.String()
+	})
+	return shares
+}
+
+// Delete removes a grant.
+func (r *SecretShareRepository) Delete(_ context.Context, secretID, recipientID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.shares[secretID][recipientID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.shares[secretID], recipientID)
+	return nil
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// ShareRepository is an in-memory repository.ShareRepository.
+type ShareRepository struct {
+	mu    sync.RWMutex
+	links map[uuid.UUID]models.ShareLink
+}
+
+// NewShareRepository creates an empty ShareRepository.
+func NewShareRepository() *ShareRepository {
+	return &ShareRepository{links: make(map[uuid.UUID]models.ShareLink)}
+}
+
+// Create inserts a share link.
+func (r *ShareRepository) Create(_ context.Context, link *models.ShareLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *link
+	stored.TokenHash = append([]byte(nil), link.TokenHash...)
+	stored.Name = append([]byte(nil), link.Name...)
+	stored.Payload = append([]byte(nil), link.Payload...)
+	r.links[link.ID] = stored
+	return nil
+}
+
+// GetByTokenHash fetches a link by the hash of its URL token.
+func (r *ShareRepository) GetByTokenHash(_ context.Context, hash []byte) (*models.ShareLink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, link := range r.links {
+		if crypto.ConstantTimeEqual(link.TokenHash, hash) {
+			link.Name = append([]byte(nil), link.Name...)
+			link.Payload = append([]byte(nil), link.Payload...)
+			return &link, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// Delete removes a link.
+func (r *ShareRepository) Delete(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.links[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.links, id)
+	return nil
+}
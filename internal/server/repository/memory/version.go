@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// SecretVersionRepository is an in-memory
+// repository.SecretVersionRepository.
+type SecretVersionRepository struct {
+	mu       sync.RWMutex
+	versions map[uuid.UUID][]models.SecretVersion
+}
+
+// NewSecretVersionRepository creates an empty SecretVersionRepository.
+func NewSecretVersionRepository() *SecretVersionRepository {
+	return &SecretVersionRepository{versions: make(map[uuid.UUID][]models.SecretVersion)}
+}
+
+// Create inserts a snapshot, assigning the next version number of the
+// secret and setting it on the model.
+func (r *SecretVersionRepository) Create(_ context.Context, v *models.SecretVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v.Version = len(r.versions[v.SecretID]) + 1
+	stored := *v
+	stored.Data = append([]byte(nil), v.Data...)
+	r.versions[v.SecretID] = append(r.versions[v.SecretID], stored)
+	return nil
+}
+
+// ListBySecret fetches the snapshots of one secret, newest first.
+func (r *SecretVersionRepository) ListBySecret(_ context.Context, userID, secretID uuid.UUID) ([]models.SecretVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var versions []models.SecretVersion
+	for _, v := range r.versions[secretID] {
+		if v.UserID == userID {
+			v.Data = append([]byte(nil), v.Data...)
+			versions = append(versions, v)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version > versions[j].Version })
+	return versions, nil
+}
+
+// GetByVersion fetches one snapshot by its version number.
+func (r *SecretVersionRepository) GetByVersion(_ context.Context, userID, secretID uuid.UUID, version int) (*models.SecretVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, v := range r.versions[secretID] {
+		if v.UserID == userID && v.Version == version {
+			v.Data = append([]byte(nil), v.Data...)
+			return &v, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// ReplaceData rewrites the stored ciphertext of one snapshot, for key
+// rotation.
+func (r *SecretVersionRepository) ReplaceData(_ context.Context, id uuid.UUID, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for secretID, versions := range r.versions {
+		for i := range versions {
+			if versions[i].ID == id {
+				r.versions[secretID][i].Data = append([]byte(nil), data...)
+				return nil
+			}
+		}
+	}
+	return repository.ErrNotFound
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// WebhookRepository is an in-memory repository.WebhookRepository.
+type WebhookRepository struct {
+	mu         sync.RWMutex
+	hooks      map[uuid.UUID]models.Webhook
+	deliveries []models.WebhookDelivery
+	nextID     int64
+}
+
+// NewWebhookRepository creates an empty WebhookRepository.
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{hooks: make(map[uuid.UUID]models.Webhook)}
+}
+
+// Create inserts a webhook.
+func (r *WebhookRepository) Create(_ context.Context, hook *models.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks[hook.ID] = *hook
+	return nil
+}
+
+// Get fetches one webhook.
+func (r *WebhookRepository) Get(_ context.Context, userID, id uuid.UUID) (*models.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	hook, ok := r.hooks[id]
+	if !ok || hook.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &hook, nil
+}
+
+// ListByUser fetches a user's webhooks, oldest first.
+func (r *WebhookRepository) ListByUser(_ context.Context, userID uuid.UUID) ([]models.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var hooks []models.Webhook
+	for _, hook := range r.hooks {
+		if hook.UserID == userID {
+			hooks = append(hooks, hook)
+		}
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].CreatedAt.Before(hooks[j].CreatedAt) })
+	return hooks, nil
+}
+
+// Delete removes a webhook and its delivery log.
+func (r *WebhookRepository) Delete(_ context.Context, userID, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hook, ok := r.hooks[id]
+	if !ok || hook.UserID != userID {
+		return repository.ErrNotFound
+	}
+	delete(r.hooks, id)
+	kept := r.deliveries[:0]
+	for _, d := range r.deliveries {
+		if d.WebhookID != id {
+			kept = append(kept, d)
+		}
+	}
+	r.deliveries = kept
+	return nil
+}
+
+// RecordDelivery appends one delivery attempt to the hook's log.
+func (r *WebhookRepository) RecordDelivery(_ context.Context, delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	delivery.ID = r.nextID
+	r.deliveries = append(r.deliveries, *delivery)
+	return nil
+}
+
+// ListDeliveries returns the hook's latest delivery attempts, newest first.
+func (r *WebhookRepository) ListDeliveries(_ context.Context, webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var deliveries []models.WebhookDelivery
+	for i := len(r.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if r.deliveries[i].WebhookID == webhookID {
+			deliveries = append(deliveries, r.deliveries[i])
+		}
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// AttachmentRepository is the Postgres implementation of
+// repository.AttachmentRepository.
+type AttachmentRepository struct {
+	pool *Pool
+}
+
+// NewAttachmentRepository creates an AttachmentRepository.
+func NewAttachmentRepository(pool *Pool) *AttachmentRepository {
+	return &AttachmentRepository{pool: pool}
+}
+
+// Create inserts an attachment.
+func (r *AttachmentRepository) Create(ctx context.Context, a *models.Attachment) error {
+	_, err := db(ctx, r.pool).Exec(ctx,
+		`INSERT INTO attachments (id, secret_id, user_id, filename, size, content, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		a.ID, a.SecretID, a.UserID, a.Filename, a.Size, a.Content, a.CreatedAt)
+	return err
+}
+
+// GetByID fetches one attachment, including its content.
+func (r *AttachmentRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*models.Attachment, error) {
+	var a models.Attachment
+	err := db(ctx, r.pool).QueryRow(ctx,
+		`SELECT id, secret_id, user_id, filename, size, content, created_at
+		 FROM attachments WHERE user_id = $1 AND id = $2`,
+		userID, id).
+		Scan(&a.ID, &a.SecretID, &a.UserID, &a.Filename, &a.Size, &a.Content, &a.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListBySecret fetches the attachment metadata of one secret, without
+// content.
+func (r *AttachmentRepository) ListBySecret(ctx context.Context, userID, secretID uuid.UUID) ([]models.Attachment, error) {
+	rows, err := db(ctx, r.pool).Query(ctx,
+		`SELECT id, secret_id, user_id, filename, size, created_at
+		 FROM attachments WHERE user_id = $1 AND secret_id = $2 ORDER BY created_at`,
+		userID, secretID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		var a models.Attachment
+		if err := rows.Scan(&a.ID, &a.SecretID, &a.UserID, &a.Filename, &a.Size, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+// Delete removes an attachment.
+func (r *AttachmentRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `DELETE FROM attachments WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Reassign moves one attachment to another owner with re-encrypted content.
+func (r *AttachmentRepository) Reassign(ctx context.Context, id, fromUserID, toUserID uuid.UUID, content []byte) error {
+	tag, err := db(ctx, r.pool).Exec(ctx,
+		`UPDATE attachments SET user_id = $1, content = $2 WHERE user_id = $3 AND id = $4`,
+		toUserID, content, fromUserID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ReplaceContent rewrites the stored ciphertext of one attachment, for key
+// rotation.
+func (r *AttachmentRepository) ReplaceContent(ctx context.Context, id uuid.UUID, content []byte) error {
+	tag, err := db(ctx, r.pool).Exec(ctx,
+		`UPDATE attachments SET content = $1 WHERE id = $2`, content, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
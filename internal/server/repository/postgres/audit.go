@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// AuditRepository is the Postgres implementation of
+// repository.AuditRepository.
+type AuditRepository struct {
+	pool *Pool
+}
+
+// NewAuditRepository creates an AuditRepository.
+func NewAuditRepository(pool *Pool) *AuditRepository {
+	return &AuditRepository{pool: pool}
+}
+
+// Append inserts an event and fills in its assigned ID.
+func (r *AuditRepository) Append(ctx context.Context, event *models.AuditEvent) error {
+	return db(ctx, r.pool).QueryRow(ctx,
+		`INSERT INTO audit_events (user_id, action, resource, ip, user_agent, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		event.UserID, event.Action, event.Resource, event.IP, event.UserAgent, event.CreatedAt).
+		Scan(&event.ID)
+}
+
+// ListAfter returns up to limit events of a user past the given cursor.
+func (r *AuditRepository) ListAfter(ctx context.Context, userID uuid.UUID, after int64, limit int) ([]models.AuditEvent, error) {
+	rows, err := reader(ctx, r.pool).Query(ctx,
+		`SELECT id, user_id, action, resource, ip, user_agent, created_at FROM audit_events
+		 WHERE user_id = $1 AND id > $2 ORDER BY id LIMIT $3`,
+		userID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		var e models.AuditEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Action, &e.Resource, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// ListRecentByActions returns up to limit of the user's newest events
+// matching one of the actions, newest first.
+func (r *AuditRepository) ListRecentByActions(ctx context.Context, userID uuid.UUID, actions []string, limit int) ([]models.AuditEvent, error) {
+	rows, err := reader(ctx, r.pool).Query(ctx,
+		`SELECT id, user_id, action, resource, ip, user_agent, created_at FROM audit_events
+		 WHERE user_id = $1 AND action = ANY($2) ORDER BY id DESC LIMIT $3`,
+		userID, actions, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		var e models.AuditEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Action, &e.Resource, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,255 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// snapshotVersion is bumped when the snapshot layout changes.
+const snapshotVersion = 1
+
+// Snapshot is a consistent dump of the vault tables: accounts,
+// organizations and the encrypted secrets with their attachments and
+// version history. Payloads stay ciphertext, so a snapshot is as safe to
+// store as the database itself. Operational tables (audit trail, outbox,
+// devices, share links) are not part of it, and payloads offloaded to a
+// blob store are referenced, not copied.
+type Snapshot struct {
+	Version     int             `json:"version"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Users       []userRow       `json:"users"`
+	Orgs        []orgRow        `json:"organizations"`
+	OrgMembers  []orgMemberRow  `json:"org_members"`
+	Secrets     []secretRow     `json:"secrets"`
+	Attachments []attachmentRow `json:"attachments"`
+	Versions    []versionRow    `json:"secret_versions"`
+}
+
+type userRow struct {
+	ID               uuid.UUID `json:"id"`
+	Login            string    `json:"login"`
+	PasswordHash     string    `json:"password_hash"`
+	Role             string    `json:"role"`
+	ArchiveAfterDays int       `json:"archive_after_days"`
+	Disabled         bool      `json:"disabled"`
+	Hint             []byte    `json:"hint,omitempty"`
+	MasterVerifier   []byte    `json:"master_verifier,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type orgRow struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type orgMemberRow struct {
+	OrgID     uuid.UUID `json:"org_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type secretRow struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	OrgID      *uuid.UUID `json:"org_id,omitempty"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Metadata   string     `json:"metadata"`
+	Protected  bool       `json:"protected"`
+	Data       []byte     `json:"data"`
+	BlobRef    string     `json:"blob_ref,omitempty"`
+	Archived   bool       `json:"archived"`
+	NameIndex  []byte     `json:"name_index,omitempty"`
+	AccessedAt time.Time  `json:"accessed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type attachmentRow struct {
+	ID        uuid.UUID `json:"id"`
+	SecretID  uuid.UUID `json:"secret_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Filename  string    `json:"filename"`
+	Size      int64     `json:"size"`
+	Content   []byte    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type versionRow struct {
+	ID        uuid.UUID `json:"id"`
+	SecretID  uuid.UUID `json:"secret_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Version   int       `json:"version"`
+	Data      []byte    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DumpSnapshot reads the vault tables inside one repeatable-read
+// transaction, so writes landing during the dump do not tear it.
+func DumpSnapshot(ctx context.Context, pool *pgxpool.Pool) (*Snapshot, error) {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("postgres: begin snapshot: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	snap := &Snapshot{Version: snapshotVersion, CreatedAt: time.Now().UTC()}
+	if err := forEachRow(ctx, tx,
+		`SELECT id, login, password_hash, role, archive_after_days, disabled, hint, master_verifier, created_at FROM users ORDER BY created_at`,
+		func(rows pgx.Rows) error {
+			var u userRow
+			if err := rows.Scan(&u.ID, &u.Login, &u.PasswordHash, &u.Role, &u.ArchiveAfterDays, &u.Disabled, &u.Hint, &u.MasterVerifier, &u.CreatedAt); err != nil {
+				return err
+			}
+			snap.Users = append(snap.Users, u)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+	if err := forEachRow(ctx, tx,
+		`SELECT id, name, created_at FROM organizations ORDER BY created_at`,
+		func(rows pgx.Rows) error {
+			var o orgRow
+			if err := rows.Scan(&o.ID, &o.Name, &o.CreatedAt); err != nil {
+				return err
+			}
+			snap.Orgs = append(snap.Orgs, o)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+	if err := forEachRow(ctx, tx,
+		`SELECT org_id, user_id, role, created_at FROM org_members ORDER BY created_at`,
+		func(rows pgx.Rows) error {
+			var m orgMemberRow
+			if err := rows.Scan(&m.OrgID, &m.UserID, &m.Role, &m.CreatedAt); err != nil {
+				return err
+			}
+			snap.OrgMembers = append(snap.OrgMembers, m)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+	if err := forEachRow(ctx, tx,
+		`SELECT id, user_id, org_id, name, type, metadata, protected, data, blob_ref, archived, name_index, accessed_at, created_at, updated_at FROM secrets ORDER BY created_at`,
+		func(rows pgx.Rows) error {
+			var s secretRow
+			if err := rows.Scan(&s.ID, &s.UserID, &s.OrgID, &s.Name, &s.Type, &s.Metadata, &s.Protected, &s.Data, &s.BlobRef, &s.Archived, &s.NameIndex, &s.AccessedAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+				return err
+			}
+			snap.Secrets = append(snap.Secrets, s)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+	if err := forEachRow(ctx, tx,
+		`SELECT id, secret_id, user_id, filename, size, content, created_at FROM attachments ORDER BY created_at`,
+		func(rows pgx.Rows) error {
+			var a attachmentRow
+			if err := rows.Scan(&a.ID, &a.SecretID, &a.UserID, &a.Filename, &a.Size, &a.Content, &a.CreatedAt); err != nil {
+				return err
+			}
+			snap.Attachments = append(snap.Attachments, a)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+	if err := forEachRow(ctx, tx,
+		`SELECT id, secret_id, user_id, version, data, created_at FROM secret_versions ORDER BY created_at`,
+		func(rows pgx.Rows) error {
+			var v versionRow
+			if err := rows.Scan(&v.ID, &v.SecretID, &v.UserID, &v.Version, &v.Data, &v.CreatedAt); err != nil {
+				return err
+			}
+			snap.Versions = append(snap.Versions, v)
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+	return snap, tx.Commit(ctx)
+}
+
+// forEachRow runs a query and invokes scan once per row.
+func forEachRow(ctx context.Context, tx pgx.Tx, query string, scan func(pgx.Rows) error) error {
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// RestoreSnapshot loads a snapshot into the database inside one
+// transaction. Rows that collide with existing keys fail the whole
+// restore, so it is meant for an empty, freshly migrated database.
+func RestoreSnapshot(ctx context.Context, pool *pgxpool.Pool, snap *Snapshot) error {
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("postgres: unsupported snapshot version %d", snap.Version)
+	}
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("postgres: begin restore: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	for _, u := range snap.Users {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO users (id, login, password_hash, role, archive_after_days, disabled, hint, master_verifier, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			u.ID, u.Login, u.PasswordHash, u.Role, u.ArchiveAfterDays, u.Disabled, u.Hint, u.MasterVerifier, u.CreatedAt); err != nil {
+			return fmt.Errorf("postgres: restore user %s: %w", u.ID, err)
+		}
+	}
+	for _, o := range snap.Orgs {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO organizations (id, name, created_at) VALUES ($1, $2, $3)`,
+			o.ID, o.Name, o.CreatedAt); err != nil {
+			return fmt.Errorf("postgres: restore organization %s: %w", o.ID, err)
+		}
+	}
+	for _, m := range snap.OrgMembers {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO org_members (org_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)`,
+			m.OrgID, m.UserID, m.Role, m.CreatedAt); err != nil {
+			return fmt.Errorf("postgres: restore org member %s/%s: %w", m.OrgID, m.UserID, err)
+		}
+	}
+	for _, s := range snap.Secrets {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO secrets (id, user_id, org_id, name, type, metadata, protected, data, blob_ref, archived, name_index, accessed_at, created_at, updated_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+			s.ID, s.UserID, s.OrgID, s.Name, s.Type, s.Metadata, s.Protected, s.Data, s.BlobRef,
+			s.Archived, s.NameIndex, s.AccessedAt, s.CreatedAt, s.UpdatedAt); err != nil {
+			return fmt.Errorf("postgres: restore secret %s: %w", s.ID, err)
+		}
+	}
+	for _, a := range snap.Attachments {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO attachments (id, secret_id, user_id, filename, size, content, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			a.ID, a.SecretID, a.UserID, a.Filename, a.Size, a.Content, a.CreatedAt); err != nil {
+			return fmt.Errorf("postgres: restore attachment %s: %w", a.ID, err)
+		}
+	}
+	for _, v := range snap.Versions {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO secret_versions (id, secret_id, user_id, version, data, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			v.ID, v.SecretID, v.UserID, v.Version, v.Data, v.CreatedAt); err != nil {
+			return fmt.Errorf("postgres: restore version %s: %w", v.ID, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
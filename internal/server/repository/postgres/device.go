@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// DeviceRepository is the Postgres implementation of
+// repository.DeviceRepository.
+type DeviceRepository struct {
+	pool *Pool
+}
+
+// NewDeviceRepository creates a DeviceRepository.
+func NewDeviceRepository(pool *Pool) *DeviceRepository {
+	return &DeviceRepository{pool: pool}
+}
+
+// Create inserts a device.
+func (r *DeviceRepository) Create(ctx context.Context, d *models.Device) error {
+	_, err := db(ctx, r.pool).Exec(ctx,
+		`INSERT INTO devices (id, user_id, name, platform, last_synced_at, last_cursor, revoked, fingerprint, pending, approval_token, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		d.ID, d.UserID, d.Name, d.Platform, d.LastSyncedAt, d.LastCursor, d.Revoked, d.Fingerprint, d.Pending, d.ApprovalToken, d.CreatedAt)
+	return err
+}
+
+// Get fetches one device.
+func (r *DeviceRepository) Get(ctx context.Context, userID, id uuid.UUID) (*models.Device, error) {
+	var d models.Device
+	err := db(ctx, r.pool).QueryRow(ctx,
+		`SELECT id, user_id, name, platform, last_synced_at, last_cursor, revoked, fingerprint, pending, approval_token, created_at
+		 FROM devices WHERE user_id = $1 AND id = $2`,
+		userID, id).
+		Scan(&d.ID, &d.UserID, &d.Name, &d.Platform, &d.LastSyncedAt, &d.LastCursor, &d.Revoked, &d.Fingerprint, &d.Pending, &d.ApprovalToken, &d.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListByUser fetches a user's devices, oldest first.
+func (r *DeviceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Device, error) {
+	rows, err := db(ctx, r.pool).Query(ctx,
+		`SELECT id, user_id, name, platform, last_synced_at, last_cursor, revoked, fingerprint, pending, approval_token, created_at
+		 FROM devices WHERE user_id = $1 ORDER BY created_at`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices []models.Device
+	for rows.Next() {
+		var d models.Device
+		if err := rows.Scan(&d.ID, &d.UserID, &d.Name, &d.Platform, &d.LastSyncedAt, &d.LastCursor, &d.Revoked, &d.Fingerprint, &d.Pending, &d.ApprovalToken, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
+// UpdateSyncState records the sync point a device reached.
+func (r *DeviceRepository) UpdateSyncState(ctx context.Context, userID, id uuid.UUID, syncedAt time.Time, cursor int64) error {
+	tag, err := db(ctx, r.pool).Exec(ctx,
+		`UPDATE devices SET last_synced_at = $1, last_cursor = $2 WHERE user_id = $3 AND id = $4`,
+		syncedAt, cursor, userID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// GetByFingerprint fetches the user's device carrying the login
+// fingerprint.
+func (r *DeviceRepository) GetByFingerprint(ctx context.Context, userID uuid.UUID, fingerprint string) (*models.Device, error) {
+	var d models.Device
+	err := db(ctx, r.pool).QueryRow(ctx,
+		`SELECT id, user_id, name, platform, last_synced_at, last_cursor, revoked, fingerprint, pending, approval_token, created_at
+		 FROM devices WHERE user_id = $1 AND fingerprint = $2`,
+		userID, fingerprint).
+		Scan(&d.ID, &d.UserID, &d.Name, &d.Platform, &d.LastSyncedAt, &d.LastCursor, &d.Revoked, &d.Fingerprint, &d.Pending, &d.ApprovalToken, &d.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Approve clears the pending flag and approval token of the user's
+// device.
+func (r *DeviceRepository) Approve(ctx context.Context, userID, id uuid.UUID) error {
+	tag, err := db(ctx, r.pool).Exec(ctx,
+		`UPDATE devices SET pending = FALSE, approval_token = '' WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ApproveByToken approves the pending device holding the approval token
+// and returns it.
+func (r *DeviceRepository) ApproveByToken(ctx context.Context, token string) (*models.Device, error) {
+	var d models.Device
+	err := db(ctx, r.pool).QueryRow(ctx,
+		`UPDATE devices SET pending = FALSE, approval_token = ''
+		 WHERE approval_token = $1 AND pending
+		 RETURNING id, user_id, name, platform, last_synced_at, last_cursor, revoked, fingerprint, pending, approval_token, created_at`,
+		token).
+		Scan(&d.ID, &d.UserID, &d.Name, &d.Platform, &d.LastSyncedAt, &d.LastCursor, &d.Revoked, &d.Fingerprint, &d.Pending, &d.ApprovalToken, &d.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// SetRevoked marks a device as revoked.
+func (r *DeviceRepository) SetRevoked(ctx context.Context, userID, id uuid.UUID, revoked bool) error {
+	tag, err := db(ctx, r.pool).Exec(ctx,
+		`UPDATE devices SET revoked = $1 WHERE user_id = $2 AND id = $3`, revoked, userID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
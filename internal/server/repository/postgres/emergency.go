@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// EmergencyRepository is the Postgres implementation of
+// repository.EmergencyRepository.
+type EmergencyRepository struct {
+	pool *Pool
+}
+
+// NewEmergencyRepository creates an EmergencyRepository.
+func NewEmergencyRepository(pool *Pool) *EmergencyRepository {
+	return &EmergencyRepository{pool: pool}
+}
+
+// Upsert inserts a designation or replaces the wait period and request
+// state of an existing one.
+func (r *EmergencyRepository) Upsert(ctx context.Context, contact *models.EmergencyContact) error {
+	_, err := db(ctx, r.pool).Exec(ctx,
+		`INSERT INTO emergency_contacts (owner_id, contact_id, wait_days, requested_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (owner_id, contact_id)
+		 DO UPDATE SET wait_days = EXCLUDED.wait_days, requested_at = EXCLUDED.requested_at`,
+		contact.OwnerID, contact.ContactID, contact.WaitDays, contact.RequestedAt, contact.CreatedAt)
+	return err
+}
+
+// Get fetches one designation.
+func (r *EmergencyRepository) Get(ctx context.Context, ownerID, contactID uuid.UUID) (*models.EmergencyContact, error) {
+	var c models.EmergencyContact
+	err := db(ctx, r.pool).QueryRow(ctx,
+		`SELECT owner_id, contact_id, wait_days, requested_at, created_at
+		 FROM emergency_contacts WHERE owner_id = $1 AND contact_id = $2`,
+		ownerID, contactID).
+		Scan(&c.OwnerID, &c.ContactID, &c.WaitDays, &c.RequestedAt, &c.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListByOwner returns the contacts the owner has designated, oldest first.
+func (r *EmergencyRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.EmergencyContact, error) {
+	return r.list(ctx,
+		`SELECT owner_id, contact_id, wait_days, requested_at, created_at
+		 FROM emergency_contacts WHERE owner_id = $1 ORDER BY created_at, contact_id`,
+		ownerID)
+}
+
+// ListByContact returns the designations naming the user as contact,
+// oldest first.
+func (r *EmergencyRepository) ListByContact(ctx context.Context, contactID uuid.UUID) ([]models.EmergencyContact, error) {
+	return r.list(ctx,
+		`SELECT owner_id, contact_id, wait_days, requested_at, created_at
+		 FROM emergency_contacts WHERE contact_id = $1 ORDER BY created_at, owner_id`,
+		contactID)
+}
+
+func (r *EmergencyRepository) list(ctx context.Context, query string, args ...any) ([]models.EmergencyContact, error) {
+	rows, err := db(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []models.EmergencyContact
+	for rows.Next() {
+		var c models.EmergencyContact
+		if err := rows.Scan(&c.OwnerID, &c.ContactID, &c.WaitDays, &c.RequestedAt, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
+// Delete removes a designation.
+func (r *EmergencyRepository) Delete(ctx context.Context, ownerID, contactID uuid.UUID) error {
+	tag, err := db(ctx, r.pool).Exec(ctx,
+		`DELETE FROM emergency_contacts WHERE owner_id = $1 AND contact_id = $2`,
+		ownerID, contactID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/alexuryumtsev/gophkeeper/migrations"
+)
+
+// Migration is the status of one embedded migration script.
+type Migration struct {
+	Filename  string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrate applies the embedded migration scripts that have not run yet, in
+// filename order, each inside its own transaction, and returns how many it
+// applied. A database migrated by hand before the tracking table existed
+// re-runs every script; seed schema_migrations first to skip them.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) (int, error) {
+	if err := ensureMigrationTable(ctx, pool); err != nil {
+		return 0, err
+	}
+	entries, err := migrations.Files.ReadDir(".")
+	if err != nil {
+		return 0, err
+	}
+	applied := 0
+	for _, entry := range entries {
+		var count int
+		if err := pool.QueryRow(ctx,
+			`SELECT COUNT(*) FROM schema_migrations WHERE filename = $1`, entry.Name()).Scan(&count); err != nil {
+			return applied, err
+		}
+		if count > 0 {
+			continue
+		}
+		script, err := migrations.Files.ReadFile(entry.Name())
+		if err != nil {
+			return applied, err
+		}
+		if err := applyMigration(ctx, pool, entry.Name(), string(script)); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// MigrationStatus reports every embedded script and whether it has run.
+func MigrationStatus(ctx context.Context, pool *pgxpool.Pool) ([]Migration, error) {
+	if err := ensureMigrationTable(ctx, pool); err != nil {
+		return nil, err
+	}
+	rows, err := pool.Query(ctx, `SELECT filename, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[string]time.Time)
+	for rows.Next() {
+		var filename string
+		var at time.Time
+		if err := rows.Scan(&filename, &at); err != nil {
+			return nil, err
+		}
+		appliedAt[filename] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := migrations.Files.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+	var status []Migration
+	for _, entry := range entries {
+		at, ok := appliedAt[entry.Name()]
+		status = append(status, Migration{Filename: entry.Name(), Applied: ok, AppliedAt: at})
+	}
+	return status, nil
+}
+
+// MigrationsApplied returns an error naming the first embedded migration
+// that has not run, nil when the schema is up to date. The readiness
+// probe uses it, so a server missing migrations never enters rotation.
+func MigrationsApplied(ctx context.Context, pool *pgxpool.Pool) error {
+	status, err := MigrationStatus(ctx, pool)
+	if err != nil {
+		return err
+	}
+	for _, m := range status {
+		if !m.Applied {
+			return fmt.Errorf("postgres: migration %s not applied", m.Filename)
+		}
+	}
+	return nil
+}
+
+func ensureMigrationTable(ctx context.Context, pool *pgxpool.Pool) error {
+	_, err := pool.Exec(ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (filename TEXT PRIMARY KEY, applied_at TIMESTAMPTZ NOT NULL)`)
+	if err != nil {
+		return fmt.Errorf("postgres: create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, pool *pgxpool.Pool, filename, script string) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	if _, err := tx.Exec(ctx, script); err != nil {
+		return fmt.Errorf("postgres: apply %s: %w", filename, err)
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO schema_migrations (filename, applied_at) VALUES ($1, $2)`,
+		filename, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// OutboxRepository is the Postgres implementation of
+// repository.OutboxRepository.
+type OutboxRepository struct {
+	pool *Pool
+}
+
+// NewOutboxRepository creates an OutboxRepository.
+func NewOutboxRepository(pool *Pool) *OutboxRepository {
+	return &OutboxRepository{pool: pool}
+}
+
+// Append inserts an event and fills in its assigned ID.
+func (r *OutboxRepository) Append(ctx context.Context, event *models.OutboxEvent) error {
+	return db(ctx, r.pool).QueryRow(ctx,
+		`INSERT INTO outbox (user_id, action, resource, created_at)
+		 VALUES ($1, $2, $3, $4) RETURNING id`,
+		event.UserID, event.Action, event.Resource, event.CreatedAt).
+		Scan(&event.ID)
+}
+
+// ListUnpublished returns up to limit undispatched events in ID order.
+func (r *OutboxRepository) ListUnpublished(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	rows, err := db(ctx, r.pool).Query(ctx,
+		`SELECT id, user_id, action, resource, created_at FROM outbox
+		 WHERE published_at IS NULL ORDER BY id LIMIT $1`,
+		limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var e models.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Action, &e.Resource, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// MarkPublished records that the events with the given IDs were delivered.
+func (r *OutboxRepository) MarkPublished(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := db(ctx, r.pool).Exec(ctx, `UPDATE outbox SET published_at = now() WHERE id = ANY($1)`, ids)
+	return err
+}
@@ -0,0 +1,84 @@
+// Package postgres implements the repository interfaces on top of a pgx
+// connection pool.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const uniqueViolationCode = "23505"
+
+// PoolSettings tunes the connection pool. Zero values keep the pgx
+// defaults.
+type PoolSettings struct {
+	// MaxConns caps the number of open connections.
+	MaxConns int32
+	// MaxConnIdleTime closes connections idle for longer than this.
+	MaxConnIdleTime time.Duration
+	// HealthCheckPeriod is how often idle connections are health-checked.
+	HealthCheckPeriod time.Duration
+}
+
+// New creates a connection pool with the given settings and verifies
+// connectivity.
+func New(ctx context.Context, dsn string, settings PoolSettings) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: parse config: %w", err)
+	}
+	if settings.MaxConns > 0 {
+		cfg.MaxConns = settings.MaxConns
+	}
+	if settings.MaxConnIdleTime > 0 {
+		cfg.MaxConnIdleTime = settings.MaxConnIdleTime
+	}
+	if settings.HealthCheckPeriod > 0 {
+		cfg.HealthCheckPeriod = settings.HealthCheckPeriod
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: create pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("postgres: ping: %w", err)
+	}
+	return pool, nil
+}
+
+// Pool pairs the primary connection pool with an optional read replica.
+// Writes and read-for-update lookups always hit the primary; listing
+// reads that tolerate replication lag go to the replica when one is
+// configured.
+type Pool struct {
+	primary *pgxpool.Pool
+	replica *pgxpool.Pool
+}
+
+// NewPool creates a Pool. replica may be nil, in which case every query
+// runs on the primary.
+func NewPool(primary, replica *pgxpool.Pool) *Pool {
+	return &Pool{primary: primary, replica: replica}
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+// constraintName returns the name of the constraint a Postgres error
+// reports, empty for non-Postgres errors. It lets callers tell which
+// unique constraint a violation hit.
+func constraintName(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// SecretShareRepository is the Postgres implementation of
+// repository.SecretShareRepository.
+type SecretShareRepository struct {
+	pool *Pool
+}
+
+// NewSecretShareRepository creates a SecretShareRepository.
+func NewSecretShareRepository(pool *Pool) *SecretShareRepository {
+	return &SecretShareRepository{pool: pool}
+}
+
+// Upsert inserts a grant or updates the mode of an existing one.
+func (r *SecretShareRepository) Upsert(ctx context.Context, share *models.SecretShare) error {
+	_, err := db(ctx, r.pool).Exec(ctx,
+		`INSERT INTO secret_shares (secret_id, owner_id, recipient_id, mode, created_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (secret_id, recipient_id) DO UPDATE SET mode = EXCLUDED.mode`,
+		share.SecretID, share.OwnerID, share.RecipientID, share.Mode, share.CreatedAt)
+	return err
+}
+
+// Get fetches one grant.
+func (r *SecretShareRepository) Get(ctx context.Context, secretID, recipientID uuid.UUID) (*models.SecretShare, error) {
+	var s models.SecretShare
+	err := db(ctx, r.pool).QueryRow(ctx,
+		`SELECT secret_id, owner_id, recipient_id, mode, created_at
+		 FROM secret_shares WHERE secret_id = $1 AND recipient_id = $2`,
+		secretID, recipientID).
+		Scan(&s.SecretID, &s.OwnerID, &s.RecipientID, &s.Mode, &s.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListBySecret returns the grants of one secret, oldest first.
+func (r *SecretShareRepository) ListBySecret(ctx context.Context, ownerID, secretID uuid.UUID) ([]models.SecretShare, error) {
+	return r.list(ctx,
+		`SELECT secret_id, owner_id, recipient_id, mode, created_at
+		 FROM secret_shares WHERE owner_id = $1 AND secret_id = $2 ORDER BY created_at, recipient_id`,
+		ownerID, secretID)
+}
+
+// ListByOwner returns every grant the owner has given, oldest first.
+func (r *SecretShareRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.SecretShare, error) {
+	return r.list(ctx,
+		`SELECT secret_id, owner_id, recipient_id, mode, created_at
+		 FROM secret_shares WHERE owner_id = $1 ORDER BY created_at, secret_id`,
+		ownerID)
+}
+
+// ListByRecipient returns the grants the user has received, oldest first.
+func (r *SecretShareRepository) ListByRecipient(ctx context.Context, recipientID uuid.UUID) ([]models.SecretShare, error) {
+	return r.list(ctx,
+		`SELECT secret_id, owner_id, recipient_id, mode, created_at
+		 FROM secret_shares WHERE recipient_id = $1 ORDER BY created_at, secret_id`,
+		recipientID)
+}
+
+func (r *SecretShareRepository) list(ctx context.Context, query string, args ...any) ([]models.SecretShare, error) {
+	rows, err := db(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []models.SecretShare
+	for rows.Next() {
+		var s models.SecretShare
+		if err := rows.Scan(&s.SecretID, &s.OwnerID, &s.RecipientID, &s.Mode, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, rows.Err()
+}
+
+// Delete removes a grant.
+func (r *SecretShareRepository) Delete(ctx context.Context, secretID, recipientID uuid.UUID) error {
+	tag, err := db(ctx, r.pool).Exec(ctx,
+		`DELETE FROM secret_shares WHERE secret_id = $1 AND recipient_id = $2`,
+		secretID, recipientID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// ShareRepository is the Postgres implementation of
+// repository.ShareRepository.
+type ShareRepository struct {
+	pool *Pool
+}
+
+// NewShareRepository creates a ShareRepository.
+func NewShareRepository(pool *Pool) *ShareRepository {
+	return &ShareRepository{pool: pool}
+}
+
+// Create inserts a share link.
+func (r *ShareRepository) Create(ctx context.Context, link *models.ShareLink) error {
+	_, err := db(ctx, r.pool).Exec(ctx,
+		`INSERT INTO share_links (id, user_id, secret_id, token_hash, name, type, payload, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		link.ID, link.UserID, link.SecretID, link.TokenHash, link.Name, link.Type,
+		link.Payload, link.ExpiresAt, link.CreatedAt)
+	return err
+}
+
+// GetByTokenHash fetches a link by the hash of its URL token.
+func (r *ShareRepository) GetByTokenHash(ctx context.Context, hash []byte) (*models.ShareLink, error) {
+	var link models.ShareLink
+	err := db(ctx, r.pool).QueryRow(ctx,
+		`SELECT id, user_id, secret_id, token_hash, name, type, payload, expires_at, created_at
+		 FROM share_links WHERE token_hash = $1`,
+		hash).
+		Scan(&link.ID, &link.UserID, &link.SecretID, &link.TokenHash, &link.Name,
+			&link.Type, &link.Payload, &link.ExpiresAt, &link.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// Delete removes a link.
+func (r *ShareRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `DELETE FROM share_links WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
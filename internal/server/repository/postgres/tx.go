@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier is the subset of pgx the repositories use. Both the pool and an
+// open transaction satisfy it, so repository methods can run against either.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// txKey carries the active transaction through context.
+type txKey struct{}
+
+// db returns the transaction from ctx when one is active, the primary
+// pool otherwise. Every repository method goes through it or through
+// reader, so any call made inside WithinTransaction automatically joins
+// the transaction.
+func db(ctx context.Context, pool *Pool) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool.primary
+}
+
+// reader returns where a lag-tolerant read should run: the transaction
+// when one is active (reads inside a transaction stay consistent),
+// otherwise the replica when configured, otherwise the primary. Point
+// lookups that feed writes use db instead, since a lagging replica could
+// hand back a stale row.
+func reader(ctx context.Context, pool *Pool) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	if pool.replica != nil {
+		return pool.replica
+	}
+	return pool.primary
+}
+
+// TransactionManager implements repository.TransactionManager on a pgx
+// connection pool. The transaction travels in the context, so the function
+// keeps calling the same repository instances it uses outside one.
+type TransactionManager struct {
+	pool *pgxpool.Pool
+}
+
+// NewTransactionManager creates a TransactionManager on the given pool.
+func NewTransactionManager(pool *pgxpool.Pool) *TransactionManager {
+	return &TransactionManager{pool: pool}
+}
+
+// WithinTransaction runs fn inside a transaction, committing on success and
+// rolling back on error. A nested call joins the transaction already in the
+// context instead of opening a second one.
+func (m *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("postgres: begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("postgres: commit transaction: %w", err)
+	}
+	return nil
+}
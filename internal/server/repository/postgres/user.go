@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// UserRepository is the Postgres implementation of repository.UserRepository.
+type UserRepository struct {
+	pool *Pool
+}
+
+// NewUserRepository creates a UserRepository.
+func NewUserRepository(pool *Pool) *UserRepository {
+	return &UserRepository{pool: pool}
+}
+
+// Create inserts a user, reporting repository.ErrAlreadyExists on a
+// duplicate login and repository.ErrEmailExists on a duplicate email.
+// Uniqueness lives in the database, so concurrent registrations cannot
+// race past it.
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	_, err := db(ctx, r.pool).Exec(ctx,
+		`INSERT INTO users (id, login, email, password_hash, role, archive_after_days, disabled, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		user.ID, user.Login, user.Email, user.PasswordHash, user.Role, user.ArchiveAfterDays, user.Disabled, user.CreatedAt)
+	if isUniqueViolation(err) {
+		if strings.Contains(constraintName(err), "email") {
+			return repository.ErrEmailExists
+		}
+		return repository.ErrAlreadyExists
+	}
+	return err
+}
+
+// GetByLogin fetches a user by login.
+func (r *UserRepository) GetByLogin(ctx context.Context, login string) (*models.User, error) {
+	return r.get(ctx, `SELECT id, login, email, password_hash, role, archive_after_days, disabled, created_at FROM users WHERE login = $1`, login)
+}
+
+// GetByID fetches a user by ID.
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return r.get(ctx, `SELECT id, login, email, password_hash, role, archive_after_days, disabled, created_at FROM users WHERE id = $1`, id)
+}
+
+// SetArchiveAfterDays updates the user's auto-archival policy; 0 opts out.
+func (r *UserRepository) SetArchiveAfterDays(ctx context.Context, id uuid.UUID, days int) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `UPDATE users SET archive_after_days = $1 WHERE id = $2`, days, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListWithArchivePolicy returns the users with auto-archival enabled.
+func (r *UserRepository) ListWithArchivePolicy(ctx context.Context) ([]models.User, error) {
+	rows, err := db(ctx, r.pool).Query(ctx,
+		`SELECT id, login, email, password_hash, role, archive_after_days, disabled, created_at FROM users WHERE archive_after_days > 0`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Login, &u.Email, &u.PasswordHash, &u.Role, &u.ArchiveAfterDays, &u.Disabled, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// SetHint replaces the user's encrypted vault hint; empty clears it.
+func (r *UserRepository) SetHint(ctx context.Context, id uuid.UUID, hint []byte) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `UPDATE users SET hint = $1 WHERE id = $2`, hint, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// GetHint returns the user's encrypted vault hint, empty when unset.
+func (r *UserRepository) GetHint(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	var hint []byte
+	err := db(ctx, r.pool).QueryRow(ctx, `SELECT hint FROM users WHERE id = $1`, id).Scan(&hint)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	return hint, err
+}
+
+// SetMasterVerifier replaces the user's master-password verifier; empty
+// clears it.
+func (r *UserRepository) SetMasterVerifier(ctx context.Context, id uuid.UUID, verifier []byte) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `UPDATE users SET master_verifier = $1 WHERE id = $2`, verifier, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// GetMasterVerifier returns the user's master-password verifier, empty
+// when unset.
+func (r *UserRepository) GetMasterVerifier(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	var verifier []byte
+	err := db(ctx, r.pool).QueryRow(ctx, `SELECT master_verifier FROM users WHERE id = $1`, id).Scan(&verifier)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	return verifier, err
+}
+
+// SetNotifyDisabled replaces the notification kinds the user opted out
+// of; empty re-enables everything.
+func (r *UserRepository) SetNotifyDisabled(ctx context.Context, id uuid.UUID, disabled []string) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `UPDATE users SET notify_disabled = $1 WHERE id = $2`, joinEvents(disabled), id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// GetNotifyDisabled returns the notification kinds the user opted out of.
+func (r *UserRepository) GetNotifyDisabled(ctx context.Context, id uuid.UUID) ([]string, error) {
+	var disabled string
+	err := db(ctx, r.pool).QueryRow(ctx, `SELECT notify_disabled FROM users WHERE id = $1`, id).Scan(&disabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	return splitEvents(disabled), err
+}
+
+// SetDisabled marks an account as deactivated.
+func (r *UserRepository) SetDisabled(ctx context.Context, id uuid.UUID, disabled bool) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `UPDATE users SET disabled = $1 WHERE id = $2`, disabled, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// SetDeleteAfter schedules the account for deletion once at passes; nil
+// cancels a pending schedule.
+func (r *UserRepository) SetDeleteAfter(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `UPDATE users SET delete_after = $1 WHERE id = $2`, at, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListDeletable returns the users whose scheduled deletion time has passed.
+func (r *UserRepository) ListDeletable(ctx context.Context, now time.Time) ([]models.User, error) {
+	rows, err := db(ctx, r.pool).Query(ctx,
+		`SELECT id, login, email, password_hash, role, archive_after_days, disabled, created_at FROM users WHERE delete_after IS NOT NULL AND delete_after <= $1`, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Login, &u.Email, &u.PasswordHash, &u.Role, &u.ArchiveAfterDays, &u.Disabled, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// SetRole changes an account's role.
+func (r *UserRepository) SetRole(ctx context.Context, id uuid.UUID, role string) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `UPDATE users SET role = $1 WHERE id = $2`, role, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// List returns every account, oldest first.
+func (r *UserRepository) List(ctx context.Context) ([]models.User, error) {
+	rows, err := db(ctx, r.pool).Query(ctx,
+		`SELECT id, login, email, password_hash, role, archive_after_days, disabled, created_at FROM users ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Login, &u.Email, &u.PasswordHash, &u.Role, &u.ArchiveAfterDays, &u.Disabled, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// Delete removes an account; secrets and devices cascade at the database.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) get(ctx context.Context, query string, arg any) (*models.User, error) {
+	var u models.User
+	err := db(ctx, r.pool).QueryRow(ctx, query, arg).Scan(&u.ID, &u.Login, &u.Email, &u.PasswordHash, &u.Role, &u.ArchiveAfterDays, &u.Disabled, &u.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// SecretVersionRepository is the Postgres implementation of
+// repository.SecretVersionRepository.
+type SecretVersionRepository struct {
+	pool *Pool
+}
+
+// NewSecretVersionRepository creates a SecretVersionRepository.
+func NewSecretVersionRepository(pool *Pool) *SecretVersionRepository {
+	return &SecretVersionRepository{pool: pool}
+}
+
+// Create inserts a snapshot, assigning the next version number of the
+// secret and setting it on the model.
+func (r *SecretVersionRepository) Create(ctx context.Context, v *models.SecretVersion) error {
+	return db(ctx, r.pool).QueryRow(ctx,
+		`INSERT INTO secret_versions (id, secret_id, user_id, version, data, created_at)
+		 VALUES ($1, $2, $3,
+		         (SELECT COALESCE(MAX(version), 0) + 1 FROM secret_versions WHERE secret_id = $2),
+		         $4, $5)
+		 RETURNING version`,
+		v.ID, v.SecretID, v.UserID, v.Data, v.CreatedAt).Scan(&v.Version)
+}
+
+// ListBySecret fetches the snapshots of one secret, newest first.
+func (r *SecretVersionRepository) ListBySecret(ctx context.Context, userID, secretID uuid.UUID) ([]models.SecretVersion, error) {
+	rows, err := db(ctx, r.pool).Query(ctx,
+		`SELECT id, secret_id, user_id, version, data, created_at
+		 FROM secret_versions WHERE user_id = $1 AND secret_id = $2 ORDER BY version DESC`,
+		userID, secretID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.SecretVersion
+	for rows.Next() {
+		var v models.SecretVersion
+		if err := rows.Scan(&v.ID, &v.SecretID, &v.UserID, &v.Version, &v.Data, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// GetByVersion fetches one snapshot by its version number.
+func (r *SecretVersionRepository) GetByVersion(ctx context.Context, userID, secretID uuid.UUID, version int) (*models.SecretVersion, error) {
+	var v models.SecretVersion
+	err := db(ctx, r.pool).QueryRow(ctx,
+		`SELECT id, secret_id, user_id, version, data, created_at
+		 FROM secret_versions WHERE user_id = $1 AND secret_id = $2 AND version = $3`,
+		userID, secretID, version).
+		Scan(&v.ID, &v.SecretID, &v.UserID, &v.Version, &v.Data, &v.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ReplaceData rewrites the stored ciphertext of one snapshot, for key
+// rotation.
+func (r *SecretVersionRepository) ReplaceData(ctx context.Context, id uuid.UUID, data []byte) error {
+	tag, err := db(ctx, r.pool).Exec(ctx,
+		`UPDATE secret_versions SET data = $1 WHERE id = $2`, data, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
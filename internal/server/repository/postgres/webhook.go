@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// WebhookRepository is the Postgres implementation of
+// repository.WebhookRepository.
+type WebhookRepository struct {
+	pool *Pool
+}
+
+// NewWebhookRepository creates a WebhookRepository.
+func NewWebhookRepository(pool *Pool) *WebhookRepository {
+	return &WebhookRepository{pool: pool}
+}
+
+// Create inserts a webhook.
+func (r *WebhookRepository) Create(ctx context.Context, hook *models.Webhook) error {
+	_, err := db(ctx, r.pool).Exec(ctx,
+		`INSERT INTO webhooks (id, user_id, url, secret, events, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		hook.ID, hook.UserID, hook.URL, hook.Secret, joinEvents(hook.Events), hook.CreatedAt)
+	return err
+}
+
+// Get fetches one webhook.
+func (r *WebhookRepository) Get(ctx context.Context, userID, id uuid.UUID) (*models.Webhook, error) {
+	var hook models.Webhook
+	var events string
+	err := db(ctx, r.pool).QueryRow(ctx,
+		`SELECT id, user_id, url, secret, events, created_at FROM webhooks WHERE user_id = $1 AND id = $2`,
+		userID, id).
+		Scan(&hook.ID, &hook.UserID, &hook.URL, &hook.Secret, &events, &hook.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	hook.Events = splitEvents(events)
+	return &hook, nil
+}
+
+// ListByUser fetches a user's webhooks, oldest first.
+func (r *WebhookRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Webhook, error) {
+	rows, err := db(ctx, r.pool).Query(ctx,
+		`SELECT id, user_id, url, secret, events, created_at FROM webhooks WHERE user_id = $1 ORDER BY created_at`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hooks []models.Webhook
+	for rows.Next() {
+		var hook models.Webhook
+		var events string
+		if err := rows.Scan(&hook.ID, &hook.UserID, &hook.URL, &hook.Secret, &events, &hook.CreatedAt); err != nil {
+			return nil, err
+		}
+		hook.Events = splitEvents(events)
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+// Delete removes a webhook; its delivery log cascades at the database.
+func (r *WebhookRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	tag, err := db(ctx, r.pool).Exec(ctx, `DELETE FROM webhooks WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// RecordDelivery appends one delivery attempt to the hook's log.
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	_, err := db(ctx, r.pool).Exec(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, event_id, action, attempt, status_code, error, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		delivery.WebhookID, delivery.EventID, delivery.Action, delivery.Attempt, delivery.StatusCode, delivery.Error, delivery.CreatedAt)
+	return err
+}
+
+// ListDeliveries returns the hook's latest delivery attempts, newest first.
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := db(ctx, r.pool).Query(ctx,
+		`SELECT id, webhook_id, event_id, action, attempt, status_code, error, created_at
+		 FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY id DESC LIMIT $2`,
+		webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.Action, &d.Attempt, &d.StatusCode, &d.Error, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// joinEvents flattens the event filter for storage; the empty string
+// means every event.
+func joinEvents(events []string) string {
+	return strings.Join(events, ",")
+}
+
+// splitEvents is the inverse of joinEvents.
+func splitEvents(events string) []string {
+	if events == "" {
+		return nil
+	}
+	return strings.Split(events, ",")
+}
@@ -0,0 +1,322 @@
+// Package repository defines the storage interfaces used by the service
+// layer and the errors implementations report through them.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// Sentinel errors shared by all repository implementations.
+var (
+	ErrNotFound      = errors.New("repository: not found")
+	ErrAlreadyExists = errors.New("repository: already exists")
+	// ErrEmailExists distinguishes a duplicate email from a duplicate
+	// login on user creation, so callers can report which field clashed.
+	ErrEmailExists = errors.New("repository: email already exists")
+)
+
+// UserRepository stores user accounts.
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByLogin(ctx context.Context, login string) (*models.User, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	// SetArchiveAfterDays updates the user's auto-archival policy; 0 opts
+	// out.
+	SetArchiveAfterDays(ctx context.Context, id uuid.UUID, days int) error
+	// ListWithArchivePolicy returns the users with auto-archival enabled.
+	ListWithArchivePolicy(ctx context.Context) ([]models.User, error)
+	// SetHint replaces the user's encrypted vault hint; empty clears it.
+	SetHint(ctx context.Context, id uuid.UUID, hint []byte) error
+	// GetHint returns the user's encrypted vault hint, empty when unset.
+	GetHint(ctx context.Context, id uuid.UUID) ([]byte, error)
+	// SetMasterVerifier replaces the user's master-password verifier;
+	// empty clears it.
+	SetMasterVerifier(ctx context.Context, id uuid.UUID, verifier []byte) error
+	// GetMasterVerifier returns the user's master-password verifier, empty
+	// when unset.
+	GetMasterVerifier(ctx context.Context, id uuid.UUID) ([]byte, error)
+	// SetNotifyDisabled replaces the notification kinds the user opted
+	// out of; empty re-enables everything.
+	SetNotifyDisabled(ctx context.Context, id uuid.UUID, disabled []string) error
+	// GetNotifyDisabled returns the notification kinds the user opted out
+	// of.
+	GetNotifyDisabled(ctx context.Context, id uuid.UUID) ([]string, error)
+	// SetDisabled marks an account as deactivated; disabled users cannot
+	// log in.
+	SetDisabled(ctx context.Context, id uuid.UUID, disabled bool) error
+	// SetRole changes an account's role.
+	SetRole(ctx context.Context, id uuid.UUID, role string) error
+	// SetDeleteAfter schedules the account for deletion once at passes;
+	// nil cancels a pending schedule.
+	SetDeleteAfter(ctx context.Context, id uuid.UUID, at *time.Time) error
+	// ListDeletable returns the users whose scheduled deletion time has
+	// passed as of now.
+	ListDeletable(ctx context.Context, now time.Time) ([]models.User, error)
+	// List returns every account, oldest first.
+	List(ctx context.Context) ([]models.User, error)
+	// Delete removes an account and, through cascading, everything it
+	// owns.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// SecretSortField is a sortable column that never requires decryption.
+type SecretSortField string
+
+// Sort fields accepted by SecretListOptions.
+const (
+	SortByUpdatedAt SecretSortField = "updated_at"
+	SortByCreatedAt SecretSortField = "created_at"
+	SortByName      SecretSortField = "name"
+	SortByType      SecretSortField = "type"
+)
+
+// Valid reports whether f is one of the known sort fields.
+func (f SecretSortField) Valid() bool {
+	switch f {
+	case SortByUpdatedAt, SortByCreatedAt, SortByName, SortByType:
+		return true
+	}
+	return false
+}
+
+// SecretListOptions shapes a GetByUser listing. The zero value lists all
+// active secrets sorted by updated_at descending.
+type SecretListOptions struct {
+	IncludeArchived bool
+	// SortBy defaults to SortByUpdatedAt; timestamps sort descending and
+	// text fields ascending unless Descending overrides it.
+	SortBy     SecretSortField
+	Descending *bool
+	// Limit caps the page size; 0 means no limit.
+	Limit int
+	// After resumes a keyset page after the secret with this ID, which
+	// must have appeared in the previous page under the same sort.
+	After uuid.UUID
+	// Name filters to secrets with exactly this name. Rows matching
+	// NameIndex are included too, which covers vaults mixing plaintext
+	// and sealed names.
+	Name string
+	// NameIndex is the keyed hash of Name. The service layer derives it;
+	// implementations only compare it against the stored column.
+	NameIndex []byte
+}
+
+// Sort resolves the zero values of SortBy and Descending for
+// implementations.
+func (o SecretListOptions) Sort() (SecretSortField, bool) {
+	field := o.SortBy
+	if field == "" {
+		field = SortByUpdatedAt
+	}
+	if o.Descending != nil {
+		return field, *o.Descending
+	}
+	return field, field == SortByUpdatedAt || field == SortByCreatedAt
+}
+
+// SecretRepository stores secrets. Data is always ciphertext at this layer.
+type SecretRepository interface {
+	Create(ctx context.Context, secret *models.Secret) error
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*models.Secret, error)
+	// GetByUser lists a user's secrets according to opts.
+	GetByUser(ctx context.Context, userID uuid.UUID, opts SecretListOptions) ([]models.Secret, error)
+	Update(ctx context.Context, secret *models.Secret) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	// GetModifiedAfter lists the user's secrets updated strictly after the
+	// given time, oldest first, for sync.
+	GetModifiedAfter(ctx context.Context, userID uuid.UUID, after time.Time) ([]models.Secret, error)
+	// MarkAccessed records that a secret was read.
+	MarkAccessed(ctx context.Context, userID, id uuid.UUID, at time.Time) error
+	// ArchiveUnaccessed archives the user's secrets last accessed before
+	// the cutoff and returns how many it touched.
+	ArchiveUnaccessed(ctx context.Context, userID uuid.UUID, before time.Time) (int64, error)
+	// ListUnaccessed returns the IDs ArchiveUnaccessed would archive, for
+	// dry runs.
+	ListUnaccessed(ctx context.Context, userID uuid.UUID, before time.Time) ([]uuid.UUID, error)
+	// Reassign moves one secret to another owner, replacing its payload
+	// with data and blobRef re-encrypted for the new owner's key.
+	Reassign(ctx context.Context, id, fromUserID, toUserID uuid.UUID, data []byte, blobRef string) error
+	// UsageByUser returns how many secrets a user stores and the total
+	// size of their encrypted payloads in bytes.
+	UsageByUser(ctx context.Context, userID uuid.UUID) (count int, bytes int64, err error)
+	// GetByOrg lists the secrets of an organization vault, newest first.
+	GetByOrg(ctx context.Context, orgID uuid.UUID) ([]models.Secret, error)
+	// GetByOrgAndID fetches one secret from an organization vault.
+	GetByOrgAndID(ctx context.Context, orgID, id uuid.UUID) (*models.Secret, error)
+	// AttachToOrg moves a personal secret into an organization vault,
+	// replacing its payload with data and blobRef re-encrypted under the
+	// org key.
+	AttachToOrg(ctx context.Context, id, userID, orgID uuid.UUID, data []byte, blobRef string) error
+	// DetachFromOrg moves an org secret into the personal vault of
+	// toUserID, replacing its payload with data and blobRef re-encrypted
+	// under that user's key.
+	DetachFromOrg(ctx context.Context, id, orgID, toUserID uuid.UUID, data []byte, blobRef string) error
+	// ReplaceData rewrites the stored ciphertext of any secret without
+	// touching its other fields. It exists for key rotation.
+	ReplaceData(ctx context.Context, id uuid.UUID, data []byte, blobRef string) error
+	// ReplaceMeta rewrites the stored name, metadata and name index of any
+	// secret without touching its payload. It exists for metadata
+	// encryption: vault moves and key rotation re-seal these fields after
+	// the payload has been rewritten.
+	ReplaceMeta(ctx context.Context, id uuid.UUID, name, metadata string, nameIndex []byte) error
+}
+
+// SecretShareRepository stores user-to-user share grants. Upsert inserts a
+// grant or updates the mode of an existing one.
+type SecretShareRepository interface {
+	Upsert(ctx context.Context, share *models.SecretShare) error
+	Get(ctx context.Context, secretID, recipientID uuid.UUID) (*models.SecretShare, error)
+	// ListBySecret returns the grants of one secret, oldest first.
+	ListBySecret(ctx context.Context, ownerID, secretID uuid.UUID) ([]models.SecretShare, error)
+	// ListByOwner returns every grant the owner has given, oldest first.
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.SecretShare, error)
+	// ListByRecipient returns the grants the user has received, oldest
+	// first.
+	ListByRecipient(ctx context.Context, recipientID uuid.UUID) ([]models.SecretShare, error)
+	Delete(ctx context.Context, secretID, recipientID uuid.UUID) error
+}
+
+// EmergencyRepository stores emergency-access designations. Upsert inserts
+// a designation or replaces the wait period and request state of an
+// existing one.
+type EmergencyRepository interface {
+	Upsert(ctx context.Context, contact *models.EmergencyContact) error
+	Get(ctx context.Context, ownerID, contactID uuid.UUID) (*models.EmergencyContact, error)
+	// ListByOwner returns the contacts the owner has designated, oldest
+	// first.
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.EmergencyContact, error)
+	// ListByContact returns the designations naming the user as contact,
+	// oldest first.
+	ListByContact(ctx context.Context, contactID uuid.UUID) ([]models.EmergencyContact, error)
+	Delete(ctx context.Context, ownerID, contactID uuid.UUID) error
+}
+
+// OrgRepository stores organizations and their memberships. AddMember
+// reports repository.ErrAlreadyExists for a user who is already a member.
+type OrgRepository interface {
+	Create(ctx context.Context, org *models.Organization) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Organization, error)
+	// ListByUser returns the organizations the user is a member of,
+	// oldest first.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Organization, error)
+	AddMember(ctx context.Context, member *models.OrgMember) error
+	GetMember(ctx context.Context, orgID, userID uuid.UUID) (*models.OrgMember, error)
+	// ListMembers returns the members of an organization, oldest first.
+	ListMembers(ctx context.Context, orgID uuid.UUID) ([]models.OrgMember, error)
+}
+
+// SecretVersionRepository stores payload snapshots taken before updates.
+// Data is always ciphertext at this layer. Create assigns the next version
+// number of the secret and sets it on the model.
+type SecretVersionRepository interface {
+	Create(ctx context.Context, version *models.SecretVersion) error
+	// ListBySecret returns the versions of a secret, newest first.
+	ListBySecret(ctx context.Context, userID, secretID uuid.UUID) ([]models.SecretVersion, error)
+	// GetByVersion fetches one snapshot by its version number.
+	GetByVersion(ctx context.Context, userID, secretID uuid.UUID, version int) (*models.SecretVersion, error)
+	// ReplaceData rewrites the stored ciphertext of one snapshot. It
+	// exists for key rotation.
+	ReplaceData(ctx context.Context, id uuid.UUID, data []byte) error
+}
+
+// AttachmentRepository stores encrypted files attached to secrets. Content
+// is always ciphertext at this layer. Listing omits content; GetByID
+// includes it.
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *models.Attachment) error
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*models.Attachment, error)
+	ListBySecret(ctx context.Context, userID, secretID uuid.UUID) ([]models.Attachment, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	// Reassign moves one attachment to another owner, replacing its
+	// content with bytes re-encrypted for the new owner's key.
+	Reassign(ctx context.Context, id, fromUserID, toUserID uuid.UUID, content []byte) error
+	// ReplaceContent rewrites the stored ciphertext of one attachment. It
+	// exists for key rotation.
+	ReplaceContent(ctx context.Context, id uuid.UUID, content []byte) error
+}
+
+// DeviceRepository stores registered sync devices and their sync points.
+type DeviceRepository interface {
+	Create(ctx context.Context, device *models.Device) error
+	Get(ctx context.Context, userID, id uuid.UUID) (*models.Device, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Device, error)
+	// UpdateSyncState records the sync point a device reached.
+	UpdateSyncState(ctx context.Context, userID, id uuid.UUID, syncedAt time.Time, cursor int64) error
+	// SetRevoked marks a device as revoked; revoked devices cannot sync.
+	SetRevoked(ctx context.Context, userID, id uuid.UUID, revoked bool) error
+	// GetByFingerprint fetches the user's device carrying the login
+	// fingerprint.
+	GetByFingerprint(ctx context.Context, userID uuid.UUID, fingerprint string) (*models.Device, error)
+	// Approve clears the pending flag and approval token of the user's
+	// device.
+	Approve(ctx context.Context, userID, id uuid.UUID) error
+	// ApproveByToken approves the pending device holding the approval
+	// token and returns it.
+	ApproveByToken(ctx context.Context, token string) (*models.Device, error)
+}
+
+// ShareRepository stores single-use public share links.
+type ShareRepository interface {
+	Create(ctx context.Context, link *models.ShareLink) error
+	// GetByTokenHash fetches a link by the hash of its URL token.
+	GetByTokenHash(ctx context.Context, hash []byte) (*models.ShareLink, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// AuditRepository stores the append-only audit trail.
+type AuditRepository interface {
+	Append(ctx context.Context, event *models.AuditEvent) error
+	// ListAfter returns up to limit events of a user with ID greater than
+	// after, in ascending ID order.
+	ListAfter(ctx context.Context, userID uuid.UUID, after int64, limit int) ([]models.AuditEvent, error)
+	// ListRecentByActions returns up to limit of the user's newest events
+	// matching one of the actions, newest first.
+	ListRecentByActions(ctx context.Context, userID uuid.UUID, actions []string, limit int) ([]models.AuditEvent, error)
+}
+
+// OutboxRepository stores change events pending dispatch. Append must run
+// inside the same transaction as the mutation it describes.
+type OutboxRepository interface {
+	Append(ctx context.Context, event *models.OutboxEvent) error
+	// ListUnpublished returns up to limit undispatched events in ID order.
+	ListUnpublished(ctx context.Context, limit int) ([]models.OutboxEvent, error)
+	// MarkPublished records that the events with the given IDs were
+	// delivered to every consumer.
+	MarkPublished(ctx context.Context, ids []int64) error
+}
+
+// WebhookRepository stores user-registered webhooks and their delivery
+// log.
+type WebhookRepository interface {
+	Create(ctx context.Context, hook *models.Webhook) error
+	Get(ctx context.Context, userID, id uuid.UUID) (*models.Webhook, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Webhook, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	// RecordDelivery appends one delivery attempt to the hook's log.
+	RecordDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	// ListDeliveries returns the hook's latest delivery attempts, newest
+	// first, up to limit.
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+}
+
+// TransactionManager runs a function within a storage transaction. The
+// context passed to fn carries the transaction and must be used for all
+// repository calls inside it.
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// NoopTransactionManager satisfies TransactionManager without transactional
+// guarantees. It is used by backends that do not support transactions.
+type NoopTransactionManager struct{}
+
+// WithinTransaction simply invokes fn.
+func (NoopTransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
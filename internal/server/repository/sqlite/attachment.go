@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// AttachmentRepository is the SQLite implementation of
+// repository.AttachmentRepository.
+type AttachmentRepository struct {
+	db *sql.DB
+}
+
+// NewAttachmentRepository creates an AttachmentRepository.
+func NewAttachmentRepository(dbh *sql.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: dbh}
+}
+
+// Create inserts an attachment.
+func (r *AttachmentRepository) Create(ctx context.Context, a *models.Attachment) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO attachments (id, secret_id, user_id, filename, size, content, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		a.ID, a.SecretID, a.UserID, a.Filename, a.Size, a.Content, a.CreatedAt)
+	return err
+}
+
+// GetByID fetches one attachment, including its content.
+func (r *AttachmentRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*models.Attachment, error) {
+	var a models.Attachment
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, secret_id, user_id, filename, size, content, created_at
+		 FROM attachments WHERE user_id = ? AND id = ?`,
+		userID, id).
+		Scan(&a.ID, &a.SecretID, &a.UserID, &a.Filename, &a.Size, &a.Content, &a.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListBySecret fetches the attachment metadata of one secret, without
+// content.
+func (r *AttachmentRepository) ListBySecret(ctx context.Context, userID, secretID uuid.UUID) ([]models.Attachment, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id, secret_id, user_id, filename, size, created_at
+		 FROM attachments WHERE user_id = ? AND secret_id = ? ORDER BY created_at`,
+		userID, secretID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		var a models.Attachment
+		if err := rows.Scan(&a.ID, &a.SecretID, &a.UserID, &a.Filename, &a.Size, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+// Delete removes an attachment.
+func (r *AttachmentRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `DELETE FROM attachments WHERE user_id = ? AND id = ?`, userID, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// Reassign moves one attachment to another owner with re-encrypted content.
+func (r *AttachmentRepository) Reassign(ctx context.Context, id, fromUserID, toUserID uuid.UUID, content []byte) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE attachments SET user_id = ?, content = ? WHERE user_id = ? AND id = ?`,
+		toUserID, content, fromUserID, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// ReplaceContent rewrites the stored ciphertext of one attachment, for key
+// rotation.
+func (r *AttachmentRepository) ReplaceContent(ctx context.Context, id uuid.UUID, content []byte) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE attachments SET content = ? WHERE id = ?`, content, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// AuditRepository is the SQLite implementation of
+// repository.AuditRepository.
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates an AuditRepository.
+func NewAuditRepository(dbh *sql.DB) *AuditRepository {
+	return &AuditRepository{db: dbh}
+}
+
+// Append inserts an event and fills in its assigned ID.
+func (r *AuditRepository) Append(ctx context.Context, event *models.AuditEvent) error {
+	return db(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO audit_events (user_id, action, resource, ip, user_agent, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?) RETURNING id`,
+		event.UserID, event.Action, event.Resource, event.IP, event.UserAgent, event.CreatedAt).
+		Scan(&event.ID)
+}
+
+// ListAfter returns up to limit events of a user past the given cursor.
+func (r *AuditRepository) ListAfter(ctx context.Context, userID uuid.UUID, after int64, limit int) ([]models.AuditEvent, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, action, resource, ip, user_agent, created_at FROM audit_events
+		 WHERE user_id = ? AND id > ? ORDER BY id LIMIT ?`,
+		userID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		var e models.AuditEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Action, &e.Resource, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// ListRecentByActions returns up to limit of the user's newest events
+// matching one of the actions, newest first.
+func (r *AuditRepository) ListRecentByActions(ctx context.Context, userID uuid.UUID, actions []string, limit int) ([]models.AuditEvent, error) {
+	if len(actions) == 0 {
+		return nil, nil
+	}
+	args := []any{userID}
+	placeholders := make([]string, len(actions))
+	for i, action := range actions {
+		placeholders[i] = "?"
+		args = append(args, action)
+	}
+	args = append(args, limit)
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, action, resource, ip, user_agent, created_at FROM audit_events
+		 WHERE user_id = ? AND action IN (`+strings.Join(placeholders, ", ")+`) ORDER BY id DESC LIMIT ?`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		var e models.AuditEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Action, &e.Resource, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
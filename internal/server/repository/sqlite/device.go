@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// DeviceRepository is the SQLite implementation of
+// repository.DeviceRepository.
+type DeviceRepository struct {
+	db *sql.DB
+}
+
+// NewDeviceRepository creates a DeviceRepository.
+func NewDeviceRepository(dbh *sql.DB) *DeviceRepository {
+	return &DeviceRepository{db: dbh}
+}
+
+// Create inserts a device.
+func (r *DeviceRepository) Create(ctx context.Context, d *models.Device) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO devices (id, user_id, name, platform, last_synced_at, last_cursor, revoked, fingerprint, pending, approval_token, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.ID, d.UserID, d.Name, d.Platform, d.LastSyncedAt, d.LastCursor, d.Revoked, d.Fingerprint, d.Pending, d.ApprovalToken, d.CreatedAt)
+	return err
+}
+
+// Get fetches one device.
+func (r *DeviceRepository) Get(ctx context.Context, userID, id uuid.UUID) (*models.Device, error) {
+	var d models.Device
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, user_id, name, platform, last_synced_at, last_cursor, revoked, fingerprint, pending, approval_token, created_at
+		 FROM devices WHERE user_id = ? AND id = ?`,
+		userID, id).
+		Scan(&d.ID, &d.UserID, &d.Name, &d.Platform, &d.LastSyncedAt, &d.LastCursor, &d.Revoked, &d.Fingerprint, &d.Pending, &d.ApprovalToken, &d.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListByUser fetches a user's devices, oldest first.
+func (r *DeviceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Device, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, name, platform, last_synced_at, last_cursor, revoked, fingerprint, pending, approval_token, created_at
+		 FROM devices WHERE user_id = ? ORDER BY created_at`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices []models.Device
+	for rows.Next() {
+		var d models.Device
+		if err := rows.Scan(&d.ID, &d.UserID, &d.Name, &d.Platform, &d.LastSyncedAt, &d.LastCursor, &d.Revoked, &d.Fingerprint, &d.Pending, &d.ApprovalToken, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
+// UpdateSyncState records the sync point a device reached.
+func (r *DeviceRepository) UpdateSyncState(ctx context.Context, userID, id uuid.UUID, syncedAt time.Time, cursor int64) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE devices SET last_synced_at = ?, last_cursor = ? WHERE user_id = ? AND id = ?`,
+		syncedAt, cursor, userID, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// GetByFingerprint fetches the user's device carrying the login
+// fingerprint.
+func (r *DeviceRepository) GetByFingerprint(ctx context.Context, userID uuid.UUID, fingerprint string) (*models.Device, error) {
+	var d models.Device
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, user_id, name, platform, last_synced_at, last_cursor, revoked, fingerprint, pending, approval_token, created_at
+		 FROM devices WHERE user_id = ? AND fingerprint = ?`,
+		userID, fingerprint).
+		Scan(&d.ID, &d.UserID, &d.Name, &d.Platform, &d.LastSyncedAt, &d.LastCursor, &d.Revoked, &d.Fingerprint, &d.Pending, &d.ApprovalToken, &d.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Approve clears the pending flag and approval token of the user's
+// device.
+func (r *DeviceRepository) Approve(ctx context.Context, userID, id uuid.UUID) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE devices SET pending = FALSE, approval_token = '' WHERE user_id = ? AND id = ?`, userID, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// ApproveByToken approves the pending device holding the approval token
+// and returns it.
+func (r *DeviceRepository) ApproveByToken(ctx context.Context, token string) (*models.Device, error) {
+	var d models.Device
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, user_id, name, platform, last_synced_at, last_cursor, revoked, fingerprint, pending, approval_token, created_at
+		 FROM devices WHERE approval_token = ? AND pending`,
+		token).
+		Scan(&d.ID, &d.UserID, &d.Name, &d.Platform, &d.LastSyncedAt, &d.LastCursor, &d.Revoked, &d.Fingerprint, &d.Pending, &d.ApprovalToken, &d.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE devices SET pending = FALSE, approval_token = '' WHERE id = ?`, d.ID); err != nil {
+		return nil, err
+	}
+	d.Pending = false
+	d.ApprovalToken = ""
+	return &d, nil
+}
+
+// SetRevoked marks a device as revoked.
+func (r *DeviceRepository) SetRevoked(ctx context.Context, userID, id uuid.UUID, revoked bool) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE devices SET revoked = ? WHERE user_id = ? AND id = ?`, revoked, userID, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
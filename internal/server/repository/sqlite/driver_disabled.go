@@ -0,0 +1,8 @@
+//go:build !sqlite
+
+package sqlite
+
+// driverName is empty without the sqlite build tag: the package still
+// compiles, but Open refuses to start so the default build carries no
+// SQLite driver dependency.
+const driverName = ""
@@ -0,0 +1,9 @@
+//go:build sqlite
+
+package sqlite
+
+import _ "modernc.org/sqlite"
+
+// driverName is the database/sql driver registered by the pure-Go SQLite
+// driver the sqlite build tag pulls in.
+const driverName = "sqlite"
@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// EmergencyRepository is the SQLite implementation of
+// repository.EmergencyRepository.
+type EmergencyRepository struct {
+	db *sql.DB
+}
+
+// NewEmergencyRepository creates an EmergencyRepository.
+func NewEmergencyRepository(dbh *sql.DB) *EmergencyRepository {
+	return &EmergencyRepository{db: dbh}
+}
+
+// Upsert inserts a designation or replaces the wait period and request
+// state of an existing one.
+func (r *EmergencyRepository) Upsert(ctx context.Context, contact *models.EmergencyContact) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO emergency_contacts (owner_id, contact_id, wait_days, requested_at, created_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT (owner_id, contact_id)
+		 DO UPDATE SET wait_days = excluded.wait_days, requested_at = excluded.requested_at`,
+		contact.OwnerID, contact.ContactID, contact.WaitDays, contact.RequestedAt, contact.CreatedAt)
+	return err
+}
+
+// Get fetches one designation.
+func (r *EmergencyRepository) Get(ctx context.Context, ownerID, contactID uuid.UUID) (*models.EmergencyContact, error) {
+	var c models.EmergencyContact
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT owner_id, contact_id, wait_days, requested_at, created_at
+		 FROM emergency_contacts WHERE owner_id = ? AND contact_id = ?`,
+		ownerID, contactID).
+		Scan(&c.OwnerID, &c.ContactID, &c.WaitDays, &c.RequestedAt, &c.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListByOwner returns the contacts the owner has designated, oldest first.
+func (r *EmergencyRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.EmergencyContact, error) {
+	return r.list(ctx,
+		`SELECT owner_id, contact_id, wait_days, requested_at, created_at
+		 FROM emergency_contacts WHERE owner_id = ? ORDER BY created_at, contact_id`,
+		ownerID)
+}
+
+// ListByContact returns the designations naming the user as contact,
+// oldest first.
+func (r *EmergencyRepository) ListByContact(ctx context.Context, contactID uuid.UUID) ([]models.EmergencyContact, error) {
+	return r.list(ctx,
+		`SELECT owner_id, contact_id, wait_days, requested_at, created_at
+		 FROM emergency_contacts WHERE contact_id = ? ORDER BY created_at, owner_id`,
+		contactID)
+}
+
+func (r *EmergencyRepository) list(ctx context.Context, query string, args ...any) ([]models.EmergencyContact, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []models.EmergencyContact
+	for rows.Next() {
+		var c models.EmergencyContact
+		if err := rows.Scan(&c.OwnerID, &c.ContactID, &c.WaitDays, &c.RequestedAt, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
+// Delete removes a designation.
+func (r *EmergencyRepository) Delete(ctx context.Context, ownerID, contactID uuid.UUID) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM emergency_contacts WHERE owner_id = ? AND contact_id = ?`,
+		ownerID, contactID)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
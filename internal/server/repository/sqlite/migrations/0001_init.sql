@@ -0,0 +1,147 @@
+-- The full schema as of Postgres migration 0020. SQLite deployments are
+-- new, so one file carries everything; later changes land as further
+-- numbered files.
+
+CREATE TABLE IF NOT EXISTS users (
+    id                 TEXT PRIMARY KEY,
+    login              TEXT NOT NULL UNIQUE,
+    password_hash      TEXT NOT NULL,
+    role               TEXT NOT NULL DEFAULT 'user',
+    archive_after_days INTEGER NOT NULL DEFAULT 0,
+    disabled           BOOLEAN NOT NULL DEFAULT FALSE,
+    hint               BLOB,
+    master_verifier    BLOB,
+    created_at         TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id         TEXT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS org_members (
+    org_id     TEXT NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role       TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (org_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS secrets (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    org_id      TEXT REFERENCES organizations (id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    type        TEXT NOT NULL,
+    metadata    TEXT NOT NULL DEFAULT '',
+    protected   BOOLEAN NOT NULL DEFAULT FALSE,
+    data        BLOB NOT NULL,
+    blob_ref    TEXT NOT NULL DEFAULT '',
+    archived    BOOLEAN NOT NULL DEFAULT FALSE,
+    name_index  BLOB,
+    accessed_at TIMESTAMP NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_secrets_user_id ON secrets (user_id);
+CREATE INDEX IF NOT EXISTS secrets_user_updated_idx ON secrets (user_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS secrets_user_created_idx ON secrets (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS secrets_user_name_idx ON secrets (user_id, name, id);
+CREATE INDEX IF NOT EXISTS secrets_user_type_idx ON secrets (user_id, type, id);
+CREATE INDEX IF NOT EXISTS idx_secrets_org_id ON secrets (org_id) WHERE org_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_secrets_name_index ON secrets (user_id, name_index);
+
+CREATE TABLE IF NOT EXISTS attachments (
+    id         TEXT PRIMARY KEY,
+    secret_id  TEXT NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    filename   TEXT NOT NULL,
+    size       INTEGER NOT NULL,
+    content    BLOB NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_secret_id ON attachments (secret_id);
+
+CREATE TABLE IF NOT EXISTS secret_versions (
+    id         TEXT PRIMARY KEY,
+    secret_id  TEXT NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL REFERENCES users (id),
+    version    INTEGER NOT NULL,
+    data       BLOB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (secret_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS secret_versions_secret_idx ON secret_versions (secret_id, version);
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    TEXT NOT NULL,
+    action     TEXT NOT NULL,
+    resource   TEXT NOT NULL DEFAULT '',
+    ip         TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_user_id ON audit_events (user_id, id);
+
+CREATE TABLE IF NOT EXISTS outbox (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id      TEXT NOT NULL REFERENCES users (id),
+    action       TEXT NOT NULL,
+    resource     TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMP NOT NULL,
+    published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (id) WHERE published_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS share_links (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL REFERENCES users (id),
+    secret_id  TEXT NOT NULL,
+    token_hash BLOB NOT NULL UNIQUE,
+    name       BLOB NOT NULL,
+    type       TEXT NOT NULL,
+    payload    BLOB NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS devices (
+    id             TEXT PRIMARY KEY,
+    user_id        TEXT NOT NULL REFERENCES users (id),
+    name           TEXT NOT NULL,
+    platform       TEXT NOT NULL DEFAULT '',
+    last_synced_at TIMESTAMP NOT NULL,
+    last_cursor    INTEGER NOT NULL DEFAULT 0,
+    revoked        BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at     TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_devices_user_id ON devices (user_id);
+
+CREATE TABLE IF NOT EXISTS secret_shares (
+    secret_id    TEXT NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+    owner_id     TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    recipient_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    mode         TEXT NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    PRIMARY KEY (secret_id, recipient_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_secret_shares_recipient ON secret_shares (recipient_id);
+
+CREATE TABLE IF NOT EXISTS emergency_contacts (
+    owner_id     TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    contact_id   TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    wait_days    INTEGER NOT NULL,
+    requested_at TIMESTAMP,
+    created_at   TIMESTAMP NOT NULL,
+    PRIMARY KEY (owner_id, contact_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_emergency_contacts_contact ON emergency_contacts (contact_id);
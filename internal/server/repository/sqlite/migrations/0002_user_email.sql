@@ -0,0 +1,6 @@
+-- An optional contact email per account, unique case-insensitively for
+-- accounts that set one. Mirrors the Postgres 0021_user_email migration.
+ALTER TABLE users
+    ADD COLUMN email TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX users_email_key ON users (lower(email)) WHERE email <> '';
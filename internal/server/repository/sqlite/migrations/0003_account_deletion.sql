@@ -0,0 +1,4 @@
+-- A scheduled account deletion, NULL when none is pending. Mirrors the
+-- Postgres 0022_account_deletion migration.
+ALTER TABLE users
+    ADD COLUMN delete_after TIMESTAMP;
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN notify_disabled TEXT NOT NULL DEFAULT '';
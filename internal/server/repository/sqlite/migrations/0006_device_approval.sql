@@ -0,0 +1,10 @@
+ALTER TABLE devices
+    ADD COLUMN fingerprint TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE devices
+    ADD COLUMN pending BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE devices
+    ADD COLUMN approval_token TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_devices_fingerprint ON devices (user_id, fingerprint);
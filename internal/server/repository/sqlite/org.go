@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// OrgRepository is the SQLite implementation of repository.OrgRepository.
+type OrgRepository struct {
+	db *sql.DB
+}
+
+// NewOrgRepository creates an OrgRepository.
+func NewOrgRepository(dbh *sql.DB) *OrgRepository {
+	return &OrgRepository{db: dbh}
+}
+
+// Create inserts an organization.
+func (r *OrgRepository) Create(ctx context.Context, org *models.Organization) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO organizations (id, name, created_at) VALUES (?, ?, ?)`,
+		org.ID, org.Name, org.CreatedAt)
+	return err
+}
+
+// GetByID fetches one organization.
+func (r *OrgRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	var org models.Organization
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, name, created_at FROM organizations WHERE id = ?`, id).
+		Scan(&org.ID, &org.Name, &org.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// ListByUser returns the organizations the user is a member of, oldest
+// first.
+func (r *OrgRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Organization, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT o.id, o.name, o.created_at
+		 FROM organizations o JOIN org_members m ON m.org_id = o.id
+		 WHERE m.user_id = ? ORDER BY o.created_at, o.id`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgs []models.Organization
+	for rows.Next() {
+		var org models.Organization
+		if err := rows.Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, rows.Err()
+}
+
+// AddMember inserts a membership, reporting repository.ErrAlreadyExists
+// for a user who is already a member.
+func (r *OrgRepository) AddMember(ctx context.Context, member *models.OrgMember) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO org_members (org_id, user_id, role, created_at) VALUES (?, ?, ?, ?)`,
+		member.OrgID, member.UserID, member.Role, member.CreatedAt)
+	if isUniqueViolation(err) {
+		return repository.ErrAlreadyExists
+	}
+	return err
+}
+
+// GetMember fetches one membership.
+func (r *OrgRepository) GetMember(ctx context.Context, orgID, userID uuid.UUID) (*models.OrgMember, error) {
+	var m models.OrgMember
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT org_id, user_id, role, created_at FROM org_members WHERE org_id = ? AND user_id = ?`,
+		orgID, userID).
+		Scan(&m.OrgID, &m.UserID, &m.Role, &m.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListMembers returns the members of an organization, oldest first.
+func (r *OrgRepository) ListMembers(ctx context.Context, orgID uuid.UUID) ([]models.OrgMember, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT org_id, user_id, role, created_at FROM org_members
+		 WHERE org_id = ? ORDER BY created_at, user_id`,
+		orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.OrgMember
+	for rows.Next() {
+		var m models.OrgMember
+		if err := rows.Scan(&m.OrgID, &m.UserID, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
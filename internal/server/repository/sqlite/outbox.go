@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// OutboxRepository is the SQLite implementation of
+// repository.OutboxRepository.
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates an OutboxRepository.
+func NewOutboxRepository(dbh *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: dbh}
+}
+
+// Append inserts an event and fills in its assigned ID.
+func (r *OutboxRepository) Append(ctx context.Context, event *models.OutboxEvent) error {
+	return db(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO outbox (user_id, action, resource, created_at)
+		 VALUES (?, ?, ?, ?) RETURNING id`,
+		event.UserID, event.Action, event.Resource, event.CreatedAt).
+		Scan(&event.ID)
+}
+
+// ListUnpublished returns up to limit undispatched events in ID order.
+func (r *OutboxRepository) ListUnpublished(ctx context.Context, limit int) ([]models.OutboxEvent, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, action, resource, created_at FROM outbox
+		 WHERE published_at IS NULL ORDER BY id LIMIT ?`,
+		limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var e models.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Action, &e.Resource, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// MarkPublished records that the events with the given IDs were delivered.
+func (r *OutboxRepository) MarkPublished(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := []any{time.Now().UTC()}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE outbox SET published_at = ? WHERE id IN (`+placeholders+`)`, args...)
+	return err
+}
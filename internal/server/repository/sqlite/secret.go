@@ -0,0 +1,308 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// SecretRepository is the SQLite implementation of
+// repository.SecretRepository.
+type SecretRepository struct {
+	db *sql.DB
+}
+
+// NewSecretRepository creates a SecretRepository.
+func NewSecretRepository(dbh *sql.DB) *SecretRepository {
+	return &SecretRepository{db: dbh}
+}
+
+// Create inserts a secret.
+func (r *SecretRepository) Create(ctx context.Context, secret *models.Secret) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO secrets (id, user_id, name, type, metadata, protected, data, blob_ref, archived, accessed_at, created_at, updated_at, name_index)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		secret.ID, secret.UserID, secret.Name, secret.Type, secret.Metadata,
+		secret.Protected, secret.Data, secret.BlobRef, secret.Archived, secret.AccessedAt,
+		secret.CreatedAt, secret.UpdatedAt, secret.NameIndex)
+	return err
+}
+
+// GetByID fetches one secret owned by userID.
+func (r *SecretRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*models.Secret, error) {
+	var s models.Secret
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, user_id, name, type, metadata, protected, data, blob_ref, archived, accessed_at, created_at, updated_at
+		 FROM secrets WHERE user_id = ? AND id = ? AND org_id IS NULL`,
+		userID, id).
+		Scan(&s.ID, &s.UserID, &s.Name, &s.Type, &s.Metadata, &s.Protected, &s.Data, &s.BlobRef,
+			&s.Archived, &s.AccessedAt, &s.CreatedAt, &s.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetByUser fetches the secrets owned by userID according to opts. Sorting
+// uses only plaintext columns and pages with a (sort column, id) keyset so
+// large vaults never need an offset scan.
+func (r *SecretRepository) GetByUser(ctx context.Context, userID uuid.UUID, opts repository.SecretListOptions) ([]models.Secret, error) {
+	field, descending := opts.Sort()
+	if !field.Valid() {
+		return nil, fmt.Errorf("invalid sort field %q", field)
+	}
+	column := string(field)
+	direction, comparison := "ASC", ">"
+	if descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	query := `SELECT id, user_id, name, type, metadata, protected, data, blob_ref, archived, accessed_at, created_at, updated_at
+	 FROM secrets WHERE user_id = ? AND org_id IS NULL AND (archived = FALSE OR ?)`
+	args := []any{userID, opts.IncludeArchived}
+	if opts.After != uuid.Nil {
+		query += fmt.Sprintf(` AND (%s, id) %s (SELECT %s, id FROM secrets WHERE id = ?)`, column, comparison, column)
+		args = append(args, opts.After)
+	}
+	if opts.Name != "" {
+		filter := `name = ?`
+		args = append(args, opts.Name)
+		if len(opts.NameIndex) > 0 {
+			filter = `(` + filter + ` OR name_index = ?)`
+			args = append(args, opts.NameIndex)
+		}
+		query += ` AND ` + filter
+	}
+	query += fmt.Sprintf(` ORDER BY %s %s, id %s`, column, direction, direction)
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(` LIMIT %d`, opts.Limit)
+	}
+
+	rows, err := db(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var secrets []models.Secret
+	for rows.Next() {
+		var s models.Secret
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Name, &s.Type, &s.Metadata, &s.Protected,
+			&s.Data, &s.BlobRef, &s.Archived, &s.AccessedAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, s)
+	}
+	return secrets, rows.Err()
+}
+
+// Update rewrites a secret owned by its user.
+func (r *SecretRepository) Update(ctx context.Context, secret *models.Secret) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE secrets SET name = ?, type = ?, metadata = ?, protected = ?, data = ?, blob_ref = ?, archived = ?, updated_at = ?, name_index = ?
+		 WHERE user_id = ? AND id = ? AND org_id IS NULL`,
+		secret.Name, secret.Type, secret.Metadata, secret.Protected, secret.Data, secret.BlobRef,
+		secret.Archived, secret.UpdatedAt, secret.NameIndex, secret.UserID, secret.ID)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// Reassign moves one secret to another owner with re-encrypted payload.
+func (r *SecretRepository) Reassign(ctx context.Context, id, fromUserID, toUserID uuid.UUID, data []byte, blobRef string) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE secrets SET user_id = ?, data = ?, blob_ref = ? WHERE user_id = ? AND id = ? AND org_id IS NULL`,
+		toUserID, data, blobRef, fromUserID, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// UsageByUser reports the user's secret count and total payload bytes.
+func (r *SecretRepository) UsageByUser(ctx context.Context, userID uuid.UUID) (int, int64, error) {
+	var count int
+	var size int64
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(LENGTH(data)), 0) FROM secrets WHERE user_id = ? AND org_id IS NULL`,
+		userID).Scan(&count, &size)
+	return count, size, err
+}
+
+// Delete removes a secret owned by userID.
+func (r *SecretRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `DELETE FROM secrets WHERE user_id = ? AND id = ? AND org_id IS NULL`, userID, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// GetModifiedAfter lists the user's secrets updated strictly after the
+// given time, oldest first.
+func (r *SecretRepository) GetModifiedAfter(ctx context.Context, userID uuid.UUID, after time.Time) ([]models.Secret, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, name, type, metadata, protected, data, blob_ref, archived, accessed_at, created_at, updated_at
+		 FROM secrets WHERE user_id = ? AND org_id IS NULL AND updated_at > ? ORDER BY updated_at, id`,
+		userID, after)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var secrets []models.Secret
+	for rows.Next() {
+		var s models.Secret
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Name, &s.Type, &s.Metadata, &s.Protected,
+			&s.Data, &s.BlobRef, &s.Archived, &s.AccessedAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, s)
+	}
+	return secrets, rows.Err()
+}
+
+// MarkAccessed records that a secret was read. Reading an archived secret
+// also brings it back out of the archive.
+func (r *SecretRepository) MarkAccessed(ctx context.Context, userID, id uuid.UUID, at time.Time) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE secrets SET accessed_at = ?, archived = FALSE WHERE user_id = ? AND id = ? AND org_id IS NULL`,
+		at, userID, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// ArchiveUnaccessed archives the user's secrets last accessed before the
+// cutoff and returns how many it touched. Protected secrets are left alone.
+func (r *SecretRepository) ArchiveUnaccessed(ctx context.Context, userID uuid.UUID, before time.Time) (int64, error) {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE secrets SET archived = TRUE
+		 WHERE user_id = ? AND org_id IS NULL AND archived = FALSE AND protected = FALSE AND accessed_at < ?`,
+		userID, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetByOrg lists the secrets of an organization vault, newest first.
+func (r *SecretRepository) GetByOrg(ctx context.Context, orgID uuid.UUID) ([]models.Secret, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, org_id, name, type, metadata, protected, data, blob_ref, archived, accessed_at, created_at, updated_at
+		 FROM secrets WHERE org_id = ? ORDER BY updated_at DESC, id DESC`,
+		orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var secrets []models.Secret
+	for rows.Next() {
+		var s models.Secret
+		if err := rows.Scan(&s.ID, &s.UserID, &s.OrgID, &s.Name, &s.Type, &s.Metadata, &s.Protected,
+			&s.Data, &s.BlobRef, &s.Archived, &s.AccessedAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, s)
+	}
+	return secrets, rows.Err()
+}
+
+// GetByOrgAndID fetches one secret from an organization vault.
+func (r *SecretRepository) GetByOrgAndID(ctx context.Context, orgID, id uuid.UUID) (*models.Secret, error) {
+	var s models.Secret
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, user_id, org_id, name, type, metadata, protected, data, blob_ref, archived, accessed_at, created_at, updated_at
+		 FROM secrets WHERE org_id = ? AND id = ?`,
+		orgID, id).
+		Scan(&s.ID, &s.UserID, &s.OrgID, &s.Name, &s.Type, &s.Metadata, &s.Protected, &s.Data, &s.BlobRef,
+			&s.Archived, &s.AccessedAt, &s.CreatedAt, &s.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// AttachToOrg moves a personal secret into an organization vault with a
+// payload re-encrypted under the org key.
+func (r *SecretRepository) AttachToOrg(ctx context.Context, id, userID, orgID uuid.UUID, data []byte, blobRef string) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE secrets SET org_id = ?, data = ?, blob_ref = ? WHERE id = ? AND user_id = ? AND org_id IS NULL`,
+		orgID, data, blobRef, id, userID)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// DetachFromOrg moves an org secret into the personal vault of toUserID
+// with a payload re-encrypted under that user's key.
+func (r *SecretRepository) DetachFromOrg(ctx context.Context, id, orgID, toUserID uuid.UUID, data []byte, blobRef string) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE secrets SET org_id = NULL, user_id = ?, data = ?, blob_ref = ? WHERE id = ? AND org_id = ?`,
+		toUserID, data, blobRef, id, orgID)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// ListUnaccessed returns the IDs ArchiveUnaccessed would archive.
+func (r *SecretRepository) ListUnaccessed(ctx context.Context, userID uuid.UUID, before time.Time) ([]uuid.UUID, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id FROM secrets
+		 WHERE user_id = ? AND org_id IS NULL AND archived = FALSE AND protected = FALSE AND accessed_at < ?`,
+		userID, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ReplaceData rewrites the stored ciphertext of any secret without
+// touching its other fields, for key rotation.
+func (r *SecretRepository) ReplaceData(ctx context.Context, id uuid.UUID, data []byte, blobRef string) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE secrets SET data = ?, blob_ref = ? WHERE id = ?`, data, blobRef, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// ReplaceMeta rewrites the stored name, metadata and name index of any
+// secret without touching its payload, for metadata encryption.
+func (r *SecretRepository) ReplaceMeta(ctx context.Context, id uuid.UUID, name, metadata string, nameIndex []byte) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE secrets SET name = ?, metadata = ?, name_index = ? WHERE id = ?`, name, metadata, nameIndex, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// SecretShareRepository is the SQLite implementation of
+// repository.SecretShareRepository.
+type SecretShareRepository struct {
+	db *sql.DB
+}
+
+// NewSecretShareRepository creates a SecretShareRepository.
+func NewSecretShareRepository(dbh *sql.DB) *SecretShareRepository {
+	return &SecretShareRepository{db: dbh}
+}
+
+// Upsert inserts a grant or updates the mode of an existing one.
+func (r *SecretShareRepository) Upsert(ctx context.Context, share *models.SecretShare) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO secret_shares (secret_id, owner_id, recipient_id, mode, created_at)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT (secret_id, recipient_id) DO UPDATE SET mode = excluded.mode`,
+		share.SecretID, share.OwnerID, share.RecipientID, share.Mode, share.CreatedAt)
+	return err
+}
+
+// Get fetches one grant.
+func (r *SecretShareRepository) Get(ctx context.Context, secretID, recipientID uuid.UUID) (*models.SecretShare, error) {
+	var s models.SecretShare
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT secret_id, owner_id, recipient_id, mode, created_at
+		 FROM secret_shares WHERE secret_id = ? AND recipient_id = ?`,
+		secretID, recipientID).
+		Scan(&s.SecretID, &s.OwnerID, &s.RecipientID, &s.Mode, &s.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListBySecret returns the grants of one secret, oldest first.
+func (r *SecretShareRepository) ListBySecret(ctx context.Context, ownerID, secretID uuid.UUID) ([]models.SecretShare, error) {
+	return r.list(ctx,
+		`SELECT secret_id, owner_id, recipient_id, mode, created_at
+		 FROM secret_shares WHERE owner_id = ? AND secret_id = ? ORDER BY created_at, recipient_id`,
+		ownerID, secretID)
+}
+
+// ListByOwner returns every grant the owner has given, oldest first.
+func (r *SecretShareRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.SecretShare, error) {
+	return r.list(ctx,
+		`SELECT secret_id, owner_id, recipient_id, mode, created_at
+		 FROM secret_shares WHERE owner_id = ? ORDER BY created_at, secret_id`,
+		ownerID)
+}
+
+// ListByRecipient returns the grants the user has received, oldest first.
+func (r *SecretShareRepository) ListByRecipient(ctx context.Context, recipientID uuid.UUID) ([]models.SecretShare, error) {
+	return r.list(ctx,
+		`SELECT secret_id, owner_id, recipient_id, mode, created_at
+		 FROM secret_shares WHERE recipient_id = ? ORDER BY created_at, secret_id`,
+		recipientID)
+}
+
+func (r *SecretShareRepository) list(ctx context.Context, query string, args ...any) ([]models.SecretShare, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []models.SecretShare
+	for rows.Next() {
+		var s models.SecretShare
+		if err := rows.Scan(&s.SecretID, &s.OwnerID, &s.RecipientID, &s.Mode, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, s)
+	}
+	return shares, rows.Err()
+}
+
+// Delete removes a grant.
+func (r *SecretShareRepository) Delete(ctx context.Context, secretID, recipientID uuid.UUID) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM secret_shares WHERE secret_id = ? AND recipient_id = ?`,
+		secretID, recipientID)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
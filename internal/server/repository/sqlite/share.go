@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// ShareRepository is the SQLite implementation of
+// repository.ShareRepository.
+type ShareRepository struct {
+	db *sql.DB
+}
+
+// NewShareRepository creates a ShareRepository.
+func NewShareRepository(dbh *sql.DB) *ShareRepository {
+	return &ShareRepository{db: dbh}
+}
+
+// Create inserts a share link.
+func (r *ShareRepository) Create(ctx context.Context, link *models.ShareLink) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO share_links (id, user_id, secret_id, token_hash, name, type, payload, expires_at, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		link.ID, link.UserID, link.SecretID, link.TokenHash, link.Name, link.Type,
+		link.Payload, link.ExpiresAt, link.CreatedAt)
+	return err
+}
+
+// GetByTokenHash fetches a link by the hash of its URL token.
+func (r *ShareRepository) GetByTokenHash(ctx context.Context, hash []byte) (*models.ShareLink, error) {
+	var link models.ShareLink
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, user_id, secret_id, token_hash, name, type, payload, expires_at, created_at
+		 FROM share_links WHERE token_hash = ?`,
+		hash).
+		Scan(&link.ID, &link.UserID, &link.SecretID, &link.TokenHash, &link.Name,
+			&link.Type, &link.Payload, &link.ExpiresAt, &link.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// Delete removes a link.
+func (r *ShareRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `DELETE FROM share_links WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
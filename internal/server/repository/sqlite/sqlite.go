@@ -0,0 +1,94 @@
+// Package sqlite implements the repository interfaces on a single-file
+// SQLite database, so small self-hosted deployments do not need a
+// database server. The repositories are written against database/sql;
+// the driver itself is compiled in with the "sqlite" build tag.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Open opens the database file at path, creating it on first start, and
+// brings its schema up to date from the embedded migrations.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	if driverName == "" {
+		return nil, errors.New("sqlite: server built without SQLite support, rebuild with -tags sqlite")
+	}
+	dbh, err := sql.Open(driverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: open: %w", err)
+	}
+	// SQLite permits one writer at a time; a single connection serializes
+	// access instead of surfacing SQLITE_BUSY to callers.
+	dbh.SetMaxOpenConns(1)
+	for _, pragma := range []string{`PRAGMA journal_mode = WAL`, `PRAGMA foreign_keys = ON`} {
+		if _, err := dbh.ExecContext(ctx, pragma); err != nil {
+			dbh.Close()
+			return nil, fmt.Errorf("sqlite: %s: %w", pragma, err)
+		}
+	}
+	if err := migrate(ctx, dbh); err != nil {
+		dbh.Close()
+		return nil, err
+	}
+	return dbh, nil
+}
+
+// migrate applies the embedded migration files that have not run yet, in
+// filename order, each inside its own transaction.
+func migrate(ctx context.Context, dbh *sql.DB) error {
+	if _, err := dbh.ExecContext(ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (filename TEXT PRIMARY KEY, applied_at TIMESTAMP NOT NULL)`); err != nil {
+		return fmt.Errorf("sqlite: create schema_migrations: %w", err)
+	}
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		var applied int
+		err := dbh.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM schema_migrations WHERE filename = ?`, entry.Name()).Scan(&applied)
+		if err != nil {
+			return err
+		}
+		if applied > 0 {
+			continue
+		}
+		script, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return err
+		}
+		tx, err := dbh.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+			tx.Rollback() //nolint:errcheck
+			return fmt.Errorf("sqlite: apply %s: %w", entry.Name(), err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_migrations (filename, applied_at) VALUES (?, ?)`,
+			entry.Name(), time.Now().UTC()); err != nil {
+			tx.Rollback() //nolint:errcheck
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("sqlite: commit %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
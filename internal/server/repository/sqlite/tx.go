@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// querier is the subset of database/sql the repositories use. Both the
+// database handle and an open transaction satisfy it, so repository
+// methods can run against either.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// txKey carries the active transaction through context.
+type txKey struct{}
+
+// db returns the transaction from ctx when one is active, the database
+// handle otherwise. Every repository method goes through it, so any call
+// made inside WithinTransaction automatically joins the transaction.
+func db(ctx context.Context, dbh *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return dbh
+}
+
+// affectedOrNotFound translates an update or delete that touched no rows
+// into repository.ErrNotFound.
+func affectedOrNotFound(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// TransactionManager implements repository.TransactionManager on a SQLite
+// database handle. The transaction travels in the context, so the
+// function keeps calling the same repository instances it uses outside
+// one.
+type TransactionManager struct {
+	db *sql.DB
+}
+
+// NewTransactionManager creates a TransactionManager on the given handle.
+func NewTransactionManager(dbh *sql.DB) *TransactionManager {
+	return &TransactionManager{db: dbh}
+}
+
+// WithinTransaction runs fn inside a transaction, committing on success
+// and rolling back on error. A nested call joins the transaction already
+// in the context instead of opening a second one.
+func (m *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlite: begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sqlite: commit transaction: %w", err)
+	}
+	return nil
+}
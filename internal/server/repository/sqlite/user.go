@@ -0,0 +1,204 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// UserRepository is the SQLite implementation of repository.UserRepository.
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a UserRepository.
+func NewUserRepository(dbh *sql.DB) *UserRepository {
+	return &UserRepository{db: dbh}
+}
+
+// Create inserts a user, reporting repository.ErrAlreadyExists on a
+// duplicate login and repository.ErrEmailExists on a duplicate email.
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO users (id, login, email, password_hash, role, archive_after_days, disabled, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		user.ID, user.Login, user.Email, user.PasswordHash, user.Role, user.ArchiveAfterDays, user.Disabled, user.CreatedAt)
+	if isUniqueViolation(err) {
+		// SQLite names the violated index (or column) in the message.
+		if strings.Contains(err.Error(), "email") {
+			return repository.ErrEmailExists
+		}
+		return repository.ErrAlreadyExists
+	}
+	return err
+}
+
+// GetByLogin fetches a user by login.
+func (r *UserRepository) GetByLogin(ctx context.Context, login string) (*models.User, error) {
+	return r.get(ctx, `SELECT id, login, email, password_hash, role, archive_after_days, disabled, created_at FROM users WHERE login = ?`, login)
+}
+
+// GetByID fetches a user by ID.
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return r.get(ctx, `SELECT id, login, email, password_hash, role, archive_after_days, disabled, created_at FROM users WHERE id = ?`, id)
+}
+
+// SetArchiveAfterDays updates the user's auto-archival policy; 0 opts out.
+func (r *UserRepository) SetArchiveAfterDays(ctx context.Context, id uuid.UUID, days int) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `UPDATE users SET archive_after_days = ? WHERE id = ?`, days, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// ListWithArchivePolicy returns the users with auto-archival enabled.
+func (r *UserRepository) ListWithArchivePolicy(ctx context.Context) ([]models.User, error) {
+	return r.list(ctx, `SELECT id, login, email, password_hash, role, archive_after_days, disabled, created_at FROM users WHERE archive_after_days > 0`)
+}
+
+// SetHint replaces the user's encrypted vault hint; empty clears it.
+func (r *UserRepository) SetHint(ctx context.Context, id uuid.UUID, hint []byte) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `UPDATE users SET hint = ? WHERE id = ?`, hint, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// GetHint returns the user's encrypted vault hint, empty when unset.
+func (r *UserRepository) GetHint(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	var hint []byte
+	err := db(ctx, r.db).QueryRowContext(ctx, `SELECT hint FROM users WHERE id = ?`, id).Scan(&hint)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	return hint, err
+}
+
+// SetMasterVerifier replaces the user's master-password verifier; empty
+// clears it.
+func (r *UserRepository) SetMasterVerifier(ctx context.Context, id uuid.UUID, verifier []byte) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `UPDATE users SET master_verifier = ? WHERE id = ?`, verifier, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// GetMasterVerifier returns the user's master-password verifier, empty
+// when unset.
+func (r *UserRepository) GetMasterVerifier(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	var verifier []byte
+	err := db(ctx, r.db).QueryRowContext(ctx, `SELECT master_verifier FROM users WHERE id = ?`, id).Scan(&verifier)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	return verifier, err
+}
+
+// SetNotifyDisabled replaces the notification kinds the user opted out
+// of; empty re-enables everything.
+func (r *UserRepository) SetNotifyDisabled(ctx context.Context, id uuid.UUID, disabled []string) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `UPDATE users SET notify_disabled = ? WHERE id = ?`, strings.Join(disabled, ","), id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// GetNotifyDisabled returns the notification kinds the user opted out of.
+func (r *UserRepository) GetNotifyDisabled(ctx context.Context, id uuid.UUID) ([]string, error) {
+	var disabled string
+	err := db(ctx, r.db).QueryRowContext(ctx, `SELECT notify_disabled FROM users WHERE id = ?`, id).Scan(&disabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if disabled == "" {
+		return nil, err
+	}
+	return strings.Split(disabled, ","), err
+}
+
+// SetDisabled marks an account as deactivated.
+func (r *UserRepository) SetDisabled(ctx context.Context, id uuid.UUID, disabled bool) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `UPDATE users SET disabled = ? WHERE id = ?`, disabled, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// SetDeleteAfter schedules the account for deletion once at passes; nil
+// cancels a pending schedule.
+func (r *UserRepository) SetDeleteAfter(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `UPDATE users SET delete_after = ? WHERE id = ?`, at, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// ListDeletable returns the users whose scheduled deletion time has passed.
+func (r *UserRepository) ListDeletable(ctx context.Context, now time.Time) ([]models.User, error) {
+	return r.list(ctx, `SELECT id, login, email, password_hash, role, archive_after_days, disabled, created_at FROM users WHERE delete_after IS NOT NULL AND delete_after <= ?`, now)
+}
+
+// SetRole changes an account's role.
+func (r *UserRepository) SetRole(ctx context.Context, id uuid.UUID, role string) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `UPDATE users SET role = ? WHERE id = ?`, role, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// List returns every account, oldest first.
+func (r *UserRepository) List(ctx context.Context) ([]models.User, error) {
+	return r.list(ctx, `SELECT id, login, email, password_hash, role, archive_after_days, disabled, created_at FROM users ORDER BY created_at`)
+}
+
+// Delete removes an account; secrets and devices cascade at the database.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+func (r *UserRepository) get(ctx context.Context, query string, arg any) (*models.User, error) {
+	var u models.User
+	err := db(ctx, r.db).QueryRowContext(ctx, query, arg).Scan(&u.ID, &u.Login, &u.Email, &u.PasswordHash, &u.Role, &u.ArchiveAfterDays, &u.Disabled, &u.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) list(ctx context.Context, query string, args ...any) ([]models.User, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Login, &u.Email, &u.PasswordHash, &u.Role, &u.ArchiveAfterDays, &u.Disabled, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// SecretVersionRepository is the SQLite implementation of
+// repository.SecretVersionRepository.
+type SecretVersionRepository struct {
+	db *sql.DB
+}
+
+// NewSecretVersionRepository creates a SecretVersionRepository.
+func NewSecretVersionRepository(dbh *sql.DB) *SecretVersionRepository {
+	return &SecretVersionRepository{db: dbh}
+}
+
+// Create inserts a snapshot, assigning the next version number of the
+// secret and setting it on the model.
+func (r *SecretVersionRepository) Create(ctx context.Context, v *models.SecretVersion) error {
+	return db(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO secret_versions (id, secret_id, user_id, version, data, created_at)
+		 VALUES (?, ?, ?,
+		         (SELECT COALESCE(MAX(version), 0) + 1 FROM secret_versions WHERE secret_id = ?),
+		         ?, ?)
+		 RETURNING version`,
+		v.ID, v.SecretID, v.UserID, v.SecretID, v.Data, v.CreatedAt).Scan(&v.Version)
+}
+
+// ListBySecret fetches the snapshots of one secret, newest first.
+func (r *SecretVersionRepository) ListBySecret(ctx context.Context, userID, secretID uuid.UUID) ([]models.SecretVersion, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id, secret_id, user_id, version, data, created_at
+		 FROM secret_versions WHERE user_id = ? AND secret_id = ? ORDER BY version DESC`,
+		userID, secretID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.SecretVersion
+	for rows.Next() {
+		var v models.SecretVersion
+		if err := rows.Scan(&v.ID, &v.SecretID, &v.UserID, &v.Version, &v.Data, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// GetByVersion fetches one snapshot by its version number.
+func (r *SecretVersionRepository) GetByVersion(ctx context.Context, userID, secretID uuid.UUID, version int) (*models.SecretVersion, error) {
+	var v models.SecretVersion
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, secret_id, user_id, version, data, created_at
+		 FROM secret_versions WHERE user_id = ? AND secret_id = ? AND version = ?`,
+		userID, secretID, version).
+		Scan(&v.ID, &v.SecretID, &v.UserID, &v.Version, &v.Data, &v.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ReplaceData rewrites the stored ciphertext of one snapshot, for key
+// rotation.
+func (r *SecretVersionRepository) ReplaceData(ctx context.Context, id uuid.UUID, data []byte) error {
+	res, err := db(ctx, r.db).ExecContext(ctx,
+		`UPDATE secret_versions SET data = ? WHERE id = ?`, data, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// WebhookRepository is the SQLite implementation of
+// repository.WebhookRepository.
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a WebhookRepository.
+func NewWebhookRepository(dbh *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: dbh}
+}
+
+// Create inserts a webhook.
+func (r *WebhookRepository) Create(ctx context.Context, hook *models.Webhook) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO webhooks (id, user_id, url, secret, events, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		hook.ID, hook.UserID, hook.URL, hook.Secret, strings.Join(hook.Events, ","), hook.CreatedAt)
+	return err
+}
+
+// Get fetches one webhook.
+func (r *WebhookRepository) Get(ctx context.Context, userID, id uuid.UUID) (*models.Webhook, error) {
+	var hook models.Webhook
+	var events string
+	err := db(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, user_id, url, secret, events, created_at FROM webhooks WHERE user_id = ? AND id = ?`,
+		userID, id).
+		Scan(&hook.ID, &hook.UserID, &hook.URL, &hook.Secret, &events, &hook.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if events != "" {
+		hook.Events = strings.Split(events, ",")
+	}
+	return &hook, nil
+}
+
+// ListByUser fetches a user's webhooks, oldest first.
+func (r *WebhookRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Webhook, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id, user_id, url, secret, events, created_at FROM webhooks WHERE user_id = ? ORDER BY created_at`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hooks []models.Webhook
+	for rows.Next() {
+		var hook models.Webhook
+		var events string
+		if err := rows.Scan(&hook.ID, &hook.UserID, &hook.URL, &hook.Secret, &events, &hook.CreatedAt); err != nil {
+			return nil, err
+		}
+		if events != "" {
+			hook.Events = strings.Split(events, ",")
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+// Delete removes a webhook; its delivery log cascades at the database.
+func (r *WebhookRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	res, err := db(ctx, r.db).ExecContext(ctx, `DELETE FROM webhooks WHERE user_id = ? AND id = ?`, userID, id)
+	if err != nil {
+		return err
+	}
+	return affectedOrNotFound(res)
+}
+
+// RecordDelivery appends one delivery attempt to the hook's log.
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	_, err := db(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, event_id, action, attempt, status_code, error, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		delivery.WebhookID, delivery.EventID, delivery.Action, delivery.Attempt, delivery.StatusCode, delivery.Error, delivery.CreatedAt)
+	return err
+}
+
+// ListDeliveries returns the hook's latest delivery attempts, newest first.
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := db(ctx, r.db).QueryContext(ctx,
+		`SELECT id, webhook_id, event_id, action, attempt, status_code, error, created_at
+		 FROM webhook_deliveries WHERE webhook_id = ? ORDER BY id DESC LIMIT ?`,
+		webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.Action, &d.Attempt, &d.StatusCode, &d.Error, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// AdminService manages accounts on behalf of administrators. Every
+// operation is audited under the acting admin's ID with the target
+// account as the resource.
+type AdminService struct {
+	users repository.UserRepository
+	audit AuditRecorder
+}
+
+// NewAdminService creates an AdminService. audit may be nil.
+func NewAdminService(users repository.UserRepository, audit AuditRecorder) *AdminService {
+	return &AdminService{users: users, audit: audit}
+}
+
+// Role returns the role of an account, for the authorization middleware.
+func (s *AdminService) Role(ctx context.Context, userID uuid.UUID) (string, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return user.Role, nil
+}
+
+// ListUsers returns every account, oldest first.
+func (s *AdminService) ListUsers(ctx context.Context) ([]models.User, error) {
+	return s.users.List(ctx)
+}
+
+// SetUserDisabled disables or re-enables an account. Admins cannot
+// disable themselves: that could lock the last admin out.
+func (s *AdminService) SetUserDisabled(ctx context.Context, adminID, userID uuid.UUID, disabled bool) error {
+	if adminID == userID {
+		return fmt.Errorf("%w: cannot disable your own account", ErrValidation)
+	}
+	if err := s.users.SetDisabled(ctx, userID, disabled); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	action := models.AuditActionUserDisabled
+	if !disabled {
+		action = models.AuditActionUserEnabled
+	}
+	s.record(ctx, adminID, action, userID.String())
+	return nil
+}
+
+// DeleteUser removes an account and everything it owns. Self-deletion is
+// rejected for the same reason as self-disabling.
+func (s *AdminService) DeleteUser(ctx context.Context, adminID, userID uuid.UUID) error {
+	if adminID == userID {
+		return fmt.Errorf("%w: cannot delete your own account", ErrValidation)
+	}
+	if err := s.users.Delete(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	s.record(ctx, adminID, models.AuditActionUserDeleted, userID.String())
+	return nil
+}
+
+// PromoteAdmins grants the admin role to the named logins. Unknown logins
+// are skipped: they may simply not have registered yet.
+func (s *AdminService) PromoteAdmins(ctx context.Context, logins []string) error {
+	for _, login := range logins {
+		user, err := s.users.GetByLogin(ctx, login)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if user.Role == models.RoleAdmin {
+			continue
+		}
+		if err := s.users.SetRole(ctx, user.ID, models.RoleAdmin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *AdminService) record(ctx context.Context, adminID uuid.UUID, action, resource string) {
+	if s.audit != nil {
+		s.audit.Record(ctx, adminID, action, resource)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestAdminAccountManagement(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("test jwt secret"), []byte("test master key"), nil)
+	svc := NewAdminService(users, nil)
+
+	for _, login := range []string{"root", "alice"} {
+		if _, err := auth.Register(ctx, login, login+" password", ""); err != nil {
+			t.Fatalf("Register(%s): %v", login, err)
+		}
+	}
+	if err := svc.PromoteAdmins(ctx, []string{"root", "not-registered"}); err != nil {
+		t.Fatalf("PromoteAdmins: %v", err)
+	}
+	root, _ := users.GetByLogin(ctx, "root")
+	alice, _ := users.GetByLogin(ctx, "alice")
+
+	if role, err := svc.Role(ctx, root.ID); err != nil || role != models.RoleAdmin {
+		t.Fatalf("Role(root) = %q, %v; want admin", role, err)
+	}
+	if role, err := svc.Role(ctx, alice.ID); err != nil || role != models.RoleUser {
+		t.Fatalf("Role(alice) = %q, %v; want user", role, err)
+	}
+
+	if err := svc.SetUserDisabled(ctx, root.ID, alice.ID, true); err != nil {
+		t.Fatalf("SetUserDisabled: %v", err)
+	}
+	if _, err := auth.Login(ctx, "alice", "alice password", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("disabled login error = %v, want ErrInvalidCredentials", err)
+	}
+	if err := svc.SetUserDisabled(ctx, root.ID, alice.ID, false); err != nil {
+		t.Fatalf("re-enable: %v", err)
+	}
+	if _, err := auth.Login(ctx, "alice", "alice password", ""); err != nil {
+		t.Fatalf("re-enabled login: %v", err)
+	}
+
+	if err := svc.SetUserDisabled(ctx, root.ID, root.ID, true); !errors.Is(err, ErrValidation) {
+		t.Fatalf("self-disable error = %v, want ErrValidation", err)
+	}
+	if err := svc.DeleteUser(ctx, root.ID, root.ID); !errors.Is(err, ErrValidation) {
+		t.Fatalf("self-delete error = %v, want ErrValidation", err)
+	}
+
+	if err := svc.DeleteUser(ctx, root.ID, alice.ID); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if err := svc.DeleteUser(ctx, root.ID, alice.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("second delete error = %v, want ErrNotFound", err)
+	}
+	list, err := svc.ListUsers(ctx)
+	if err != nil || len(list) != 1 || list[0].Login != "root" {
+		t.Fatalf("ListUsers = %v, %v; want just root", list, err)
+	}
+}
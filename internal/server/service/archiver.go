@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// ArchiveInterval is how often the archiver scans for unused secrets.
+const ArchiveInterval = 24 * time.Hour
+
+// Archiver periodically archives secrets a user has not accessed within
+// their archive_after_days policy, keeping active listings short. Archived
+// secrets stay fully readable; reading one restores it.
+type Archiver struct {
+	users   repository.UserRepository
+	secrets repository.SecretRepository
+	dryRun  bool
+}
+
+// archiveSampleIDs caps how many affected IDs a dry-run sweep logs per user.
+const archiveSampleIDs = 5
+
+// NewArchiver creates an Archiver. With dryRun set every sweep only logs
+// what it would archive — row counts and sample IDs — without touching
+// anything.
+func NewArchiver(users repository.UserRepository, secrets repository.SecretRepository, dryRun bool) *Archiver {
+	return &Archiver{users: users, secrets: secrets, dryRun: dryRun}
+}
+
+// Run executes a sweep every ArchiveInterval until ctx is cancelled. An
+// initial sweep runs immediately.
+func (a *Archiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(ArchiveInterval)
+	defer ticker.Stop()
+	for {
+		if err := a.Sweep(ctx); err != nil {
+			slog.WarnContext(ctx, "archive sweep", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep archives expired secrets for every user with a policy. A failure
+// for one user does not stop the sweep; the first error is returned after
+// all users were tried.
+func (a *Archiver) Sweep(ctx context.Context) error {
+	users, err := a.users.ListWithArchivePolicy(ctx)
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	now := time.Now().UTC()
+	for _, u := range users {
+		cutoff := now.AddDate(0, 0, -u.ArchiveAfterDays)
+		if a.dryRun {
+			if err := a.dryRunUser(ctx, u.ID, cutoff); err != nil && firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		n, err := a.secrets.ArchiveUnaccessed(ctx, u.ID, cutoff)
+		if err != nil {
+			slog.WarnContext(ctx, "archive secrets", "user", u.ID, "err", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if n > 0 {
+			slog.InfoContext(ctx, "archived unused secrets", "count", n, "user", u.ID)
+		}
+	}
+	return firstErr
+}
+
+// dryRunUser logs what a sweep would archive for one user.
+func (a *Archiver) dryRunUser(ctx context.Context, userID uuid.UUID, cutoff time.Time) error {
+	ids, err := a.secrets.ListUnaccessed(ctx, userID, cutoff)
+	if err != nil {
+		slog.WarnContext(ctx, "dry-run archive secrets", "user", userID, "err", err)
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	sample := ids
+	if len(sample) > archiveSampleIDs {
+		sample = sample[:archiveSampleIDs]
+	}
+	slog.InfoContext(ctx, "dry run: would archive unused secrets", "count", len(ids), "user", userID, "sample", fmt.Sprint(sample))
+	return nil
+}
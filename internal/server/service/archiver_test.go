@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestArchiverSweep(t *testing.T) {
+	users := memory.NewUserRepository()
+	secrets := memory.NewSecretRepository()
+	svc := NewSecretsService(SecretsServiceDeps{
+		Secrets:     secrets,
+		Attachments: memory.NewAttachmentRepository(),
+		TM:          repository.NoopTransactionManager{},
+		MasterKey:   []byte("test master key"),
+	})
+	ctx := context.Background()
+
+	user := &models.User{ID: uuid.New(), Login: "alice", ArchiveAfterDays: 30}
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := mustCreateCredentials(t, svc, user.ID, "old")
+	fresh := mustCreateCredentials(t, svc, user.ID, "new")
+	// Push the first secret's access time past the user's policy.
+	if err := secrets.MarkAccessed(ctx, user.ID, stale.ID, time.Now().AddDate(0, 0, -31)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewArchiver(users, secrets, false).Sweep(ctx); err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+
+	active, _, err := svc.GetSecrets(ctx, user.ID, repository.SecretListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 1 || active[0].ID != fresh.ID {
+		t.Fatalf("active secrets = %v, want only the fresh one", active)
+	}
+	all, _, err := svc.GetSecrets(ctx, user.ID, repository.SecretListOptions{IncludeArchived: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("got %d secrets with archived included, want 2", len(all))
+	}
+
+	// Reading an archived secret restores it.
+	if _, err := svc.GetSecret(ctx, user.ID, stale.ID); err != nil {
+		t.Fatal(err)
+	}
+	active, _, err = svc.GetSecrets(ctx, user.ID, repository.SecretListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 2 {
+		t.Fatalf("got %d active secrets after reading the archived one, want 2", len(active))
+	}
+}
+
+func TestArchiverDryRunTouchesNothing(t *testing.T) {
+	users := memory.NewUserRepository()
+	secrets := memory.NewSecretRepository()
+	svc := NewSecretsService(SecretsServiceDeps{
+		Secrets:     secrets,
+		Attachments: memory.NewAttachmentRepository(),
+		TM:          repository.NoopTransactionManager{},
+		MasterKey:   []byte("test master key"),
+	})
+	ctx := context.Background()
+
+	user := &models.User{ID: uuid.New(), Login: "carol", ArchiveAfterDays: 30}
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	secret := mustCreateCredentials(t, svc, user.ID, "old")
+	if err := secrets.MarkAccessed(ctx, user.ID, secret.ID, time.Now().AddDate(0, 0, -31)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewArchiver(users, secrets, true).Sweep(ctx); err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	active, _, err := svc.GetSecrets(ctx, user.ID, repository.SecretListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 1 {
+		t.Fatalf("got %d active secrets, want 1: a dry run must not archive", len(active))
+	}
+}
+
+func TestArchiverSkipsUsersWithoutPolicy(t *testing.T) {
+	users := memory.NewUserRepository()
+	secrets := memory.NewSecretRepository()
+	svc := NewSecretsService(SecretsServiceDeps{
+		Secrets:     secrets,
+		Attachments: memory.NewAttachmentRepository(),
+		TM:          repository.NoopTransactionManager{},
+		MasterKey:   []byte("test master key"),
+	})
+	ctx := context.Background()
+
+	user := &models.User{ID: uuid.New(), Login: "bob"}
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	secret := mustCreateCredentials(t, svc, user.ID, "pw")
+	if err := secrets.MarkAccessed(ctx, user.ID, secret.ID, time.Now().AddDate(-1, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewArchiver(users, secrets, false).Sweep(ctx); err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	active, _, err := svc.GetSecrets(ctx, user.ID, repository.SecretListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 1 {
+		t.Fatalf("got %d active secrets, want 1: opted-out users must not be archived", len(active))
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// AddAttachment encrypts and stores a file attached to an existing secret
+// of any type.
+func (s *SecretsService) AddAttachment(ctx context.Context, userID, secretID uuid.UUID, filename string, content []byte) (*models.Attachment, error) {
+	if filename == "" {
+		return nil, fmt.Errorf("%w: filename is required", ErrValidation)
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf("%w: content is required", ErrValidation)
+	}
+	// Verify the secret exists and belongs to the caller.
+	if _, err := s.secrets.GetByID(ctx, userID, secretID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	encrypted, err := crypto.Encrypt(s.userKey(userID), content)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt attachment: %w", err)
+	}
+	attachment := &models.Attachment{
+		ID:        uuid.New(),
+		SecretID:  secretID,
+		UserID:    userID,
+		Filename:  filename,
+		Size:      int64(len(content)),
+		Content:   encrypted,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.attachments.Create(ctx, attachment); err != nil {
+		return nil, err
+	}
+	attachment.Content = nil
+	return attachment, nil
+}
+
+// GetAttachment fetches and decrypts one attachment.
+func (s *SecretsService) GetAttachment(ctx context.Context, userID, id uuid.UUID) (*models.Attachment, error) {
+	attachment, err := s.attachments.GetByID(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	content, err := crypto.Decrypt(s.userKey(userID), attachment.Content)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt attachment: %w", err)
+	}
+	attachment.Content = content
+	return attachment, nil
+}
+
+// ListAttachments returns the attachment metadata of one secret.
+func (s *SecretsService) ListAttachments(ctx context.Context, userID, secretID uuid.UUID) ([]models.Attachment, error) {
+	return s.attachments.ListBySecret(ctx, userID, secretID)
+}
+
+// DeleteAttachment removes an attachment.
+func (s *SecretsService) DeleteAttachment(ctx context.Context, userID, id uuid.UUID) error {
+	err := s.attachments.Delete(ctx, userID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
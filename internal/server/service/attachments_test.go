@@ -0,0 +1,51 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAttachmentRoundTrip(t *testing.T) {
+	svc := newTestSecretsService()
+	userID := uuid.New()
+	secret := mustCreateCredentials(t, svc, userID, "hunter2")
+	ctx := context.Background()
+
+	content := []byte("file contents")
+	attachment, err := svc.AddAttachment(ctx, userID, secret.ID, "notes.txt", content)
+	if err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+	if attachment.Size != int64(len(content)) {
+		t.Fatalf("size = %d, want %d", attachment.Size, len(content))
+	}
+
+	got, err := svc.GetAttachment(ctx, userID, attachment.ID)
+	if err != nil {
+		t.Fatalf("GetAttachment: %v", err)
+	}
+	if !bytes.Equal(got.Content, content) {
+		t.Fatalf("content mismatch: %q", got.Content)
+	}
+
+	list, err := svc.ListAttachments(ctx, userID, secret.ID)
+	if err != nil {
+		t.Fatalf("ListAttachments: %v", err)
+	}
+	if len(list) != 1 || list[0].Filename != "notes.txt" {
+		t.Fatalf("list = %+v", list)
+	}
+	if len(list[0].Content) != 0 {
+		t.Fatal("list leaked attachment content")
+	}
+}
+
+func TestAddAttachmentToMissingSecret(t *testing.T) {
+	svc := newTestSecretsService()
+	if _, err := svc.AddAttachment(context.Background(), uuid.New(), uuid.New(), "f", []byte("x")); err != ErrNotFound {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/logging"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// AuditRecorder records security-relevant events. Services hold it as an
+// optional dependency; a nil recorder disables auditing.
+type AuditRecorder interface {
+	Record(ctx context.Context, userID uuid.UUID, action, resource string)
+}
+
+// Audit export formats.
+const (
+	AuditFormatJSONL = "jsonl"
+	AuditFormatCEF   = "cef"
+)
+
+// auditExportLimit bounds one export page; clients paginate with the
+// `after` cursor.
+const auditExportLimit = 1000
+
+// AuditService appends to and exports the audit trail. If a forwarder is
+// configured every recorded event is also pushed to it. With a master key
+// the resource of every stored event is encrypted with the per-user key, so
+// a database snapshot does not reveal which secrets a user touched.
+type AuditService struct {
+	events    repository.AuditRepository
+	forwarder *SyslogForwarder
+	masterKey []byte
+}
+
+// NewAuditService creates an AuditService. forwarder may be nil; a nil
+// masterKey stores resources in plaintext.
+func NewAuditService(events repository.AuditRepository, forwarder *SyslogForwarder, masterKey []byte) *AuditService {
+	return &AuditService{events: events, forwarder: forwarder, masterKey: masterKey}
+}
+
+// Record appends an event to the trail. Audit failures are logged, never
+// surfaced: they must not fail the operation being audited.
+func (s *AuditService) Record(ctx context.Context, userID uuid.UUID, action, resource string) {
+	event := &models.AuditEvent{
+		UserID:    userID,
+		Action:    action,
+		Resource:  resource,
+		IP:        logging.ClientIP(ctx),
+		UserAgent: logging.UserAgent(ctx),
+		CreatedAt: time.Now().UTC(),
+	}
+	stored := *event
+	var err error
+	if stored.Resource, err = s.sealResource(userID, resource); err != nil {
+		slog.WarnContext(ctx, "audit: seal resource", "action", action, "err", err)
+		return
+	}
+	if err := s.events.Append(ctx, &stored); err != nil {
+		slog.WarnContext(ctx, "audit: append", "action", action, "err", err)
+		return
+	}
+	event.ID = stored.ID
+	if s.forwarder != nil {
+		s.forwarder.Forward(event)
+	}
+}
+
+// ListAfter returns up to limit events of a user past the `after` cursor,
+// with resources decrypted.
+func (s *AuditService) ListAfter(ctx context.Context, userID uuid.UUID, after int64, limit int) ([]models.AuditEvent, error) {
+	events, err := s.events.ListAfter(ctx, userID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		events[i].Resource = s.openResource(userID, events[i].Resource)
+	}
+	return events, nil
+}
+
+// LoginHistory returns the user's newest sign-in attempts, successful and
+// failed, newest first, so suspicious activity is easy to spot.
+func (s *AuditService) LoginHistory(ctx context.Context, userID uuid.UUID, limit int) ([]models.LoginRecord, error) {
+	events, err := s.events.ListRecentByActions(ctx, userID,
+		[]string{models.AuditActionLogin, models.AuditActionLoginFailed}, limit)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]models.LoginRecord, 0, len(events))
+	for _, e := range events {
+		records = append(records, models.LoginRecord{
+			At:        e.CreatedAt,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			Success:   e.Action == models.AuditActionLogin,
+		})
+	}
+	return records, nil
+}
+
+// sealResource encrypts a resource with the per-user key for storage. Empty
+// resources and a nil master key pass through unchanged.
+func (s *AuditService) sealResource(userID uuid.UUID, resource string) (string, error) {
+	if s.masterKey == nil || resource == "" {
+		return resource, nil
+	}
+	sealed, err := crypto.Encrypt(crypto.DeriveKey(s.masterKey, userID[:]), []byte(resource))
+	if err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// openResource reverses sealResource. Values that do not decrypt are
+// returned as stored: rows written before encryption was introduced are
+// plaintext.
+func (s *AuditService) openResource(userID uuid.UUID, resource string) string {
+	if s.masterKey == nil || resource == "" {
+		return resource
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(resource)
+	if err != nil {
+		return resource
+	}
+	opened, err := crypto.Decrypt(crypto.DeriveKey(s.masterKey, userID[:]), sealed)
+	if err != nil {
+		return resource
+	}
+	return string(opened)
+}
+
+// Export returns up to one page of events past the `after` cursor, rendered
+// in the requested format, together with the cursor for the next page.
+func (s *AuditService) Export(ctx context.Context, userID uuid.UUID, after int64, format string) (string, int64, error) {
+	events, err := s.ListAfter(ctx, userID, after, auditExportLimit)
+	if err != nil {
+		return "", 0, err
+	}
+	next := after
+	var b strings.Builder
+	for _, e := range events {
+		line, err := FormatAuditEvent(e, format)
+		if err != nil {
+			return "", 0, err
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+		next = e.ID
+	}
+	return b.String(), next, nil
+}
+
+// FormatAuditEvent renders one event as a single line in the given format.
+func FormatAuditEvent(e models.AuditEvent, format string) (string, error) {
+	switch format {
+	case AuditFormatJSONL, "":
+		return fmt.Sprintf(`{"id":%d,"user_id":%q,"action":%q,"resource":%q,"created_at":%q}`,
+			e.ID, e.UserID, e.Action, e.Resource, e.CreatedAt.Format(time.RFC3339Nano)), nil
+	case AuditFormatCEF:
+		return fmt.Sprintf("CEF:0|gophkeeper|gophkeeper|1|%s|%s|3|rt=%d suid=%s cs1Label=resource cs1=%s externalId=%d",
+			cefEscape(e.Action), cefEscape(e.Action), e.CreatedAt.UnixMilli(),
+			e.UserID, cefEscape(e.Resource), e.ID), nil
+	default:
+		return "", fmt.Errorf("%w: unknown audit format %q", ErrValidation, format)
+	}
+}
+
+// cefEscape escapes the characters CEF reserves in extension values.
+func cefEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", ``)
+	return r.Replace(s)
+}
+
+// SyslogForwarder pushes audit events as CEF lines to a syslog collector
+// over UDP or TCP.
+type SyslogForwarder struct {
+	network string
+	addr    string
+}
+
+// NewSyslogForwarder creates a forwarder for the given network ("udp" or
+// "tcp") and collector address.
+func NewSyslogForwarder(network, addr string) *SyslogForwarder {
+	return &SyslogForwarder{network: network, addr: addr}
+}
+
+// Forward sends one event, logging delivery failures. A fresh connection
+// per event keeps the forwarder stateless; audit volume is low.
+func (f *SyslogForwarder) Forward(event *models.AuditEvent) {
+	line, err := FormatAuditEvent(*event, AuditFormatCEF)
+	if err != nil {
+		slog.Warn("audit: format event", "event", event.ID, "err", err)
+		return
+	}
+	conn, err := net.DialTimeout(f.network, f.addr, 5*time.Second)
+	if err != nil {
+		slog.Warn("audit: forward", "addr", f.addr, "err", err)
+		return
+	}
+	defer conn.Close()
+	// RFC 3164 framing: <facility*8+severity>timestamp host tag: message.
+	msg := fmt.Sprintf("<134>%s gophkeeper audit: %s\n", event.CreatedAt.Format(time.Stamp), line)
+	if _, err := conn.Write([]byte(msg)); err != nil {
+		slog.Warn("audit: forward", "addr", f.addr, "err", err)
+	}
+}
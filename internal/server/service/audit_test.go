@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestAuditExportCursor(t *testing.T) {
+	svc := NewAuditService(memory.NewAuditRepository(), nil, nil)
+	userID := uuid.New()
+	ctx := context.Background()
+
+	svc.Record(ctx, userID, models.AuditActionLogin, "")
+	svc.Record(ctx, userID, models.AuditActionSecretCreated, "abc")
+	svc.Record(ctx, uuid.New(), models.AuditActionLogin, "")
+
+	body, next, err := svc.Export(ctx, userID, 0, AuditFormatJSONL)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if lines := strings.Count(body, "\n"); lines != 2 {
+		t.Fatalf("exported %d lines, want 2:\n%s", lines, body)
+	}
+	if next != 2 {
+		t.Fatalf("next cursor = %d, want 2", next)
+	}
+
+	body, next, err = svc.Export(ctx, userID, next, AuditFormatJSONL)
+	if err != nil {
+		t.Fatalf("Export after cursor: %v", err)
+	}
+	if body != "" || next != 2 {
+		t.Fatalf("expected empty page, got body %q next %d", body, next)
+	}
+}
+
+func TestResourceRoundTrip(t *testing.T) {
+	svc := NewAuditService(memory.NewAuditRepository(), nil, []byte("test master key"))
+	userID := uuid.New()
+	ctx := context.Background()
+
+	svc.Record(ctx, userID, models.AuditActionSecretUpdated, "secret-id")
+
+	events, err := svc.ListAfter(ctx, userID, 0, 10)
+	if err != nil {
+		t.Fatalf("ListAfter: %v", err)
+	}
+	if len(events) != 1 || events[0].Resource != "secret-id" {
+		t.Fatalf("events = %+v, want one with the original resource", events)
+	}
+
+	// A second service with a different key cannot open the resource, but
+	// plaintext rows from before encryption still list as stored.
+	other := NewAuditService(memory.NewAuditRepository(), nil, []byte("other key"))
+	if got := other.openResource(userID, "plain"); got != "plain" {
+		t.Fatalf("openResource(plain) = %q, want passthrough", got)
+	}
+}
+
+func TestFormatAuditEventCEF(t *testing.T) {
+	e := models.AuditEvent{ID: 7, UserID: uuid.New(), Action: "secret.updated", Resource: "a=b"}
+	line, err := FormatAuditEvent(e, AuditFormatCEF)
+	if err != nil {
+		t.Fatalf("FormatAuditEvent: %v", err)
+	}
+	if !strings.HasPrefix(line, "CEF:0|gophkeeper|gophkeeper|1|secret.updated|") {
+		t.Fatalf("unexpected CEF header: %s", line)
+	}
+	if !strings.Contains(line, `cs1=a\=b`) {
+		t.Fatalf("resource not escaped: %s", line)
+	}
+}
+
+func TestFormatAuditEventUnknownFormat(t *testing.T) {
+	if _, err := FormatAuditEvent(models.AuditEvent{}, "xml"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
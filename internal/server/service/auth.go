@@ -0,0 +1,369 @@
+// Package service contains the business logic of the server. Services sit
+// between HTTP handlers and repositories and own validation, encryption and
+// error mapping.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// Service-level errors mapped to HTTP statuses by the handlers.
+var (
+	ErrInvalidCredentials = errors.New("invalid login or password")
+	ErrUserExists         = errors.New("user already exists")
+	ErrEmailExists        = errors.New("email already in use")
+	ErrNotFound           = errors.New("not found")
+)
+
+const tokenTTL = 24 * time.Hour
+
+// maxHintLength bounds the vault hint a user can store.
+const maxHintLength = 1024
+
+// maxVerifierLength bounds the master-password verifier a user can store.
+const maxVerifierLength = 256
+
+// SecurityNotifier alerts users about suspicious sign-in activity.
+// Services hold it as an optional hook; nil drops the alerts.
+type SecurityNotifier interface {
+	LoginFailed(ctx context.Context, userID uuid.UUID)
+}
+
+// DeviceGate authorizes the device fingerprint a login came from. nil
+// disables the check.
+type DeviceGate interface {
+	Authorize(ctx context.Context, userID uuid.UUID, fingerprint string) error
+}
+
+// AuthService registers users and issues access tokens.
+type AuthService struct {
+	users     repository.UserRepository
+	jwtKeys   *JWTKeyring
+	masterKey []byte
+	audit     AuditRecorder
+	policy    PasswordPolicy
+	notifier  SecurityNotifier
+	devices   DeviceGate
+	// deleteGrace is how long DELETE /me waits before the account is
+	// purged; during the grace period the deletion can be cancelled.
+	deleteGrace time.Duration
+	outbox      repository.OutboxRepository
+}
+
+// NewAuthService creates an AuthService. masterKey encrypts per-user
+// account data such as the vault hint; audit may be nil to disable
+// auditing.
+func NewAuthService(users repository.UserRepository, jwtSecret, masterKey []byte, audit AuditRecorder) *AuthService {
+	return &AuthService{users: users, jwtKeys: newJWTKeyring(jwtSecret), masterKey: masterKey, audit: audit}
+}
+
+// SetJWTKeys replaces the single constructor secret with a full keyring,
+// so deployments rotating their JWT keys can keep old tokens valid.
+func (s *AuthService) SetJWTKeys(keys *JWTKeyring) {
+	s.jwtKeys = keys
+}
+
+// SetPasswordPolicy installs the password policy new registrations must
+// satisfy. Existing passwords are untouched: they are only re-checked
+// when the user changes them.
+func (s *AuthService) SetPasswordPolicy(policy PasswordPolicy) {
+	s.policy = policy
+}
+
+func (s *AuthService) record(ctx context.Context, userID uuid.UUID, action, resource string) {
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, action, resource)
+	}
+}
+
+// Register creates a user and returns an access token for it. email is
+// an optional contact address; when given it must be unique across
+// accounts. Both login and email uniqueness are enforced by the
+// repository, not checked here, so concurrent registrations cannot race.
+func (s *AuthService) Register(ctx context.Context, login, password, email string) (string, error) {
+	if login == "" || password == "" {
+		return "", ErrInvalidCredentials
+	}
+	email = strings.TrimSpace(email)
+	if email != "" && !strings.Contains(email, "@") {
+		return "", fmt.Errorf("%w: invalid email address", ErrValidation)
+	}
+	if err := s.policy.Validate(password); err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+	user := &models.User{
+		ID:           uuid.New(),
+		Login:        login,
+		Email:        email,
+		PasswordHash: string(hash),
+		Role:         models.RoleUser,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := s.users.Create(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrEmailExists) {
+			return "", ErrEmailExists
+		}
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return "", ErrUserExists
+		}
+		return "", fmt.Errorf("create user: %w", err)
+	}
+	s.record(ctx, user.ID, models.AuditActionRegister, "")
+	return s.issueToken(user.ID)
+}
+
+// Login verifies credentials and returns an access token. fingerprint
+// optionally identifies the client installation; when the device gate is
+// installed and the fingerprint is unknown, the login is refused with
+// ErrDevicePending until the device is approved.
+func (s *AuthService) Login(ctx context.Context, login, password, fingerprint string) (string, error) {
+	user, err := s.users.GetByLogin(ctx, login)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", ErrInvalidCredentials
+		}
+		return "", fmt.Errorf("get user: %w", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		s.record(ctx, user.ID, models.AuditActionLoginFailed, "")
+		if s.notifier != nil {
+			s.notifier.LoginFailed(ctx, user.ID)
+		}
+		return "", ErrInvalidCredentials
+	}
+	// Deactivated accounts (for example the source of an account merge)
+	// keep their data but can no longer sign in.
+	if user.Disabled {
+		s.record(ctx, user.ID, models.AuditActionLoginFailed, "")
+		return "", ErrInvalidCredentials
+	}
+	if s.devices != nil && fingerprint != "" {
+		if err := s.devices.Authorize(ctx, user.ID, fingerprint); err != nil {
+			return "", err
+		}
+	}
+	s.record(ctx, user.ID, models.AuditActionLogin, "")
+	// A failed event append never blocks the login itself.
+	if s.outbox != nil {
+		event := &models.OutboxEvent{UserID: user.ID, Action: models.AuditActionLogin, CreatedAt: time.Now().UTC()}
+		if err := s.outbox.Append(ctx, event); err != nil {
+			slog.WarnContext(ctx, "enqueue login event", "user", user.ID, "err", err)
+		}
+	}
+	return s.issueToken(user.ID)
+}
+
+// VerifyPassword checks the account password of an existing user. It is used
+// for operations that require explicit re-authentication.
+func (s *AuthService) VerifyPassword(ctx context.Context, userID uuid.UUID, password string) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidCredentials
+		}
+		return fmt.Errorf("get user: %w", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// SetArchivePolicy updates the user's auto-archival policy: secrets
+// unaccessed for days days are archived by the background job. 0 opts out.
+func (s *AuthService) SetArchivePolicy(ctx context.Context, userID uuid.UUID, days int) error {
+	if days < 0 {
+		return fmt.Errorf("%w: archive_after_days must not be negative", ErrValidation)
+	}
+	err := s.users.SetArchiveAfterDays(ctx, userID, days)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// SetHint stores the user's vault hint, encrypted with the per-user key.
+// An empty hint clears it. Hints are meant as a reminder of the password
+// scheme, never the password itself, so length is the only validation.
+func (s *AuthService) SetHint(ctx context.Context, userID uuid.UUID, hint string) error {
+	if len(hint) > maxHintLength {
+		return fmt.Errorf("%w: hint must not exceed %d bytes", ErrValidation, maxHintLength)
+	}
+	var sealed []byte
+	if hint != "" {
+		var err error
+		sealed, err = crypto.Encrypt(crypto.DeriveKey(s.masterKey, userID[:]), []byte(hint))
+		if err != nil {
+			return fmt.Errorf("encrypt hint: %w", err)
+		}
+	}
+	err := s.users.SetHint(ctx, userID, sealed)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// GetHint returns the user's vault hint, empty when none is set.
+func (s *AuthService) GetHint(ctx context.Context, userID uuid.UUID) (string, error) {
+	sealed, err := s.users.GetHint(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) == 0 {
+		return "", nil
+	}
+	hint, err := crypto.Decrypt(crypto.DeriveKey(s.masterKey, userID[:]), sealed)
+	if err != nil {
+		return "", fmt.Errorf("decrypt hint: %w", err)
+	}
+	return string(hint), nil
+}
+
+// SetMasterVerifier stores the user's master-password verifier: an opaque
+// salted hash of the key clients derive from the end-to-end master
+// password, so a mistyped password fails one early check instead of a
+// decryption attempt per secret. Like the account password hash it is
+// stored as-is; empty clears it.
+func (s *AuthService) SetMasterVerifier(ctx context.Context, userID uuid.UUID, verifier []byte) error {
+	if len(verifier) > maxVerifierLength {
+		return fmt.Errorf("%w: verifier must not exceed %d bytes", ErrValidation, maxVerifierLength)
+	}
+	err := s.users.SetMasterVerifier(ctx, userID, verifier)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// GetMasterVerifier returns the user's master-password verifier, empty
+// when none is set.
+func (s *AuthService) GetMasterVerifier(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	verifier, err := s.users.GetMasterVerifier(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return verifier, err
+}
+
+// SetNotifier installs the hook that alerts users about failed sign-in
+// bursts.
+func (s *AuthService) SetNotifier(notifier SecurityNotifier) {
+	s.notifier = notifier
+}
+
+// SetDeviceGate installs the device-approval check logins with a
+// fingerprint must pass.
+func (s *AuthService) SetDeviceGate(devices DeviceGate) {
+	s.devices = devices
+}
+
+// SetOutbox enables login events in the change feed, next to the secret
+// events the secrets service enqueues, so webhooks can observe sign-ins.
+func (s *AuthService) SetOutbox(outbox repository.OutboxRepository) {
+	s.outbox = outbox
+}
+
+// SetDeletionGrace sets how long a scheduled account deletion waits
+// before the reaper purges the account.
+func (s *AuthService) SetDeletionGrace(d time.Duration) {
+	s.deleteGrace = d
+}
+
+// ScheduleDeletion re-checks the account password and schedules the
+// account for permanent deletion once the grace period passes. It returns
+// the scheduled time so the client can confirm it to the user; until then
+// CancelDeletion reverts the schedule. The actual purge — secrets, queued
+// sync operations and devices included — happens in the reaper.
+func (s *AuthService) ScheduleDeletion(ctx context.Context, userID uuid.UUID, password string) (time.Time, error) {
+	if err := s.VerifyPassword(ctx, userID, password); err != nil {
+		return time.Time{}, err
+	}
+	at := time.Now().UTC().Add(s.deleteGrace)
+	if err := s.users.SetDeleteAfter(ctx, userID, &at); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return time.Time{}, ErrNotFound
+		}
+		return time.Time{}, err
+	}
+	s.record(ctx, userID, models.AuditActionDeleteScheduled, "")
+	return at, nil
+}
+
+// CancelDeletion reverts a pending account deletion. Cancelling when none
+// is scheduled is a no-op.
+func (s *AuthService) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	if err := s.users.SetDeleteAfter(ctx, userID, nil); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	s.record(ctx, userID, models.AuditActionDeleteCancelled, "")
+	return nil
+}
+
+// ParseToken validates a token and returns the user ID it was issued for.
+func (s *AuthService) ParseToken(token string) (uuid.UUID, error) {
+	claims := &jwt.RegisteredClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		if kid, ok := t.Header["kid"].(string); ok {
+			key, ok := s.jwtKeys.key(kid)
+			if !ok {
+				return nil, fmt.Errorf("unknown key id %q", kid)
+			}
+			if t.Method.Alg() != key.method.Alg() {
+				return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+			}
+			return key.verify, nil
+		}
+		// Tokens from before rotation was configured carry no key ID, so
+		// any shared secret on the ring may verify them.
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+		}
+		return s.jwtKeys.verificationKeys(), nil
+	})
+	if err != nil || !parsed.Valid {
+		return uuid.Nil, ErrInvalidCredentials
+	}
+	return uuid.Parse(claims.Subject)
+}
+
+func (s *AuthService) issueToken(userID uuid.UUID) (string, error) {
+	now := time.Now()
+	key := s.jwtKeys.signer()
+	token := jwt.NewWithClaims(key.method, jwt.RegisteredClaims{
+		Subject:   userID.String(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(tokenTTL)),
+	})
+	if key.kid != "" {
+		token.Header["kid"] = key.kid
+	}
+	signed, err := token.SignedString(key.sign)
+	if err != nil {
+		return "", fmt.Errorf("sign token: %w", err)
+	}
+	return signed, nil
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestHintRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("jwt secret"), []byte("test master key"), nil)
+
+	if _, err := auth.Register(ctx, "alice", "password", ""); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	user, err := users.GetByLogin(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetByLogin: %v", err)
+	}
+
+	const hint = "work scheme, capital city + year"
+	if err := auth.SetHint(ctx, user.ID, hint); err != nil {
+		t.Fatalf("SetHint: %v", err)
+	}
+
+	got, err := auth.GetHint(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetHint: %v", err)
+	}
+	if got != hint {
+		t.Errorf("GetHint = %q, want %q", got, hint)
+	}
+
+	// The repository must only ever see ciphertext.
+	raw, err := users.GetHint(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("repository GetHint: %v", err)
+	}
+	if strings.Contains(string(raw), hint) {
+		t.Error("hint stored in plaintext")
+	}
+
+	if err := auth.SetHint(ctx, user.ID, ""); err != nil {
+		t.Fatalf("clear hint: %v", err)
+	}
+	if got, err := auth.GetHint(ctx, user.ID); err != nil || got != "" {
+		t.Errorf("GetHint after clear = %q, %v; want empty", got, err)
+	}
+
+	if err := auth.SetHint(ctx, user.ID, strings.Repeat("x", maxHintLength+1)); !errors.Is(err, ErrValidation) {
+		t.Errorf("SetHint(too long) = %v, want ErrValidation", err)
+	}
+}
+
+func TestMasterVerifierRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("jwt secret"), []byte("test master key"), nil)
+
+	if _, err := auth.Register(ctx, "bob", "password", ""); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	user, err := users.GetByLogin(ctx, "bob")
+	if err != nil {
+		t.Fatalf("GetByLogin: %v", err)
+	}
+
+	if got, err := auth.GetMasterVerifier(ctx, user.ID); err != nil || len(got) != 0 {
+		t.Fatalf("GetMasterVerifier before set = %v, %v; want empty", got, err)
+	}
+
+	verifier := []byte("opaque verifier bytes")
+	if err := auth.SetMasterVerifier(ctx, user.ID, verifier); err != nil {
+		t.Fatalf("SetMasterVerifier: %v", err)
+	}
+	got, err := auth.GetMasterVerifier(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetMasterVerifier: %v", err)
+	}
+	if string(got) != string(verifier) {
+		t.Errorf("GetMasterVerifier = %q, want %q", got, verifier)
+	}
+
+	if err := auth.SetMasterVerifier(ctx, user.ID, nil); err != nil {
+		t.Fatalf("clear verifier: %v", err)
+	}
+	if got, err := auth.GetMasterVerifier(ctx, user.ID); err != nil || len(got) != 0 {
+		t.Errorf("GetMasterVerifier after clear = %v, %v; want empty", got, err)
+	}
+
+	if err := auth.SetMasterVerifier(ctx, user.ID, make([]byte, maxVerifierLength+1)); !errors.Is(err, ErrValidation) {
+		t.Errorf("SetMasterVerifier(too long) = %v, want ErrValidation", err)
+	}
+}
+
+func TestRegisterUniqueness(t *testing.T) {
+	ctx := context.Background()
+	auth := NewAuthService(memory.NewUserRepository(), []byte("jwt secret"), []byte("test master key"), nil)
+
+	if _, err := auth.Register(ctx, "alice", "password", "alice@example.com"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, err := auth.Register(ctx, "alice", "password", ""); !errors.Is(err, ErrUserExists) {
+		t.Errorf("Register(duplicate login) = %v, want ErrUserExists", err)
+	}
+	if _, err := auth.Register(ctx, "bob", "password", "Alice@Example.com"); !errors.Is(err, ErrEmailExists) {
+		t.Errorf("Register(duplicate email) = %v, want ErrEmailExists", err)
+	}
+	if _, err := auth.Register(ctx, "carol", "password", "not-an-address"); !errors.Is(err, ErrValidation) {
+		t.Errorf("Register(malformed email) = %v, want ErrValidation", err)
+	}
+	if _, err := auth.Register(ctx, "carol", "password", "carol@example.com"); err != nil {
+		t.Errorf("Register(distinct email) = %v, want nil", err)
+	}
+}
+
+func TestAccountDeletionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("jwt secret"), []byte("test master key"), nil)
+	auth.SetDeletionGrace(time.Hour)
+
+	if _, err := auth.Register(ctx, "alice", "password", ""); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	user, err := users.GetByLogin(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetByLogin: %v", err)
+	}
+
+	if _, err := auth.ScheduleDeletion(ctx, user.ID, "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("ScheduleDeletion(wrong password) = %v, want ErrInvalidCredentials", err)
+	}
+	at, err := auth.ScheduleDeletion(ctx, user.ID, "password")
+	if err != nil {
+		t.Fatalf("ScheduleDeletion: %v", err)
+	}
+	if remaining := time.Until(at); remaining < 50*time.Minute || remaining > 70*time.Minute {
+		t.Errorf("delete_after %v from now, want about an hour", remaining)
+	}
+
+	// The grace period has not passed, so a sweep must leave the account.
+	if err := NewReaper(users).Sweep(ctx); err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	if _, err := users.GetByID(ctx, user.ID); err != nil {
+		t.Fatalf("account purged before the grace period ended: %v", err)
+	}
+
+	if err := auth.CancelDeletion(ctx, user.ID); err != nil {
+		t.Fatalf("CancelDeletion: %v", err)
+	}
+
+	// With a zero grace the schedule is due immediately and the reaper
+	// purges the account on the next sweep.
+	auth.SetDeletionGrace(0)
+	if _, err := auth.ScheduleDeletion(ctx, user.ID, "password"); err != nil {
+		t.Fatalf("ScheduleDeletion: %v", err)
+	}
+	if err := NewReaper(users).Sweep(ctx); err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	if _, err := users.GetByID(ctx, user.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetByID after purge = %v, want repository.ErrNotFound", err)
+	}
+}
+
+func TestDeviceApprovalFlow(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("jwt secret"), []byte("test master key"), nil)
+	devices := NewDeviceService(memory.NewDeviceRepository(), nil)
+	auth.SetDeviceGate(devices)
+
+	if _, err := auth.Register(ctx, "dana", "dana password", ""); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	user, err := users.GetByLogin(ctx, "dana")
+	if err != nil {
+		t.Fatalf("GetByLogin: %v", err)
+	}
+
+	// The first fingerprinted device is trusted on the spot.
+	if _, err := auth.Login(ctx, "dana", "dana password", "fp-laptop"); err != nil {
+		t.Fatalf("first-device login: %v", err)
+	}
+	// A second, unknown fingerprint must be approved first.
+	if _, err := auth.Login(ctx, "dana", "dana password", "fp-phone"); !errors.Is(err, ErrDevicePending) {
+		t.Fatalf("unknown-device login = %v, want ErrDevicePending", err)
+	}
+
+	list, err := devices.List(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var pending *models.Device
+	for i := range list {
+		if list[i].Pending {
+			pending = &list[i]
+		}
+	}
+	if pending == nil {
+		t.Fatal("refused login did not record a pending device")
+	}
+
+	// Approval from a trusted session unlocks the device.
+	if err := devices.Approve(ctx, user.ID, pending.ID); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if _, err := auth.Login(ctx, "dana", "dana password", "fp-phone"); err != nil {
+		t.Fatalf("login after approval: %v", err)
+	}
+
+	// The emailed token approves a device without a session.
+	if _, err := auth.Login(ctx, "dana", "dana password", "fp-tablet"); !errors.Is(err, ErrDevicePending) {
+		t.Fatalf("third-device login = %v, want ErrDevicePending", err)
+	}
+	list, err = devices.List(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	token := ""
+	for _, d := range list {
+		if d.Pending {
+			token = d.ApprovalToken
+		}
+	}
+	if token == "" {
+		t.Fatal("pending device has no approval token")
+	}
+	if err := devices.ApproveByToken(ctx, "wrong token"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("ApproveByToken with bad token = %v, want ErrNotFound", err)
+	}
+	if err := devices.ApproveByToken(ctx, token); err != nil {
+		t.Fatalf("ApproveByToken: %v", err)
+	}
+	if _, err := auth.Login(ctx, "dana", "dana password", "fp-tablet"); err != nil {
+		t.Fatalf("login after token approval: %v", err)
+	}
+
+	// Clients without fingerprints are unaffected by the gate.
+	if _, err := auth.Login(ctx, "dana", "dana password", ""); err != nil {
+		t.Fatalf("fingerprintless login: %v", err)
+	}
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("old secret"), []byte("test master key"), nil)
+
+	token, err := auth.Register(ctx, "erin", "erin password", "")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	user, err := users.GetByLogin(ctx, "erin")
+	if err != nil {
+		t.Fatalf("GetByLogin: %v", err)
+	}
+
+	// Rotating in a new signing key keeps the kid-less token valid as
+	// long as the old secret stays on the ring.
+	keys, err := ParseJWTKeys("2024=new secret,2023=old secret")
+	if err != nil {
+		t.Fatalf("ParseJWTKeys: %v", err)
+	}
+	auth.SetJWTKeys(keys)
+	if got, err := auth.ParseToken(token); err != nil || got != user.ID {
+		t.Fatalf("ParseToken(old token after rotation) = %v, %v; want user ID", got, err)
+	}
+
+	// New tokens are signed with the newest key and verify under a ring
+	// that no longer carries the old secret.
+	rotated, err := auth.Login(ctx, "erin", "erin password", "")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	newOnly, err := ParseJWTKeys("2024=new secret")
+	if err != nil {
+		t.Fatalf("ParseJWTKeys: %v", err)
+	}
+	auth.SetJWTKeys(newOnly)
+	if got, err := auth.ParseToken(rotated); err != nil || got != user.ID {
+		t.Fatalf("ParseToken(rotated token) = %v, %v; want user ID", got, err)
+	}
+
+	// Dropping the old key finally invalidates tokens signed with it.
+	if _, err := auth.ParseToken(token); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("ParseToken(old token without old key) = %v, want ErrInvalidCredentials", err)
+	}
+
+	if _, err := ParseJWTKeys("2024=new secret,2024=other"); err == nil {
+		t.Error("ParseJWTKeys accepted a duplicate key id")
+	}
+	if _, err := ParseJWTKeys("=secret"); err == nil {
+		t.Error("ParseJWTKeys accepted an empty key id")
+	}
+}
+
+func TestAsymmetricJWTSigning(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("old secret"), []byte("test master key"), nil)
+
+	hmacToken, err := auth.Register(ctx, "frank", "frank password", "")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	user, err := users.GetByLogin(ctx, "frank")
+	if err != nil {
+		t.Fatalf("GetByLogin: %v", err)
+	}
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	keys, err := ParseJWTKeys("old secret")
+	if err != nil {
+		t.Fatalf("ParseJWTKeys: %v", err)
+	}
+	if err := keys.AddKeyPair(pemKey); err != nil {
+		t.Fatalf("AddKeyPair: %v", err)
+	}
+	auth.SetJWTKeys(keys)
+
+	// New tokens are signed with the key pair; the old HMAC token stays
+	// valid because the shared secret is still on the ring.
+	token, err := auth.Login(ctx, "frank", "frank password", "")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if got, err := auth.ParseToken(token); err != nil || got != user.ID {
+		t.Fatalf("ParseToken(EdDSA token) = %v, %v; want user ID", got, err)
+	}
+	if got, err := auth.ParseToken(hmacToken); err != nil || got != user.ID {
+		t.Fatalf("ParseToken(HMAC token) = %v, %v; want user ID", got, err)
+	}
+
+	// Only the public half is published, with the kid tokens carry.
+	jwks := keys.JWKS()
+	if len(jwks) != 1 {
+		t.Fatalf("JWKS returned %d keys, want 1", len(jwks))
+	}
+	if jwks[0].Kty != "OKP" || jwks[0].Alg != "EdDSA" || jwks[0].X == "" || jwks[0].Kid == "" {
+		t.Errorf("unexpected JWKS entry: %+v", jwks[0])
+	}
+}
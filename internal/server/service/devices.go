@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// maxDeviceNameLength bounds the name a device registers under.
+const maxDeviceNameLength = 128
+
+// ErrDeviceRevoked rejects syncs from a device the user revoked.
+var ErrDeviceRevoked = errors.New("device is revoked")
+
+// ErrDevicePending rejects logins from a device awaiting approval by a
+// trusted device or the emailed approval link.
+var ErrDevicePending = errors.New("device approval required")
+
+// DeviceService registers sync devices and answers who may sync. The sync
+// point of each device lives on the server, so a client with a drifting
+// clock or lost state still resumes from the right place.
+type DeviceService struct {
+	devices  repository.DeviceRepository
+	audit    AuditRecorder
+	notifier DeviceNotifier
+}
+
+// DeviceNotifier alerts the account owner when a device is registered or
+// needs approval. Services hold it as an optional hook; nil drops the
+// alerts.
+type DeviceNotifier interface {
+	NewDevice(ctx context.Context, userID uuid.UUID, name, platform string)
+	// DeviceApproval asks the owner to approve a pending device; token is
+	// the secret of the approval link.
+	DeviceApproval(ctx context.Context, userID uuid.UUID, name, token string)
+}
+
+// NewDeviceService creates a DeviceService. Audit is optional.
+func NewDeviceService(devices repository.DeviceRepository, audit AuditRecorder) *DeviceService {
+	return &DeviceService{devices: devices, audit: audit}
+}
+
+// SetNotifier installs the hook that alerts the owner about new devices.
+func (s *DeviceService) SetNotifier(notifier DeviceNotifier) {
+	s.notifier = notifier
+}
+
+// Register creates a device for the user and returns it.
+func (s *DeviceService) Register(ctx context.Context, userID uuid.UUID, name, platform string) (*models.Device, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: device name is required", ErrValidation)
+	}
+	if len(name) > maxDeviceNameLength {
+		return nil, fmt.Errorf("%w: device name must not exceed %d bytes", ErrValidation, maxDeviceNameLength)
+	}
+	device := &models.Device{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Platform:  platform,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.devices.Create(ctx, device); err != nil {
+		return nil, err
+	}
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, models.AuditActionDeviceAdded, device.ID.String())
+	}
+	if s.notifier != nil {
+		s.notifier.NewDevice(ctx, userID, name, platform)
+	}
+	return device, nil
+}
+
+// List returns the user's devices, oldest first.
+func (s *DeviceService) List(ctx context.Context, userID uuid.UUID) ([]models.Device, error) {
+	return s.devices.ListByUser(ctx, userID)
+}
+
+// Authorize checks the fingerprint a login came from. The account's first
+// fingerprinted device is trusted on the spot — someone has to be able to
+// sign in — and every later unknown fingerprint is recorded as a pending
+// device that must be approved by a trusted session or the emailed link
+// before it can obtain a token.
+func (s *DeviceService) Authorize(ctx context.Context, userID uuid.UUID, fingerprint string) error {
+	device, err := s.devices.GetByFingerprint(ctx, userID, fingerprint)
+	if err == nil {
+		switch {
+		case device.Revoked:
+			return ErrDeviceRevoked
+		case device.Pending:
+			return ErrDevicePending
+		}
+		return nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	existing, err := s.devices.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	trusted := false
+	for _, d := range existing {
+		if d.Fingerprint != "" && !d.Pending && !d.Revoked {
+			trusted = true
+			break
+		}
+	}
+	name := "device " + fingerprint
+	if len(fingerprint) > 8 {
+		name = "device " + fingerprint[:8]
+	}
+	device = &models.Device{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        name,
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if !trusted {
+		if err := s.devices.Create(ctx, device); err != nil {
+			return err
+		}
+		if s.audit != nil {
+			s.audit.Record(ctx, userID, models.AuditActionDeviceAdded, device.ID.String())
+		}
+		return nil
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("generate approval token: %w", err)
+	}
+	device.Pending = true
+	device.ApprovalToken = hex.EncodeToString(token)
+	if err := s.devices.Create(ctx, device); err != nil {
+		return err
+	}
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, models.AuditActionDevicePending, device.ID.String())
+	}
+	if s.notifier != nil {
+		s.notifier.DeviceApproval(ctx, userID, device.Name, device.ApprovalToken)
+	}
+	return ErrDevicePending
+}
+
+// Approve marks the user's pending device as trusted; approving an
+// already-trusted device is a no-op.
+func (s *DeviceService) Approve(ctx context.Context, userID, id uuid.UUID) error {
+	err := s.devices.Approve(ctx, userID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, models.AuditActionDeviceApproved, id.String())
+	}
+	return nil
+}
+
+// ApproveByToken marks the pending device holding the emailed approval
+// token as trusted.
+func (s *DeviceService) ApproveByToken(ctx context.Context, token string) error {
+	if token == "" {
+		return ErrNotFound
+	}
+	device, err := s.devices.ApproveByToken(ctx, token)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if s.audit != nil {
+		s.audit.Record(ctx, device.UserID, models.AuditActionDeviceApproved, device.ID.String())
+	}
+	return nil
+}
+
+// Revoke bars a device from syncing.
+func (s *DeviceService) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	err := s.devices.SetRevoked(ctx, userID, id, true)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, models.AuditActionDeviceRevoked, id.String())
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// EmergencyNotifier delivers out-of-band notices about emergency-access
+// events. Services hold it as an optional hook; a nil notifier drops the
+// notices.
+type EmergencyNotifier interface {
+	Notify(ctx context.Context, userID uuid.UUID, message string)
+}
+
+// EmergencyService lets a user designate trusted contacts who can request
+// access to the vault. A request starts a per-contact waiting period; the
+// owner can deny it at any point before the period ends, otherwise the
+// contact gains read access to the owner's secrets, re-encrypted for the
+// contact on each read like a share grant.
+type EmergencyService struct {
+	contacts repository.EmergencyRepository
+	users    repository.UserRepository
+	secrets  *SecretsService
+	audit    AuditRecorder
+	notifier EmergencyNotifier
+}
+
+// NewEmergencyService creates an EmergencyService. audit and notifier may
+// be nil.
+func NewEmergencyService(contacts repository.EmergencyRepository, users repository.UserRepository, secrets *SecretsService, audit AuditRecorder, notifier EmergencyNotifier) *EmergencyService {
+	return &EmergencyService{contacts: contacts, users: users, secrets: secrets, audit: audit, notifier: notifier}
+}
+
+func (s *EmergencyService) record(ctx context.Context, userID uuid.UUID, action, resource string) {
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, action, resource)
+	}
+}
+
+func (s *EmergencyService) notify(ctx context.Context, userID uuid.UUID, format string, args ...any) {
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, userID, fmt.Sprintf(format, args...))
+	}
+}
+
+// AddContact designates a registered user as the owner's emergency
+// contact, or changes the wait period of an existing designation. Changing
+// the designation cancels any pending request.
+func (s *EmergencyService) AddContact(ctx context.Context, ownerID uuid.UUID, login string, waitDays int) (*models.EmergencyContact, error) {
+	if waitDays < 1 {
+		return nil, fmt.Errorf("%w: wait period must be at least one day", ErrValidation)
+	}
+	contact, err := s.users.GetByLogin(ctx, login)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: user %q is not registered", ErrValidation, login)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if contact.ID == ownerID {
+		return nil, fmt.Errorf("%w: cannot be your own emergency contact", ErrValidation)
+	}
+	designation := &models.EmergencyContact{
+		OwnerID:   ownerID,
+		ContactID: contact.ID,
+		Login:     contact.Login,
+		WaitDays:  waitDays,
+		Status:    models.EmergencyStatusIdle,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.contacts.Upsert(ctx, designation); err != nil {
+		return nil, err
+	}
+	s.record(ctx, ownerID, models.AuditActionEmergencyContactAdded, contact.ID.String())
+	s.notify(ctx, contact.ID, "you were designated an emergency contact with a %d-day wait period", waitDays)
+	return designation, nil
+}
+
+// RemoveContact revokes a designation; any pending request dies with it.
+func (s *EmergencyService) RemoveContact(ctx context.Context, ownerID uuid.UUID, login string) error {
+	contact, err := s.users.GetByLogin(ctx, login)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: user %q is not registered", ErrValidation, login)
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.contacts.Delete(ctx, ownerID, contact.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	s.record(ctx, ownerID, models.AuditActionEmergencyContactRemoved, contact.ID.String())
+	return nil
+}
+
+// Contacts returns the owner's designations with contact logins and
+// request status resolved.
+func (s *EmergencyService) Contacts(ctx context.Context, ownerID uuid.UUID) ([]models.EmergencyContact, error) {
+	contacts, err := s.contacts.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for i := range contacts {
+		contacts[i].Status = status(&contacts[i], now)
+		if user, err := s.users.GetByID(ctx, contacts[i].ContactID); err == nil {
+			contacts[i].Login = user.Login
+		}
+	}
+	return contacts, nil
+}
+
+// Trusts returns the designations naming the caller as contact, with
+// owner logins and request status resolved.
+func (s *EmergencyService) Trusts(ctx context.Context, contactID uuid.UUID) ([]models.EmergencyContact, error) {
+	trusts, err := s.contacts.ListByContact(ctx, contactID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for i := range trusts {
+		trusts[i].Status = status(&trusts[i], now)
+		if user, err := s.users.GetByID(ctx, trusts[i].OwnerID); err == nil {
+			trusts[i].Login = user.Login
+		}
+	}
+	return trusts, nil
+}
+
+// RequestAccess starts the waiting period on a designation naming the
+// caller. Repeating a request does not restart the clock.
+func (s *EmergencyService) RequestAccess(ctx context.Context, contactID uuid.UUID, ownerLogin string) (*models.EmergencyContact, error) {
+	designation, err := s.designationFor(ctx, contactID, ownerLogin)
+	if err != nil {
+		return nil, err
+	}
+	if designation.RequestedAt == nil {
+		now := time.Now().UTC()
+		designation.RequestedAt = &now
+		if err := s.contacts.Upsert(ctx, designation); err != nil {
+			return nil, err
+		}
+		s.record(ctx, contactID, models.AuditActionEmergencyRequested, designation.OwnerID.String())
+		s.notify(ctx, designation.OwnerID,
+			"%s requested emergency access; deny within %d days to keep your vault locked",
+			ownerLogin, designation.WaitDays)
+	}
+	designation.Status = status(designation, time.Now().UTC())
+	designation.Login = ownerLogin
+	return designation, nil
+}
+
+// DenyAccess cancels a pending request on one of the owner's
+// designations. The designation itself stays in place.
+func (s *EmergencyService) DenyAccess(ctx context.Context, ownerID uuid.UUID, login string) error {
+	contact, err := s.users.GetByLogin(ctx, login)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: user %q is not registered", ErrValidation, login)
+	}
+	if err != nil {
+		return err
+	}
+	designation, err := s.contacts.Get(ctx, ownerID, contact.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if designation.RequestedAt == nil {
+		return fmt.Errorf("%w: no pending request from %q", ErrValidation, login)
+	}
+	designation.RequestedAt = nil
+	if err := s.contacts.Upsert(ctx, designation); err != nil {
+		return err
+	}
+	s.record(ctx, ownerID, models.AuditActionEmergencyDenied, contact.ID.String())
+	s.notify(ctx, contact.ID, "your emergency access request was denied")
+	return nil
+}
+
+// VaultSecrets returns the owner's decrypted secrets to a contact whose
+// waiting period has elapsed. Every read is audited on the owner's trail.
+func (s *EmergencyService) VaultSecrets(ctx context.Context, contactID uuid.UUID, ownerLogin string) ([]models.Secret, error) {
+	designation, err := s.designationFor(ctx, contactID, ownerLogin)
+	if err != nil {
+		return nil, err
+	}
+	if status(designation, time.Now().UTC()) != models.EmergencyStatusGranted {
+		return nil, fmt.Errorf("%w: access has not been granted", ErrValidation)
+	}
+	secrets, _, err := s.secrets.GetSecrets(ctx, designation.OwnerID, repository.SecretListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, designation.OwnerID, models.AuditActionEmergencyAccessed, contactID.String())
+	s.notify(ctx, designation.OwnerID, "your vault was read through emergency access")
+	return secrets, nil
+}
+
+// designationFor resolves the designation an owner login points at from
+// the contact's side, hiding its absence behind ErrNotFound.
+func (s *EmergencyService) designationFor(ctx context.Context, contactID uuid.UUID, ownerLogin string) (*models.EmergencyContact, error) {
+	owner, err := s.users.GetByLogin(ctx, ownerLogin)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	designation, err := s.contacts.Get(ctx, owner.ID, contactID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return designation, nil
+}
+
+// status derives the request state of a designation at a point in time.
+func status(c *models.EmergencyContact, now time.Time) string {
+	switch {
+	case c.RequestedAt == nil:
+		return models.EmergencyStatusIdle
+	case now.Before(c.AvailableAt()):
+		return models.EmergencyStatusRequested
+	default:
+		return models.EmergencyStatusGranted
+	}
+}
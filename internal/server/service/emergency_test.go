@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestEmergencyAccess(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("test jwt secret"), []byte("test master key"), nil)
+	secrets := newTestSecretsService()
+	contacts := memory.NewEmergencyRepository()
+	svc := NewEmergencyService(contacts, users, secrets, nil, nil)
+
+	for _, login := range []string{"alice", "bob"} {
+		if _, err := auth.Register(ctx, login, login+" password", ""); err != nil {
+			t.Fatalf("Register(%s): %v", login, err)
+		}
+	}
+	alice, _ := users.GetByLogin(ctx, "alice")
+	bob, _ := users.GetByLogin(ctx, "bob")
+	mustCreateCredentials(t, secrets, alice.ID, "vault password")
+
+	if _, err := svc.AddContact(ctx, alice.ID, "alice", 7); !errors.Is(err, ErrValidation) {
+		t.Fatalf("self-contact error = %v, want ErrValidation", err)
+	}
+	if _, err := svc.AddContact(ctx, alice.ID, "nobody", 7); !errors.Is(err, ErrValidation) {
+		t.Fatalf("unknown login error = %v, want ErrValidation", err)
+	}
+	if _, err := svc.AddContact(ctx, alice.ID, "bob", 0); !errors.Is(err, ErrValidation) {
+		t.Fatalf("zero wait error = %v, want ErrValidation", err)
+	}
+	if _, err := svc.AddContact(ctx, alice.ID, "bob", 2); err != nil {
+		t.Fatalf("AddContact: %v", err)
+	}
+	list, err := svc.Contacts(ctx, alice.ID)
+	if err != nil || len(list) != 1 || list[0].Login != "bob" || list[0].Status != models.EmergencyStatusIdle {
+		t.Fatalf("Contacts = %+v, %v; want one idle designation for bob", list, err)
+	}
+
+	// A fresh request starts the clock but grants nothing yet, and the
+	// owner can still shut it down.
+	designation, err := svc.RequestAccess(ctx, bob.ID, "alice")
+	if err != nil || designation.Status != models.EmergencyStatusRequested {
+		t.Fatalf("RequestAccess = %+v, %v; want requested", designation, err)
+	}
+	if _, err := svc.VaultSecrets(ctx, bob.ID, "alice"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("early vault read error = %v, want ErrValidation", err)
+	}
+	if err := svc.DenyAccess(ctx, alice.ID, "bob"); err != nil {
+		t.Fatalf("DenyAccess: %v", err)
+	}
+	if err := svc.DenyAccess(ctx, alice.ID, "bob"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("repeat deny error = %v, want ErrValidation", err)
+	}
+
+	// An undenied request unlocks the vault once the wait period elapses.
+	if _, err := svc.RequestAccess(ctx, bob.ID, "alice"); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	aged, err := contacts.Get(ctx, alice.ID, bob.ID)
+	if err != nil {
+		t.Fatalf("Get designation: %v", err)
+	}
+	past := time.Now().UTC().Add(-3 * 24 * time.Hour)
+	aged.RequestedAt = &past
+	if err := contacts.Upsert(ctx, aged); err != nil {
+		t.Fatalf("age request: %v", err)
+	}
+	vault, err := svc.VaultSecrets(ctx, bob.ID, "alice")
+	if err != nil || len(vault) != 1 {
+		t.Fatalf("VaultSecrets = %d secrets, %v; want 1", len(vault), err)
+	}
+	var payload models.CredentialsPayload
+	if err := vault[0].DecodePayload(&payload); err != nil || payload.Password != "vault password" {
+		t.Fatalf("vault payload = %+v, %v; want decrypted password", payload, err)
+	}
+	trusts, err := svc.Trusts(ctx, bob.ID)
+	if err != nil || len(trusts) != 1 || trusts[0].Login != "alice" || trusts[0].Status != models.EmergencyStatusGranted {
+		t.Fatalf("Trusts = %+v, %v; want one granted designation from alice", trusts, err)
+	}
+
+	// Removing the designation cuts access and kills the pending request.
+	if err := svc.RemoveContact(ctx, alice.ID, "bob"); err != nil {
+		t.Fatalf("RemoveContact: %v", err)
+	}
+	if _, err := svc.VaultSecrets(ctx, bob.ID, "alice"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("revoked vault read error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.RequestAccess(ctx, bob.ID, "alice"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("revoked request error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// Identity nonce bounds: long enough to be unguessable, short enough not
+// to be a free signing oracle for bulk data.
+const (
+	identityNonceMin = 16
+	identityNonceMax = 64
+)
+
+// IdentityService proves the server's identity to clients that pin it.
+// The keypair is derived deterministically from the master key, so every
+// instance sharing a key (and its data) presents the same identity.
+type IdentityService struct {
+	key ed25519.PrivateKey
+}
+
+// NewIdentityService derives the identity keypair from the master key.
+func NewIdentityService(masterKey []byte) *IdentityService {
+	seed := crypto.DeriveKey(masterKey, []byte("server-identity"))
+	return &IdentityService{key: ed25519.NewKeyFromSeed(seed)}
+}
+
+// Sign returns the public key and a signature over the client's nonce, so
+// the client knows it is talking to the holder of the private key and not
+// a replay.
+func (s *IdentityService) Sign(nonce []byte) (publicKey, signature []byte, err error) {
+	if len(nonce) < identityNonceMin || len(nonce) > identityNonceMax {
+		return nil, nil, fmt.Errorf("%w: nonce must be %d to %d bytes", ErrValidation, identityNonceMin, identityNonceMax)
+	}
+	return s.key.Public().(ed25519.PublicKey), ed25519.Sign(s.key, nonce), nil
+}
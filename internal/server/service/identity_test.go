@@ -0,0 +1,46 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+)
+
+func TestIdentitySignAndVerify(t *testing.T) {
+	svc := NewIdentityService([]byte("test master key"))
+	nonce := []byte("0123456789abcdef0123456789abcdef")
+
+	publicKey, signature, err := svc.Sign(nonce)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if !ed25519.Verify(publicKey, nonce, signature) {
+		t.Error("signature does not verify")
+	}
+
+	// The identity must be stable across restarts with the same master key.
+	again, _, err := NewIdentityService([]byte("test master key")).Sign(nonce)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if string(again) != string(publicKey) {
+		t.Error("identity differs between instances with the same master key")
+	}
+
+	other, _, err := NewIdentityService([]byte("another master key")).Sign(nonce)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if string(other) == string(publicKey) {
+		t.Error("different master keys produced the same identity")
+	}
+}
+
+func TestIdentityRejectsBadNonce(t *testing.T) {
+	svc := NewIdentityService([]byte("test master key"))
+	for _, nonce := range [][]byte{nil, []byte("short"), make([]byte, identityNonceMax+1)} {
+		if _, _, err := svc.Sign(nonce); !errors.Is(err, ErrValidation) {
+			t.Errorf("Sign(%d bytes) = %v, want ErrValidation", len(nonce), err)
+		}
+	}
+}
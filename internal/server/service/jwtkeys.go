@@ -0,0 +1,191 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtKey is one entry of the keyring: a shared HMAC secret or an
+// asymmetric key pair.
+type jwtKey struct {
+	kid    string
+	method jwt.SigningMethod
+	sign   any
+	verify any
+}
+
+// JWTKeyring holds the active JWT signing keys. New tokens are signed
+// with the newest key; tokens signed with any older key on the ring keep
+// verifying until they expire, so keys rotate without logging everyone
+// out. A ring can mix shared secrets and asymmetric key pairs, e.g.
+// while a deployment migrates from HS256 to RS256 or EdDSA.
+type JWTKeyring struct {
+	// keys preserves the configured order, newest first; the first
+	// entry signs.
+	keys  []jwtKey
+	byKid map[string]jwtKey
+}
+
+// ParseJWTKeys builds a keyring from the JWT secret setting: either a
+// single bare secret, or a comma-separated list of kid=secret pairs with
+// the newest first. To rotate, prepend a new pair and redeploy; drop the
+// old pair once every token signed with it has expired.
+func ParseJWTKeys(value string) (*JWTKeyring, error) {
+	k := &JWTKeyring{byKid: make(map[string]jwtKey)}
+	if value == "" {
+		return k, nil
+	}
+	if !strings.Contains(value, "=") {
+		// A bare secret is a one-key ring without a key ID, matching
+		// tokens issued before rotation was configured.
+		k.add(jwtKey{method: jwt.SigningMethodHS256, sign: []byte(value), verify: []byte(value)})
+		return k, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		kid, secret, ok := strings.Cut(pair, "=")
+		kid = strings.TrimSpace(kid)
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("jwt keys: entry %q is not kid=secret", pair)
+		}
+		if _, dup := k.byKid[kid]; dup {
+			return nil, fmt.Errorf("jwt keys: duplicate key id %q", kid)
+		}
+		k.add(jwtKey{kid: kid, method: jwt.SigningMethodHS256, sign: []byte(secret), verify: []byte(secret)})
+	}
+	return k, nil
+}
+
+// newJWTKeyring wraps a single kid-less secret, the pre-rotation setup.
+func newJWTKeyring(secret []byte) *JWTKeyring {
+	k := &JWTKeyring{byKid: make(map[string]jwtKey)}
+	k.add(jwtKey{method: jwt.SigningMethodHS256, sign: secret, verify: secret})
+	return k
+}
+
+func (k *JWTKeyring) add(key jwtKey) {
+	k.keys = append(k.keys, key)
+	k.byKid[key.kid] = key
+}
+
+// AddKeyPair prepends a PEM-encoded RSA or Ed25519 private key, making
+// it the signing key; the remaining entries keep verifying existing
+// tokens. The key ID is derived from the public key, so the JWKS entry
+// and the kid token header always agree.
+func (k *JWTKeyring) AddKeyPair(pemData []byte) error {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return errors.New("jwt keys: no PEM block in private key")
+	}
+	var private any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return fmt.Errorf("jwt keys: parse private key: %w", err)
+	}
+	var key jwtKey
+	switch private := private.(type) {
+	case *rsa.PrivateKey:
+		key = jwtKey{method: jwt.SigningMethodRS256, sign: private, verify: private.Public()}
+	case ed25519.PrivateKey:
+		key = jwtKey{method: jwt.SigningMethodEdDSA, sign: private, verify: private.Public()}
+	default:
+		return fmt.Errorf("jwt keys: unsupported key type %T", private)
+	}
+	kid, err := publicKID(key.verify)
+	if err != nil {
+		return err
+	}
+	key.kid = kid
+	if _, dup := k.byKid[kid]; dup {
+		return fmt.Errorf("jwt keys: duplicate key id %q", kid)
+	}
+	k.keys = append([]jwtKey{key}, k.keys...)
+	k.byKid[kid] = key
+	return nil
+}
+
+// publicKID derives a stable key ID from a public key: the truncated
+// SHA-256 of its DER encoding.
+func publicKID(public any) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return "", fmt.Errorf("jwt keys: encode public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// signer returns the key new tokens are signed with: the first on the
+// ring.
+func (k *JWTKeyring) signer() jwtKey { return k.keys[0] }
+
+// key returns the entry of one key ID.
+func (k *JWTKeyring) key(kid string) (jwtKey, bool) {
+	key, ok := k.byKid[kid]
+	return key, ok
+}
+
+// verificationKeys returns every shared secret on the ring, for tokens
+// that carry no key ID: those all predate asymmetric signing, which
+// always stamps one.
+func (k *JWTKeyring) verificationKeys() jwt.VerificationKeySet {
+	set := jwt.VerificationKeySet{Keys: make([]jwt.VerificationKey, 0, len(k.keys))}
+	for _, key := range k.keys {
+		if _, ok := key.method.(*jwt.SigningMethodHMAC); ok {
+			set.Keys = append(set.Keys, key.verify)
+		}
+	}
+	return set
+}
+
+// JWK is one public signing key in JSON Web Key form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS returns the public keys of the ring, for the JWKS endpoint.
+// Shared secrets are never included, so a ring of only HMAC keys yields
+// an empty set.
+func (k *JWTKeyring) JWKS() []JWK {
+	jwks := []JWK{}
+	for _, key := range k.keys {
+		switch public := key.verify.(type) {
+		case *rsa.PublicKey:
+			jwks = append(jwks, JWK{
+				Kty: "RSA", Kid: key.kid, Alg: key.method.Alg(), Use: "sig",
+				N: base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks = append(jwks, JWK{
+				Kty: "OKP", Kid: key.kid, Alg: key.method.Alg(), Use: "sig",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+	return jwks
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// MergeService folds one account into another: every secret and attachment
+// of the source is re-encrypted for the target's key and reassigned, after
+// which the source account is deactivated. Both passwords are required, so
+// only someone who owns both accounts can merge them.
+type MergeService struct {
+	users   repository.UserRepository
+	secrets *SecretsService
+	tm      repository.TransactionManager
+	audit   AuditRecorder
+}
+
+// NewMergeService creates a MergeService. Audit is optional.
+func NewMergeService(users repository.UserRepository, secrets *SecretsService, tm repository.TransactionManager, audit AuditRecorder) *MergeService {
+	return &MergeService{users: users, secrets: secrets, tm: tm, audit: audit}
+}
+
+// Merge moves everything from the account named by sourceLogin into the
+// target account and deactivates the source. It returns how many secrets
+// moved. Version history stays with the source and is not carried over.
+func (s *MergeService) Merge(ctx context.Context, targetID uuid.UUID, targetPassword, sourceLogin, sourcePassword string) (int, error) {
+	target, err := s.verify(ctx, targetID, targetPassword)
+	if err != nil {
+		return 0, err
+	}
+	source, err := s.users.GetByLogin(ctx, sourceLogin)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, ErrInvalidCredentials
+		}
+		return 0, fmt.Errorf("get source user: %w", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(source.PasswordHash), []byte(sourcePassword)) != nil {
+		return 0, ErrInvalidCredentials
+	}
+	if source.ID == target.ID {
+		return 0, fmt.Errorf("%w: cannot merge an account into itself", ErrValidation)
+	}
+	if source.Disabled {
+		return 0, fmt.Errorf("%w: source account is already deactivated", ErrValidation)
+	}
+
+	// Decrypted up front: inside the transaction the payloads only need
+	// re-encrypting, not another round-trip through the blob store.
+	secrets, failed, err := s.secrets.GetSecrets(ctx, source.ID, repository.SecretListOptions{IncludeArchived: true})
+	if err != nil {
+		return 0, err
+	}
+	// Merging deactivates the source account, so leaving undecryptable
+	// secrets behind would bury them. Refuse instead.
+	if len(failed) > 0 {
+		return 0, fmt.Errorf("%d secrets of the source account cannot be decrypted, merge aborted", len(failed))
+	}
+
+	err = s.tm.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i := range secrets {
+			if err := s.moveSecret(ctx, &secrets[i], target.ID); err != nil {
+				return err
+			}
+		}
+		return s.users.SetDisabled(ctx, source.ID, true)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if s.audit != nil {
+		s.audit.Record(ctx, target.ID, models.AuditActionAccountMerged, source.ID.String())
+		s.audit.Record(ctx, source.ID, models.AuditActionAccountMerged, target.ID.String())
+	}
+	return len(secrets), nil
+}
+
+// verify authenticates the calling user by password.
+func (s *MergeService) verify(ctx context.Context, userID uuid.UUID, password string) (*models.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// moveSecret reassigns one decrypted secret and its attachments to the
+// target, re-encrypted for the target's key.
+func (s *MergeService) moveSecret(ctx context.Context, secret *models.Secret, targetID uuid.UUID) error {
+	oldRef := secret.BlobRef
+	stored := *secret
+	stored.UserID = targetID
+	if err := s.secrets.encrypt(&stored); err != nil {
+		return err
+	}
+	if err := s.secrets.offloadBlob(ctx, &stored); err != nil {
+		return err
+	}
+	if err := s.secrets.secrets.Reassign(ctx, secret.ID, secret.UserID, targetID, stored.Data, stored.BlobRef); err != nil {
+		s.secrets.discardBlob(ctx, stored.BlobRef)
+		return err
+	}
+	if oldRef != stored.BlobRef {
+		s.secrets.discardBlob(ctx, oldRef)
+	}
+	// Reassign writes only the payload; sealed name and metadata must be
+	// re-keyed for the new owner separately.
+	moved := *secret
+	moved.UserID = targetID
+	if err := s.secrets.persistMeta(ctx, &moved); err != nil {
+		return err
+	}
+	return s.moveAttachments(ctx, secret.UserID, secret.ID, targetID)
+}
+
+// moveAttachments re-encrypts and reassigns the attachments of one secret.
+func (s *MergeService) moveAttachments(ctx context.Context, sourceID, secretID, targetID uuid.UUID) error {
+	if s.secrets.attachments == nil {
+		return nil
+	}
+	attachments, err := s.secrets.attachments.ListBySecret(ctx, sourceID, secretID)
+	if err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		full, err := s.secrets.attachments.GetByID(ctx, sourceID, a.ID)
+		if err != nil {
+			return err
+		}
+		content, err := crypto.Decrypt(s.secrets.userKey(sourceID), full.Content)
+		if err != nil {
+			return fmt.Errorf("decrypt attachment %s: %w", a.ID, err)
+		}
+		reencrypted, err := crypto.Encrypt(s.secrets.userKey(targetID), content)
+		if err != nil {
+			return fmt.Errorf("encrypt attachment %s: %w", a.ID, err)
+		}
+		if err := s.secrets.attachments.Reassign(ctx, a.ID, sourceID, targetID, reencrypted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
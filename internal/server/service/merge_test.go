@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestMergeAccounts(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("test jwt secret"), []byte("test master key"), nil)
+	secrets := NewSecretsService(SecretsServiceDeps{
+		Secrets:     memory.NewSecretRepository(),
+		Attachments: memory.NewAttachmentRepository(),
+		TM:          repository.NoopTransactionManager{},
+		MasterKey:   []byte("test master key"),
+	})
+	svc := NewMergeService(users, secrets, repository.NoopTransactionManager{}, nil)
+
+	for _, login := range []string{"target", "source"} {
+		if _, err := auth.Register(ctx, login, login+" password", ""); err != nil {
+			t.Fatalf("Register(%s): %v", login, err)
+		}
+	}
+	target, _ := users.GetByLogin(ctx, "target")
+	source, _ := users.GetByLogin(ctx, "source")
+
+	secret := &models.Secret{
+		UserID: source.ID,
+		Name:   "shared note",
+		Type:   models.SecretTypeText,
+		Data:   []byte(`{"text":"moved"}`),
+	}
+	if err := secrets.CreateSecret(ctx, secret); err != nil {
+		t.Fatalf("CreateSecret: %v", err)
+	}
+	if _, err := secrets.AddAttachment(ctx, source.ID, secret.ID, "note.txt", []byte("attached")); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	if _, err := svc.Merge(ctx, target.ID, "wrong", "source", "source password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Merge with wrong target password = %v, want ErrInvalidCredentials", err)
+	}
+
+	moved, err := svc.Merge(ctx, target.ID, "target password", "source", "source password")
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if moved != 1 {
+		t.Errorf("Merge moved %d secrets, want 1", moved)
+	}
+
+	// The secret decrypts under the target's key now, attachment included.
+	got, err := secrets.GetSecret(ctx, target.ID, secret.ID)
+	if err != nil {
+		t.Fatalf("GetSecret as target: %v", err)
+	}
+	if string(got.Data) != `{"text":"moved"}` {
+		t.Errorf("moved secret data = %s", got.Data)
+	}
+	attachments, err := secrets.ListAttachments(ctx, target.ID, secret.ID)
+	if err != nil || len(attachments) != 1 {
+		t.Fatalf("ListAttachments as target = %v, %v", attachments, err)
+	}
+	full, err := secrets.GetAttachment(ctx, target.ID, attachments[0].ID)
+	if err != nil {
+		t.Fatalf("GetAttachment as target: %v", err)
+	}
+	if string(full.Content) != "attached" {
+		t.Errorf("moved attachment content = %s", full.Content)
+	}
+
+	// The source account is deactivated and can no longer log in.
+	if _, err := auth.Login(ctx, "source", "source password", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Login on merged source = %v, want ErrInvalidCredentials", err)
+	}
+	if _, err := svc.Merge(ctx, target.ID, "target password", "source", "source password"); !errors.Is(err, ErrValidation) {
+		t.Errorf("second Merge = %v, want ErrValidation", err)
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/notify"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// Notification kinds a user can opt out of individually.
+const (
+	NotifyNewDevice    = "new_device"
+	NotifyFailedLogins = "failed_logins"
+	NotifyEmergency    = "emergency"
+)
+
+// notifyKinds is the set of valid preference kinds.
+var notifyKinds = map[string]bool{
+	NotifyNewDevice:    true,
+	NotifyFailedLogins: true,
+	NotifyEmergency:    true,
+}
+
+// Failed-login burst detection: once a user accumulates
+// failedLoginBurstSize failures within failedLoginBurstWindow, one alert
+// goes out and the window restarts, so a password-guessing run produces a
+// single message rather than one per attempt.
+const (
+	failedLoginBurstSize   = 5
+	failedLoginBurstWindow = 15 * time.Minute
+)
+
+// NotificationService sends security alerts — new sync devices, bursts of
+// failed logins, emergency-access activity — to the account's email
+// address through the configured providers. Users without an email or
+// with the kind disabled in their preferences are silently skipped, and a
+// provider failure is only logged: alerts are best effort and never fail
+// the operation that triggered them.
+type NotificationService struct {
+	users     repository.UserRepository
+	providers []notify.Provider
+
+	mu       sync.Mutex
+	failures map[uuid.UUID][]time.Time
+}
+
+// NewNotificationService creates a NotificationService. With no providers
+// it still serves preferences but sends nothing.
+func NewNotificationService(users repository.UserRepository, providers ...notify.Provider) *NotificationService {
+	return &NotificationService{
+		users:     users,
+		providers: providers,
+		failures:  make(map[uuid.UUID][]time.Time),
+	}
+}
+
+// Preferences returns the notification kinds the user opted out of.
+func (s *NotificationService) Preferences(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	disabled, err := s.users.GetNotifyDisabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if disabled == nil {
+		disabled = []string{}
+	}
+	return disabled, nil
+}
+
+// SetPreferences replaces the notification kinds the user opted out of;
+// an empty list re-enables everything.
+func (s *NotificationService) SetPreferences(ctx context.Context, userID uuid.UUID, disabled []string) error {
+	for _, kind := range disabled {
+		if !notifyKinds[kind] {
+			return fmt.Errorf("%w: unknown notification kind %q", ErrValidation, kind)
+		}
+	}
+	err := s.users.SetNotifyDisabled(ctx, userID, disabled)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// NewDevice alerts the user that a device was registered for sync.
+func (s *NotificationService) NewDevice(ctx context.Context, userID uuid.UUID, name, platform string) {
+	body := fmt.Sprintf("A new device %q (%s) was registered on your account. If this was not you, revoke it and change your password.", name, platform)
+	s.send(ctx, userID, NotifyNewDevice, "New device registered", body)
+}
+
+// DeviceApproval asks the user to approve a pending device. It ignores
+// the notification preferences: suppressing it would lock the user out of
+// approving new devices by email.
+func (s *NotificationService) DeviceApproval(ctx context.Context, userID uuid.UUID, name, token string) {
+	body := fmt.Sprintf("A sign-in from an unrecognized device %q needs your approval. Approve it from a trusted device, or open /device-approval/%s on your server. If this was not you, change your password.", name, token)
+	s.send(ctx, userID, "", "Approve a new device", body)
+}
+
+// LoginFailed records one failed sign-in attempt and alerts the user when
+// the attempts form a burst.
+func (s *NotificationService) LoginFailed(ctx context.Context, userID uuid.UUID) {
+	now := time.Now()
+	s.mu.Lock()
+	recent := s.failures[userID][:0]
+	for _, at := range s.failures[userID] {
+		if now.Sub(at) < failedLoginBurstWindow {
+			recent = append(recent, at)
+		}
+	}
+	recent = append(recent, now)
+	burst := len(recent) >= failedLoginBurstSize
+	if burst {
+		// Restart the window so the next alert needs a fresh burst.
+		recent = recent[:0]
+	}
+	s.failures[userID] = recent
+	s.mu.Unlock()
+	if !burst {
+		return
+	}
+	body := fmt.Sprintf("There were %d failed sign-in attempts on your account in the last %v. If this was not you, consider changing your password.", failedLoginBurstSize, failedLoginBurstWindow)
+	s.send(ctx, userID, NotifyFailedLogins, "Failed sign-in attempts", body)
+}
+
+// Notify implements EmergencyNotifier: emergency-access notices reuse the
+// message the emergency service already formats.
+func (s *NotificationService) Notify(ctx context.Context, userID uuid.UUID, message string) {
+	s.send(ctx, userID, NotifyEmergency, "Emergency access", message)
+}
+
+// send delivers one alert through every provider, honouring the user's
+// preferences; an empty kind cannot be opted out of. Errors are logged,
+// never returned: no alert is worth failing a login or device
+// registration over.
+func (s *NotificationService) send(ctx context.Context, userID uuid.UUID, kind, subject, body string) {
+	if len(s.providers) == 0 {
+		return
+	}
+	if kind != "" {
+		disabled, err := s.users.GetNotifyDisabled(ctx, userID)
+		if err != nil {
+			slog.WarnContext(ctx, "notify: load preferences", "user", userID, "err", err)
+			return
+		}
+		for _, d := range disabled {
+			if d == kind {
+				return
+			}
+		}
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		slog.WarnContext(ctx, "notify: load user", "user", userID, "err", err)
+		return
+	}
+	if user.Email == "" {
+		return
+	}
+	msg := notify.Message{To: user.Email, Subject: "gophkeeper: " + subject, Body: body}
+	for _, p := range s.providers {
+		if err := p.Send(ctx, msg); err != nil {
+			slog.WarnContext(ctx, "notify: send", "kind", kind, "user", userID, "err", err)
+		}
+	}
+}
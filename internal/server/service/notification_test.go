@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/notify"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+// capturingProvider collects the messages it was asked to send.
+type capturingProvider struct {
+	messages []notify.Message
+}
+
+func (p *capturingProvider) Send(_ context.Context, msg notify.Message) error {
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func newNotifyUser(t *testing.T, users *memory.UserRepository, email string) uuid.UUID {
+	t.Helper()
+	user := &models.User{
+		ID:        uuid.New(),
+		Login:     uuid.NewString(),
+		Email:     email,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := users.Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user.ID
+}
+
+func TestFailedLoginBurstAlertsOnce(t *testing.T) {
+	users := memory.NewUserRepository()
+	provider := &capturingProvider{}
+	svc := NewNotificationService(users, provider)
+	ctx := context.Background()
+	userID := newNotifyUser(t, users, "user@example.com")
+
+	for i := 0; i < failedLoginBurstSize-1; i++ {
+		svc.LoginFailed(ctx, userID)
+	}
+	if len(provider.messages) != 0 {
+		t.Fatalf("alerted after %d failures, want none before %d", failedLoginBurstSize-1, failedLoginBurstSize)
+	}
+	svc.LoginFailed(ctx, userID)
+	if len(provider.messages) != 1 {
+		t.Fatalf("got %d alerts after the burst, want 1", len(provider.messages))
+	}
+	if provider.messages[0].To != "user@example.com" {
+		t.Errorf("alert addressed to %q, want the account email", provider.messages[0].To)
+	}
+	// The window restarts after an alert: one more failure stays quiet.
+	svc.LoginFailed(ctx, userID)
+	if len(provider.messages) != 1 {
+		t.Fatalf("got %d alerts, want the window to restart after the first", len(provider.messages))
+	}
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	users := memory.NewUserRepository()
+	provider := &capturingProvider{}
+	svc := NewNotificationService(users, provider)
+	ctx := context.Background()
+	userID := newNotifyUser(t, users, "user@example.com")
+
+	if err := svc.SetPreferences(ctx, userID, []string{"carrier_pigeon"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("SetPreferences with unknown kind = %v, want ErrValidation", err)
+	}
+	if err := svc.SetPreferences(ctx, userID, []string{NotifyNewDevice}); err != nil {
+		t.Fatalf("SetPreferences: %v", err)
+	}
+	disabled, err := svc.Preferences(ctx, userID)
+	if err != nil {
+		t.Fatalf("Preferences: %v", err)
+	}
+	if len(disabled) != 1 || disabled[0] != NotifyNewDevice {
+		t.Fatalf("Preferences = %v, want [%s]", disabled, NotifyNewDevice)
+	}
+
+	// The disabled kind is suppressed; others still go out.
+	svc.NewDevice(ctx, userID, "laptop", "linux")
+	if len(provider.messages) != 0 {
+		t.Fatalf("disabled kind was delivered")
+	}
+	svc.Notify(ctx, userID, "emergency access requested")
+	if len(provider.messages) != 1 {
+		t.Fatalf("got %d alerts, want the emergency notice delivered", len(provider.messages))
+	}
+}
+
+func TestNotificationSkipsUsersWithoutEmail(t *testing.T) {
+	users := memory.NewUserRepository()
+	provider := &capturingProvider{}
+	svc := NewNotificationService(users, provider)
+	ctx := context.Background()
+	userID := newNotifyUser(t, users, "")
+
+	svc.NewDevice(ctx, userID, "laptop", "linux")
+	if len(provider.messages) != 0 {
+		t.Fatalf("alerted a user without an email address")
+	}
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// OrgService manages organizations: shared vaults whose secrets are
+// encrypted under a per-organization key so every member can read them.
+// Secrets enter and leave an org by moving, never by copying; a secret is
+// in exactly one vault at any time. Non-members get ErrNotFound for
+// everything org-scoped, so org IDs leak nothing.
+type OrgService struct {
+	orgs    repository.OrgRepository
+	users   repository.UserRepository
+	secrets *SecretsService
+	audit   AuditRecorder
+}
+
+// NewOrgService creates an OrgService. audit may be nil.
+func NewOrgService(orgs repository.OrgRepository, users repository.UserRepository, secrets *SecretsService, audit AuditRecorder) *OrgService {
+	return &OrgService{orgs: orgs, users: users, secrets: secrets, audit: audit}
+}
+
+func (s *OrgService) record(ctx context.Context, userID uuid.UUID, action, resource string) {
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, action, resource)
+	}
+}
+
+// requireMember resolves the caller's membership in an org, hiding the
+// org's existence from non-members behind ErrNotFound.
+func (s *OrgService) requireMember(ctx context.Context, orgID, userID uuid.UUID) (*models.OrgMember, error) {
+	member, err := s.orgs.GetMember(ctx, orgID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// CreateOrg creates an organization with the caller as its owner.
+func (s *OrgService) CreateOrg(ctx context.Context, userID uuid.UUID, name string) (*models.Organization, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	now := time.Now().UTC()
+	org := &models.Organization{ID: uuid.New(), Name: name, CreatedAt: now}
+	if err := s.orgs.Create(ctx, org); err != nil {
+		return nil, err
+	}
+	if err := s.orgs.AddMember(ctx, &models.OrgMember{
+		OrgID:     org.ID,
+		UserID:    userID,
+		Role:      models.OrgRoleOwner,
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+	s.record(ctx, userID, models.AuditActionOrgCreated, org.ID.String())
+	return org, nil
+}
+
+// ListOrgs returns the organizations the caller is a member of.
+func (s *OrgService) ListOrgs(ctx context.Context, userID uuid.UUID) ([]models.Organization, error) {
+	return s.orgs.ListByUser(ctx, userID)
+}
+
+// InviteMember adds a registered user to an org by login. Only the owner
+// can invite.
+func (s *OrgService) InviteMember(ctx context.Context, actorID, orgID uuid.UUID, login string) (*models.OrgMember, error) {
+	actor, err := s.requireMember(ctx, orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Role != models.OrgRoleOwner {
+		return nil, fmt.Errorf("%w: only the org owner can invite members", ErrValidation)
+	}
+	user, err := s.users.GetByLogin(ctx, login)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: user %q is not registered", ErrValidation, login)
+	}
+	if err != nil {
+		return nil, err
+	}
+	member := &models.OrgMember{
+		OrgID:     orgID,
+		UserID:    user.ID,
+		Login:     user.Login,
+		Role:      models.OrgRoleMember,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.orgs.AddMember(ctx, member); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, fmt.Errorf("%w: user %q is already a member", ErrValidation, login)
+		}
+		return nil, err
+	}
+	s.record(ctx, actorID, models.AuditActionOrgMemberAdded, user.ID.String())
+	return member, nil
+}
+
+// ListMembers returns the members of an org with their logins resolved.
+func (s *OrgService) ListMembers(ctx context.Context, actorID, orgID uuid.UUID) ([]models.OrgMember, error) {
+	if _, err := s.requireMember(ctx, orgID, actorID); err != nil {
+		return nil, err
+	}
+	members, err := s.orgs.ListMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range members {
+		user, err := s.users.GetByID(ctx, members[i].UserID)
+		if err != nil {
+			// A deleted account leaves its membership behind; show the
+			// entry without a login rather than failing the listing.
+			continue
+		}
+		members[i].Login = user.Login
+	}
+	return members, nil
+}
+
+// ListOrgSecrets returns the decrypted secrets of an org vault.
+func (s *OrgService) ListOrgSecrets(ctx context.Context, userID, orgID uuid.UUID) ([]models.Secret, error) {
+	if _, err := s.requireMember(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	secrets, err := s.secrets.secrets.GetByOrg(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := secrets[:0]
+	for i := range secrets {
+		if err := s.secrets.decrypt(ctx, &secrets[i]); err != nil {
+			slog.WarnContext(ctx, "skipping org secret", "secret", secrets[i].ID, "err", err)
+			continue
+		}
+		decrypted = append(decrypted, secrets[i])
+	}
+	return decrypted, nil
+}
+
+// MoveSecretToOrg moves one of the caller's personal secrets into an org
+// vault, re-encrypting the payload under the org key. Attachments stay
+// encrypted under the owner's key, so a secret must shed them before it
+// can be shared.
+func (s *OrgService) MoveSecretToOrg(ctx context.Context, userID, orgID, secretID uuid.UUID) error {
+	if _, err := s.requireMember(ctx, orgID, userID); err != nil {
+		return err
+	}
+	if s.secrets.attachments != nil {
+		attachments, err := s.secrets.attachments.ListBySecret(ctx, userID, secretID)
+		if err != nil {
+			return err
+		}
+		if len(attachments) > 0 {
+			return fmt.Errorf("%w: secret has attachments, remove them before moving it to an org", ErrValidation)
+		}
+	}
+	secret, err := s.secrets.GetSecret(ctx, userID, secretID)
+	if err != nil {
+		return err
+	}
+	secret.OrgID = orgID
+	if err := s.moveEncrypted(ctx, secret, func(ctx context.Context, stored *models.Secret) error {
+		return s.secrets.secrets.AttachToOrg(ctx, secretID, userID, orgID, stored.Data, stored.BlobRef)
+	}); err != nil {
+		return err
+	}
+	s.record(ctx, userID, models.AuditActionOrgSecretMoved, secretID.String())
+	return nil
+}
+
+// MoveSecretToPersonal moves an org secret into the caller's personal
+// vault, re-encrypting the payload under their key.
+func (s *OrgService) MoveSecretToPersonal(ctx context.Context, userID, orgID, secretID uuid.UUID) error {
+	if _, err := s.requireMember(ctx, orgID, userID); err != nil {
+		return err
+	}
+	secret, err := s.secrets.secrets.GetByOrgAndID(ctx, orgID, secretID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if err := s.secrets.decrypt(ctx, secret); err != nil {
+		return err
+	}
+	secret.OrgID = uuid.Nil
+	secret.UserID = userID
+	if err := s.moveEncrypted(ctx, secret, func(ctx context.Context, stored *models.Secret) error {
+		return s.secrets.secrets.DetachFromOrg(ctx, secretID, orgID, userID, stored.Data, stored.BlobRef)
+	}); err != nil {
+		return err
+	}
+	s.record(ctx, userID, models.AuditActionOrgSecretTaken, secretID.String())
+	return nil
+}
+
+// moveEncrypted re-encrypts a decrypted secret for its new vault, offloads
+// an oversized payload and persists the move with save, cleaning up
+// whichever blob ends up orphaned.
+func (s *OrgService) moveEncrypted(ctx context.Context, secret *models.Secret, save func(context.Context, *models.Secret) error) error {
+	oldRef := secret.BlobRef
+	stored := *secret
+	if err := s.secrets.encrypt(&stored); err != nil {
+		return err
+	}
+	if err := s.secrets.offloadBlob(ctx, &stored); err != nil {
+		return err
+	}
+	if err := save(ctx, &stored); err != nil {
+		s.secrets.discardBlob(ctx, stored.BlobRef)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if oldRef != stored.BlobRef {
+		s.secrets.discardBlob(ctx, oldRef)
+	}
+	// The move writes only the payload; sealed name and metadata must be
+	// re-keyed for the destination vault separately.
+	return s.secrets.persistMeta(ctx, secret)
+}
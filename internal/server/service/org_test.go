@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestOrgSharedVault(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("test jwt secret"), []byte("test master key"), nil)
+	secrets := newTestSecretsService()
+	svc := NewOrgService(memory.NewOrgRepository(), users, secrets, nil)
+
+	for _, login := range []string{"alice", "bob"} {
+		if _, err := auth.Register(ctx, login, login+" password", ""); err != nil {
+			t.Fatalf("Register(%s): %v", login, err)
+		}
+	}
+	alice, _ := users.GetByLogin(ctx, "alice")
+	bob, _ := users.GetByLogin(ctx, "bob")
+
+	org, err := svc.CreateOrg(ctx, alice.ID, "platform team")
+	if err != nil {
+		t.Fatalf("CreateOrg: %v", err)
+	}
+	if _, err := svc.CreateOrg(ctx, alice.ID, ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("empty name error = %v, want ErrValidation", err)
+	}
+
+	// Bob cannot see the org or its vault before being invited.
+	if _, err := svc.ListMembers(ctx, bob.ID, org.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("non-member ListMembers error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.ListOrgSecrets(ctx, bob.ID, org.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("non-member ListOrgSecrets error = %v, want ErrNotFound", err)
+	}
+
+	if _, err := svc.InviteMember(ctx, alice.ID, org.ID, "bob"); err != nil {
+		t.Fatalf("InviteMember: %v", err)
+	}
+	if _, err := svc.InviteMember(ctx, alice.ID, org.ID, "bob"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("duplicate invite error = %v, want ErrValidation", err)
+	}
+	if _, err := svc.InviteMember(ctx, alice.ID, org.ID, "nobody"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("unknown login invite error = %v, want ErrValidation", err)
+	}
+	if _, err := svc.InviteMember(ctx, bob.ID, org.ID, "alice"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("member invite error = %v, want ErrValidation", err)
+	}
+
+	members, err := svc.ListMembers(ctx, bob.ID, org.ID)
+	if err != nil || len(members) != 2 {
+		t.Fatalf("ListMembers = %v, %v; want 2 members", members, err)
+	}
+	if members[0].Login != "alice" || members[0].Role != models.OrgRoleOwner {
+		t.Fatalf("first member = %+v, want owner alice", members[0])
+	}
+
+	// Alice moves a personal secret into the org; Bob can read it there,
+	// and it leaves Alice's personal vault.
+	secret := mustCreateCredentials(t, secrets, alice.ID, "shared password")
+	if err := svc.MoveSecretToOrg(ctx, alice.ID, org.ID, secret.ID); err != nil {
+		t.Fatalf("MoveSecretToOrg: %v", err)
+	}
+	if _, err := secrets.GetSecret(ctx, alice.ID, secret.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("personal get after move error = %v, want ErrNotFound", err)
+	}
+	shared, err := svc.ListOrgSecrets(ctx, bob.ID, org.ID)
+	if err != nil || len(shared) != 1 {
+		t.Fatalf("ListOrgSecrets = %v, %v; want 1 secret", shared, err)
+	}
+	var payload models.CredentialsPayload
+	if err := shared[0].DecodePayload(&payload); err != nil || payload.Password != "shared password" {
+		t.Fatalf("shared payload = %+v, %v; want decrypted password", payload, err)
+	}
+
+	// Bob cannot move a secret into an org he does not belong to.
+	other := mustCreateCredentials(t, secrets, bob.ID, "private password")
+	if err := svc.MoveSecretToOrg(ctx, bob.ID, uuid.New(), other.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("move to unknown org error = %v, want ErrNotFound", err)
+	}
+
+	// Bob takes the shared secret into his personal vault and can decrypt
+	// it under his own key.
+	if err := svc.MoveSecretToPersonal(ctx, bob.ID, org.ID, secret.ID); err != nil {
+		t.Fatalf("MoveSecretToPersonal: %v", err)
+	}
+	taken, err := secrets.GetSecret(ctx, bob.ID, secret.ID)
+	if err != nil {
+		t.Fatalf("personal get after take: %v", err)
+	}
+	if err := taken.DecodePayload(&payload); err != nil || payload.Password != "shared password" {
+		t.Fatalf("taken payload = %+v, %v; want decrypted password", payload, err)
+	}
+	if shared, err := svc.ListOrgSecrets(ctx, alice.ID, org.ID); err != nil || len(shared) != 0 {
+		t.Fatalf("ListOrgSecrets after take = %v, %v; want empty", shared, err)
+	}
+}
+
+func TestOrgMoveRejectsAttachments(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("test jwt secret"), []byte("test master key"), nil)
+	secrets := newTestSecretsService()
+	svc := NewOrgService(memory.NewOrgRepository(), users, secrets, nil)
+
+	if _, err := auth.Register(ctx, "alice", "alice password", ""); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	alice, _ := users.GetByLogin(ctx, "alice")
+	org, err := svc.CreateOrg(ctx, alice.ID, "platform team")
+	if err != nil {
+		t.Fatalf("CreateOrg: %v", err)
+	}
+
+	secret := mustCreateCredentials(t, secrets, alice.ID, "password")
+	if _, err := secrets.AddAttachment(ctx, alice.ID, secret.ID, "note.txt", []byte("attached")); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+	if err := svc.MoveSecretToOrg(ctx, alice.ID, org.ID, secret.ID); !errors.Is(err, ErrValidation) {
+		t.Fatalf("move with attachments error = %v, want ErrValidation", err)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// OutboxPublisher delivers one change event to a consumer (WebSocket hub,
+// webhook sender, replication feed). A returned error leaves the event in
+// the outbox for the next dispatch round.
+type OutboxPublisher interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// Dispatch tuning of the OutboxDispatcher.
+const (
+	outboxDispatchInterval = 5 * time.Second
+	outboxDispatchBatch    = 100
+)
+
+// OutboxDispatcher drains the outbox to its publishers. Events are only
+// marked published once every publisher accepted them, so a crash between
+// commit and delivery replays the event instead of dropping it.
+type OutboxDispatcher struct {
+	outbox     repository.OutboxRepository
+	publishers []OutboxPublisher
+}
+
+// NewOutboxDispatcher creates an OutboxDispatcher.
+func NewOutboxDispatcher(outbox repository.OutboxRepository, publishers ...OutboxPublisher) *OutboxDispatcher {
+	return &OutboxDispatcher{outbox: outbox, publishers: publishers}
+}
+
+// Run dispatches pending events every outboxDispatchInterval until ctx is
+// cancelled.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(outboxDispatchInterval)
+	defer ticker.Stop()
+	for {
+		if err := d.Dispatch(ctx); err != nil {
+			slog.WarnContext(ctx, "outbox dispatch", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch publishes one batch of pending events. An event failing on any
+// publisher stays pending; later events are still attempted, since
+// consumers tolerate reordering within a batch.
+func (d *OutboxDispatcher) Dispatch(ctx context.Context) error {
+	events, err := d.outbox.ListUnpublished(ctx, outboxDispatchBatch)
+	if err != nil {
+		return err
+	}
+	var delivered []int64
+	for _, event := range events {
+		ok := true
+		for _, p := range d.publishers {
+			if err := p.Publish(ctx, event); err != nil {
+				slog.WarnContext(ctx, "publish outbox event", "event", event.ID, "err", err)
+				ok = false
+				break
+			}
+		}
+		if ok {
+			delivered = append(delivered, event.ID)
+		}
+	}
+	return d.outbox.MarkPublished(ctx, delivered)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+// recordingPublisher collects delivered events, failing while broken is set.
+type recordingPublisher struct {
+	events []models.OutboxEvent
+	broken bool
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event models.OutboxEvent) error {
+	if p.broken {
+		return errors.New("consumer down")
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestMutationsEnqueueOutboxEvents(t *testing.T) {
+	outbox := memory.NewOutboxRepository()
+	svc := NewSecretsService(SecretsServiceDeps{
+		Secrets:     memory.NewSecretRepository(),
+		Attachments: memory.NewAttachmentRepository(),
+		Versions:    memory.NewSecretVersionRepository(),
+		Outbox:      outbox,
+		TM:          repository.NoopTransactionManager{},
+		MasterKey:   []byte("test master key"),
+	})
+	ctx := context.Background()
+	userID := uuid.New()
+
+	secret := mustCreateCredentials(t, svc, userID, "hunter2")
+	updatePassword(t, svc, secret, "hunter3")
+	if err := svc.DeleteSecret(ctx, userID, secret.ID, false); err != nil {
+		t.Fatalf("DeleteSecret: %v", err)
+	}
+
+	events, err := outbox.ListUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListUnpublished: %v", err)
+	}
+	want := []string{
+		models.AuditActionSecretCreated,
+		models.AuditActionSecretUpdated,
+		models.AuditActionSecretDeleted,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if e.Action != want[i] {
+			t.Errorf("event %d action = %q, want %q", i, e.Action, want[i])
+		}
+		if e.UserID != userID || e.Resource != secret.ID.String() {
+			t.Errorf("event %d addressed to %s/%s, want %s/%s", i, e.UserID, e.Resource, userID, secret.ID)
+		}
+	}
+}
+
+func TestDispatchMarksPublishedOnlyOnDelivery(t *testing.T) {
+	ctx := context.Background()
+	outbox := memory.NewOutboxRepository()
+	for i := 0; i < 2; i++ {
+		err := outbox.Append(ctx, &models.OutboxEvent{
+			UserID:   uuid.New(),
+			Action:   models.AuditActionSecretUpdated,
+			Resource: uuid.NewString(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	pub := &recordingPublisher{broken: true}
+	dispatcher := NewOutboxDispatcher(outbox, pub)
+
+	// A failing consumer must leave every event pending for retry.
+	if err := dispatcher.Dispatch(ctx); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	pending, err := outbox.ListUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("got %d pending events after failed dispatch, want 2", len(pending))
+	}
+
+	pub.broken = false
+	if err := dispatcher.Dispatch(ctx); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	if len(pub.events) != 2 {
+		t.Fatalf("publisher received %d events, want 2", len(pub.events))
+	}
+	pending, err = outbox.ListUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("got %d pending events after delivery, want 0", len(pending))
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 10, MinClasses: 2, MinScore: 2}
+	cases := []struct {
+		name     string
+		password string
+		ok       bool
+	}{
+		{"strong mixed", "Tr4verse-north", true},
+		{"long passphrase", "correct horse battery", true},
+		{"too short", "Ab1!x", false},
+		{"one class", "aaaaaaaaaaaa", false},
+		{"common password", "password123", false},
+		{"empty policy accepts anything", "x", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := policy
+			if tc.name == "empty policy accepts anything" {
+				p = PasswordPolicy{}
+			}
+			err := p.Validate(tc.password)
+			if tc.ok && err != nil {
+				t.Errorf("Validate(%q) = %v, want nil", tc.password, err)
+			}
+			if !tc.ok {
+				if err == nil {
+					t.Errorf("Validate(%q) = nil, want error", tc.password)
+				} else if !errors.Is(err, ErrValidation) {
+					t.Errorf("Validate(%q) = %v, want ErrValidation", tc.password, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterEnforcesPasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+	auth := NewAuthService(memory.NewUserRepository(), []byte("jwt secret"), []byte("test master key"), nil)
+	auth.SetPasswordPolicy(PasswordPolicy{MinLength: 12})
+
+	if _, err := auth.Register(ctx, "alice", "short", ""); !errors.Is(err, ErrValidation) {
+		t.Fatalf("Register with a short password = %v, want ErrValidation", err)
+	}
+	if _, err := auth.Register(ctx, "alice", "a long enough password", ""); err != nil {
+		t.Fatalf("Register with a conforming password: %v", err)
+	}
+}
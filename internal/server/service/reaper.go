@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// ReapInterval is how often the reaper looks for accounts whose deletion
+// grace period has ended.
+const ReapInterval = time.Hour
+
+// Reaper permanently removes accounts once their scheduled deletion time
+// passes. Deleting the user row cascades at the database to secrets,
+// devices, shares and queued sync operations, so a single delete purges
+// everything the account owned.
+type Reaper struct {
+	users repository.UserRepository
+}
+
+// NewReaper creates a Reaper.
+func NewReaper(users repository.UserRepository) *Reaper {
+	return &Reaper{users: users}
+}
+
+// Run executes a sweep every ReapInterval until ctx is cancelled. An
+// initial sweep runs immediately.
+func (r *Reaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(ReapInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.Sweep(ctx); err != nil {
+			slog.WarnContext(ctx, "account reap sweep", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep purges every account whose deletion became due. A failure for one
+// account does not stop the sweep; the first error is returned after all
+// were tried.
+func (r *Reaper) Sweep(ctx context.Context) error {
+	users, err := r.users.ListDeletable(ctx, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	for _, u := range users {
+		if err := r.users.Delete(ctx, u.ID); err != nil {
+			slog.WarnContext(ctx, "purge account", "user", u.ID, "err", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		slog.InfoContext(ctx, "purged account after grace period", "user", u.ID, "login", u.Login)
+	}
+	return firstErr
+}
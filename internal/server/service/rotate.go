@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// RotationReport summarizes one rotation run.
+type RotationReport struct {
+	Users       int `json:"users"`
+	Secrets     int `json:"secrets"`
+	Versions    int `json:"versions"`
+	Attachments int `json:"attachments"`
+	Hints       int `json:"hints"`
+	// Skipped counts items that were already under the new key, which is
+	// what makes an interrupted run safe to repeat.
+	Skipped int `json:"skipped"`
+}
+
+// RotationService re-encrypts stored ciphertext from the current master
+// key to a staged replacement. Each vault is rewritten in its own
+// transaction and every item is checked against the new key first, so an
+// interrupted run resumes from where it stopped instead of starting over.
+//
+// Envelope-encrypted secrets only have their data key re-wrapped;
+// pre-envelope secrets are upgraded to the envelope format on the way.
+// The append-only audit trail is not rewritten: resources sealed under
+// the old key display as stored, like pre-encryption rows do.
+type RotationService struct {
+	users   repository.UserRepository
+	orgs    repository.OrgRepository
+	secrets *SecretsService
+	newKey  []byte
+	audit   AuditRecorder
+}
+
+// NewRotationService creates a RotationService. newKey is the staged
+// replacement master secret; audit may be nil.
+func NewRotationService(users repository.UserRepository, orgs repository.OrgRepository, secrets *SecretsService, newKey []byte, audit AuditRecorder) *RotationService {
+	return &RotationService{users: users, orgs: orgs, secrets: secrets, newKey: newKey, audit: audit}
+}
+
+// Rotate walks every account and re-encrypts its secrets, versions,
+// attachments and vault hint under the new master key, one transaction
+// per vault. Organization vaults are rotated once, when the walk first
+// reaches a member. The caller must restart the server with the new key
+// as ENCRYPTION_KEY once the run reports success.
+func (s *RotationService) Rotate(ctx context.Context, adminID uuid.UUID) (*RotationReport, error) {
+	if len(s.newKey) == 0 {
+		return nil, fmt.Errorf("%w: no rotation key is configured", ErrValidation)
+	}
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &RotationReport{}
+	rotatedOrgs := make(map[uuid.UUID]bool)
+	for i := range users {
+		userID := users[i].ID
+		err := s.secrets.tm.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.rotateUser(ctx, userID, report)
+		})
+		if err != nil {
+			return report, fmt.Errorf("rotate user %s: %w", users[i].Login, err)
+		}
+		orgs, err := s.orgs.ListByUser(ctx, userID)
+		if err != nil {
+			return report, err
+		}
+		for j := range orgs {
+			orgID := orgs[j].ID
+			if rotatedOrgs[orgID] {
+				continue
+			}
+			err := s.secrets.tm.WithinTransaction(ctx, func(ctx context.Context) error {
+				return s.rotateOrg(ctx, orgID, report)
+			})
+			if err != nil {
+				return report, fmt.Errorf("rotate org %s: %w", orgs[j].Name, err)
+			}
+			rotatedOrgs[orgID] = true
+		}
+		report.Users++
+		slog.InfoContext(ctx, "rotated vault",
+			"user", users[i].Login,
+			"secrets", report.Secrets,
+			"skipped", report.Skipped)
+	}
+	if s.audit != nil {
+		s.audit.Record(ctx, adminID, models.AuditActionKeyRotated, "")
+	}
+	return report, nil
+}
+
+// rotateUser rewrites one personal vault: secrets and their versions and
+// attachments, plus the account's vault hint.
+func (s *RotationService) rotateUser(ctx context.Context, userID uuid.UUID, report *RotationReport) error {
+	oldK := crypto.DeriveKey(s.secrets.masterKey, userID[:])
+	newK := crypto.DeriveKey(s.newKey, userID[:])
+	defer crypto.Zero(oldK)
+	defer crypto.Zero(newK)
+
+	secrets, err := s.secrets.secrets.GetByUser(ctx, userID, repository.SecretListOptions{IncludeArchived: true})
+	if err != nil {
+		return err
+	}
+	for i := range secrets {
+		if err := s.rotateSecret(ctx, &secrets[i], oldK, newK, report); err != nil {
+			return fmt.Errorf("secret %s: %w", secrets[i].ID, err)
+		}
+		if err := s.rotateVersions(ctx, userID, secrets[i].ID, report); err != nil {
+			return fmt.Errorf("secret %s versions: %w", secrets[i].ID, err)
+		}
+		if err := s.rotateAttachments(ctx, userID, secrets[i].ID, oldK, newK, report); err != nil {
+			return fmt.Errorf("secret %s attachments: %w", secrets[i].ID, err)
+		}
+	}
+
+	hint, err := s.users.GetHint(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(hint) == 0 {
+		return nil
+	}
+	rotated, changed, err := recrypt(oldK, newK, hint)
+	if err != nil {
+		return fmt.Errorf("hint: %w", err)
+	}
+	if !changed {
+		report.Skipped++
+		return nil
+	}
+	if err := s.users.SetHint(ctx, userID, rotated); err != nil {
+		return err
+	}
+	report.Hints++
+	return nil
+}
+
+// rotateOrg rewrites the secrets of one organization vault. Org secrets
+// carry no attachments, and their versions predate the move to the org,
+// so those rows rotate with their owner's personal vault.
+func (s *RotationService) rotateOrg(ctx context.Context, orgID uuid.UUID, report *RotationReport) error {
+	oldK := crypto.DeriveKey(s.secrets.masterKey, orgID[:])
+	newK := crypto.DeriveKey(s.newKey, orgID[:])
+	defer crypto.Zero(oldK)
+	defer crypto.Zero(newK)
+	secrets, err := s.secrets.secrets.GetByOrg(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for i := range secrets {
+		if err := s.rotateSecret(ctx, &secrets[i], oldK, newK, report); err != nil {
+			return fmt.Errorf("secret %s: %w", secrets[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// rotateSecret rewrites one payload, following it into the blob store
+// when it lives there.
+func (s *RotationService) rotateSecret(ctx context.Context, secret *models.Secret, oldK, newK []byte, report *RotationReport) error {
+	if metaSealed(secret) {
+		if err := s.rotateSecretMeta(ctx, secret, oldK, newK, report); err != nil {
+			return fmt.Errorf("metadata: %w", err)
+		}
+	}
+	oldRef := secret.BlobRef
+	if err := s.secrets.loadBlob(ctx, secret); err != nil {
+		return err
+	}
+	rotated, changed, err := recryptPayload(oldK, newK, secret.Data)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		report.Skipped++
+		return nil
+	}
+	stored := *secret
+	stored.Data = rotated
+	if err := s.secrets.offloadBlob(ctx, &stored); err != nil {
+		return err
+	}
+	if err := s.secrets.secrets.ReplaceData(ctx, secret.ID, stored.Data, stored.BlobRef); err != nil {
+		s.secrets.discardBlob(ctx, stored.BlobRef)
+		return err
+	}
+	if oldRef != stored.BlobRef {
+		s.secrets.discardBlob(ctx, oldRef)
+	}
+	report.Secrets++
+	return nil
+}
+
+// rotateSecretMeta re-seals an encrypted name and metadata under the new
+// vault key. Like payloads, fields that already open under the new key
+// were rewritten by an interrupted previous run and are skipped.
+func (s *RotationService) rotateSecretMeta(ctx context.Context, secret *models.Secret, oldK, newK []byte, report *RotationReport) error {
+	name, err := openField(oldK, secret.Name)
+	if err != nil {
+		if _, retryErr := openField(newK, secret.Name); retryErr != nil {
+			return fmt.Errorf("name: %w", err)
+		}
+		report.Skipped++
+		return nil
+	}
+	metadata, err := openField(oldK, secret.Metadata)
+	if err != nil {
+		return err
+	}
+	sealedName, err := sealField(newK, name)
+	if err != nil {
+		return err
+	}
+	sealedMetadata, err := sealField(newK, metadata)
+	if err != nil {
+		return err
+	}
+	return s.secrets.secrets.ReplaceMeta(ctx, secret.ID, sealedName, sealedMetadata, nameIndex(newK, name))
+}
+
+// rotateVersions rewrites the history rows of one secret. Version rows
+// are keyed by the user they were written for, which may differ from the
+// current owner after a move.
+func (s *RotationService) rotateVersions(ctx context.Context, userID, secretID uuid.UUID, report *RotationReport) error {
+	if s.secrets.versions == nil {
+		return nil
+	}
+	versions, err := s.secrets.versions.ListBySecret(ctx, userID, secretID)
+	if err != nil {
+		return err
+	}
+	for i := range versions {
+		v := &versions[i]
+		rotated, changed, err := recrypt(
+			crypto.DeriveKey(s.secrets.masterKey, v.UserID[:]),
+			crypto.DeriveKey(s.newKey, v.UserID[:]),
+			v.Data,
+		)
+		if err != nil {
+			return fmt.Errorf("version %d: %w", v.Version, err)
+		}
+		if !changed {
+			report.Skipped++
+			continue
+		}
+		if err := s.secrets.versions.ReplaceData(ctx, v.ID, rotated); err != nil {
+			return err
+		}
+		report.Versions++
+	}
+	return nil
+}
+
+// rotateAttachments rewrites the attachment contents of one secret.
+func (s *RotationService) rotateAttachments(ctx context.Context, userID, secretID uuid.UUID, oldK, newK []byte, report *RotationReport) error {
+	if s.secrets.attachments == nil {
+		return nil
+	}
+	attachments, err := s.secrets.attachments.ListBySecret(ctx, userID, secretID)
+	if err != nil {
+		return err
+	}
+	for i := range attachments {
+		attachment, err := s.secrets.attachments.GetByID(ctx, userID, attachments[i].ID)
+		if err != nil {
+			return err
+		}
+		rotated, changed, err := recrypt(oldK, newK, attachment.Content)
+		if err != nil {
+			return fmt.Errorf("attachment %s: %w", attachment.ID, err)
+		}
+		if !changed {
+			report.Skipped++
+			continue
+		}
+		if err := s.secrets.attachments.ReplaceContent(ctx, attachment.ID, rotated); err != nil {
+			return err
+		}
+		report.Attachments++
+	}
+	return nil
+}
+
+// recryptPayload rotates a secret payload. Envelopes are cheaply
+// re-wrapped; pre-envelope ciphertext is decrypted and re-sealed as an
+// envelope, completing the format migration as a side effect. changed is
+// false when the blob is already under the new key.
+func recryptPayload(oldK, newK, data []byte) (rotated []byte, changed bool, err error) {
+	if crypto.IsEnvelope(data) {
+		if rotated, err = crypto.RewrapEnvelope(oldK, newK, data); err == nil {
+			return rotated, true, nil
+		}
+		if _, retryErr := crypto.DecryptEnvelope(newK, data); retryErr == nil {
+			return data, false, nil
+		}
+		return nil, false, err
+	}
+	plaintext, err := crypto.Decrypt(oldK, data)
+	if err != nil {
+		if _, retryErr := crypto.Decrypt(newK, data); retryErr == nil {
+			return data, false, nil
+		}
+		return nil, false, err
+	}
+	rotated, err = crypto.EncryptEnvelope(newK, plaintext)
+	return rotated, err == nil, err
+}
+
+// recrypt rotates directly sealed ciphertext, keeping its format.
+func recrypt(oldK, newK, data []byte) (rotated []byte, changed bool, err error) {
+	plaintext, err := crypto.Decrypt(oldK, data)
+	if err != nil {
+		if _, retryErr := crypto.Decrypt(newK, data); retryErr == nil {
+			return data, false, nil
+		}
+		return nil, false, err
+	}
+	rotated, err = crypto.Encrypt(newK, plaintext)
+	return rotated, err == nil, err
+}
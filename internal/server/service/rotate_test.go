@@ -0,0 +1,175 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+func TestRotateMasterKey(t *testing.T) {
+	ctx := context.Background()
+	oldMaster := []byte("old master key")
+	newMaster := []byte("new master key")
+	users := memory.NewUserRepository()
+	secretRepo := memory.NewSecretRepository()
+	attachments := memory.NewAttachmentRepository()
+	versions := memory.NewSecretVersionRepository()
+	deps := SecretsServiceDeps{
+		Secrets:     secretRepo,
+		Attachments: attachments,
+		Versions:    versions,
+		TM:          repository.NoopTransactionManager{},
+		MasterKey:   oldMaster,
+	}
+	svc := NewSecretsService(deps)
+
+	userID := uuid.New()
+	if err := users.Create(ctx, &models.User{ID: userID, Login: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	oldKey := crypto.DeriveKey(oldMaster, userID[:])
+
+	// An envelope secret with a version and an attachment.
+	secret := mustCreateCredentials(t, svc, userID, "first")
+	updatePassword(t, svc, secret, "second")
+	if _, err := svc.AddAttachment(ctx, userID, secret.ID, "note.txt", []byte("attached")); err != nil {
+		t.Fatalf("AddAttachment: %v", err)
+	}
+
+	// A secret written before envelope encryption, sealed directly.
+	legacy := &models.Secret{
+		ID:     uuid.New(),
+		UserID: userID,
+		Name:   "legacy",
+		Type:   models.SecretTypeText,
+	}
+	raw := []byte(`{"text":"pre-envelope"}`)
+	sealed, err := crypto.Encrypt(oldKey, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := *legacy
+	stored.Data = sealed
+	if err := secretRepo.Create(ctx, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	hint, err := crypto.Encrypt(oldKey, []byte("the usual one"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.SetHint(ctx, userID, hint); err != nil {
+		t.Fatal(err)
+	}
+
+	rotation := NewRotationService(users, memory.NewOrgRepository(), svc, newMaster, nil)
+	report, err := rotation.Rotate(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if report.Users != 1 || report.Secrets != 2 || report.Versions != 1 ||
+		report.Attachments != 1 || report.Hints != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	// Everything must decrypt under the new master key.
+	deps.MasterKey = newMaster
+	rotated := NewSecretsService(deps)
+	got, err := rotated.GetSecret(ctx, userID, legacy.ID)
+	if err != nil {
+		t.Fatalf("GetSecret after rotation: %v", err)
+	}
+	if !bytes.Equal(got.Data, raw) {
+		t.Fatalf("legacy payload = %q, want %q", got.Data, raw)
+	}
+	if _, err := rotated.GetSecret(ctx, userID, secret.ID); err != nil {
+		t.Fatalf("GetSecret after rotation: %v", err)
+	}
+	if _, err := rotated.GetSecretVersion(ctx, userID, secret.ID, 1); err != nil {
+		t.Fatalf("GetSecretVersion after rotation: %v", err)
+	}
+	list, err := rotated.ListAttachments(ctx, userID, secret.ID)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("ListAttachments after rotation: %v", err)
+	}
+	if _, err := rotated.GetAttachment(ctx, userID, list[0].ID); err != nil {
+		t.Fatalf("GetAttachment after rotation: %v", err)
+	}
+	rotatedHint, err := users.GetHint(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := crypto.Decrypt(crypto.DeriveKey(newMaster, userID[:]), rotatedHint); err != nil {
+		t.Fatalf("hint does not decrypt under the new key: %v", err)
+	}
+
+	// A repeated run finds nothing left to rotate.
+	report, err = rotation.Rotate(ctx, uuid.New())
+	if err != nil {
+		t.Fatalf("second Rotate: %v", err)
+	}
+	if report.Secrets != 0 || report.Versions != 0 || report.Attachments != 0 || report.Hints != 0 {
+		t.Fatalf("second run report = %+v", report)
+	}
+	if report.Skipped == 0 {
+		t.Fatal("second run skipped nothing")
+	}
+}
+
+func TestRotateWithoutStagedKey(t *testing.T) {
+	svc := newTestSecretsService()
+	rotation := NewRotationService(memory.NewUserRepository(), memory.NewOrgRepository(), svc, nil, nil)
+	if _, err := rotation.Rotate(context.Background(), uuid.New()); !errors.Is(err, ErrValidation) {
+		t.Fatalf("err = %v, want ErrValidation", err)
+	}
+}
+
+func TestRotateEncryptedMetadata(t *testing.T) {
+	ctx := context.Background()
+	oldMaster := []byte("old master key")
+	newMaster := []byte("new master key")
+	users := memory.NewUserRepository()
+	deps := SecretsServiceDeps{
+		Secrets:         memory.NewSecretRepository(),
+		TM:              repository.NoopTransactionManager{},
+		MasterKey:       oldMaster,
+		EncryptMetadata: true,
+	}
+	svc := NewSecretsService(deps)
+
+	userID := uuid.New()
+	if err := users.Create(ctx, &models.User{ID: userID, Login: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	secret := mustCreateCredentials(t, svc, userID, "password")
+
+	rotation := NewRotationService(users, memory.NewOrgRepository(), svc, newMaster, nil)
+	if _, err := rotation.Rotate(ctx, uuid.New()); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	deps.MasterKey = newMaster
+	rotated := NewSecretsService(deps)
+	got, err := rotated.GetSecret(ctx, userID, secret.ID)
+	if err != nil {
+		t.Fatalf("GetSecret after rotation: %v", err)
+	}
+	if got.Name != "example.com" {
+		t.Fatalf("name after rotation = %q", got.Name)
+	}
+	matches, _, err := rotated.GetSecrets(ctx, userID, repository.SecretListOptions{Name: "example.com"})
+	if err != nil {
+		t.Fatalf("GetSecrets: %v", err)
+	}
+	if len(matches) != 1 || matches[0].ID != secret.ID {
+		t.Fatalf("name lookup after rotation returned %d secrets", len(matches))
+	}
+}
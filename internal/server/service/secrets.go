@@ -0,0 +1,680 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/blob"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// ErrValidation wraps user input errors.
+var ErrValidation = errors.New("validation error")
+
+// ErrProtected is returned when a protected secret is deleted without
+// explicit confirmation.
+var ErrProtected = errors.New("secret is protected, deletion requires confirmation")
+
+// ErrQuotaExceeded is returned when storing a secret would exceed the
+// user's storage quota.
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// WarnPasswordReused is attached to an update response when the new password
+// of a credentials secret matches the current one or an entry in its history.
+const WarnPasswordReused = "password was used before for this credential"
+
+// SecretsService owns secret CRUD. Payloads are encrypted with a per-user
+// key before they reach the repository and decrypted on the way out.
+// Encrypted payloads above blob.InlineThreshold live in the blob store,
+// with only a reference in the database row.
+type SecretsService struct {
+	secrets     repository.SecretRepository
+	attachments repository.AttachmentRepository
+	versions    repository.SecretVersionRepository
+	outbox      repository.OutboxRepository
+	tm          repository.TransactionManager
+	blobs       blob.Store
+	offloadAll  bool
+	masterKey   []byte
+	encryptMeta bool
+	audit       AuditRecorder
+
+	// Quotas; 0 disables the respective limit.
+	maxSecrets      int
+	maxStorageBytes int64
+}
+
+// SecretsServiceDeps collects the dependencies of a SecretsService.
+// MasterKey is the server encryption secret from the configuration; Audit,
+// Blobs and Versions are optional.
+type SecretsServiceDeps struct {
+	Secrets     repository.SecretRepository
+	Attachments repository.AttachmentRepository
+	Versions    repository.SecretVersionRepository
+	Outbox      repository.OutboxRepository
+	TM          repository.TransactionManager
+	Blobs       blob.Store
+	// OffloadAll sends every payload to Blobs regardless of size, keeping
+	// the database free of payload bytes for data-residency deployments.
+	OffloadAll bool
+	MasterKey  []byte
+	// EncryptMetadata seals secret names and metadata at rest and keeps a
+	// keyed name index so exact-name lookups stay fast. Rows written while
+	// it was off stay plaintext until their next write; sealed rows keep
+	// decrypting after it is turned off.
+	EncryptMetadata bool
+	Audit           AuditRecorder
+	// MaxSecrets and MaxStorageBytes cap a user's secret count and total
+	// payload bytes; 0 disables the respective limit.
+	MaxSecrets      int
+	MaxStorageBytes int64
+}
+
+// NewSecretsService creates a SecretsService.
+func NewSecretsService(deps SecretsServiceDeps) *SecretsService {
+	return &SecretsService{
+		secrets:     deps.Secrets,
+		attachments: deps.Attachments,
+		versions:    deps.Versions,
+		outbox:      deps.Outbox,
+		tm:          deps.TM,
+		blobs:       deps.Blobs,
+		offloadAll:  deps.OffloadAll,
+		masterKey:   deps.MasterKey,
+		encryptMeta: deps.EncryptMetadata,
+		audit:       deps.Audit,
+
+		maxSecrets:      deps.MaxSecrets,
+		maxStorageBytes: deps.MaxStorageBytes,
+	}
+}
+
+func (s *SecretsService) record(ctx context.Context, userID uuid.UUID, action string, secretID uuid.UUID) {
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, action, secretID.String())
+	}
+}
+
+// enqueue writes a change event to the outbox. It must be called inside the
+// same transaction as the mutation; a failure here fails the mutation, which
+// is what guarantees no notification is ever lost.
+func (s *SecretsService) enqueue(ctx context.Context, userID uuid.UUID, action string, secretID uuid.UUID) error {
+	if s.outbox == nil {
+		return nil
+	}
+	return s.outbox.Append(ctx, &models.OutboxEvent{
+		UserID:    userID,
+		Action:    action,
+		Resource:  secretID.String(),
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+// CreateSecret validates, encrypts and stores a new secret.
+func (s *SecretsService) CreateSecret(ctx context.Context, secret *models.Secret) error {
+	if err := validateSecret(secret); err != nil {
+		return err
+	}
+	if err := s.checkQuota(ctx, secret.UserID, int64(len(secret.Data))); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	secret.ID = uuid.New()
+	secret.AccessedAt = now
+	secret.CreatedAt = now
+	secret.UpdatedAt = now
+
+	err := s.tm.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.saveEncrypted(ctx, secret, "", s.secrets.Create); err != nil {
+			return err
+		}
+		return s.enqueue(ctx, secret.UserID, models.AuditActionSecretCreated, secret.ID)
+	})
+	if err != nil {
+		return err
+	}
+	s.record(ctx, secret.UserID, models.AuditActionSecretCreated, secret.ID)
+	return nil
+}
+
+// checkQuota rejects a create that would push the user over a configured
+// limit. incoming is the plaintext payload size; encryption overhead is a
+// constant few dozen bytes, not worth accounting for.
+func (s *SecretsService) checkQuota(ctx context.Context, userID uuid.UUID, incoming int64) error {
+	if s.maxSecrets == 0 && s.maxStorageBytes == 0 {
+		return nil
+	}
+	count, size, err := s.secrets.UsageByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if s.maxSecrets > 0 && count >= s.maxSecrets {
+		return fmt.Errorf("%w: at most %d secrets allowed", ErrQuotaExceeded, s.maxSecrets)
+	}
+	if s.maxStorageBytes > 0 && size+incoming > s.maxStorageBytes {
+		return fmt.Errorf("%w: at most %d bytes allowed", ErrQuotaExceeded, s.maxStorageBytes)
+	}
+	return nil
+}
+
+// Usage reports the user's storage consumption and the configured limits.
+func (s *SecretsService) Usage(ctx context.Context, userID uuid.UUID) (models.StorageUsage, error) {
+	count, size, err := s.secrets.UsageByUser(ctx, userID)
+	if err != nil {
+		return models.StorageUsage{}, err
+	}
+	return models.StorageUsage{
+		SecretsCount:    count,
+		StorageBytes:    size,
+		MaxSecrets:      s.maxSecrets,
+		MaxStorageBytes: s.maxStorageBytes,
+	}, nil
+}
+
+// GetSecret fetches and decrypts one secret.
+func (s *SecretsService) GetSecret(ctx context.Context, userID, id uuid.UUID) (*models.Secret, error) {
+	secret, err := s.secrets.GetByID(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if err := s.decrypt(ctx, secret); err != nil {
+		return nil, err
+	}
+	// Reading a secret keeps it out of the archive; losing the timestamp
+	// must not fail the read.
+	if err := s.secrets.MarkAccessed(ctx, userID, id, time.Now().UTC()); err != nil {
+		slog.WarnContext(ctx, "mark secret accessed", "secret", id, "err", err)
+	}
+	s.record(ctx, userID, models.AuditActionSecretRead, id)
+	return secret, nil
+}
+
+// GetSecrets fetches and decrypts the secrets of a user according to opts.
+// Secrets whose payload cannot be decrypted come back as error entries
+// instead of being dropped, so data loss is never silent. When nothing
+// decrypts at all the listing fails outright: that points at a key
+// problem, not isolated corruption.
+func (s *SecretsService) GetSecrets(ctx context.Context, userID uuid.UUID, opts repository.SecretListOptions) ([]models.Secret, []models.SecretError, error) {
+	if opts.SortBy != "" && !opts.SortBy.Valid() {
+		return nil, nil, fmt.Errorf("%w: unknown sort field %q", ErrValidation, opts.SortBy)
+	}
+	if opts.Name != "" {
+		// Sealed rows are found through the keyed index; the plaintext
+		// comparison in the repository covers rows written while metadata
+		// encryption was off.
+		opts.NameIndex = nameIndex(s.userKey(userID), opts.Name)
+	}
+	secrets, err := s.secrets.GetByUser(ctx, userID, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	decrypted := secrets[:0]
+	var failed []models.SecretError
+	for i := range secrets {
+		if err := s.decrypt(ctx, &secrets[i]); err != nil {
+			slog.WarnContext(ctx, "undecryptable secret", "secret", secrets[i].ID, "err", err)
+			failed = append(failed, models.SecretError{SecretID: secrets[i].ID, Error: "cannot decrypt payload"})
+			continue
+		}
+		decrypted = append(decrypted, secrets[i])
+	}
+	if len(decrypted) == 0 && len(failed) > 0 {
+		return nil, nil, fmt.Errorf("none of %d secrets could be decrypted, is the encryption key correct", len(failed))
+	}
+	return decrypted, failed, nil
+}
+
+// UpsertSecret writes a secret keeping its client-assigned ID, creating or
+// updating as needed. It backs the push half of delta sync, where the
+// client already named the secret.
+func (s *SecretsService) UpsertSecret(ctx context.Context, secret *models.Secret) error {
+	if secret.ID == uuid.Nil {
+		return s.CreateSecret(ctx, secret)
+	}
+	_, err := s.secrets.GetByID(ctx, secret.UserID, secret.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		if err := validateSecret(secret); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		secret.AccessedAt = now
+		secret.CreatedAt = now
+		secret.UpdatedAt = now
+		err := s.tm.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.saveEncrypted(ctx, secret, "", s.secrets.Create); err != nil {
+				return err
+			}
+			return s.enqueue(ctx, secret.UserID, models.AuditActionSecretCreated, secret.ID)
+		})
+		if err != nil {
+			return err
+		}
+		s.record(ctx, secret.UserID, models.AuditActionSecretCreated, secret.ID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.UpdateSecret(ctx, secret)
+	return err
+}
+
+// UpdateSecret rewrites the name, metadata and payload of an existing
+// secret. For credentials secrets a password change records the previous
+// password into the payload history; reusing a previous password produces a
+// warning in the returned slice.
+func (s *SecretsService) UpdateSecret(ctx context.Context, secret *models.Secret) ([]string, error) {
+	if err := validateSecret(secret); err != nil {
+		return nil, err
+	}
+	var warnings []string
+	err := s.tm.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.GetSecret(ctx, secret.UserID, secret.ID)
+		if err != nil {
+			return err
+		}
+		if existing.Type == models.SecretTypeCredentials && secret.Type == models.SecretTypeCredentials {
+			w, err := carryPasswordHistory(existing, secret)
+			if err != nil {
+				return err
+			}
+			warnings = append(warnings, w...)
+		}
+		secret.Type = existing.Type
+		secret.CreatedAt = existing.CreatedAt
+		secret.UpdatedAt = time.Now().UTC()
+
+		if err := s.snapshotVersion(ctx, existing, secret.Data, ""); err != nil {
+			return fmt.Errorf("snapshot version: %w", err)
+		}
+		if err := s.saveEncrypted(ctx, secret, existing.BlobRef, s.secrets.Update); err != nil {
+			return err
+		}
+		return s.enqueue(ctx, secret.UserID, models.AuditActionSecretUpdated, secret.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, secret.UserID, models.AuditActionSecretUpdated, secret.ID)
+	return warnings, nil
+}
+
+// ConvertSecret rewrites a secret under a different type with a payload the
+// client already mapped to the new shape. The previous payload is kept as a
+// version snapshot, so nothing is destroyed.
+func (s *SecretsService) ConvertSecret(ctx context.Context, secret *models.Secret) error {
+	if err := validateSecret(secret); err != nil {
+		return err
+	}
+	err := s.tm.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.GetSecret(ctx, secret.UserID, secret.ID)
+		if err != nil {
+			return err
+		}
+		if existing.Type == secret.Type {
+			return fmt.Errorf("%w: secret is already of type %s", ErrValidation, secret.Type)
+		}
+		summary := fmt.Sprintf("converted from %s to %s", existing.Type, secret.Type)
+		if err := s.snapshotVersion(ctx, existing, secret.Data, summary); err != nil {
+			return fmt.Errorf("snapshot version: %w", err)
+		}
+		secret.CreatedAt = existing.CreatedAt
+		secret.UpdatedAt = time.Now().UTC()
+		if err := s.saveEncrypted(ctx, secret, existing.BlobRef, s.secrets.Update); err != nil {
+			return err
+		}
+		return s.enqueue(ctx, secret.UserID, models.AuditActionSecretUpdated, secret.ID)
+	})
+	if err != nil {
+		return err
+	}
+	s.record(ctx, secret.UserID, models.AuditActionSecretUpdated, secret.ID)
+	return nil
+}
+
+// WarnFewRecoveryCodes is attached to a use-code response when the number
+// of unused codes drops to LowRecoveryCodesThreshold or below.
+const WarnFewRecoveryCodes = "few unused recovery codes remain, consider regenerating them"
+
+// UseRecoveryCode atomically consumes the next unused code of a
+// recovery_codes secret. It returns the consumed code, the number of codes
+// still unused, and a warning when that number is low.
+func (s *SecretsService) UseRecoveryCode(ctx context.Context, userID, id uuid.UUID) (code string, remaining int, warnings []string, err error) {
+	err = s.tm.WithinTransaction(ctx, func(ctx context.Context) error {
+		secret, err := s.GetSecret(ctx, userID, id)
+		if err != nil {
+			return err
+		}
+		if secret.Type != models.SecretTypeRecoveryCodes {
+			return fmt.Errorf("%w: secret is %s, not %s", ErrValidation, secret.Type, models.SecretTypeRecoveryCodes)
+		}
+		var payload models.RecoveryCodesPayload
+		if err := secret.DecodePayload(&payload); err != nil {
+			return fmt.Errorf("decode payload: %w", err)
+		}
+		consumed, ok := payload.Consume(time.Now().UTC())
+		if !ok {
+			return fmt.Errorf("%w: all recovery codes have been used", ErrValidation)
+		}
+		if err := secret.EncodePayload(&payload); err != nil {
+			return err
+		}
+		secret.UpdatedAt = time.Now().UTC()
+
+		if err := s.saveEncrypted(ctx, secret, secret.BlobRef, s.secrets.Update); err != nil {
+			return err
+		}
+		if err := s.enqueue(ctx, userID, models.AuditActionSecretUpdated, id); err != nil {
+			return err
+		}
+		code = consumed
+		remaining = payload.Remaining()
+		if remaining <= models.LowRecoveryCodesThreshold {
+			warnings = append(warnings, WarnFewRecoveryCodes)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", 0, nil, err
+	}
+	s.record(ctx, userID, models.AuditActionSecretUpdated, id)
+	return code, remaining, warnings, nil
+}
+
+// DeleteSecret removes a secret. Deleting a protected secret fails with
+// ErrProtected unless confirm is set.
+func (s *SecretsService) DeleteSecret(ctx context.Context, userID, id uuid.UUID, confirm bool) error {
+	existing, err := s.secrets.GetByID(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if existing.Protected && !confirm {
+		return ErrProtected
+	}
+	err = s.tm.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.secrets.Delete(ctx, userID, id); err != nil {
+			return err
+		}
+		return s.enqueue(ctx, userID, models.AuditActionSecretDeleted, id)
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err == nil {
+		s.discardBlob(ctx, existing.BlobRef)
+		s.record(ctx, userID, models.AuditActionSecretDeleted, id)
+	}
+	return err
+}
+
+// carryPasswordHistory merges the history of the stored payload into the
+// incoming one and pushes the replaced password onto it. It returns a
+// warning when the new password was already used for this credential.
+func carryPasswordHistory(existing, incoming *models.Secret) ([]string, error) {
+	var old, updated models.CredentialsPayload
+	if err := existing.DecodePayload(&old); err != nil {
+		return nil, fmt.Errorf("decode stored payload: %w", err)
+	}
+	if err := incoming.DecodePayload(&updated); err != nil {
+		return nil, fmt.Errorf("%w: bad credentials payload", ErrValidation)
+	}
+	// Clients never submit history; the stored list is authoritative.
+	updated.History = old.History
+
+	var warnings []string
+	if updated.Password != old.Password {
+		if old.UsedBefore(updated.Password) {
+			warnings = append(warnings, WarnPasswordReused)
+		}
+		old.History = updated.History
+		old.PushHistory(time.Now().UTC())
+		updated.History = old.History
+	}
+	if err := incoming.EncodePayload(&updated); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+func validateSecret(secret *models.Secret) error {
+	if secret.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	if !secret.Type.Valid() {
+		return fmt.Errorf("%w: unknown secret type %q", ErrValidation, secret.Type)
+	}
+	if len(secret.Data) == 0 {
+		return fmt.Errorf("%w: data is required", ErrValidation)
+	}
+	return nil
+}
+
+// encrypt seals the payload under a fresh per-secret data key wrapped by
+// the vault key, so rotating the master key only re-wraps data keys. With
+// metadata encryption on, the name and metadata are sealed too.
+func (s *SecretsService) encrypt(secret *models.Secret) error {
+	key := s.contentKey(secret)
+	data, err := crypto.EncryptEnvelope(key, secret.Data)
+	if err != nil {
+		return fmt.Errorf("encrypt payload: %w", err)
+	}
+	secret.Data = data
+	if s.encryptMeta {
+		return s.sealMeta(secret, key)
+	}
+	return nil
+}
+
+func (s *SecretsService) decrypt(ctx context.Context, secret *models.Secret) error {
+	if err := s.loadBlob(ctx, secret); err != nil {
+		return err
+	}
+	key := s.contentKey(secret)
+	var data []byte
+	var err error
+	if crypto.IsEnvelope(secret.Data) {
+		data, err = crypto.DecryptEnvelope(key, secret.Data)
+	} else {
+		// Secrets written before envelope encryption are sealed directly
+		// under the vault key; they pick up a data key on their next write.
+		data, err = crypto.Decrypt(key, secret.Data)
+	}
+	if err != nil {
+		return fmt.Errorf("decrypt payload: %w", err)
+	}
+	secret.Data = data
+	return s.openMeta(secret, key)
+}
+
+// metaMarker prefixes a name or metadata value sealed at rest. Values
+// written before metadata encryption was enabled carry no marker and pass
+// through openField untouched.
+const metaMarker = "gkmeta1:"
+
+// sealField encrypts a metadata field under key into a marked string;
+// empty fields stay empty.
+func sealField(key []byte, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	sealed, err := crypto.Encrypt(key, []byte(value))
+	if err != nil {
+		return "", err
+	}
+	return metaMarker + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// openField decrypts a marked metadata field; unmarked values pass through.
+func openField(key []byte, value string) (string, error) {
+	if !strings.HasPrefix(value, metaMarker) {
+		return value, nil
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, metaMarker))
+	if err != nil {
+		return "", err
+	}
+	opened, err := crypto.Decrypt(key, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(opened), nil
+}
+
+// metaSealed reports whether the name or metadata of a stored secret is
+// sealed.
+func metaSealed(secret *models.Secret) bool {
+	return strings.HasPrefix(secret.Name, metaMarker) || strings.HasPrefix(secret.Metadata, metaMarker)
+}
+
+// nameIndex computes the keyed hash stored alongside a sealed name, so
+// exact-name lookups stay indexed without the plaintext.
+func nameIndex(key []byte, name string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	return mac.Sum(nil)[:16]
+}
+
+// sealMeta encrypts the name and metadata of a secret in place and sets
+// its name index; the payload stays untouched.
+func (s *SecretsService) sealMeta(secret *models.Secret, key []byte) error {
+	name, err := sealField(key, secret.Name)
+	if err != nil {
+		return fmt.Errorf("encrypt name: %w", err)
+	}
+	metadata, err := sealField(key, secret.Metadata)
+	if err != nil {
+		return fmt.Errorf("encrypt metadata: %w", err)
+	}
+	secret.NameIndex = nameIndex(key, secret.Name)
+	secret.Name = name
+	secret.Metadata = metadata
+	return nil
+}
+
+// openMeta decrypts a sealed name and metadata in place. Plaintext rows
+// pass through, so the setting can be toggled without rewriting the vault.
+func (s *SecretsService) openMeta(secret *models.Secret, key []byte) error {
+	name, err := openField(key, secret.Name)
+	if err != nil {
+		return fmt.Errorf("decrypt name: %w", err)
+	}
+	metadata, err := openField(key, secret.Metadata)
+	if err != nil {
+		return fmt.Errorf("decrypt metadata: %w", err)
+	}
+	secret.Name = name
+	secret.Metadata = metadata
+	return nil
+}
+
+// persistMeta rewrites the stored name, metadata and name index of a
+// decrypted secret for the vault it now lives in. Moves between vaults
+// carry only the payload, so sealed metadata must follow separately; with
+// metadata encryption off this rewrites the plaintext, healing rows whose
+// metadata was sealed under a key the secret left behind.
+func (s *SecretsService) persistMeta(ctx context.Context, secret *models.Secret) error {
+	stored := *secret
+	stored.NameIndex = nil
+	if s.encryptMeta {
+		if err := s.sealMeta(&stored, s.contentKey(&stored)); err != nil {
+			return err
+		}
+	}
+	return s.secrets.ReplaceMeta(ctx, stored.ID, stored.Name, stored.Metadata, stored.NameIndex)
+}
+
+// saveEncrypted encrypts a copy of secret, offloads an oversized payload to
+// the blob store and persists the copy with save. oldRef is the blob
+// reference being replaced, if any; it is removed once the row is written.
+func (s *SecretsService) saveEncrypted(ctx context.Context, secret *models.Secret, oldRef string, save func(context.Context, *models.Secret) error) error {
+	stored := *secret
+	if err := s.encrypt(&stored); err != nil {
+		return err
+	}
+	if err := s.offloadBlob(ctx, &stored); err != nil {
+		return err
+	}
+	if err := save(ctx, &stored); err != nil {
+		s.discardBlob(ctx, stored.BlobRef)
+		return err
+	}
+	if oldRef != stored.BlobRef {
+		s.discardBlob(ctx, oldRef)
+	}
+	return nil
+}
+
+// offloadBlob moves an encrypted payload above blob.InlineThreshold — or
+// any payload when OffloadAll is set — into the blob store, leaving only
+// the reference on the secret.
+func (s *SecretsService) offloadBlob(ctx context.Context, secret *models.Secret) error {
+	secret.BlobRef = ""
+	if s.blobs == nil || (!s.offloadAll && len(secret.Data) <= blob.InlineThreshold) {
+		return nil
+	}
+	ref := uuid.NewString()
+	if err := s.blobs.Put(ctx, ref, secret.Data); err != nil {
+		return fmt.Errorf("store blob: %w", err)
+	}
+	secret.Data = nil
+	secret.BlobRef = ref
+	return nil
+}
+
+// loadBlob restores an offloaded payload onto the secret.
+func (s *SecretsService) loadBlob(ctx context.Context, secret *models.Secret) error {
+	if secret.BlobRef == "" {
+		return nil
+	}
+	if s.blobs == nil {
+		return fmt.Errorf("secret %s is in the blob store but no store is configured", secret.ID)
+	}
+	data, err := s.blobs.Get(ctx, secret.BlobRef)
+	if err != nil {
+		return fmt.Errorf("load blob: %w", err)
+	}
+	secret.Data = data
+	return nil
+}
+
+// discardBlob removes a replaced or orphaned blob, logging failures: blob
+// garbage must never fail the main operation.
+func (s *SecretsService) discardBlob(ctx context.Context, ref string) {
+	if ref == "" || s.blobs == nil {
+		return
+	}
+	if err := s.blobs.Delete(ctx, ref); err != nil {
+		slog.WarnContext(ctx, "delete blob", "ref", ref, "err", err)
+	}
+}
+
+// userKey derives the per-user encryption key from the server master secret.
+func (s *SecretsService) userKey(userID uuid.UUID) []byte {
+	return crypto.DeriveKey(s.masterKey, userID[:])
+}
+
+// contentKey selects the encryption key of a secret: the org key for
+// secrets in an organization vault, the owner's key otherwise.
+func (s *SecretsService) contentKey(secret *models.Secret) []byte {
+	if secret.OrgID != uuid.Nil {
+		return crypto.DeriveKey(s.masterKey, secret.OrgID[:])
+	}
+	return s.userKey(secret.UserID)
+}
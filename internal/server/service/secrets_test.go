@@ -0,0 +1,526 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/blob"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+func newTestSecretsService() *SecretsService {
+	return NewSecretsService(SecretsServiceDeps{
+		Secrets:     memory.NewSecretRepository(),
+		Attachments: memory.NewAttachmentRepository(),
+		TM:          repository.NoopTransactionManager{},
+		MasterKey:   []byte("test master key"),
+	})
+}
+
+func mustCreateCredentials(t *testing.T, svc *SecretsService, userID uuid.UUID, password string) *models.Secret {
+	t.Helper()
+	secret := &models.Secret{
+		UserID: userID,
+		Name:   "example.com",
+		Type:   models.SecretTypeCredentials,
+	}
+	if err := secret.EncodePayload(&models.CredentialsPayload{Login: "user", Password: password}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateSecret(context.Background(), secret); err != nil {
+		t.Fatalf("CreateSecret: %v", err)
+	}
+	return secret
+}
+
+func updatePassword(t *testing.T, svc *SecretsService, secret *models.Secret, password string) []string {
+	t.Helper()
+	upd := &models.Secret{
+		ID:     secret.ID,
+		UserID: secret.UserID,
+		Name:   secret.Name,
+		Type:   secret.Type,
+	}
+	if err := upd.EncodePayload(&models.CredentialsPayload{Login: "user", Password: password}); err != nil {
+		t.Fatal(err)
+	}
+	warnings, err := svc.UpdateSecret(context.Background(), upd)
+	if err != nil {
+		t.Fatalf("UpdateSecret: %v", err)
+	}
+	return warnings
+}
+
+func TestSecretRoundTrip(t *testing.T) {
+	svc := newTestSecretsService()
+	userID := uuid.New()
+	secret := mustCreateCredentials(t, svc, userID, "hunter2")
+
+	got, err := svc.GetSecret(context.Background(), userID, secret.ID)
+	if err != nil {
+		t.Fatalf("GetSecret: %v", err)
+	}
+	var p models.CredentialsPayload
+	if err := got.DecodePayload(&p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Password != "hunter2" {
+		t.Fatalf("password = %q, want hunter2", p.Password)
+	}
+}
+
+func TestGetSecretWrongUser(t *testing.T) {
+	svc := newTestSecretsService()
+	secret := mustCreateCredentials(t, svc, uuid.New(), "hunter2")
+
+	if _, err := svc.GetSecret(context.Background(), uuid.New(), secret.ID); err != ErrNotFound {
+		t.Fatalf("GetSecret for other user: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUpdateRecordsPasswordHistory(t *testing.T) {
+	svc := newTestSecretsService()
+	userID := uuid.New()
+	secret := mustCreateCredentials(t, svc, userID, "first")
+
+	if w := updatePassword(t, svc, secret, "second"); len(w) != 0 {
+		t.Fatalf("unexpected warnings: %v", w)
+	}
+	if w := updatePassword(t, svc, secret, "third"); len(w) != 0 {
+		t.Fatalf("unexpected warnings: %v", w)
+	}
+
+	got, err := svc.GetSecret(context.Background(), userID, secret.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p models.CredentialsPayload
+	if err := got.DecodePayload(&p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Password != "third" {
+		t.Fatalf("password = %q, want third", p.Password)
+	}
+	if len(p.History) != 2 || p.History[0].Password != "second" || p.History[1].Password != "first" {
+		t.Fatalf("history = %+v, want [second first]", p.History)
+	}
+}
+
+func TestUpdateWarnsOnPasswordReuse(t *testing.T) {
+	svc := newTestSecretsService()
+	secret := mustCreateCredentials(t, svc, uuid.New(), "first")
+
+	updatePassword(t, svc, secret, "second")
+	warnings := updatePassword(t, svc, secret, "first")
+	if len(warnings) != 1 || warnings[0] != WarnPasswordReused {
+		t.Fatalf("warnings = %v, want [%q]", warnings, WarnPasswordReused)
+	}
+}
+
+func TestDeleteProtectedSecret(t *testing.T) {
+	svc := newTestSecretsService()
+	userID := uuid.New()
+	secret := &models.Secret{
+		UserID:    userID,
+		Name:      "recovery codes",
+		Type:      models.SecretTypeText,
+		Protected: true,
+	}
+	if err := secret.EncodePayload(&models.TextPayload{Content: "codes"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateSecret(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.DeleteSecret(context.Background(), userID, secret.ID, false); err != ErrProtected {
+		t.Fatalf("delete without confirm: err = %v, want ErrProtected", err)
+	}
+	if err := svc.DeleteSecret(context.Background(), userID, secret.ID, true); err != nil {
+		t.Fatalf("delete with confirm: %v", err)
+	}
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	svc := newTestSecretsService()
+	userID := uuid.New()
+	secret := &models.Secret{UserID: userID, Name: "github codes", Type: models.SecretTypeRecoveryCodes}
+	payload := &models.RecoveryCodesPayload{}
+	for _, c := range []string{"aaa", "bbb", "ccc", "ddd"} {
+		payload.Codes = append(payload.Codes, models.RecoveryCode{Code: c})
+	}
+	if err := secret.EncodePayload(payload); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateSecret(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+
+	code, remaining, warnings, err := svc.UseRecoveryCode(context.Background(), userID, secret.ID)
+	if err != nil {
+		t.Fatalf("UseRecoveryCode: %v", err)
+	}
+	if code != "aaa" || remaining != 3 {
+		t.Fatalf("code = %q remaining = %d, want aaa/3", code, remaining)
+	}
+	if len(warnings) != 1 || warnings[0] != WarnFewRecoveryCodes {
+		t.Fatalf("warnings = %v, want low-codes warning", warnings)
+	}
+
+	// A second call consumes the next code.
+	code, _, _, err = svc.UseRecoveryCode(context.Background(), userID, secret.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "bbb" {
+		t.Fatalf("second code = %q, want bbb", code)
+	}
+}
+
+func TestUseRecoveryCodeWrongType(t *testing.T) {
+	svc := newTestSecretsService()
+	userID := uuid.New()
+	secret := mustCreateCredentials(t, svc, userID, "pw")
+	if _, _, _, err := svc.UseRecoveryCode(context.Background(), userID, secret.ID); !errors.Is(err, ErrValidation) {
+		t.Fatalf("err = %v, want ErrValidation", err)
+	}
+}
+
+func TestLargePayloadOffloadsToBlobStore(t *testing.T) {
+	store, err := blob.NewDiskStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := memory.NewSecretRepository()
+	svc := NewSecretsService(SecretsServiceDeps{
+		Secrets:     repo,
+		Attachments: memory.NewAttachmentRepository(),
+		TM:          repository.NoopTransactionManager{},
+		Blobs:       store,
+		MasterKey:   []byte("test master key"),
+	})
+	userID := uuid.New()
+
+	secret := &models.Secret{UserID: userID, Name: "big file", Type: models.SecretTypeBinary}
+	content := bytes.Repeat([]byte("x"), blob.InlineThreshold+1)
+	if err := secret.EncodePayload(&models.BinaryPayload{Filename: "big.bin", Content: content}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateSecret(context.Background(), secret); err != nil {
+		t.Fatalf("CreateSecret: %v", err)
+	}
+
+	// The row must hold only the reference, not the payload.
+	row, err := repo.GetByID(context.Background(), userID, secret.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row.BlobRef == "" || len(row.Data) != 0 {
+		t.Fatalf("row not offloaded: ref=%q data=%d bytes", row.BlobRef, len(row.Data))
+	}
+
+	got, err := svc.GetSecret(context.Background(), userID, secret.ID)
+	if err != nil {
+		t.Fatalf("GetSecret: %v", err)
+	}
+	var p models.BinaryPayload
+	if err := got.DecodePayload(&p); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(p.Content, content) {
+		t.Fatal("payload mismatch after blob round trip")
+	}
+
+	if err := svc.DeleteSecret(context.Background(), userID, secret.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(context.Background(), row.BlobRef); !errors.Is(err, blob.ErrNotFound) {
+		t.Fatalf("blob not removed with secret: err = %v", err)
+	}
+}
+
+func TestOffloadAllKeepsPayloadsOutOfDatabase(t *testing.T) {
+	store, err := blob.NewDiskStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := memory.NewSecretRepository()
+	svc := NewSecretsService(SecretsServiceDeps{
+		Secrets:     repo,
+		Attachments: memory.NewAttachmentRepository(),
+		TM:          repository.NoopTransactionManager{},
+		Blobs:       store,
+		OffloadAll:  true,
+		MasterKey:   []byte("test master key"),
+	})
+	userID := uuid.New()
+
+	// Well below InlineThreshold, yet the row must hold only a reference.
+	secret := mustCreateCredentials(t, svc, userID, "pw")
+	row, err := repo.GetByID(context.Background(), userID, secret.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row.BlobRef == "" || len(row.Data) != 0 {
+		t.Fatalf("row not offloaded: ref=%q data=%d bytes", row.BlobRef, len(row.Data))
+	}
+	got, err := svc.GetSecret(context.Background(), userID, secret.ID)
+	if err != nil {
+		t.Fatalf("GetSecret: %v", err)
+	}
+	var p models.CredentialsPayload
+	if err := got.DecodePayload(&p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Password != "pw" {
+		t.Fatalf("password = %q after blob round trip, want pw", p.Password)
+	}
+}
+
+func TestListSortingAndKeysetPaging(t *testing.T) {
+	svc := newTestSecretsService()
+	userID := uuid.New()
+	for _, name := range []string{"charlie", "alpha", "bravo"} {
+		secret := &models.Secret{UserID: userID, Name: name, Type: models.SecretTypeText}
+		if err := secret.EncodePayload(&models.TextPayload{Content: name}); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.CreateSecret(context.Background(), secret); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, _, err := svc.GetSecrets(context.Background(), userID, repository.SecretListOptions{
+		SortBy: repository.SortByName,
+		Limit:  2,
+	})
+	if err != nil {
+		t.Fatalf("GetSecrets: %v", err)
+	}
+	if len(page) != 2 || page[0].Name != "alpha" || page[1].Name != "bravo" {
+		t.Fatalf("first page = %v, want [alpha bravo]", names(page))
+	}
+
+	rest, _, err := svc.GetSecrets(context.Background(), userID, repository.SecretListOptions{
+		SortBy: repository.SortByName,
+		Limit:  2,
+		After:  page[1].ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 1 || rest[0].Name != "charlie" {
+		t.Fatalf("second page = %v, want [charlie]", names(rest))
+	}
+
+	if _, _, err := svc.GetSecrets(context.Background(), userID, repository.SecretListOptions{SortBy: "data"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("sort by data: err = %v, want ErrValidation", err)
+	}
+}
+
+func names(secrets []models.Secret) []string {
+	out := make([]string, len(secrets))
+	for i, s := range secrets {
+		out[i] = s.Name
+	}
+	return out
+}
+
+func TestHistoryIsBounded(t *testing.T) {
+	p := &models.CredentialsPayload{Password: "p0"}
+	for i := 0; i < models.MaxPasswordHistory+5; i++ {
+		p.PushHistory(time.Now())
+	}
+	if len(p.History) != models.MaxPasswordHistory {
+		t.Fatalf("history length = %d, want %d", len(p.History), models.MaxPasswordHistory)
+	}
+}
+
+func TestStorageQuotas(t *testing.T) {
+	svc := NewSecretsService(SecretsServiceDeps{
+		Secrets:    memory.NewSecretRepository(),
+		TM:         repository.NoopTransactionManager{},
+		MasterKey:  []byte("test master key"),
+		MaxSecrets: 2,
+	})
+	userID := uuid.New()
+	mustCreateCredentials(t, svc, userID, "one")
+	mustCreateCredentials(t, svc, userID, "two")
+
+	third := &models.Secret{UserID: userID, Name: "third", Type: models.SecretTypeText, Data: []byte(`{"text":"x"}`)}
+	if err := svc.CreateSecret(context.Background(), third); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("third create error = %v, want ErrQuotaExceeded", err)
+	}
+	// Other users are unaffected.
+	mustCreateCredentials(t, svc, uuid.New(), "elsewhere")
+
+	usage, err := svc.Usage(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	if usage.SecretsCount != 2 || usage.MaxSecrets != 2 || usage.StorageBytes == 0 {
+		t.Fatalf("usage = %+v, want 2 of 2 secrets and non-zero bytes", usage)
+	}
+
+	tight := NewSecretsService(SecretsServiceDeps{
+		Secrets:         memory.NewSecretRepository(),
+		TM:              repository.NoopTransactionManager{},
+		MasterKey:       []byte("test master key"),
+		MaxStorageBytes: 10,
+	})
+	big := &models.Secret{UserID: userID, Name: "big", Type: models.SecretTypeText, Data: []byte(`{"text":"way past ten bytes"}`)}
+	if err := tight.CreateSecret(context.Background(), big); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("oversized create error = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestDecryptPreEnvelopeSecret(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestSecretsService()
+	userID := uuid.New()
+
+	// Secrets written before envelope encryption are sealed directly under
+	// the vault key; reads must still open them.
+	raw := []byte(`{"content":"legacy note"}`)
+	data, err := crypto.Encrypt(svc.userKey(userID), raw)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	legacy := &models.Secret{ID: uuid.New(), UserID: userID, Name: "legacy", Type: models.SecretTypeText, Data: data}
+	if err := svc.secrets.Create(ctx, legacy); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := svc.GetSecret(ctx, userID, legacy.ID)
+	if err != nil {
+		t.Fatalf("GetSecret: %v", err)
+	}
+	if !bytes.Equal(got.Data, raw) {
+		t.Fatalf("payload = %q, want %q", got.Data, raw)
+	}
+}
+
+func TestEncryptedMetadata(t *testing.T) {
+	ctx := context.Background()
+	secretRepo := memory.NewSecretRepository()
+	deps := SecretsServiceDeps{
+		Secrets:         secretRepo,
+		TM:              repository.NoopTransactionManager{},
+		MasterKey:       []byte("test master key"),
+		EncryptMetadata: true,
+	}
+	svc := NewSecretsService(deps)
+
+	userID := uuid.New()
+	secret := &models.Secret{
+		UserID:   userID,
+		Name:     "example.com",
+		Type:     models.SecretTypeText,
+		Metadata: "personal account",
+	}
+	if err := secret.EncodePayload(&models.TextPayload{Content: "note"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateSecret(ctx, secret); err != nil {
+		t.Fatalf("CreateSecret: %v", err)
+	}
+
+	stored, err := secretRepo.GetByID(ctx, userID, secret.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored.Name, metaMarker) || strings.Contains(stored.Name, "example.com") {
+		t.Fatalf("stored name is not sealed: %q", stored.Name)
+	}
+	if !strings.HasPrefix(stored.Metadata, metaMarker) {
+		t.Fatalf("stored metadata is not sealed: %q", stored.Metadata)
+	}
+	if len(stored.NameIndex) == 0 {
+		t.Fatal("stored secret has no name index")
+	}
+
+	got, err := svc.GetSecret(ctx, userID, secret.ID)
+	if err != nil {
+		t.Fatalf("GetSecret: %v", err)
+	}
+	if got.Name != "example.com" || got.Metadata != "personal account" {
+		t.Fatalf("metadata did not round-trip: name %q metadata %q", got.Name, got.Metadata)
+	}
+
+	// A row written while the setting was off keeps its plaintext name.
+	plain := NewSecretsService(SecretsServiceDeps{
+		Secrets:   secretRepo,
+		TM:        repository.NoopTransactionManager{},
+		MasterKey: deps.MasterKey,
+	})
+	other := &models.Secret{UserID: userID, Name: "other.com", Type: models.SecretTypeText}
+	if err := other.EncodePayload(&models.TextPayload{Content: "note"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.CreateSecret(ctx, other); err != nil {
+		t.Fatalf("CreateSecret: %v", err)
+	}
+
+	// Exact-name lookups find sealed rows through the keyed index and
+	// plaintext rows through the name itself.
+	for _, tc := range []struct {
+		name string
+		want uuid.UUID
+	}{{"example.com", secret.ID}, {"other.com", other.ID}} {
+		matches, _, err := svc.GetSecrets(ctx, userID, repository.SecretListOptions{Name: tc.name})
+		if err != nil {
+			t.Fatalf("GetSecrets(%q): %v", tc.name, err)
+		}
+		if len(matches) != 1 || matches[0].ID != tc.want {
+			t.Fatalf("GetSecrets(%q) returned %d secrets", tc.name, len(matches))
+		}
+	}
+	if matches, _, err := svc.GetSecrets(ctx, userID, repository.SecretListOptions{Name: "missing.com"}); err != nil || len(matches) != 0 {
+		t.Fatalf("GetSecrets(missing.com) = %d secrets, err %v", len(matches), err)
+	}
+}
+
+func TestGetSecretsReportsUndecryptable(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewSecretRepository()
+	svc := NewSecretsService(SecretsServiceDeps{
+		Secrets:   repo,
+		TM:        repository.NoopTransactionManager{},
+		MasterKey: []byte("test master key"),
+	})
+	userID := uuid.New()
+	good := mustCreateCredentials(t, svc, userID, "pw")
+	bad := mustCreateCredentials(t, svc, userID, "pw")
+	if err := repo.ReplaceData(ctx, bad.ID, []byte("corrupted"), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	secrets, failed, err := svc.GetSecrets(ctx, userID, repository.SecretListOptions{})
+	if err != nil {
+		t.Fatalf("GetSecrets: %v", err)
+	}
+	if len(secrets) != 1 || secrets[0].ID != good.ID {
+		t.Fatalf("got %d secrets, want only the readable one", len(secrets))
+	}
+	if len(failed) != 1 || failed[0].SecretID != bad.ID {
+		t.Fatalf("failed = %+v, want the corrupted secret", failed)
+	}
+
+	// When nothing decrypts the listing fails: the key is wrong, not the
+	// data.
+	if err := repo.ReplaceData(ctx, good.ID, []byte("also corrupted"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.GetSecrets(ctx, userID, repository.SecretListOptions{}); err == nil {
+		t.Fatal("expected an error when no secret decrypts")
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+const (
+	// shareTokenBytes is the entropy of a share URL token.
+	shareTokenBytes = 32
+	// shareDefaultTTL applies when a link is created without an expiry.
+	shareDefaultTTL = 24 * time.Hour
+	// shareMaxTTL bounds how long a share link may live.
+	shareMaxTTL = 7 * 24 * time.Hour
+)
+
+// shareKeySalt separates token-derived share keys from other key uses.
+var shareKeySalt = []byte("share-link")
+
+// ShareService owns single-use public share links. The stored name and
+// payload are encrypted with a key derived from the URL token, and only a
+// hash of the token is kept, so a link is readable exactly when the full
+// URL is presented.
+type ShareService struct {
+	shares  repository.ShareRepository
+	secrets *SecretsService
+	audit   AuditRecorder
+}
+
+// NewShareService creates a ShareService. Audit is optional.
+func NewShareService(shares repository.ShareRepository, secrets *SecretsService, audit AuditRecorder) *ShareService {
+	return &ShareService{shares: shares, secrets: secrets, audit: audit}
+}
+
+// CreateLink shares one secret of a user through a new single-use link and
+// returns the URL token. A zero ttl means shareDefaultTTL.
+func (s *ShareService) CreateLink(ctx context.Context, userID, secretID uuid.UUID, ttl time.Duration) (string, *models.ShareLink, error) {
+	if ttl == 0 {
+		ttl = shareDefaultTTL
+	}
+	if ttl < 0 || ttl > shareMaxTTL {
+		return "", nil, fmt.Errorf("%w: expiry must be between 0 and %s", ErrValidation, shareMaxTTL)
+	}
+	secret, err := s.secrets.GetSecret(ctx, userID, secretID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	raw := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+	key := crypto.DeriveKey(raw, shareKeySalt)
+	name, err := crypto.Encrypt(key, []byte(secret.Name))
+	if err != nil {
+		return "", nil, err
+	}
+	payload, err := crypto.Encrypt(key, secret.Data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	hash := sha256.Sum256(raw)
+	now := time.Now().UTC()
+	link := &models.ShareLink{
+		ID:        uuid.New(),
+		UserID:    userID,
+		SecretID:  secretID,
+		TokenHash: hash[:],
+		Name:      name,
+		Type:      secret.Type,
+		Payload:   payload,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	if err := s.shares.Create(ctx, link); err != nil {
+		return "", nil, err
+	}
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, models.AuditActionShareCreated, secretID.String())
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), link, nil
+}
+
+// Peek returns the decrypted name and expiry of a live link without
+// consuming it, for the confirmation page. Unknown, malformed and expired
+// tokens all come back as ErrNotFound.
+func (s *ShareService) Peek(ctx context.Context, token string) (string, time.Time, error) {
+	link, key, err := s.open(ctx, token)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	name, err := crypto.Decrypt(key, link.Name)
+	if err != nil {
+		return "", time.Time{}, ErrNotFound
+	}
+	return string(name), link.ExpiresAt, nil
+}
+
+// Reveal consumes a link: the link is deleted first, so the payload can be
+// handed out at most once even under concurrent requests.
+func (s *ShareService) Reveal(ctx context.Context, token string) (string, models.SecretType, []byte, error) {
+	link, key, err := s.open(ctx, token)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if err := s.shares.Delete(ctx, link.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", "", nil, ErrNotFound
+		}
+		return "", "", nil, err
+	}
+	name, err := crypto.Decrypt(key, link.Name)
+	if err != nil {
+		return "", "", nil, ErrNotFound
+	}
+	payload, err := crypto.Decrypt(key, link.Payload)
+	if err != nil {
+		return "", "", nil, ErrNotFound
+	}
+	if s.audit != nil {
+		s.audit.Record(ctx, link.UserID, models.AuditActionShareRevealed, link.SecretID.String())
+	}
+	return string(name), link.Type, payload, nil
+}
+
+// open resolves a token to its link and decryption key, retiring expired
+// links on the way.
+func (s *ShareService) open(ctx context.Context, token string) (*models.ShareLink, []byte, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != shareTokenBytes {
+		return nil, nil, ErrNotFound
+	}
+	hash := sha256.Sum256(raw)
+	link, err := s.shares.GetByTokenHash(ctx, hash[:])
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if time.Now().After(link.ExpiresAt) {
+		_ = s.shares.Delete(ctx, link.ID)
+		return nil, nil, ErrNotFound
+	}
+	return link, crypto.DeriveKey(raw, shareKeySalt), nil
+}
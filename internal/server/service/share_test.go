@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+// newShareFixture wires a ShareService over in-memory repositories with one
+// stored secret and returns the service with the owner and secret IDs.
+func newShareFixture(t *testing.T) (*ShareService, uuid.UUID, uuid.UUID) {
+	t.Helper()
+	secrets := NewSecretsService(SecretsServiceDeps{
+		Secrets:   memory.NewSecretRepository(),
+		TM:        repository.NoopTransactionManager{},
+		MasterKey: []byte("test master key"),
+	})
+	userID := uuid.New()
+	secret := &models.Secret{
+		UserID: userID,
+		Name:   "prod database",
+		Type:   models.SecretTypeText,
+		Data:   []byte(`{"text":"hunter2"}`),
+	}
+	if err := secrets.CreateSecret(context.Background(), secret); err != nil {
+		t.Fatalf("CreateSecret: %v", err)
+	}
+	return NewShareService(memory.NewShareRepository(), secrets, nil), userID, secret.ID
+}
+
+func TestShareLinkRevealOnce(t *testing.T) {
+	svc, userID, secretID := newShareFixture(t)
+	ctx := context.Background()
+
+	token, link, err := svc.CreateLink(ctx, userID, secretID, 0)
+	if err != nil {
+		t.Fatalf("CreateLink: %v", err)
+	}
+	if string(link.Payload) == `{"text":"hunter2"}` {
+		t.Error("payload stored in the clear")
+	}
+
+	name, expiresAt, err := svc.Peek(ctx, token)
+	if err != nil {
+		t.Fatalf("Peek: %v", err)
+	}
+	if name != "prod database" {
+		t.Errorf("Peek name = %q", name)
+	}
+	if !expiresAt.After(time.Now()) {
+		t.Error("link already expired")
+	}
+
+	name, secretType, payload, err := svc.Reveal(ctx, token)
+	if err != nil {
+		t.Fatalf("Reveal: %v", err)
+	}
+	if name != "prod database" || secretType != models.SecretTypeText || string(payload) != `{"text":"hunter2"}` {
+		t.Errorf("Reveal = %q, %q, %q", name, secretType, payload)
+	}
+
+	// The link burns on reveal.
+	if _, _, _, err := svc.Reveal(ctx, token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Reveal = %v, want ErrNotFound", err)
+	}
+	if _, _, err := svc.Peek(ctx, token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Peek after Reveal = %v, want ErrNotFound", err)
+	}
+}
+
+func TestShareLinkRejects(t *testing.T) {
+	svc, userID, secretID := newShareFixture(t)
+	ctx := context.Background()
+
+	if _, _, err := svc.CreateLink(ctx, userID, secretID, 30*24*time.Hour); !errors.Is(err, ErrValidation) {
+		t.Errorf("CreateLink(long ttl) = %v, want ErrValidation", err)
+	}
+	if _, _, err := svc.CreateLink(ctx, userID, uuid.New(), 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CreateLink(unknown secret) = %v, want ErrNotFound", err)
+	}
+	if _, _, err := svc.Peek(ctx, "not-a-token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Peek(garbage) = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// streamBuffer is how many undelivered events a single subscriber may lag
+// behind before new ones are dropped for it.
+const streamBuffer = 16
+
+// StreamHub fans outbox events out to connected sync streams, keyed by
+// user. Delivery is best-effort: a subscriber that cannot keep up misses
+// events and recovers through its next regular sync, so Publish never
+// blocks the dispatcher and never fails.
+type StreamHub struct {
+	mu   sync.RWMutex
+	subs map[uuid.UUID]map[chan models.OutboxEvent]struct{}
+}
+
+// NewStreamHub creates an empty StreamHub.
+func NewStreamHub() *StreamHub {
+	return &StreamHub{subs: make(map[uuid.UUID]map[chan models.OutboxEvent]struct{})}
+}
+
+// Subscribe registers a listener for one user's change events. The returned
+// cancel function must be called when the listener goes away.
+func (h *StreamHub) Subscribe(userID uuid.UUID) (<-chan models.OutboxEvent, func()) {
+	ch := make(chan models.OutboxEvent, streamBuffer)
+	h.mu.Lock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[chan models.OutboxEvent]struct{})
+	}
+	h.subs[userID][ch] = struct{}{}
+	h.mu.Unlock()
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subs[userID], ch)
+		if len(h.subs[userID]) == 0 {
+			delete(h.subs, userID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Publish implements OutboxPublisher.
+func (h *StreamHub) Publish(_ context.Context, event models.OutboxEvent) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subs[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	return nil
+}
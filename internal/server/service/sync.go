@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// Warnings attached to partial sync responses.
+const (
+	WarnSyncSecretsUnavailable    = "modified secrets could not be fetched, retry with the returned token"
+	WarnSyncOperationsUnavailable = "sync operations could not be fetched, retry with the returned token"
+)
+
+// syncOperationsLimit caps the operations stream per response; the client
+// follows NextCursor for the rest.
+const syncOperationsLimit = 1000
+
+// SyncService answers incremental sync requests from two streams: secrets
+// by modification time and operations from the audit trail by cursor. The
+// streams fail independently, so a flaky database degrades a sync to a
+// partial response instead of an error.
+type SyncService struct {
+	secrets *SecretsService
+	audit   *AuditService
+	devices repository.DeviceRepository
+}
+
+// NewSyncService creates a SyncService. Operations go through the
+// AuditService so their resources leave storage decrypted. devices may be
+// nil, which disables per-device sync points.
+func NewSyncService(secrets *SecretsService, audit *AuditService, devices repository.DeviceRepository) *SyncService {
+	return &SyncService{secrets: secrets, audit: audit, devices: devices}
+}
+
+// retryToken is the decoded form of SyncRequest.RetryToken.
+type retryToken struct {
+	Since       time.Time `json:"since"`
+	AuditCursor int64     `json:"audit_cursor"`
+}
+
+func encodeRetryToken(t retryToken) string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeRetryToken(s string) (retryToken, error) {
+	var t retryToken
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// ProcessSyncRequest returns the changes since the request's sync point.
+// When one stream fails it still returns the other, with a warning and a
+// retry token that re-requests only the missing part; it errors only when
+// nothing could be fetched.
+func (s *SyncService) ProcessSyncRequest(ctx context.Context, userID uuid.UUID, req models.SyncRequest) (*models.SyncResponse, error) {
+	// A registered device syncs from its server-recorded point; the fields
+	// the client sent only matter for anonymous syncs and retry tokens.
+	var device *models.Device
+	if req.DeviceID != uuid.Nil && s.devices != nil {
+		var err error
+		device, err = s.devices.Get(ctx, userID, req.DeviceID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: unknown device", ErrValidation)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if device.Revoked {
+			return nil, ErrDeviceRevoked
+		}
+		req.Since, req.AuditCursor = device.LastSyncedAt, device.LastCursor
+	}
+	if req.RetryToken != "" {
+		token, err := decodeRetryToken(req.RetryToken)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad retry token", ErrValidation)
+		}
+		req.Since, req.AuditCursor = token.Since, token.AuditCursor
+	}
+
+	resp := &models.SyncResponse{SyncedAt: time.Now().UTC(), NextCursor: req.AuditCursor}
+
+	// Client pushes go first, so the pull below already reflects them. A
+	// rejected change warns instead of failing the whole sync.
+	for i := range req.Changes {
+		change := req.Changes[i]
+		change.UserID = userID
+		if err := s.secrets.UpsertSecret(ctx, &change); err != nil {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("change %s rejected: %v", change.ID, err))
+		}
+	}
+
+	secrets, secretsErr := s.secrets.GetSecretsModifiedAfter(ctx, userID, req.Since)
+	operations, opsErr := s.audit.ListAfter(ctx, userID, req.AuditCursor, syncOperationsLimit)
+	if secretsErr != nil && opsErr != nil {
+		return nil, fmt.Errorf("sync: secrets: %v; operations: %w", secretsErr, opsErr)
+	}
+
+	retry := retryToken{Since: resp.SyncedAt, AuditCursor: req.AuditCursor}
+	if secretsErr != nil {
+		slog.WarnContext(ctx, "sync secrets", "user", userID, "err", secretsErr)
+		resp.Warnings = append(resp.Warnings, WarnSyncSecretsUnavailable)
+		retry.Since = req.Since
+	} else {
+		resp.Secrets = dropUnchanged(secrets, req.ClientHashes)
+	}
+	if opsErr != nil {
+		slog.WarnContext(ctx, "sync operations", "user", userID, "err", opsErr)
+		resp.Warnings = append(resp.Warnings, WarnSyncOperationsUnavailable)
+	} else {
+		// Sync bookkeeping events stay in the audit trail but out of the
+		// replication stream, or every sync would dirty the next one.
+		resp.Operations = dropSyncEvents(operations)
+		resp.DeletedSecrets = tombstones(operations)
+		if n := len(operations); n > 0 {
+			resp.NextCursor = operations[n-1].ID
+		}
+		retry.AuditCursor = resp.NextCursor
+	}
+	if len(resp.Warnings) > 0 {
+		resp.RetryToken = encodeRetryToken(retry)
+	}
+	// The retry values double as the device's next sync point: on a clean
+	// sync they equal SyncedAt/NextCursor, on a partial one they re-request
+	// what was missed. Losing the update only widens the next delta.
+	if device != nil {
+		if err := s.devices.UpdateSyncState(ctx, userID, device.ID, retry.Since, retry.AuditCursor); err != nil {
+			slog.WarnContext(ctx, "update device sync state", "device", device.ID, "err", err)
+		}
+	}
+	var deviceResource string
+	if device != nil {
+		deviceResource = device.ID.String()
+	}
+	s.audit.Record(ctx, userID, models.AuditActionSync, deviceResource)
+	return resp, nil
+}
+
+// dropSyncEvents filters sync bookkeeping out of an operations page.
+func dropSyncEvents(operations []models.AuditEvent) []models.AuditEvent {
+	kept := operations[:0]
+	for _, op := range operations {
+		if op.Action != models.AuditActionSync {
+			kept = append(kept, op)
+		}
+	}
+	return kept
+}
+
+// dropUnchanged filters out secrets whose content hash matches what the
+// client reported holding, keeping responses small when only timestamps
+// moved.
+func dropUnchanged(secrets []models.Secret, clientHashes map[uuid.UUID]string) []models.Secret {
+	if len(clientHashes) == 0 {
+		return secrets
+	}
+	changed := secrets[:0]
+	for i := range secrets {
+		if clientHashes[secrets[i].ID] != secrets[i].SyncHash() {
+			changed = append(changed, secrets[i])
+		}
+	}
+	return changed
+}
+
+// tombstones extracts the IDs of deleted secrets from the operations
+// stream, deduplicated in order of first deletion.
+func tombstones(operations []models.AuditEvent) []uuid.UUID {
+	var deleted []uuid.UUID
+	seen := make(map[uuid.UUID]struct{})
+	for _, op := range operations {
+		if op.Action != models.AuditActionSecretDeleted {
+			continue
+		}
+		id, err := uuid.Parse(op.Resource)
+		if err != nil {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		deleted = append(deleted, id)
+	}
+	return deleted
+}
+
+// GetSecretsModifiedAfter fetches and decrypts the secrets updated after
+// the given time, oldest first.
+func (s *SecretsService) GetSecretsModifiedAfter(ctx context.Context, userID uuid.UUID, after time.Time) ([]models.Secret, error) {
+	secrets, err := s.secrets.GetModifiedAfter(ctx, userID, after)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := secrets[:0]
+	for i := range secrets {
+		if err := s.decrypt(ctx, &secrets[i]); err != nil {
+			slog.WarnContext(ctx, "skipping secret", "secret", secrets[i].ID, "err", err)
+			continue
+		}
+		decrypted = append(decrypted, secrets[i])
+	}
+	return decrypted, nil
+}
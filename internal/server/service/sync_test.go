@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+// failingAuditRepo breaks the operations stream to exercise partial syncs.
+type failingAuditRepo struct{}
+
+var errAuditDown = errors.New("audit store down")
+
+func (failingAuditRepo) Append(context.Context, *models.AuditEvent) error { return errAuditDown }
+func (failingAuditRepo) ListAfter(context.Context, uuid.UUID, int64, int) ([]models.AuditEvent, error) {
+	return nil, errAuditDown
+}
+func (failingAuditRepo) ListRecentByActions(context.Context, uuid.UUID, []string, int) ([]models.AuditEvent, error) {
+	return nil, errAuditDown
+}
+
+func TestSyncReturnsBothStreams(t *testing.T) {
+	secretsSvc := newTestSecretsService()
+	auditRepo := memory.NewAuditRepository()
+	audit := NewAuditService(auditRepo, nil, []byte("test master key"))
+	svc := NewSyncService(secretsSvc, audit, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	secret := mustCreateCredentials(t, secretsSvc, userID, "pw")
+	audit.Record(ctx, userID, models.AuditActionSecretCreated, secret.ID.String())
+
+	resp, err := svc.ProcessSyncRequest(ctx, userID, models.SyncRequest{})
+	if err != nil {
+		t.Fatalf("ProcessSyncRequest: %v", err)
+	}
+	if len(resp.Secrets) != 1 || len(resp.Operations) != 1 {
+		t.Fatalf("got %d secrets and %d operations, want 1 and 1", len(resp.Secrets), len(resp.Operations))
+	}
+	if len(resp.Warnings) != 0 || resp.RetryToken != "" {
+		t.Fatalf("complete sync must not warn: warnings=%v token=%q", resp.Warnings, resp.RetryToken)
+	}
+	if resp.NextCursor != resp.Operations[0].ID {
+		t.Fatalf("next cursor = %d, want %d", resp.NextCursor, resp.Operations[0].ID)
+	}
+	if resp.Operations[0].Resource != secret.ID.String() {
+		t.Fatalf("operation resource = %q, want the secret id", resp.Operations[0].Resource)
+	}
+	// At rest the linkage to the secret is encrypted.
+	raw, err := auditRepo.ListAfter(ctx, userID, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw[0].Resource == secret.ID.String() {
+		t.Fatal("stored resource must not be the plaintext secret id")
+	}
+
+	// The next incremental request sees nothing new.
+	next, err := svc.ProcessSyncRequest(ctx, userID, models.SyncRequest{
+		Since: resp.SyncedAt, AuditCursor: resp.NextCursor,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Secrets) != 0 || len(next.Operations) != 0 {
+		t.Fatalf("incremental sync returned %d secrets and %d operations, want none",
+			len(next.Secrets), len(next.Operations))
+	}
+}
+
+func TestSyncPartialOnOperationsFailure(t *testing.T) {
+	secretsSvc := newTestSecretsService()
+	svc := NewSyncService(secretsSvc, NewAuditService(failingAuditRepo{}, nil, nil), nil)
+	ctx := context.Background()
+	userID := uuid.New()
+	mustCreateCredentials(t, secretsSvc, userID, "pw")
+
+	resp, err := svc.ProcessSyncRequest(ctx, userID, models.SyncRequest{AuditCursor: 7})
+	if err != nil {
+		t.Fatalf("partial sync must not error: %v", err)
+	}
+	if len(resp.Secrets) != 1 {
+		t.Fatalf("got %d secrets, want the working stream delivered", len(resp.Secrets))
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0] != WarnSyncOperationsUnavailable {
+		t.Fatalf("warnings = %v, want operations warning", resp.Warnings)
+	}
+	if resp.RetryToken == "" {
+		t.Fatal("partial sync must return a retry token")
+	}
+
+	// The token re-requests only the missing operations: it keeps the old
+	// cursor but advances past the secrets already delivered.
+	token, err := decodeRetryToken(resp.RetryToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AuditCursor != 7 {
+		t.Fatalf("token cursor = %d, want 7", token.AuditCursor)
+	}
+	if !token.Since.Equal(resp.SyncedAt) {
+		t.Fatalf("token since = %v, want %v", token.Since, resp.SyncedAt)
+	}
+}
+
+func TestSyncRejectsBadRetryToken(t *testing.T) {
+	svc := NewSyncService(newTestSecretsService(), NewAuditService(memory.NewAuditRepository(), nil, nil), nil)
+	_, err := svc.ProcessSyncRequest(context.Background(), uuid.New(), models.SyncRequest{RetryToken: "not a token"})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("err = %v, want ErrValidation", err)
+	}
+}
+
+func TestSyncDelta(t *testing.T) {
+	audit := NewAuditService(memory.NewAuditRepository(), nil, []byte("test master key"))
+	// The secrets service shares the audit trail so deletions become
+	// tombstones.
+	secretsSvc := NewSecretsService(SecretsServiceDeps{
+		Secrets:   memory.NewSecretRepository(),
+		TM:        repository.NoopTransactionManager{},
+		MasterKey: []byte("test master key"),
+		Audit:     audit,
+	})
+	svc := NewSyncService(secretsSvc, audit, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	kept := mustCreateCredentials(t, secretsSvc, userID, "pw")
+	doomed := mustCreateCredentials(t, secretsSvc, userID, "pw")
+	if err := secretsSvc.DeleteSecret(ctx, userID, doomed.ID, true); err != nil {
+		t.Fatalf("DeleteSecret: %v", err)
+	}
+
+	// A matching client hash suppresses the unchanged secret; the deletion
+	// arrives as a tombstone.
+	current, err := secretsSvc.GetSecret(ctx, userID, kept.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := svc.ProcessSyncRequest(ctx, userID, models.SyncRequest{
+		ClientHashes: map[uuid.UUID]string{kept.ID: current.SyncHash()},
+	})
+	if err != nil {
+		t.Fatalf("ProcessSyncRequest: %v", err)
+	}
+	if len(resp.Secrets) != 0 {
+		t.Errorf("got %d secrets, want 0 with matching hashes", len(resp.Secrets))
+	}
+	if len(resp.DeletedSecrets) != 1 || resp.DeletedSecrets[0] != doomed.ID {
+		t.Errorf("tombstones = %v, want [%s]", resp.DeletedSecrets, doomed.ID)
+	}
+
+	// A stale hash brings the secret back.
+	resp, err = svc.ProcessSyncRequest(ctx, userID, models.SyncRequest{
+		ClientHashes: map[uuid.UUID]string{kept.ID: "stale"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Secrets) != 1 || resp.Secrets[0].ID != kept.ID {
+		t.Errorf("got %d secrets, want the changed one", len(resp.Secrets))
+	}
+}
+
+func TestSyncUpsertsClientChanges(t *testing.T) {
+	secretsSvc := newTestSecretsService()
+	audit := NewAuditService(memory.NewAuditRepository(), nil, []byte("test master key"))
+	svc := NewSyncService(secretsSvc, audit, nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	change := models.Secret{
+		ID:   uuid.New(),
+		Name: "offline note",
+		Type: models.SecretTypeText,
+		Data: []byte(`{"text":"written offline"}`),
+	}
+	resp, err := svc.ProcessSyncRequest(ctx, userID, models.SyncRequest{Changes: []models.Secret{change}})
+	if err != nil {
+		t.Fatalf("ProcessSyncRequest: %v", err)
+	}
+	if len(resp.Warnings) != 0 {
+		t.Fatalf("warnings = %v", resp.Warnings)
+	}
+	// The push is visible in the same response and kept its client ID.
+	if len(resp.Secrets) != 1 || resp.Secrets[0].ID != change.ID {
+		t.Fatalf("response secrets = %v, want the pushed change", resp.Secrets)
+	}
+
+	// An invalid change warns without failing the sync.
+	bad := models.Secret{ID: uuid.New(), Type: "bogus"}
+	resp, err = svc.ProcessSyncRequest(ctx, userID, models.SyncRequest{Changes: []models.Secret{bad}})
+	if err != nil {
+		t.Fatalf("ProcessSyncRequest with bad change: %v", err)
+	}
+	if len(resp.Warnings) != 1 {
+		t.Errorf("warnings = %v, want one rejection", resp.Warnings)
+	}
+}
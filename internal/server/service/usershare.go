@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// UserShareService shares single secrets between accounts. A grant gives
+// the recipient read or read-write access to the owner's live secret: the
+// payload stays encrypted under the owner's key and is re-encrypted for
+// the recipient on each read, so recipients always see the current
+// version and revoking a grant cuts access immediately.
+type UserShareService struct {
+	shares  repository.SecretShareRepository
+	users   repository.UserRepository
+	secrets *SecretsService
+	audit   AuditRecorder
+}
+
+// NewUserShareService creates a UserShareService. audit may be nil.
+func NewUserShareService(shares repository.SecretShareRepository, users repository.UserRepository, secrets *SecretsService, audit AuditRecorder) *UserShareService {
+	return &UserShareService{shares: shares, users: users, secrets: secrets, audit: audit}
+}
+
+func (s *UserShareService) record(ctx context.Context, userID uuid.UUID, action string, secretID uuid.UUID) {
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, action, secretID.String())
+	}
+}
+
+// Share grants a registered user access to one of the owner's secrets,
+// or changes the mode of an existing grant.
+func (s *UserShareService) Share(ctx context.Context, ownerID, secretID uuid.UUID, login, mode string) (*models.SecretShare, error) {
+	if mode != models.ShareModeRead && mode != models.ShareModeWrite {
+		return nil, fmt.Errorf("%w: mode must be %s or %s", ErrValidation, models.ShareModeRead, models.ShareModeWrite)
+	}
+	if _, err := s.secrets.secrets.GetByID(ctx, ownerID, secretID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	recipient, err := s.users.GetByLogin(ctx, login)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: user %q is not registered", ErrValidation, login)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if recipient.ID == ownerID {
+		return nil, fmt.Errorf("%w: cannot share a secret with yourself", ErrValidation)
+	}
+	share := &models.SecretShare{
+		SecretID:    secretID,
+		OwnerID:     ownerID,
+		RecipientID: recipient.ID,
+		Login:       recipient.Login,
+		Mode:        mode,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.shares.Upsert(ctx, share); err != nil {
+		return nil, err
+	}
+	s.record(ctx, ownerID, models.AuditActionShareGranted, secretID)
+	return share, nil
+}
+
+// Unshare revokes a recipient's grant on one of the owner's secrets.
+func (s *UserShareService) Unshare(ctx context.Context, ownerID, secretID uuid.UUID, login string) error {
+	recipient, err := s.users.GetByLogin(ctx, login)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: user %q is not registered", ErrValidation, login)
+	}
+	if err != nil {
+		return err
+	}
+	share, err := s.shares.Get(ctx, secretID, recipient.ID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && share.OwnerID != ownerID) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.shares.Delete(ctx, secretID, recipient.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	s.record(ctx, ownerID, models.AuditActionShareRevoked, secretID)
+	return nil
+}
+
+// Shares lists the grants of one of the owner's secrets with recipient
+// logins resolved.
+func (s *UserShareService) Shares(ctx context.Context, ownerID, secretID uuid.UUID) ([]models.SecretShare, error) {
+	if _, err := s.secrets.secrets.GetByID(ctx, ownerID, secretID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	shares, err := s.shares.ListBySecret(ctx, ownerID, secretID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		if user, err := s.users.GetByID(ctx, shares[i].RecipientID); err == nil {
+			shares[i].Login = user.Login
+		}
+	}
+	return shares, nil
+}
+
+// SharedWithMe lists the secrets shared with the caller, decrypted, with
+// the grant mode and owner login attached. A share whose secret has gone
+// away is skipped rather than failing the listing.
+func (s *UserShareService) SharedWithMe(ctx context.Context, recipientID uuid.UUID) ([]models.SharedSecret, error) {
+	grants, err := s.shares.ListByRecipient(ctx, recipientID)
+	if err != nil {
+		return nil, err
+	}
+	var shared []models.SharedSecret
+	for _, grant := range grants {
+		secret, err := s.openShared(ctx, &grant)
+		if err != nil {
+			slog.WarnContext(ctx, "skipping shared secret", "secret", grant.SecretID, "err", err)
+			continue
+		}
+		entry := models.SharedSecret{Secret: *secret, Mode: grant.Mode}
+		if owner, err := s.users.GetByID(ctx, grant.OwnerID); err == nil {
+			entry.Owner = owner.Login
+		}
+		shared = append(shared, entry)
+	}
+	return shared, nil
+}
+
+// GetShared fetches one secret shared with the caller.
+func (s *UserShareService) GetShared(ctx context.Context, recipientID, secretID uuid.UUID) (*models.SharedSecret, error) {
+	grant, err := s.shares.Get(ctx, secretID, recipientID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.openShared(ctx, grant)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	entry := &models.SharedSecret{Secret: *secret, Mode: grant.Mode}
+	if owner, err := s.users.GetByID(ctx, grant.OwnerID); err == nil {
+		entry.Owner = owner.Login
+	}
+	s.record(ctx, recipientID, models.AuditActionSecretRead, secretID)
+	return entry, nil
+}
+
+// UpdateShared rewrites a secret shared with the caller under a
+// read-write grant. The update runs through the owner's normal update
+// path, so versioning and password history behave as if the owner had
+// edited the secret.
+func (s *UserShareService) UpdateShared(ctx context.Context, recipientID uuid.UUID, secret *models.Secret) ([]string, error) {
+	grant, err := s.shares.Get(ctx, secret.ID, recipientID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if grant.Mode != models.ShareModeWrite {
+		return nil, fmt.Errorf("%w: share is read-only", ErrValidation)
+	}
+	secret.UserID = grant.OwnerID
+	return s.secrets.UpdateSecret(ctx, secret)
+}
+
+// openShared fetches and decrypts the live secret behind a grant.
+func (s *UserShareService) openShared(ctx context.Context, grant *models.SecretShare) (*models.Secret, error) {
+	secret, err := s.secrets.secrets.GetByID(ctx, grant.OwnerID, grant.SecretID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.secrets.decrypt(ctx, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
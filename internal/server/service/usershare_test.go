@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestUserShareLifecycle(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	auth := NewAuthService(users, []byte("test jwt secret"), []byte("test master key"), nil)
+	secrets := newTestSecretsService()
+	svc := NewUserShareService(memory.NewSecretShareRepository(), users, secrets, nil)
+
+	for _, login := range []string{"alice", "bob"} {
+		if _, err := auth.Register(ctx, login, login+" password", ""); err != nil {
+			t.Fatalf("Register(%s): %v", login, err)
+		}
+	}
+	alice, _ := users.GetByLogin(ctx, "alice")
+	bob, _ := users.GetByLogin(ctx, "bob")
+	secret := mustCreateCredentials(t, secrets, alice.ID, "original password")
+
+	if _, err := svc.Share(ctx, alice.ID, secret.ID, "alice", models.ShareModeRead); !errors.Is(err, ErrValidation) {
+		t.Fatalf("self-share error = %v, want ErrValidation", err)
+	}
+	if _, err := svc.Share(ctx, alice.ID, secret.ID, "nobody", models.ShareModeRead); !errors.Is(err, ErrValidation) {
+		t.Fatalf("unknown login error = %v, want ErrValidation", err)
+	}
+	if _, err := svc.Share(ctx, alice.ID, secret.ID, "bob", "admin"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("bad mode error = %v, want ErrValidation", err)
+	}
+
+	if _, err := svc.Share(ctx, alice.ID, secret.ID, "bob", models.ShareModeRead); err != nil {
+		t.Fatalf("Share: %v", err)
+	}
+	shared, err := svc.GetShared(ctx, bob.ID, secret.ID)
+	if err != nil {
+		t.Fatalf("GetShared: %v", err)
+	}
+	var payload models.CredentialsPayload
+	if err := shared.Secret.DecodePayload(&payload); err != nil || payload.Password != "original password" {
+		t.Fatalf("shared payload = %+v, %v; want decrypted password", payload, err)
+	}
+
+	// A read grant refuses writes; upgrading it to write allows them, and
+	// the recipient sees the owner's live secret afterwards.
+	upd := &models.Secret{ID: secret.ID, Name: secret.Name, Type: secret.Type}
+	if err := upd.EncodePayload(&models.CredentialsPayload{Login: "user", Password: "changed password"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.UpdateShared(ctx, bob.ID, upd); !errors.Is(err, ErrValidation) {
+		t.Fatalf("read-only update error = %v, want ErrValidation", err)
+	}
+	if _, err := svc.Share(ctx, alice.ID, secret.ID, "bob", models.ShareModeWrite); err != nil {
+		t.Fatalf("upgrade to write: %v", err)
+	}
+	if _, err := svc.UpdateShared(ctx, bob.ID, upd); err != nil {
+		t.Fatalf("UpdateShared: %v", err)
+	}
+	owned, err := secrets.GetSecret(ctx, alice.ID, secret.ID)
+	if err != nil {
+		t.Fatalf("owner get after shared update: %v", err)
+	}
+	if err := owned.DecodePayload(&payload); err != nil || payload.Password != "changed password" {
+		t.Fatalf("owner payload = %+v, %v; want updated password", payload, err)
+	}
+
+	list, err := svc.SharedWithMe(ctx, bob.ID)
+	if err != nil || len(list) != 1 || list[0].Owner != "alice" || list[0].Mode != models.ShareModeWrite {
+		t.Fatalf("SharedWithMe = %+v, %v; want one write share from alice", list, err)
+	}
+	shares, err := svc.Shares(ctx, alice.ID, secret.ID)
+	if err != nil || len(shares) != 1 || shares[0].Login != "bob" {
+		t.Fatalf("Shares = %+v, %v; want one grant for bob", shares, err)
+	}
+
+	if err := svc.Unshare(ctx, alice.ID, secret.ID, "bob"); err != nil {
+		t.Fatalf("Unshare: %v", err)
+	}
+	if _, err := svc.GetShared(ctx, bob.ID, secret.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("revoked get error = %v, want ErrNotFound", err)
+	}
+
+	// Only the owner can revoke: bob cannot share or unshare alice's
+	// secret through his own account.
+	if _, err := svc.Share(ctx, bob.ID, secret.ID, "alice", models.ShareModeRead); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("non-owner share error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/pkg/crypto"
+)
+
+// versionRecord is what a secret version stores, encrypted as one blob: the
+// superseded payload and the field-level summary of the update that replaced
+// it.
+type versionRecord struct {
+	Payload json.RawMessage `json:"payload"`
+	Summary string          `json:"summary"`
+}
+
+// snapshotVersion stores the payload of existing as a new version, with a
+// summary of how incoming differs from it; a non-empty summary overrides the
+// computed diff. Both payloads are plaintext here; the record is encrypted
+// before it reaches the repository.
+func (s *SecretsService) snapshotVersion(ctx context.Context, existing *models.Secret, incoming []byte, summary string) error {
+	if s.versions == nil {
+		return nil
+	}
+	if summary == "" {
+		summary = diffSummary(existing.Data, incoming)
+	}
+	record, err := json.Marshal(versionRecord{
+		Payload: existing.Data,
+		Summary: summary,
+	})
+	if err != nil {
+		return err
+	}
+	data, err := crypto.Encrypt(s.userKey(existing.UserID), record)
+	if err != nil {
+		return fmt.Errorf("encrypt version: %w", err)
+	}
+	return s.versions.Create(ctx, &models.SecretVersion{
+		ID:        uuid.New(),
+		SecretID:  existing.ID,
+		UserID:    existing.UserID,
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+// GetSecretVersions lists the versions of a secret with decrypted change
+// summaries. Payload data is omitted; GetSecretVersion returns it.
+func (s *SecretsService) GetSecretVersions(ctx context.Context, userID, secretID uuid.UUID) ([]models.SecretVersion, error) {
+	if _, err := s.secrets.GetByID(ctx, userID, secretID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if s.versions == nil {
+		return nil, nil
+	}
+	versions, err := s.versions.ListBySecret(ctx, userID, secretID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		record, err := s.decryptVersion(&versions[i])
+		if err != nil {
+			return nil, err
+		}
+		versions[i].Summary = record.Summary
+		versions[i].Data = nil
+	}
+	return versions, nil
+}
+
+// GetSecretVersion fetches one snapshot with its decrypted payload.
+func (s *SecretsService) GetSecretVersion(ctx context.Context, userID, secretID uuid.UUID, version int) (*models.SecretVersion, error) {
+	if s.versions == nil {
+		return nil, ErrNotFound
+	}
+	v, err := s.versions.GetByVersion(ctx, userID, secretID, version)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	record, err := s.decryptVersion(v)
+	if err != nil {
+		return nil, err
+	}
+	v.Summary = record.Summary
+	v.Data = record.Payload
+	return v, nil
+}
+
+func (s *SecretsService) decryptVersion(v *models.SecretVersion) (*versionRecord, error) {
+	data, err := crypto.Decrypt(s.userKey(v.UserID), v.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt version %d: %w", v.Version, err)
+	}
+	var record versionRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("decode version %d: %w", v.Version, err)
+	}
+	return &record, nil
+}
+
+// diffSummary describes how two payloads of the same secret differ, naming
+// changed fields without revealing values, e.g. "password changed, url
+// added". The bulky credentials history is an implementation detail and is
+// ignored.
+func diffSummary(oldPayload, newPayload []byte) string {
+	var oldFields, newFields map[string]json.RawMessage
+	if json.Unmarshal(oldPayload, &oldFields) != nil || json.Unmarshal(newPayload, &newFields) != nil {
+		return "payload changed"
+	}
+	delete(oldFields, "history")
+	delete(newFields, "history")
+
+	var parts []string
+	for field, oldValue := range oldFields {
+		newValue, ok := newFields[field]
+		switch {
+		case !ok:
+			parts = append(parts, field+" removed")
+		case string(oldValue) != string(newValue):
+			parts = append(parts, field+" changed")
+		}
+	}
+	for field := range newFields {
+		if _, ok := oldFields[field]; !ok {
+			parts = append(parts, field+" added")
+		}
+	}
+	if len(parts) == 0 {
+		return "no payload changes"
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
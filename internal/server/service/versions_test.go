@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestUpdateSnapshotsVersions(t *testing.T) {
+	svc := NewSecretsService(SecretsServiceDeps{
+		Secrets:     memory.NewSecretRepository(),
+		Attachments: memory.NewAttachmentRepository(),
+		Versions:    memory.NewSecretVersionRepository(),
+		TM:          repository.NoopTransactionManager{},
+		MasterKey:   []byte("test master key"),
+	})
+	ctx := context.Background()
+	userID := uuid.New()
+	secret := mustCreateCredentials(t, svc, userID, "first")
+
+	updatePassword(t, svc, secret, "second")
+	updatePassword(t, svc, secret, "third")
+
+	versions, err := svc.GetSecretVersions(ctx, userID, secret.ID)
+	if err != nil {
+		t.Fatalf("GetSecretVersions: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("got %d versions, want 2", len(versions))
+	}
+	if versions[0].Version != 2 || versions[1].Version != 1 {
+		t.Fatalf("versions not newest first: %d, %d", versions[0].Version, versions[1].Version)
+	}
+	if versions[0].Summary != "password changed" {
+		t.Fatalf("summary = %q, want %q", versions[0].Summary, "password changed")
+	}
+	if versions[0].Data != nil {
+		t.Fatal("listing must not include payload data")
+	}
+
+	v, err := svc.GetSecretVersion(ctx, userID, secret.ID, 1)
+	if err != nil {
+		t.Fatalf("GetSecretVersion: %v", err)
+	}
+	var p models.CredentialsPayload
+	if err := (&models.Secret{Data: v.Data}).DecodePayload(&p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Password != "first" {
+		t.Fatalf("version 1 password = %q, want first", p.Password)
+	}
+}
+
+func TestDiffSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{"change", `{"login":"a","password":"x"}`, `{"login":"a","password":"y"}`, "password changed"},
+		{"add", `{"login":"a"}`, `{"login":"a","url":"u"}`, "url added"},
+		{"remove", `{"login":"a","url":"u"}`, `{"login":"a"}`, "url removed"},
+		{"same", `{"login":"a"}`, `{"login":"a"}`, "no payload changes"},
+		{"history ignored", `{"password":"x","history":[1]}`, `{"password":"x","history":[1,2]}`, "no payload changes"},
+		{"garbage", `not json`, `{}`, "payload changed"},
+	}
+	for _, tt := range tests {
+		if got := diffSummary([]byte(tt.old), []byte(tt.new)); got != tt.want {
+			t.Errorf("%s: diffSummary = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
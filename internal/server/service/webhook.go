@@ -0,0 +1,244 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository"
+)
+
+// Webhook delivery tuning. Attempts are made back to back within one
+// dispatch round; an endpoint that stays down simply misses the event and
+// sees the failures in its delivery log.
+const (
+	maxWebhooksPerUser     = 10
+	webhookDeliveryTimeout = 5 * time.Second
+	webhookMaxAttempts     = 3
+	webhookRetryDelay      = time.Second
+	webhookDeliveryLogMax  = 100
+)
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of the delivery body
+// keyed with the webhook secret, so receivers can verify the origin.
+const WebhookSignatureHeader = "X-Gophkeeper-Signature"
+
+// WebhookService manages user-registered webhooks and, as an
+// OutboxPublisher, delivers vault events to them with signed payloads.
+type WebhookService struct {
+	hooks repository.WebhookRepository
+	audit AuditRecorder
+	// client sends the deliveries; tests swap it for one pointing at a
+	// local receiver.
+	client *http.Client
+}
+
+// NewWebhookService creates a WebhookService. Audit is optional.
+func NewWebhookService(hooks repository.WebhookRepository, audit AuditRecorder) *WebhookService {
+	return &WebhookService{
+		hooks: hooks,
+		audit: audit,
+		client: &http.Client{
+			Timeout: webhookDeliveryTimeout,
+		},
+	}
+}
+
+// Create registers a webhook and returns it, secret included — the only
+// time the secret leaves the server.
+func (s *WebhookService) Create(ctx context.Context, userID uuid.UUID, rawURL string, events []string) (*models.Webhook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("%w: webhook url must be http or https", ErrValidation)
+	}
+	existing, err := s.hooks.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWebhooksPerUser {
+		return nil, fmt.Errorf("%w: at most %d webhooks allowed", ErrQuotaExceeded, maxWebhooksPerUser)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate webhook secret: %w", err)
+	}
+	hook := &models.Webhook{
+		ID:        uuid.New(),
+		UserID:    userID,
+		URL:       rawURL,
+		Secret:    hex.EncodeToString(secret),
+		Events:    events,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.hooks.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+	if s.audit != nil {
+		s.audit.Record(ctx, userID, models.AuditActionWebhookCreated, hook.ID.String())
+	}
+	return hook, nil
+}
+
+// List returns the user's webhooks with the secrets redacted.
+func (s *WebhookService) List(ctx context.Context, userID uuid.UUID) ([]models.Webhook, error) {
+	hooks, err := s.hooks.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	return hooks, nil
+}
+
+// Delete removes a webhook and its delivery log.
+func (s *WebhookService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	err := s.hooks.Delete(ctx, userID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err == nil && s.audit != nil {
+		s.audit.Record(ctx, userID, models.AuditActionWebhookDeleted, id.String())
+	}
+	return err
+}
+
+// Deliveries returns the latest delivery attempts of the user's webhook,
+// newest first.
+func (s *WebhookService) Deliveries(ctx context.Context, userID, id uuid.UUID) ([]models.WebhookDelivery, error) {
+	if _, err := s.hooks.Get(ctx, userID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return s.hooks.ListDeliveries(ctx, id, webhookDeliveryLogMax)
+}
+
+// webhookPayload is the body a receiver gets. It carries only event
+// metadata — never secret material, which the server could not decrypt
+// into a useful form anyway under end-to-end encryption.
+type webhookPayload struct {
+	EventID   int64     `json:"event_id"`
+	Action    string    `json:"action"`
+	Resource  string    `json:"resource,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Publish implements OutboxPublisher. Each matching webhook gets up to
+// webhookMaxAttempts tries with every attempt logged; a hook that stays
+// unreachable does not fail the batch, so one broken receiver cannot
+// stall the event stream for everyone else.
+func (s *WebhookService) Publish(ctx context.Context, event models.OutboxEvent) error {
+	hooks, err := s.hooks.ListByUser(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if !webhookWants(hook, event.Action) {
+			continue
+		}
+		s.deliver(ctx, hook, event)
+	}
+	return nil
+}
+
+// webhookWants reports whether the hook subscribed to the action; an
+// empty filter means every event.
+func webhookWants(hook models.Webhook, action string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, e := range hook.Events {
+		if e == action {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver posts one event to one hook, retrying transport failures and
+// 5xx responses, and logs every attempt.
+func (s *WebhookService) deliver(ctx context.Context, hook models.Webhook, event models.OutboxEvent) {
+	body, err := json.Marshal(webhookPayload{
+		EventID:   event.ID,
+		Action:    event.Action,
+		Resource:  event.Resource,
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "encode webhook payload", "webhook", hook.ID, "err", err)
+		return
+	}
+	mac := hmac.New(sha256.New, []byte(hook.Secret))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		status, err := s.send(ctx, hook.URL, body, signature)
+		s.log(ctx, hook.ID, event, attempt, status, err)
+		if err == nil && status < http.StatusInternalServerError {
+			return
+		}
+		if attempt == webhookMaxAttempts {
+			slog.WarnContext(ctx, "webhook delivery failed", "webhook", hook.ID, "event", event.ID, "err", err, "status", status)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(webhookRetryDelay):
+		}
+	}
+}
+
+// send posts the signed payload and returns the response status; 4xx is
+// final, so it counts as delivered as far as retries are concerned.
+func (s *WebhookService) send(ctx context.Context, url string, body []byte, signature string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, signature)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, resp.Body) //nolint:errcheck // drain for connection reuse
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// log records one delivery attempt; a failure to log is only logged
+// itself, delivery is not retried for it.
+func (s *WebhookService) log(ctx context.Context, hookID uuid.UUID, event models.OutboxEvent, attempt, status int, sendErr error) {
+	delivery := &models.WebhookDelivery{
+		WebhookID:  hookID,
+		EventID:    event.ID,
+		Action:     event.Action,
+		Attempt:    attempt,
+		StatusCode: status,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if sendErr != nil {
+		delivery.Error = sendErr.Error()
+	}
+	if err := s.hooks.RecordDelivery(ctx, delivery); err != nil {
+		slog.WarnContext(ctx, "record webhook delivery", "webhook", hookID, "err", err)
+	}
+}
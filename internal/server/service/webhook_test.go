@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/internal/server/repository/memory"
+)
+
+func TestWebhookCreateValidation(t *testing.T) {
+	svc := NewWebhookService(memory.NewWebhookRepository(), nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	for _, bad := range []string{"", "not a url", "ftp://host/hook", "https://"} {
+		if _, err := svc.Create(ctx, userID, bad, nil); !errors.Is(err, ErrValidation) {
+			t.Errorf("Create(%q) = %v, want ErrValidation", bad, err)
+		}
+	}
+
+	for i := 0; i < maxWebhooksPerUser; i++ {
+		hook, err := svc.Create(ctx, userID, fmt.Sprintf("https://example.com/hook/%d", i), nil)
+		if err != nil {
+			t.Fatalf("Create %d: %v", i, err)
+		}
+		if hook.Secret == "" {
+			t.Fatal("Create returned no secret")
+		}
+	}
+	if _, err := svc.Create(ctx, userID, "https://example.com/extra", nil); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Create over quota = %v, want ErrQuotaExceeded", err)
+	}
+
+	hooks, err := svc.List(ctx, userID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(hooks) != maxWebhooksPerUser {
+		t.Fatalf("List returned %d hooks, want %d", len(hooks), maxWebhooksPerUser)
+	}
+	for _, hook := range hooks {
+		if hook.Secret != "" {
+			t.Fatal("List leaked a webhook secret")
+		}
+	}
+}
+
+func TestWebhookDeliverySignedAndRetried(t *testing.T) {
+	var calls atomic.Int32
+	var gotSignature atomic.Value
+	var gotBody atomic.Value
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt so the retry path is exercised.
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		gotBody.Store(body)
+		gotSignature.Store(r.Header.Get(WebhookSignatureHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	svc := NewWebhookService(memory.NewWebhookRepository(), nil)
+	ctx := context.Background()
+	userID := uuid.New()
+	hook, err := svc.Create(ctx, userID, receiver.URL, []string{models.AuditActionSecretCreated})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// A non-subscribed action must not reach the receiver.
+	event := models.OutboxEvent{ID: 7, UserID: userID, Action: models.AuditActionLogin, CreatedAt: time.Now().UTC()}
+	if err := svc.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Fatalf("unsubscribed event was delivered")
+	}
+
+	event.Action = models.AuditActionSecretCreated
+	event.Resource = uuid.NewString()
+	if err := svc.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("receiver saw %d attempts, want 2", calls.Load())
+	}
+
+	body, _ := gotBody.Load().([]byte)
+	mac := hmac.New(sha256.New, []byte(hook.Secret))
+	mac.Write(body)
+	if sig, _ := gotSignature.Load().(string); sig != hex.EncodeToString(mac.Sum(nil)) {
+		t.Fatalf("signature %q does not verify against the webhook secret", sig)
+	}
+
+	deliveries, err := svc.Deliveries(ctx, userID, hook.ID)
+	if err != nil {
+		t.Fatalf("Deliveries: %v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("logged %d deliveries, want 2", len(deliveries))
+	}
+	// Newest first: the successful second attempt, then the failed first.
+	if deliveries[0].Attempt != 2 || deliveries[0].StatusCode != http.StatusOK {
+		t.Errorf("delivery 0 = attempt %d status %d, want attempt 2 status 200", deliveries[0].Attempt, deliveries[0].StatusCode)
+	}
+	if deliveries[1].Attempt != 1 || deliveries[1].StatusCode != http.StatusInternalServerError {
+		t.Errorf("delivery 1 = attempt %d status %d, want attempt 1 status 500", deliveries[1].Attempt, deliveries[1].StatusCode)
+	}
+	if deliveries[0].EventID != event.ID || deliveries[0].Action != event.Action {
+		t.Errorf("delivery records event %d/%s, want %d/%s", deliveries[0].EventID, deliveries[0].Action, event.ID, event.Action)
+	}
+}
+
+func TestWebhookUnreachableReceiverDoesNotFailBatch(t *testing.T) {
+	svc := NewWebhookService(memory.NewWebhookRepository(), nil)
+	ctx := context.Background()
+	userID := uuid.New()
+	hook, err := svc.Create(ctx, userID, "http://127.0.0.1:1/hook", nil)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	event := models.OutboxEvent{ID: 1, UserID: userID, Action: models.AuditActionSecretDeleted, CreatedAt: time.Now().UTC()}
+	if err := svc.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish with unreachable receiver = %v, want nil", err)
+	}
+
+	deliveries, err := svc.Deliveries(ctx, userID, hook.ID)
+	if err != nil {
+		t.Fatalf("Deliveries: %v", err)
+	}
+	if len(deliveries) != webhookMaxAttempts {
+		t.Fatalf("logged %d deliveries, want %d", len(deliveries), webhookMaxAttempts)
+	}
+	for _, d := range deliveries {
+		if d.Error == "" || d.StatusCode != 0 {
+			t.Errorf("attempt %d logged status %d error %q, want transport error", d.Attempt, d.StatusCode, d.Error)
+		}
+	}
+}
@@ -0,0 +1 @@
+ALTER TABLE secrets ADD COLUMN IF NOT EXISTS protected BOOLEAN NOT NULL DEFAULT FALSE;
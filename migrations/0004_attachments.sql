@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS attachments (
+    id         UUID PRIMARY KEY,
+    secret_id  UUID NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    filename   TEXT NOT NULL,
+    size       BIGINT NOT NULL,
+    content    BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_secret_id ON attachments (secret_id);
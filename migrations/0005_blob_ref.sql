@@ -0,0 +1 @@
+ALTER TABLE secrets ADD COLUMN IF NOT EXISTS blob_ref TEXT NOT NULL DEFAULT '';
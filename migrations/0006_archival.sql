@@ -0,0 +1,5 @@
+ALTER TABLE secrets ADD COLUMN IF NOT EXISTS archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE secrets ADD COLUMN IF NOT EXISTS accessed_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- 0 disables auto-archival for the user.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS archive_after_days INTEGER NOT NULL DEFAULT 0;
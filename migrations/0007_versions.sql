@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS secret_versions (
+    id UUID PRIMARY KEY,
+    secret_id UUID NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id),
+    version INTEGER NOT NULL,
+    data BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (secret_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS secret_versions_secret_idx ON secret_versions (secret_id, version);
@@ -0,0 +1,5 @@
+-- Keyset pagination indexes for every sortable column of GET /secrets.
+CREATE INDEX IF NOT EXISTS secrets_user_updated_idx ON secrets (user_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS secrets_user_created_idx ON secrets (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS secrets_user_name_idx ON secrets (user_id, name, id);
+CREATE INDEX IF NOT EXISTS secrets_user_type_idx ON secrets (user_id, type, id);
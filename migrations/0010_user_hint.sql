@@ -0,0 +1,2 @@
+-- Encrypted vault hint users can leave for themselves.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS hint BYTEA;
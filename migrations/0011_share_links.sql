@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS share_links (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id),
+    secret_id UUID NOT NULL,
+    token_hash BYTEA NOT NULL UNIQUE,
+    name BYTEA NOT NULL,
+    type TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
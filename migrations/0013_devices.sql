@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS devices (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id),
+    name TEXT NOT NULL,
+    platform TEXT NOT NULL DEFAULT '',
+    last_synced_at TIMESTAMPTZ NOT NULL,
+    last_cursor BIGINT NOT NULL DEFAULT 0,
+    revoked BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_devices_user_id ON devices (user_id);
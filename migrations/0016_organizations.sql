@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id         UUID PRIMARY KEY,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS org_members (
+    org_id     UUID NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (org_id, user_id)
+);
+
+-- NULL keeps a secret in its owner's personal vault.
+ALTER TABLE secrets ADD COLUMN IF NOT EXISTS org_id UUID REFERENCES organizations (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_secrets_org_id ON secrets (org_id) WHERE org_id IS NOT NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS secret_shares (
+    secret_id    UUID NOT NULL REFERENCES secrets (id) ON DELETE CASCADE,
+    owner_id     UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    recipient_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    mode         TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (secret_id, recipient_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_secret_shares_recipient ON secret_shares (recipient_id);
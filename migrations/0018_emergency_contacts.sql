@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS emergency_contacts (
+    owner_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    contact_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    wait_days INT NOT NULL,
+    requested_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (owner_id, contact_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_emergency_contacts_contact ON emergency_contacts (contact_id);
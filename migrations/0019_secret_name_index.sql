@@ -0,0 +1,7 @@
+-- Optional metadata encryption seals secret names and metadata in place
+-- and keeps a keyed hash of the name here, so exact-name lookups stay
+-- indexed without storing the plaintext.
+ALTER TABLE secrets
+    ADD COLUMN name_index BYTEA;
+
+CREATE INDEX idx_secrets_name_index ON secrets (user_id, name_index);
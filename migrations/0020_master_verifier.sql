@@ -0,0 +1,5 @@
+-- The master-password verifier lets clients tell a mistyped end-to-end
+-- master password from corrupted data before any payload is opened. It
+-- holds a salted hash of the derived key, never the password itself.
+ALTER TABLE users
+    ADD COLUMN master_verifier BYTEA;
@@ -0,0 +1,7 @@
+-- An optional contact email per account. Uniqueness is enforced by the
+-- database, case-insensitively and only for accounts that set one, so
+-- concurrent registrations cannot race past an application-level check.
+ALTER TABLE users
+    ADD COLUMN email TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX users_email_key ON users (lower(email)) WHERE email <> '';
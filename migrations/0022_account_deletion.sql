@@ -0,0 +1,5 @@
+-- A scheduled account deletion. DELETE /me stamps the end of the grace
+-- period here and the reaper purges the account once it passes. NULL
+-- means no deletion is pending.
+ALTER TABLE users
+    ADD COLUMN delete_after TIMESTAMPTZ;
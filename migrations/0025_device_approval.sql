@@ -0,0 +1,7 @@
+ALTER TABLE devices
+    ADD COLUMN fingerprint TEXT NOT NULL DEFAULT '',
+    ADD COLUMN pending BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN approval_token TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX devices_fingerprint_idx ON devices (user_id, fingerprint)
+    WHERE fingerprint <> '';
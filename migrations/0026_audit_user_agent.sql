@@ -0,0 +1,2 @@
+ALTER TABLE audit_events
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+// Package migrations embeds the Postgres schema migrations so the server
+// binary can apply them itself, without the directory shipping alongside
+// it.
+package migrations
+
+import "embed"
+
+// Files holds the numbered migration scripts in apply order.
+//
+//go:embed *.sql
+var Files embed.FS
@@ -0,0 +1,255 @@
+// Package apitest helps applications that embed the gophkeeper client
+// test without a real server. Server is an in-memory fake covering the
+// common API subset; Replay serves canned responses for everything else.
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// Server is an in-memory fake of the gophkeeper server, covering
+// registration, login and the secrets CRUD. Listing ignores sort and
+// pagination options and returns secrets in creation order. Point
+// api.New at URL() and close the server when the test ends.
+type Server struct {
+	srv *httptest.Server
+
+	mu      sync.Mutex
+	users   map[string]string                       // login -> password
+	tokens  map[string]string                       // token -> login
+	secrets map[string]map[uuid.UUID]*models.Secret // login -> secrets
+	order   map[string][]uuid.UUID                  // login -> creation order
+}
+
+// NewServer starts a fake server. Callers must Close it.
+func NewServer() *Server {
+	s := &Server{
+		users:   make(map[string]string),
+		tokens:  make(map[string]string),
+		secrets: make(map[string]map[uuid.UUID]*models.Secret),
+		order:   make(map[string][]uuid.UUID),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/auth/register", s.register)
+	mux.HandleFunc("/api/v1/auth/login", s.login)
+	mux.HandleFunc("/api/v1/secrets", s.collection)
+	mux.HandleFunc("/api/v1/secrets/", s.item)
+	s.srv = httptest.NewServer(mux)
+	return s
+}
+
+// URL returns the base URL to pass to api.New.
+func (s *Server) URL() string { return s.srv.URL }
+
+// Close shuts the fake server down.
+func (s *Server) Close() { s.srv.Close() }
+
+// Seed installs a secret for login directly, bypassing the API, so tests
+// can start from a populated vault. The login must have registered first.
+func (s *Server) Seed(login string, secret *models.Secret) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if secret.ID == uuid.Nil {
+		secret.ID = uuid.New()
+	}
+	s.put(login, secret)
+}
+
+// put stores a secret under login; the caller holds the lock.
+func (s *Server) put(login string, secret *models.Secret) {
+	if s.secrets[login] == nil {
+		s.secrets[login] = make(map[uuid.UUID]*models.Secret)
+	}
+	if _, exists := s.secrets[login][secret.ID]; !exists {
+		s.order[login] = append(s.order[login], secret.ID)
+	}
+	s.secrets[login][secret.ID] = secret
+}
+
+func (s *Server) register(w http.ResponseWriter, r *http.Request) {
+	var creds models.Credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed body")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.users[creds.Login]; exists {
+		writeError(w, http.StatusConflict, "login already taken")
+		return
+	}
+	s.users[creds.Login] = creds.Password
+	s.issueToken(w, creds.Login)
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var creds models.Credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed body")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if password, exists := s.users[creds.Login]; !exists || password != creds.Password {
+		writeError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+	s.issueToken(w, creds.Login)
+}
+
+// issueToken mints a token for login and writes the auth response; the
+// caller holds the lock.
+func (s *Server) issueToken(w http.ResponseWriter, login string) {
+	token := uuid.NewString()
+	s.tokens[token] = login
+	writeJSON(w, http.StatusOK, map[string]string{"token": token})
+}
+
+// authed resolves the bearer token; it reports the login and whether the
+// request is authenticated.
+func (s *Server) authed(r *http.Request) (string, bool) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, ok := s.tokens[token]
+	return login, ok
+}
+
+// secretResult mirrors api.SecretResult without importing pkg/api, which
+// would be an import cycle once api tests use this package.
+type secretResult struct {
+	Secret *models.Secret `json:"secret"`
+}
+
+func (s *Server) collection(w http.ResponseWriter, r *http.Request) {
+	login, ok := s.authed(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		secrets := make([]models.Secret, 0, len(s.order[login]))
+		for _, id := range s.order[login] {
+			secrets = append(secrets, *s.secrets[login][id])
+		}
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, secrets)
+	case http.MethodPost:
+		var secret models.Secret
+		if err := json.NewDecoder(r.Body).Decode(&secret); err != nil {
+			writeError(w, http.StatusBadRequest, "malformed body")
+			return
+		}
+		secret.ID = uuid.New()
+		secret.CreatedAt = time.Now().UTC()
+		secret.UpdatedAt = secret.CreatedAt
+		s.mu.Lock()
+		s.put(login, &secret)
+		s.mu.Unlock()
+		writeJSON(w, http.StatusCreated, secretResult{Secret: &secret})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) item(w http.ResponseWriter, r *http.Request) {
+	login, ok := s.authed(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	id, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/v1/secrets/"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secret, exists := s.secrets[login][id]
+	if !exists {
+		writeError(w, http.StatusNotFound, "secret not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, secretResult{Secret: secret})
+	case http.MethodPut:
+		var update models.Secret
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			writeError(w, http.StatusBadRequest, "malformed body")
+			return
+		}
+		update.ID = id
+		update.CreatedAt = secret.CreatedAt
+		update.UpdatedAt = time.Now().UTC()
+		s.secrets[login][id] = &update
+		writeJSON(w, http.StatusOK, secretResult{Secret: &update})
+	case http.MethodDelete:
+		delete(s.secrets[login], id)
+		for i, got := range s.order[login] {
+			if got == id {
+				s.order[login] = append(s.order[login][:i], s.order[login][i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// Replay is a RoundTripper serving canned responses keyed by
+// "METHOD /path", for golden-fixture tests of endpoints Server does not
+// implement. Install it with api.WithTransport on a client whose base URL
+// can be anything.
+type Replay struct {
+	// Responses maps "METHOD /path" to the fixture to serve.
+	Responses map[string]Response
+}
+
+// Response is one canned reply. Body is served verbatim as JSON.
+type Response struct {
+	Status int
+	Body   string
+}
+
+// RoundTrip serves the fixture matching the request, or fails the request
+// when none is registered.
+func (t *Replay) RoundTrip(r *http.Request) (*http.Response, error) {
+	fixture, ok := t.Responses[r.Method+" "+r.URL.Path]
+	if !ok {
+		return nil, fmt.Errorf("apitest: no fixture for %s %s", r.Method, r.URL.Path)
+	}
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		StatusCode: fixture.Status,
+		Status:     http.StatusText(fixture.Status),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(fixture.Body)),
+		Request:    r,
+	}, nil
+}
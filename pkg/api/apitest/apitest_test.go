@@ -0,0 +1,78 @@
+package apitest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+	"github.com/alexuryumtsev/gophkeeper/pkg/api"
+	"github.com/alexuryumtsev/gophkeeper/pkg/api/apitest"
+)
+
+func TestServerSecretsCRUD(t *testing.T) {
+	srv := apitest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	client := api.New(srv.URL())
+	if err := client.Register(ctx, "alice", "password1"); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := client.Register(ctx, "alice", "password1"); err == nil {
+		t.Fatal("second register should conflict")
+	}
+
+	created, err := client.CreateSecret(ctx, &models.Secret{
+		Name: "mail",
+		Type: models.SecretTypeCredentials,
+		Data: []byte(`{"login":"a","password":"b"}`),
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	got, err := client.GetSecret(ctx, created.Secret.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Secret.Name != "mail" {
+		t.Errorf("got name %q, want mail", got.Secret.Name)
+	}
+
+	got.Secret.Name = "mail-2"
+	if _, err := client.UpdateSecret(ctx, got.Secret); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	secrets, err := client.GetSecrets(ctx, api.ListOptions{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(secrets) != 1 || secrets[0].Name != "mail-2" {
+		t.Errorf("got %d secrets, want the renamed one", len(secrets))
+	}
+
+	if err := client.DeleteSecret(ctx, created.Secret.ID, false); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := client.GetSecret(ctx, created.Secret.ID); err == nil {
+		t.Fatal("get after delete should fail")
+	}
+}
+
+func TestReplayFixtures(t *testing.T) {
+	client := api.New("http://fixture", api.WithTransport(&apitest.Replay{
+		Responses: map[string]apitest.Response{
+			"GET /api/v1/user/hint": {Status: 200, Body: `{"hint":"the usual one"}`},
+		},
+	}))
+	hint, err := client.GetHint(context.Background())
+	if err != nil {
+		t.Fatalf("get hint: %v", err)
+	}
+	if hint != "the usual one" {
+		t.Errorf("got hint %q", hint)
+	}
+	if _, err := client.Usage(context.Background()); err == nil {
+		t.Fatal("unregistered path should fail")
+	}
+}
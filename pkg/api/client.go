@@ -0,0 +1,996 @@
+// Package api is the HTTP client for the gophkeeper server API. It is used
+// by the CLI and is importable by third-party tooling.
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// Client talks to a gophkeeper server.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	token   string
+	cipher  PayloadCipher
+	retry   RetryPolicy
+
+	requestHooks  []func(*http.Request)
+	responseHooks []func(*http.Response)
+	logger        *slog.Logger
+	// fingerprint identifies this client installation on login; empty
+	// skips device checks on servers that enforce them.
+	fingerprint string
+}
+
+// New creates a Client for the server at baseURL (e.g. "http://localhost:8080").
+func New(baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: 30 * time.Second},
+		retry:   DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SetTLSConfig installs a TLS configuration for all requests, e.g. a
+// client certificate for servers enforcing mutual TLS, or a custom root
+// CA pool.
+func (c *Client) SetTLSConfig(tlsCfg *tls.Config) {
+	c.http.Transport = &http.Transport{TLSClientConfig: tlsCfg}
+}
+
+// SetToken sets the bearer token used for authenticated requests.
+func (c *Client) SetToken(token string) { c.token = token }
+
+// Token returns the current bearer token.
+func (c *Client) Token() string { return c.token }
+
+// BaseURL returns the server base URL the client talks to.
+func (c *Client) BaseURL() string { return c.baseURL }
+
+// PayloadCipher transforms secret payloads at the client boundary. With a
+// cipher installed the client seals payloads before upload and opens them
+// after download, so the server only ever stores ciphertext it cannot
+// read.
+type PayloadCipher interface {
+	Seal(data []byte) ([]byte, error)
+	Open(data []byte) ([]byte, error)
+}
+
+// SetPayloadCipher installs end-to-end encryption on secret payloads.
+// Server-side features that need the plaintext — recovery code
+// consumption, share links, org vaults and per-user shares — stop working
+// for sealed secrets, and delta sync loses its hash-skip optimization
+// because sealing is not deterministic.
+func (c *Client) SetPayloadCipher(cipher PayloadCipher) { c.cipher = cipher }
+
+// sealSecret returns a copy of secret with its payload sealed by the
+// installed cipher; without one it returns secret unchanged.
+func (c *Client) sealSecret(secret *models.Secret) (*models.Secret, error) {
+	if c.cipher == nil || len(secret.Data) == 0 {
+		return secret, nil
+	}
+	sealed, err := c.cipher.Seal(secret.Data)
+	if err != nil {
+		return nil, err
+	}
+	clone := *secret
+	clone.Data = sealed
+	return &clone, nil
+}
+
+// openSecret opens a downloaded payload in place with the installed
+// cipher, if any.
+func (c *Client) openSecret(secret *models.Secret) error {
+	if c.cipher == nil || secret == nil || len(secret.Data) == 0 {
+		return nil
+	}
+	data, err := c.cipher.Open(secret.Data)
+	if err != nil {
+		return err
+	}
+	secret.Data = data
+	return nil
+}
+
+// Sentinel errors matching the server's error codes. Check them with
+// errors.Is on anything the client returns, instead of inspecting status
+// codes or message wording.
+var (
+	ErrValidation    = errors.New("validation failed")
+	ErrUnauthorized  = errors.New("authentication failed")
+	ErrForbidden     = errors.New("forbidden")
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrProtected     = errors.New("protected")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	ErrTimeout       = errors.New("timed out")
+)
+
+// Error is a non-2xx response from the server.
+type Error struct {
+	StatusCode int
+	// Code is the server's machine-readable error code; empty when the
+	// server predates codes, in which case the status fills in.
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("server returned %d: %s", e.StatusCode, e.Message)
+}
+
+// Is matches the sentinel corresponding to the error's code, so
+// errors.Is(err, ErrNotFound) and friends work on server responses.
+func (e *Error) Is(target error) bool {
+	code := e.Code
+	if code == "" {
+		code = codeForStatus(e.StatusCode)
+	}
+	switch target {
+	case ErrValidation:
+		return code == "validation"
+	case ErrUnauthorized:
+		return code == "unauthorized"
+	case ErrForbidden:
+		return code == "forbidden"
+	case ErrNotFound:
+		return code == "not_found"
+	case ErrConflict:
+		return code == "conflict"
+	case ErrProtected:
+		return code == "protected"
+	case ErrQuotaExceeded:
+		return code == "quota_exceeded"
+	case ErrTimeout:
+		return code == "timeout"
+	}
+	return false
+}
+
+// codeForStatus reconstructs the error code from the HTTP status, for
+// servers that do not send one.
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "validation"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusPreconditionFailed:
+		return "protected"
+	case http.StatusRequestEntityTooLarge:
+		return "quota_exceeded"
+	case http.StatusGatewayTimeout:
+		return "timeout"
+	default:
+		return ""
+	}
+}
+
+// SecretResult is a secret returned by the server together with any
+// non-fatal warnings produced by the operation.
+type SecretResult struct {
+	Secret   *models.Secret `json:"secret"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+type tokenResponse struct {
+	Token string `json:"token"`
+}
+
+// Register creates an account and stores the returned token on the client.
+func (c *Client) Register(ctx context.Context, login, password string) error {
+	return c.authenticate(ctx, "/api/v1/auth/register", login, password)
+}
+
+// Login authenticates and stores the returned token on the client.
+func (c *Client) Login(ctx context.Context, login, password string) error {
+	return c.authenticate(ctx, "/api/v1/auth/login", login, password)
+}
+
+// SetFingerprint installs the device fingerprint sent with logins, so
+// servers with device approval enabled can recognize this installation.
+func (c *Client) SetFingerprint(fingerprint string) {
+	c.fingerprint = fingerprint
+}
+
+func (c *Client) authenticate(ctx context.Context, path, login, password string) error {
+	var resp tokenResponse
+	err := c.do(ctx, http.MethodPost, path, models.Credentials{Login: login, Password: password, Fingerprint: c.fingerprint}, &resp)
+	if err != nil {
+		return err
+	}
+	c.token = resp.Token
+	return nil
+}
+
+// Health checks that the server is reachable and alive.
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/healthz", nil, nil)
+}
+
+type identityResponse struct {
+	PublicKey []byte `json:"public_key"`
+	Signature []byte `json:"signature"`
+}
+
+// Identity fetches the server's identity public key along with a signature
+// over nonce, for trust-on-first-use pinning.
+func (c *Client) Identity(ctx context.Context, nonce []byte) (publicKey, signature []byte, err error) {
+	var resp identityResponse
+	path := "/api/v1/identity?nonce=" + base64.RawURLEncoding.EncodeToString(nonce)
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.PublicKey, resp.Signature, nil
+}
+
+// CreateSecret stores a new secret and returns it with server-assigned
+// fields populated.
+func (c *Client) CreateSecret(ctx context.Context, secret *models.Secret) (*SecretResult, error) {
+	sealed, err := c.sealSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	var resp SecretResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/secrets", sealed, &resp); err != nil {
+		return nil, err
+	}
+	if err := c.openSecret(resp.Secret); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListOptions shapes a GetSecrets call. The zero value lists all active
+// secrets sorted by updated_at, newest first.
+type ListOptions struct {
+	IncludeArchived bool
+	// Sort is one of updated_at, created_at, name, type.
+	Sort string
+	// Order is asc or desc; empty picks the natural direction of Sort.
+	Order string
+	// Limit caps the page size; pass the last secret ID of a page as After
+	// to fetch the next one. A page shorter than Limit is the last.
+	Limit int
+	After uuid.UUID
+	// IncludeErrors asks the server to report secrets it could not
+	// decrypt instead of omitting them. Set by GetSecretsWithErrors.
+	IncludeErrors bool
+}
+
+func (o ListOptions) query() string {
+	q := url.Values{}
+	if o.IncludeArchived {
+		q.Set("include_archived", "true")
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	if o.Order != "" {
+		q.Set("order", o.Order)
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.After != uuid.Nil {
+		q.Set("after", o.After.String())
+	}
+	if o.IncludeErrors {
+		q.Set("include_errors", "true")
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+// GetSecrets lists the secrets of the authenticated user according to opts.
+func (c *Client) GetSecrets(ctx context.Context, opts ListOptions) ([]models.Secret, error) {
+	var secrets []models.Secret
+	if err := c.do(ctx, http.MethodGet, "/api/v1/secrets"+opts.query(), nil, &secrets); err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		if err := c.openSecret(&secrets[i]); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+// SecretList is a listing that also names the secrets the server could
+// not return.
+type SecretList struct {
+	Secrets []models.Secret      `json:"secrets"`
+	Errors  []models.SecretError `json:"errors,omitempty"`
+}
+
+// GetSecretsWithErrors lists secrets like GetSecrets but also reports the
+// ones the server could not decrypt, so callers can surface data problems
+// instead of hiding them.
+func (c *Client) GetSecretsWithErrors(ctx context.Context, opts ListOptions) (*SecretList, error) {
+	opts.IncludeErrors = true
+	var list SecretList
+	if err := c.do(ctx, http.MethodGet, "/api/v1/secrets"+opts.query(), nil, &list); err != nil {
+		return nil, err
+	}
+	for i := range list.Secrets {
+		if err := c.openSecret(&list.Secrets[i]); err != nil {
+			return nil, err
+		}
+	}
+	return &list, nil
+}
+
+// GetSecret fetches one secret by ID.
+func (c *Client) GetSecret(ctx context.Context, id uuid.UUID) (*SecretResult, error) {
+	var resp SecretResult
+	if err := c.do(ctx, http.MethodGet, "/api/v1/secrets/"+id.String(), nil, &resp); err != nil {
+		return nil, err
+	}
+	if err := c.openSecret(resp.Secret); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateSecret rewrites an existing secret.
+func (c *Client) UpdateSecret(ctx context.Context, secret *models.Secret) (*SecretResult, error) {
+	sealed, err := c.sealSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	var resp SecretResult
+	if err := c.do(ctx, http.MethodPut, "/api/v1/secrets/"+secret.ID.String(), sealed, &resp); err != nil {
+		return nil, err
+	}
+	if err := c.openSecret(resp.Secret); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteSecret removes a secret by ID. Deleting a protected secret requires
+// confirm to be true; without it the server answers 412 Precondition Failed.
+func (c *Client) DeleteSecret(ctx context.Context, id uuid.UUID, confirm bool) error {
+	path := "/api/v1/secrets/" + id.String()
+	if confirm {
+		path += "?confirm=true"
+	}
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// AccessReport fetches the access review report of the authenticated user.
+func (c *Client) AccessReport(ctx context.Context) (*models.AccessReport, error) {
+	var report models.AccessReport
+	if err := c.do(ctx, http.MethodGet, "/api/v1/report/access", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ConvertSecret rewrites a secret under a different type. The secret must
+// carry the new type and a payload already mapped to it; the server keeps
+// the previous payload as a version.
+func (c *Client) ConvertSecret(ctx context.Context, secret *models.Secret) (*SecretResult, error) {
+	sealed, err := c.sealSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	var resp SecretResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/secrets/"+secret.ID.String()+"/convert", sealed, &resp); err != nil {
+		return nil, err
+	}
+	if err := c.openSecret(resp.Secret); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSecretVersions lists the stored payload snapshots of a secret with
+// their change summaries, newest first.
+func (c *Client) GetSecretVersions(ctx context.Context, id uuid.UUID) ([]models.SecretVersion, error) {
+	var versions []models.SecretVersion
+	err := c.do(ctx, http.MethodGet, "/api/v1/secrets/"+id.String()+"/versions", nil, &versions)
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetSecretVersion fetches one snapshot of a secret including its payload.
+func (c *Client) GetSecretVersion(ctx context.Context, id uuid.UUID, version int) (*models.SecretVersion, error) {
+	var v models.SecretVersion
+	err := c.do(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v1/secrets/%s/versions/%d", id, version), nil, &v)
+	if err != nil {
+		return nil, err
+	}
+	if c.cipher != nil && len(v.Data) > 0 {
+		if v.Data, err = c.cipher.Open(v.Data); err != nil {
+			return nil, err
+		}
+	}
+	return &v, nil
+}
+
+// RecoveryCodeResult is the response of UseRecoveryCode.
+type RecoveryCodeResult struct {
+	Code      string   `json:"code"`
+	Remaining int      `json:"remaining"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// UseRecoveryCode consumes the next unused code of a recovery_codes secret.
+func (c *Client) UseRecoveryCode(ctx context.Context, id uuid.UUID) (*RecoveryCodeResult, error) {
+	var result RecoveryCodeResult
+	err := c.do(ctx, http.MethodPost, "/api/v1/secrets/"+id.String()+"/use-code", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UploadAttachment attaches a file to a secret.
+func (c *Client) UploadAttachment(ctx context.Context, secretID uuid.UUID, filename string, content []byte) (*models.Attachment, error) {
+	body := struct {
+		Filename string `json:"filename"`
+		Content  []byte `json:"content"`
+	}{Filename: filename, Content: content}
+	var attachment models.Attachment
+	err := c.do(ctx, http.MethodPost, "/api/v1/secrets/"+secretID.String()+"/attachments", body, &attachment)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// ListAttachments returns the attachment metadata of a secret.
+func (c *Client) ListAttachments(ctx context.Context, secretID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := c.do(ctx, http.MethodGet, "/api/v1/secrets/"+secretID.String()+"/attachments", nil, &attachments)
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// DownloadAttachment fetches the decrypted content of one attachment.
+func (c *Client) DownloadAttachment(ctx context.Context, secretID, attachmentID uuid.UUID) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		c.baseURL+"/api/v1/secrets/"+secretID.String()+"/attachments/"+attachmentID.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, c.errorFrom(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// DeleteAttachment detaches and removes an attachment.
+func (c *Client) DeleteAttachment(ctx context.Context, secretID, attachmentID uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete,
+		"/api/v1/secrets/"+secretID.String()+"/attachments/"+attachmentID.String(), nil, nil)
+}
+
+// Sync fetches everything that changed since the request's sync point. A
+// partial response carries warnings and a retry token; resubmit the token to
+// fetch what was missing.
+func (c *Client) Sync(ctx context.Context, req models.SyncRequest) (*models.SyncResponse, error) {
+	if c.cipher != nil && len(req.Changes) > 0 {
+		changes := make([]models.Secret, len(req.Changes))
+		for i := range req.Changes {
+			sealed, err := c.sealSecret(&req.Changes[i])
+			if err != nil {
+				return nil, err
+			}
+			changes[i] = *sealed
+		}
+		req.Changes = changes
+	}
+	var resp models.SyncResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/sync", req, &resp); err != nil {
+		return nil, err
+	}
+	for i := range resp.Secrets {
+		if err := c.openSecret(&resp.Secrets[i]); err != nil {
+			return nil, err
+		}
+	}
+	return &resp, nil
+}
+
+// RegisterDevice registers this client as a named sync device and returns
+// it. The returned ID goes into SyncRequest.DeviceID so the server tracks
+// the device's sync point.
+func (c *Client) RegisterDevice(ctx context.Context, name, platform string) (*models.Device, error) {
+	body := struct {
+		Name     string `json:"name"`
+		Platform string `json:"platform"`
+	}{Name: name, Platform: platform}
+	var device models.Device
+	if err := c.do(ctx, http.MethodPost, "/api/v1/devices", body, &device); err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// ListDevices fetches the registered devices of the account.
+func (c *Client) ListDevices(ctx context.Context) ([]models.Device, error) {
+	var devices []models.Device
+	if err := c.do(ctx, http.MethodGet, "/api/v1/devices", nil, &devices); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// RevokeDevice bars a device from syncing.
+func (c *Client) RevokeDevice(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/devices/"+id.String(), nil, nil)
+}
+
+// ApproveDevice marks a pending device as trusted. It must be called from
+// an already-trusted session.
+func (c *Client) ApproveDevice(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/devices/"+id.String()+"/approve", nil, nil)
+}
+
+// CreateWebhook registers a webhook URL that receives signed event
+// payloads. The returned Secret is shown only once; events narrows the
+// actions delivered, empty means all.
+func (c *Client) CreateWebhook(ctx context.Context, url string, events []string) (*models.Webhook, error) {
+	body := struct {
+		URL    string   `json:"url"`
+		Events []string `json:"events"`
+	}{URL: url, Events: events}
+	var hook models.Webhook
+	if err := c.do(ctx, http.MethodPost, "/api/v1/webhooks", body, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// ListWebhooks fetches the registered webhooks; secrets are redacted.
+func (c *Client) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	var hooks []models.Webhook
+	if err := c.do(ctx, http.MethodGet, "/api/v1/webhooks", nil, &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+// DeleteWebhook removes a webhook; its delivery log goes with it.
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/webhooks/"+id.String(), nil, nil)
+}
+
+// WebhookDeliveries fetches the latest delivery attempts of a webhook,
+// newest first.
+func (c *Client) WebhookDeliveries(ctx context.Context, id uuid.UUID) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	if err := c.do(ctx, http.MethodGet, "/api/v1/webhooks/"+id.String()+"/deliveries", nil, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// LoginHistory fetches the account's newest sign-in attempts, successful
+// and failed, newest first. limit <= 0 uses the server default.
+func (c *Client) LoginHistory(ctx context.Context, limit int) ([]models.LoginRecord, error) {
+	path := "/api/v1/me/logins"
+	if limit > 0 {
+		path += "?limit=" + strconv.Itoa(limit)
+	}
+	var records []models.LoginRecord
+	if err := c.do(ctx, http.MethodGet, path, nil, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// NotificationPreferences fetches the notification kinds the account
+// opted out of.
+func (c *Client) NotificationPreferences(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Disabled []string `json:"disabled"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/user/notifications", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Disabled, nil
+}
+
+// SetNotificationPreferences replaces the notification kinds the account
+// opts out of; an empty list re-enables everything.
+func (c *Client) SetNotificationPreferences(ctx context.Context, disabled []string) error {
+	body := struct {
+		Disabled []string `json:"disabled"`
+	}{Disabled: disabled}
+	return c.do(ctx, http.MethodPut, "/api/v1/user/notifications", body, nil)
+}
+
+// ShareSecret grants another account access to a secret, read-only or
+// read-write, or changes the mode of an existing grant.
+func (c *Client) ShareSecret(ctx context.Context, id uuid.UUID, login, mode string) (*models.SecretShare, error) {
+	body := struct {
+		Login string `json:"login"`
+		Mode  string `json:"mode"`
+	}{Login: login, Mode: mode}
+	var share models.SecretShare
+	if err := c.do(ctx, http.MethodPost, "/api/v1/secrets/"+id.String()+"/share", body, &share); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// ShareLinkResult is a freshly minted one-time share link. Path is
+// relative to the server base URL.
+type ShareLinkResult struct {
+	Token     string    `json:"token"`
+	Path      string    `json:"path"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreateShareLink mints a single-use expiring public link to a secret,
+// for handing a value to someone without an account. A zero ttl picks the
+// server default.
+func (c *Client) CreateShareLink(ctx context.Context, id uuid.UUID, ttl time.Duration) (*ShareLinkResult, error) {
+	body := struct {
+		ExpiresIn int64 `json:"expires_in,omitempty"`
+	}{ExpiresIn: int64(ttl / time.Second)}
+	var link ShareLinkResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/secrets/"+id.String()+"/links", body, &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// UnshareSecret revokes another account's access to a secret.
+func (c *Client) UnshareSecret(ctx context.Context, id uuid.UUID, login string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/secrets/"+id.String()+"/share/"+url.PathEscape(login), nil, nil)
+}
+
+// ListShares lists who a secret is shared with.
+func (c *Client) ListShares(ctx context.Context, id uuid.UUID) ([]models.SecretShare, error) {
+	var shares []models.SecretShare
+	if err := c.do(ctx, http.MethodGet, "/api/v1/secrets/"+id.String()+"/share", nil, &shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// SharedSecrets lists the secrets other accounts share with this one.
+func (c *Client) SharedSecrets(ctx context.Context) ([]models.SharedSecret, error) {
+	var shared []models.SharedSecret
+	if err := c.do(ctx, http.MethodGet, "/api/v1/shared", nil, &shared); err != nil {
+		return nil, err
+	}
+	return shared, nil
+}
+
+// GetSharedSecret fetches one secret shared with this account.
+func (c *Client) GetSharedSecret(ctx context.Context, id uuid.UUID) (*models.SharedSecret, error) {
+	var shared models.SharedSecret
+	if err := c.do(ctx, http.MethodGet, "/api/v1/shared/"+id.String(), nil, &shared); err != nil {
+		return nil, err
+	}
+	return &shared, nil
+}
+
+// UpdateSharedSecret rewrites a secret shared with this account under a
+// write grant.
+func (c *Client) UpdateSharedSecret(ctx context.Context, secret *models.Secret) (*SecretResult, error) {
+	var resp SecretResult
+	if err := c.do(ctx, http.MethodPut, "/api/v1/shared/"+secret.ID.String(), secret, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddEmergencyContact designates another account as an emergency contact
+// with the given waiting period in days.
+func (c *Client) AddEmergencyContact(ctx context.Context, login string, waitDays int) (*models.EmergencyContact, error) {
+	body := struct {
+		Login    string `json:"login"`
+		WaitDays int    `json:"wait_days"`
+	}{Login: login, WaitDays: waitDays}
+	var contact models.EmergencyContact
+	if err := c.do(ctx, http.MethodPost, "/api/v1/emergency/contacts", body, &contact); err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
+
+// RemoveEmergencyContact revokes an emergency contact designation.
+func (c *Client) RemoveEmergencyContact(ctx context.Context, login string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/emergency/contacts/"+url.PathEscape(login), nil, nil)
+}
+
+// EmergencyContacts lists the account's emergency contacts.
+func (c *Client) EmergencyContacts(ctx context.Context) ([]models.EmergencyContact, error) {
+	var contacts []models.EmergencyContact
+	if err := c.do(ctx, http.MethodGet, "/api/v1/emergency/contacts", nil, &contacts); err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+// EmergencyTrusts lists the vaults this account is an emergency contact
+// for.
+func (c *Client) EmergencyTrusts(ctx context.Context) ([]models.EmergencyContact, error) {
+	var trusts []models.EmergencyContact
+	if err := c.do(ctx, http.MethodGet, "/api/v1/emergency/trusts", nil, &trusts); err != nil {
+		return nil, err
+	}
+	return trusts, nil
+}
+
+// RequestEmergencyAccess starts the waiting period on the named owner's
+// vault.
+func (c *Client) RequestEmergencyAccess(ctx context.Context, ownerLogin string) (*models.EmergencyContact, error) {
+	var designation models.EmergencyContact
+	if err := c.do(ctx, http.MethodPost, "/api/v1/emergency/trusts/"+url.PathEscape(ownerLogin)+"/request", nil, &designation); err != nil {
+		return nil, err
+	}
+	return &designation, nil
+}
+
+// DenyEmergencyAccess cancels a contact's pending access request.
+func (c *Client) DenyEmergencyAccess(ctx context.Context, login string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/emergency/contacts/"+url.PathEscape(login)+"/request", nil, nil)
+}
+
+// EmergencyVault reads the named owner's secrets once the waiting period
+// has elapsed.
+func (c *Client) EmergencyVault(ctx context.Context, ownerLogin string) ([]models.Secret, error) {
+	var secrets []models.Secret
+	if err := c.do(ctx, http.MethodGet, "/api/v1/emergency/trusts/"+url.PathEscape(ownerLogin)+"/secrets", nil, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// Usage reports the account's storage consumption and quota limits.
+func (c *Client) Usage(ctx context.Context) (*models.StorageUsage, error) {
+	var usage models.StorageUsage
+	if err := c.do(ctx, http.MethodGet, "/api/v1/me/usage", nil, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// SetArchivePolicy sets after how many days of inactivity the user's secrets
+// are auto-archived; 0 opts out.
+func (c *Client) SetArchivePolicy(ctx context.Context, days int) error {
+	body := struct {
+		ArchiveAfterDays int `json:"archive_after_days"`
+	}{ArchiveAfterDays: days}
+	return c.do(ctx, http.MethodPut, "/api/v1/user/archive-policy", body, nil)
+}
+
+type hintBody struct {
+	Hint string `json:"hint"`
+}
+
+// SetHint replaces the account's vault hint; empty clears it.
+func (c *Client) SetHint(ctx context.Context, hint string) error {
+	return c.do(ctx, http.MethodPut, "/api/v1/user/hint", hintBody{Hint: hint}, nil)
+}
+
+// GetHint fetches the account's vault hint, empty when none is set.
+func (c *Client) GetHint(ctx context.Context) (string, error) {
+	var resp hintBody
+	if err := c.do(ctx, http.MethodGet, "/api/v1/user/hint", nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.Hint, nil
+}
+
+type verifierBody struct {
+	Verifier []byte `json:"verifier"`
+}
+
+// SetMasterVerifier stores the account's master-password verifier; empty
+// clears it. The server treats it as opaque bytes.
+func (c *Client) SetMasterVerifier(ctx context.Context, verifier []byte) error {
+	return c.do(ctx, http.MethodPut, "/api/v1/user/master-verifier", verifierBody{Verifier: verifier}, nil)
+}
+
+// GetMasterVerifier fetches the account's master-password verifier, empty
+// when none is set.
+func (c *Client) GetMasterVerifier(ctx context.Context) ([]byte, error) {
+	var resp verifierBody
+	if err := c.do(ctx, http.MethodGet, "/api/v1/user/master-verifier", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Verifier, nil
+}
+
+// DeleteAccount schedules the authenticated account for permanent
+// deletion and returns when the grace period ends. password is the
+// caller's own password, re-checked server-side.
+func (c *Client) DeleteAccount(ctx context.Context, password string) (time.Time, error) {
+	body := struct {
+		Password string `json:"password"`
+	}{Password: password}
+	var resp struct {
+		DeleteAfter time.Time `json:"delete_after"`
+	}
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/me", body, &resp); err != nil {
+		return time.Time{}, err
+	}
+	return resp.DeleteAfter, nil
+}
+
+// RestoreAccount cancels a pending account deletion while the grace
+// period lasts.
+func (c *Client) RestoreAccount(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/me/restore", nil, nil)
+}
+
+// MergeAccount folds the account named by sourceLogin into the
+// authenticated one and returns how many secrets moved. password is the
+// caller's own password; both are required server-side.
+func (c *Client) MergeAccount(ctx context.Context, password, sourceLogin, sourcePassword string) (int, error) {
+	body := struct {
+		Password       string `json:"password"`
+		SourceLogin    string `json:"source_login"`
+		SourcePassword string `json:"source_password"`
+	}{Password: password, SourceLogin: sourceLogin, SourcePassword: sourcePassword}
+	var resp struct {
+		MovedSecrets int `json:"moved_secrets"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/account/merge", body, &resp); err != nil {
+		return 0, err
+	}
+	return resp.MovedSecrets, nil
+}
+
+// VerifyPassword re-checks the account password of the authenticated user.
+func (c *Client) VerifyPassword(ctx context.Context, password string) error {
+	body := struct {
+		Password string `json:"password"`
+	}{Password: password}
+	return c.do(ctx, http.MethodPost, "/api/v1/auth/verify", body, nil)
+}
+
+// compressThreshold is the request body size above which the client gzips
+// the payload. Small bodies are not worth the header overhead. Response
+// compression needs no handling here: net/http negotiates and inflates
+// gzip transparently.
+const compressThreshold = 1 << 10
+
+// do sends one API request, retrying transient failures per the retry
+// policy. The body is marshalled once so every attempt sends identical
+// bytes.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var data []byte
+	compressed := false
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		if len(data) > compressThreshold {
+			var buf bytes.Buffer
+			zw := gzip.NewWriter(&buf)
+			if _, err := zw.Write(data); err == nil && zw.Close() == nil {
+				data = buf.Bytes()
+				compressed = true
+			}
+		}
+	}
+
+	attempts := c.retry.attempts(method)
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			if err := sleep(ctx, c.retry.backoff(attempt-1)); err != nil {
+				return lastErr
+			}
+		}
+		retryable, err := c.send(ctx, method, path, data, body != nil, compressed, out)
+		if err == nil {
+			return nil
+		}
+		if !retryable || ctx.Err() != nil {
+			return err
+		}
+		lastErr = err
+	}
+	return lastErr
+}
+
+// send performs a single attempt of do. It reports whether a failure is
+// worth retrying: connection errors and 5xx responses are, anything the
+// server answered deliberately is not.
+func (c *Client) send(ctx context.Context, method, path string, data []byte, hasBody, compressed bool, out any) (retryable bool, err error) {
+	var reqBody io.Reader
+	if hasBody {
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return false, err
+	}
+	if hasBody {
+		req.Header.Set("Content-Type", "application/json")
+		if compressed {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return true, c.errorFrom(resp)
+	}
+	if resp.StatusCode >= 400 {
+		return false, c.errorFrom(resp)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return false, fmt.Errorf("decode response: %w", err)
+		}
+	}
+	return false, nil
+}
+
+func (c *Client) errorFrom(resp *http.Response) error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	var body struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Error != "" {
+		apiErr.Message = body.Error
+		apiErr.Code = body.Code
+	} else {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
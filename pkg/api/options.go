@@ -0,0 +1,75 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ClientOption customizes a Client at construction, so consumers can add
+// tracing, metrics or extra headers without forking the client.
+type ClientOption func(*Client)
+
+// WithTransport replaces the underlying HTTP transport, e.g. with one
+// that records traces. SetTLSConfig overwrites the transport, so combine
+// the TLS configuration into the custom transport instead of calling
+// both.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) { c.http.Transport = rt }
+}
+
+// WithRequestHook registers a hook that runs on every outgoing request
+// just before it is sent, once per attempt. Hooks run in registration
+// order and may mutate the request, e.g. to add headers.
+func WithRequestHook(hook func(*http.Request)) ClientOption {
+	return func(c *Client) { c.requestHooks = append(c.requestHooks, hook) }
+}
+
+// WithResponseHook registers a hook that runs on every response before
+// the client reads its body. Hooks run in registration order and must not
+// consume the body.
+func WithResponseHook(hook func(*http.Response)) ClientOption {
+	return func(c *Client) { c.responseHooks = append(c.responseHooks, hook) }
+}
+
+// WithLogger makes the client log one line per attempt with method, path,
+// status and duration.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) { c.logger = logger }
+}
+
+// WithRetryPolicy sets the retry policy at construction; see
+// SetRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) { c.retry = p }
+}
+
+// roundTrip sends one request through the registered hooks and logger.
+// Every attempt of every API call, including attachment downloads, goes
+// through here.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	for _, hook := range c.requestHooks {
+		hook(req)
+	}
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if c.logger != nil {
+		attrs := []any{
+			"method", req.Method,
+			"path", req.URL.Path,
+			"duration", time.Since(start).String(),
+		}
+		if err != nil {
+			c.logger.Warn("api request failed", append(attrs, "err", err)...)
+		} else {
+			c.logger.Debug("api request", append(attrs, "status", resp.StatusCode)...)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range c.responseHooks {
+		hook(resp)
+	}
+	return resp, nil
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how the client retries requests that failed
+// transiently: connection errors and 5xx responses. Only idempotent
+// requests (GET, PUT, DELETE) are retried; a POST gets a single attempt,
+// since the server may have acted on it before the connection broke. The
+// zero value disables retries entirely.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries including the first; 0 or
+	// 1 disables retries.
+	MaxAttempts int
+	// BaseDelay seeds the exponential backoff: the n-th retry waits
+	// BaseDelay*2^(n-1), jittered.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between attempts.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is what New installs: three attempts with a backoff
+// that stays well under the request timeout.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// SetRetryPolicy replaces the retry policy. Pass the zero RetryPolicy to
+// turn retries off.
+func (c *Client) SetRetryPolicy(p RetryPolicy) { c.retry = p }
+
+// attempts returns how many tries a request with the given method gets.
+func (p RetryPolicy) attempts(method string) int {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		return 1
+	}
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the wait before the given retry (1-based): exponential
+// in BaseDelay, capped at MaxDelay, with the upper half jittered so
+// simultaneous clients spread out.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.BaseDelay << (retry - 1)
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// sleep waits for d or until the context is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
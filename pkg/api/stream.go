@@ -0,0 +1,56 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/alexuryumtsev/gophkeeper/internal/models"
+)
+
+// SyncStream connects to the server-sent events stream and calls fn for
+// every change event of the authenticated user. It blocks until ctx is
+// cancelled or the connection drops, returning the reason.
+func (c *Client) SyncStream(ctx context.Context, fn func(models.OutboxEvent)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/sync/stream", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	for _, hook := range c.requestHooks {
+		hook(req)
+	}
+	// The stream is expected to stay open far longer than the regular
+	// request timeout, so it goes through its own client, sharing the
+	// transport so TLS settings apply.
+	resp, err := (&http.Client{Transport: c.http.Transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return c.errorFrom(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	var data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		case line == "" && data != "":
+			var event models.OutboxEvent
+			if err := json.Unmarshal([]byte(data), &event); err == nil {
+				fn(event)
+			}
+			data = ""
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,85 @@
+// Package crypto provides the symmetric encryption primitives used by
+// gophkeeper: AES-256-GCM for payloads and Argon2id for key derivation.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// KeySize is the AES-256 key size in bytes.
+const KeySize = 32
+
+// Argon2id parameters for key derivation.
+const (
+	argonTime    = 1
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+)
+
+// ErrInvalidCiphertext is returned when a ciphertext is malformed or fails
+// authentication.
+var ErrInvalidCiphertext = errors.New("crypto: invalid ciphertext")
+
+// DeriveKey derives a KeySize-byte encryption key from a secret and a salt
+// using Argon2id. It is pinned to DefaultKDFParams: its callers re-derive
+// the key from scratch on every use and store no parameters, so both ends
+// must always agree on the cost. Passphrase sealing, which records its
+// parameters next to the ciphertext, follows SetKDFParams instead.
+func DeriveKey(secret, salt []byte) []byte {
+	return deriveKey(secret, salt, DefaultKDFParams)
+}
+
+// Encrypt seals plaintext with the configured cipher suite — AES-256-GCM
+// unless SetCipherSuite selected another. The random nonce is prepended to
+// the returned ciphertext.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	if sealSuite == SuiteXChaCha {
+		return encryptXChaCha(key, plaintext)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("crypto: generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt opens a ciphertext produced by Encrypt, dispatching on the suite
+// tag: XChaCha20-Poly1305 ciphertext is tagged, AES-256-GCM is not.
+func Decrypt(key, ciphertext []byte) ([]byte, error) {
+	if isXChaCha(ciphertext) {
+		return decryptXChaCha(key, ciphertext)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("crypto: key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,59 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	key := DeriveKey([]byte("master secret"), []byte("salt"))
+	plaintext := []byte("attack at dawn")
+
+	ciphertext, err := Encrypt(key, plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Fatal("ciphertext contains plaintext")
+	}
+
+	got, err := Decrypt(key, ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("round trip mismatch: got %q", got)
+	}
+}
+
+func TestDecryptWrongKey(t *testing.T) {
+	key := DeriveKey([]byte("master secret"), []byte("salt"))
+	other := DeriveKey([]byte("other secret"), []byte("salt"))
+
+	ciphertext, err := Encrypt(key, []byte("payload"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if _, err := Decrypt(other, ciphertext); err == nil {
+		t.Fatal("Decrypt with wrong key succeeded")
+	}
+}
+
+func TestDecryptTruncated(t *testing.T) {
+	key := DeriveKey([]byte("master secret"), []byte("salt"))
+	if _, err := Decrypt(key, []byte("short")); err == nil {
+		t.Fatal("Decrypt of truncated ciphertext succeeded")
+	}
+}
+
+func TestDeriveKeyDeterministic(t *testing.T) {
+	a := DeriveKey([]byte("secret"), []byte("salt"))
+	b := DeriveKey([]byte("secret"), []byte("salt"))
+	c := DeriveKey([]byte("secret"), []byte("other salt"))
+	if !bytes.Equal(a, b) {
+		t.Fatal("same inputs produced different keys")
+	}
+	if bytes.Equal(a, c) {
+		t.Fatal("different salts produced the same key")
+	}
+}
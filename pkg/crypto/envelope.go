@@ -0,0 +1,101 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// envelopeMagic marks an envelope-encrypted blob: a wrapped data key
+// followed by the payload sealed under it.
+var envelopeMagic = []byte("GKENV1")
+
+// wrappedKeySize is the length of a KeySize-byte data key sealed with
+// AES-256-GCM: nonce, key, tag.
+const wrappedKeySize = 12 + KeySize + 16
+
+// wrappedKeyLen returns the length of the wrapped data key at the start of
+// an envelope body, which depends on the suite that sealed it: an
+// XChaCha20-Poly1305 wrap carries its tag and a larger nonce.
+func wrappedKeyLen(body []byte) int {
+	if isXChaCha(body) {
+		return len(xchachaMagic) + chacha20poly1305.NonceSizeX + KeySize + chacha20poly1305.Overhead
+	}
+	return wrappedKeySize
+}
+
+// EncryptEnvelope seals plaintext under a fresh random data key and wraps
+// that key with kek, so rotating kek only requires re-wrapping
+// wrappedKeySize bytes per blob instead of re-encrypting every payload.
+func EncryptEnvelope(kek, plaintext []byte) ([]byte, error) {
+	dek := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, fmt.Errorf("crypto: generate data key: %w", err)
+	}
+	defer Zero(dek)
+	wrapped, err := Encrypt(kek, dek)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := Encrypt(dek, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(envelopeMagic)+len(wrapped)+len(sealed))
+	out = append(out, envelopeMagic...)
+	out = append(out, wrapped...)
+	return append(out, sealed...), nil
+}
+
+// DecryptEnvelope opens a blob produced by EncryptEnvelope.
+func DecryptEnvelope(kek, data []byte) ([]byte, error) {
+	if !IsEnvelope(data) {
+		return nil, ErrInvalidCiphertext
+	}
+	body := data[len(envelopeMagic):]
+	n := wrappedKeyLen(body)
+	if len(body) < n {
+		return nil, ErrInvalidCiphertext
+	}
+	dek, err := Decrypt(kek, body[:n])
+	if err != nil {
+		return nil, err
+	}
+	defer Zero(dek)
+	return Decrypt(dek, body[n:])
+}
+
+// IsEnvelope reports whether data carries the envelope format marker.
+func IsEnvelope(data []byte) bool {
+	return bytes.HasPrefix(data, envelopeMagic)
+}
+
+// RewrapEnvelope re-wraps the data key of an envelope under a new KEK
+// without touching the sealed payload, which is what makes key rotation
+// cheap.
+func RewrapEnvelope(oldKEK, newKEK, data []byte) ([]byte, error) {
+	if !IsEnvelope(data) {
+		return nil, ErrInvalidCiphertext
+	}
+	body := data[len(envelopeMagic):]
+	n := wrappedKeyLen(body)
+	if len(body) < n {
+		return nil, ErrInvalidCiphertext
+	}
+	dek, err := Decrypt(oldKEK, body[:n])
+	if err != nil {
+		return nil, err
+	}
+	defer Zero(dek)
+	wrapped, err := Encrypt(newKEK, dek)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(envelopeMagic)+len(wrapped)+len(body)-n)
+	out = append(out, envelopeMagic...)
+	out = append(out, wrapped...)
+	return append(out, body[n:]...), nil
+}
@@ -0,0 +1,85 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	kek := DeriveKey([]byte("master secret"), []byte("salt"))
+	plaintext := []byte("attack at dawn")
+
+	sealed, err := EncryptEnvelope(kek, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptEnvelope: %v", err)
+	}
+	if !IsEnvelope(sealed) {
+		t.Fatal("sealed blob not recognized as envelope")
+	}
+	if bytes.Contains(sealed, plaintext) {
+		t.Fatal("envelope contains plaintext")
+	}
+
+	got, err := DecryptEnvelope(kek, sealed)
+	if err != nil {
+		t.Fatalf("DecryptEnvelope: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("round trip mismatch: got %q", got)
+	}
+}
+
+func TestEnvelopeWrongKey(t *testing.T) {
+	kek := DeriveKey([]byte("master secret"), []byte("salt"))
+	other := DeriveKey([]byte("other secret"), []byte("salt"))
+
+	sealed, err := EncryptEnvelope(kek, []byte("payload"))
+	if err != nil {
+		t.Fatalf("EncryptEnvelope: %v", err)
+	}
+	if _, err := DecryptEnvelope(other, sealed); err == nil {
+		t.Fatal("DecryptEnvelope with wrong key succeeded")
+	}
+}
+
+func TestRewrapEnvelope(t *testing.T) {
+	oldKEK := DeriveKey([]byte("old secret"), []byte("salt"))
+	newKEK := DeriveKey([]byte("new secret"), []byte("salt"))
+	plaintext := []byte("payload")
+
+	sealed, err := EncryptEnvelope(oldKEK, plaintext)
+	if err != nil {
+		t.Fatalf("EncryptEnvelope: %v", err)
+	}
+	rewrapped, err := RewrapEnvelope(oldKEK, newKEK, sealed)
+	if err != nil {
+		t.Fatalf("RewrapEnvelope: %v", err)
+	}
+	got, err := DecryptEnvelope(newKEK, rewrapped)
+	if err != nil {
+		t.Fatalf("DecryptEnvelope with new KEK: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("round trip mismatch: got %q", got)
+	}
+	if _, err := DecryptEnvelope(oldKEK, rewrapped); err == nil {
+		t.Fatal("DecryptEnvelope with old KEK succeeded after rewrap")
+	}
+	if _, err := RewrapEnvelope(oldKEK, newKEK, []byte("not an envelope")); err == nil {
+		t.Fatal("RewrapEnvelope of a non-envelope succeeded")
+	}
+}
+
+func TestEnvelopeRejectsPlainCiphertext(t *testing.T) {
+	kek := DeriveKey([]byte("master secret"), []byte("salt"))
+	plain, err := Encrypt(kek, []byte("payload"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if IsEnvelope(plain) {
+		t.Fatal("plain ciphertext recognized as envelope")
+	}
+	if _, err := DecryptEnvelope(kek, plain); err == nil {
+		t.Fatal("DecryptEnvelope of plain ciphertext succeeded")
+	}
+}
@@ -0,0 +1,46 @@
+package crypto
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// KDFParams are the Argon2id cost parameters of a key derivation.
+type KDFParams struct {
+	// Time is the number of passes over memory.
+	Time uint32
+	// Memory is the amount of memory used, in KiB.
+	Memory uint32
+	// Threads is the degree of parallelism.
+	Threads uint8
+}
+
+// DefaultKDFParams are the parameters used when nothing else is
+// configured. They match what every release so far derived keys with.
+var DefaultKDFParams = KDFParams{Time: argonTime, Memory: argonMemory, Threads: argonThreads}
+
+// sealParams are the parameters new passphrase-sealed data is written
+// with. Opening is unaffected: sealed data records its own parameters.
+var sealParams = DefaultKDFParams
+
+// SetKDFParams tunes the cost of newly derived passphrase keys, e.g. down
+// for slow devices or up as hardware improves. Existing sealed data keeps
+// opening either way, because the parameters are stored alongside the
+// ciphertext.
+func SetKDFParams(p KDFParams) error {
+	if p.Time < 1 || p.Threads < 1 {
+		return fmt.Errorf("crypto: KDF time and threads must be at least 1")
+	}
+	// Argon2 requires at least 8 KiB of memory per thread.
+	if p.Memory < 8*uint32(p.Threads) {
+		return fmt.Errorf("crypto: KDF memory must be at least %d KiB for %d threads", 8*p.Threads, p.Threads)
+	}
+	sealParams = p
+	return nil
+}
+
+// deriveKey runs Argon2id with explicit cost parameters.
+func deriveKey(secret, salt []byte, p KDFParams) []byte {
+	return argon2.IDKey(secret, salt, p.Time, p.Memory, p.Threads, KeySize)
+}
@@ -0,0 +1,85 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// saltSize is the length of the random salt prepended to passphrase-sealed
+// data.
+const saltSize = 16
+
+// Versioned passphrase-sealed format: kdfMagic, a version byte, the
+// Argon2id parameters the key was derived with, the salt, then the
+// ciphertext. Recording the parameters per blob is what lets them be
+// tuned without re-sealing existing data.
+var kdfMagic = []byte("GKKDF")
+
+const (
+	// kdfV1 stores time and memory as big-endian uint32 and threads as a
+	// single byte, 9 bytes in total.
+	kdfV1         = 1
+	kdfParamsSize = 4 + 4 + 1
+)
+
+// SealWithPassphrase encrypts plaintext under a key derived from the
+// passphrase with a fresh random salt. The derivation parameters and the
+// salt are prepended to the returned ciphertext, so the passphrase alone
+// opens it again.
+func SealWithPassphrase(passphrase string, plaintext []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("crypto: generate salt: %w", err)
+	}
+	p := sealParams
+	key := deriveKey([]byte(passphrase), salt, p)
+	sealed, err := Encrypt(key, plaintext)
+	Zero(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(kdfMagic)+1+kdfParamsSize+saltSize+len(sealed))
+	out = append(out, kdfMagic...)
+	out = append(out, kdfV1)
+	out = binary.BigEndian.AppendUint32(out, p.Time)
+	out = binary.BigEndian.AppendUint32(out, p.Memory)
+	out = append(out, p.Threads)
+	out = append(out, salt...)
+	return append(out, sealed...), nil
+}
+
+// OpenWithPassphrase decrypts data produced by SealWithPassphrase, reading
+// the derivation parameters from the header. Data without the header comes
+// from before KDF versioning and opens with DefaultKDFParams, which is
+// what it was sealed with.
+func OpenWithPassphrase(passphrase string, sealed []byte) ([]byte, error) {
+	if !bytes.HasPrefix(sealed, kdfMagic) {
+		if len(sealed) < saltSize {
+			return nil, ErrInvalidCiphertext
+		}
+		salt, ciphertext := sealed[:saltSize], sealed[saltSize:]
+		key := deriveKey([]byte(passphrase), salt, DefaultKDFParams)
+		defer Zero(key)
+		return Decrypt(key, ciphertext)
+	}
+	rest := sealed[len(kdfMagic):]
+	if len(rest) < 1+kdfParamsSize+saltSize {
+		return nil, ErrInvalidCiphertext
+	}
+	if rest[0] != kdfV1 {
+		return nil, fmt.Errorf("crypto: unsupported KDF version %d", rest[0])
+	}
+	p := KDFParams{
+		Time:    binary.BigEndian.Uint32(rest[1:5]),
+		Memory:  binary.BigEndian.Uint32(rest[5:9]),
+		Threads: rest[9],
+	}
+	salt := rest[1+kdfParamsSize : 1+kdfParamsSize+saltSize]
+	ciphertext := rest[1+kdfParamsSize+saltSize:]
+	key := deriveKey([]byte(passphrase), salt, p)
+	defer Zero(key)
+	return Decrypt(key, ciphertext)
+}
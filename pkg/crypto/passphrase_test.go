@@ -0,0 +1,84 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPassphraseRoundTrip(t *testing.T) {
+	sealed, err := SealWithPassphrase("correct horse", []byte("payload"))
+	if err != nil {
+		t.Fatalf("SealWithPassphrase: %v", err)
+	}
+	opened, err := OpenWithPassphrase("correct horse", sealed)
+	if err != nil {
+		t.Fatalf("OpenWithPassphrase: %v", err)
+	}
+	if !bytes.Equal(opened, []byte("payload")) {
+		t.Fatalf("got %q, want payload", opened)
+	}
+
+	if _, err := OpenWithPassphrase("wrong", sealed); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Fatalf("err = %v, want ErrInvalidCiphertext", err)
+	}
+	if _, err := OpenWithPassphrase("correct horse", sealed[:4]); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Fatalf("short input err = %v, want ErrInvalidCiphertext", err)
+	}
+}
+
+// TestOpenPreVersioningFormat checks data sealed before the KDF header was
+// introduced — a bare salt followed by the ciphertext — still opens.
+func TestOpenPreVersioningFormat(t *testing.T) {
+	salt := bytes.Repeat([]byte{7}, saltSize)
+	sealed, err := Encrypt(DeriveKey([]byte("correct horse"), salt), []byte("payload"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	opened, err := OpenWithPassphrase("correct horse", append(salt, sealed...))
+	if err != nil {
+		t.Fatalf("OpenWithPassphrase: %v", err)
+	}
+	if !bytes.Equal(opened, []byte("payload")) {
+		t.Fatalf("got %q, want payload", opened)
+	}
+}
+
+func TestTunedKDFParams(t *testing.T) {
+	t.Cleanup(func() { sealParams = DefaultKDFParams })
+	if err := SetKDFParams(KDFParams{Time: 2, Memory: 16, Threads: 1}); err != nil {
+		t.Fatalf("SetKDFParams: %v", err)
+	}
+	sealed, err := SealWithPassphrase("correct horse", []byte("payload"))
+	if err != nil {
+		t.Fatalf("SealWithPassphrase: %v", err)
+	}
+	// Opening must not depend on the configured parameters: they are read
+	// from the header.
+	sealParams = DefaultKDFParams
+	opened, err := OpenWithPassphrase("correct horse", sealed)
+	if err != nil {
+		t.Fatalf("OpenWithPassphrase: %v", err)
+	}
+	if !bytes.Equal(opened, []byte("payload")) {
+		t.Fatalf("got %q, want payload", opened)
+	}
+
+	if err := SetKDFParams(KDFParams{Time: 0, Memory: 16, Threads: 1}); err == nil {
+		t.Fatal("SetKDFParams accepted zero time")
+	}
+	if err := SetKDFParams(KDFParams{Time: 1, Memory: 4, Threads: 1}); err == nil {
+		t.Fatal("SetKDFParams accepted too little memory")
+	}
+}
+
+func TestOpenUnknownKDFVersion(t *testing.T) {
+	sealed, err := SealWithPassphrase("correct horse", []byte("payload"))
+	if err != nil {
+		t.Fatalf("SealWithPassphrase: %v", err)
+	}
+	sealed[len(kdfMagic)] = 99
+	if _, err := OpenWithPassphrase("correct horse", sealed); err == nil {
+		t.Fatal("OpenWithPassphrase accepted an unknown version")
+	}
+}
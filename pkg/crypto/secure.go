@@ -0,0 +1,61 @@
+package crypto
+
+import "crypto/subtle"
+
+// Zero overwrites b with zeros. Call it on key material and decrypted
+// payloads as soon as they are no longer needed, so they do not linger on
+// the heap. Best effort by nature: the runtime may hold copies the caller
+// cannot reach.
+func Zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// ConstantTimeEqual reports whether a and b are equal without leaking
+// where they differ through timing. Use it whenever either side is secret
+// — tokens, fingerprints, stored hashes.
+func ConstantTimeEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// SecureString holds a secret in a wipeable buffer. Go strings are
+// immutable and stay on the heap until collected; keeping the secret in a
+// byte slice lets Wipe scrub this copy the moment it is no longer needed.
+type SecureString struct {
+	data []byte
+}
+
+// NewSecureString copies s into a SecureString. The caller still owns the
+// original string; prefer building secrets as []byte where possible.
+func NewSecureString(s string) *SecureString {
+	return &SecureString{data: []byte(s)}
+}
+
+// Reveal returns the secret as a string for APIs that require one. The
+// returned string is a fresh immutable copy outside Wipe's reach.
+func (s *SecureString) Reveal() string {
+	return string(s.data)
+}
+
+// Bytes returns the underlying buffer without copying. It becomes invalid
+// after Wipe.
+func (s *SecureString) Bytes() []byte {
+	return s.data
+}
+
+// Equal compares the secret against other in constant time.
+func (s *SecureString) Equal(other string) bool {
+	return ConstantTimeEqual(s.data, []byte(other))
+}
+
+// IsZero reports whether the secret is unset or has been wiped.
+func (s *SecureString) IsZero() bool {
+	return s == nil || len(s.data) == 0
+}
+
+// Wipe zeroes the buffer and detaches it.
+func (s *SecureString) Wipe() {
+	Zero(s.data)
+	s.data = nil
+}
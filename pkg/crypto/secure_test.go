@@ -0,0 +1,52 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestZero(t *testing.T) {
+	b := []byte("sensitive")
+	Zero(b)
+	if !bytes.Equal(b, make([]byte, len("sensitive"))) {
+		t.Fatalf("buffer not zeroed: %q", b)
+	}
+}
+
+func TestConstantTimeEqual(t *testing.T) {
+	if !ConstantTimeEqual([]byte("token"), []byte("token")) {
+		t.Fatal("equal inputs reported unequal")
+	}
+	if ConstantTimeEqual([]byte("token"), []byte("Token")) {
+		t.Fatal("different inputs reported equal")
+	}
+	if ConstantTimeEqual([]byte("token"), []byte("tok")) {
+		t.Fatal("inputs of different length reported equal")
+	}
+}
+
+func TestSecureString(t *testing.T) {
+	s := NewSecureString("hunter2")
+	if s.IsZero() {
+		t.Fatal("fresh SecureString reported zero")
+	}
+	if s.Reveal() != "hunter2" {
+		t.Fatalf("Reveal = %q", s.Reveal())
+	}
+	if !s.Equal("hunter2") || s.Equal("hunter3") {
+		t.Fatal("Equal misbehaves")
+	}
+	buf := s.Bytes()
+	s.Wipe()
+	if !s.IsZero() {
+		t.Fatal("wiped SecureString not zero")
+	}
+	if !bytes.Equal(buf, make([]byte, len("hunter2"))) {
+		t.Fatalf("underlying buffer not scrubbed: %q", buf)
+	}
+
+	var nilString *SecureString
+	if !nilString.IsZero() {
+		t.Fatal("nil SecureString not zero")
+	}
+}
@@ -0,0 +1,60 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// NewPassphraseVerifier returns a token CheckPassphraseVerifier can later
+// test a passphrase against: the KDF parameters, a fresh salt and a
+// SHA-256 of the derived key. The derived key never leaves this function,
+// so the token reveals nothing about the passphrase and is safe to store
+// server-side.
+func NewPassphraseVerifier(passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("crypto: generate salt: %w", err)
+	}
+	p := sealParams
+	key := deriveKey([]byte(passphrase), salt, p)
+	sum := sha256.Sum256(key)
+	Zero(key)
+	out := make([]byte, 0, len(kdfMagic)+1+kdfParamsSize+saltSize+len(sum))
+	out = append(out, kdfMagic...)
+	out = append(out, kdfV1)
+	out = binary.BigEndian.AppendUint32(out, p.Time)
+	out = binary.BigEndian.AppendUint32(out, p.Memory)
+	out = append(out, p.Threads)
+	out = append(out, salt...)
+	return append(out, sum[:]...), nil
+}
+
+// CheckPassphraseVerifier reports whether passphrase is the one the
+// verifier was built from, re-deriving with the recorded parameters. A
+// malformed verifier is an error, not a mismatch.
+func CheckPassphraseVerifier(passphrase string, verifier []byte) (bool, error) {
+	if !bytes.HasPrefix(verifier, kdfMagic) {
+		return false, ErrInvalidCiphertext
+	}
+	rest := verifier[len(kdfMagic):]
+	if len(rest) != 1+kdfParamsSize+saltSize+sha256.Size {
+		return false, ErrInvalidCiphertext
+	}
+	if rest[0] != kdfV1 {
+		return false, fmt.Errorf("crypto: unsupported KDF version %d", rest[0])
+	}
+	p := KDFParams{
+		Time:    binary.BigEndian.Uint32(rest[1:5]),
+		Memory:  binary.BigEndian.Uint32(rest[5:9]),
+		Threads: rest[9],
+	}
+	salt := rest[1+kdfParamsSize : 1+kdfParamsSize+saltSize]
+	key := deriveKey([]byte(passphrase), salt, p)
+	sum := sha256.Sum256(key)
+	Zero(key)
+	return ConstantTimeEqual(sum[:], rest[1+kdfParamsSize+saltSize:]), nil
+}
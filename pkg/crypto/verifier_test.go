@@ -0,0 +1,38 @@
+package crypto
+
+import "testing"
+
+func TestPassphraseVerifier(t *testing.T) {
+	verifier, err := NewPassphraseVerifier("correct horse")
+	if err != nil {
+		t.Fatalf("NewPassphraseVerifier: %v", err)
+	}
+	ok, err := CheckPassphraseVerifier("correct horse", verifier)
+	if err != nil || !ok {
+		t.Fatalf("right passphrase rejected: ok=%v err=%v", ok, err)
+	}
+	ok, err = CheckPassphraseVerifier("correct hors", verifier)
+	if err != nil || ok {
+		t.Fatalf("wrong passphrase accepted: ok=%v err=%v", ok, err)
+	}
+	if _, err := CheckPassphraseVerifier("correct horse", []byte("garbage")); err == nil {
+		t.Fatal("malformed verifier accepted")
+	}
+}
+
+func TestPassphraseVerifierRecordsParams(t *testing.T) {
+	original := sealParams
+	t.Cleanup(func() { sealParams = original })
+
+	sealParams = KDFParams{Time: 2, Memory: 16 * 1024, Threads: 2}
+	verifier, err := NewPassphraseVerifier("tuned")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Checking must use the recorded parameters, not the configured ones.
+	sealParams = DefaultKDFParams
+	ok, err := CheckPassphraseVerifier("tuned", verifier)
+	if err != nil || !ok {
+		t.Fatalf("verifier not self-describing: ok=%v err=%v", ok, err)
+	}
+}